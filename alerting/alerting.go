@@ -0,0 +1,326 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+// Alert states. An alert is pending while its condition holds for less than the rule's for
+// duration, firing afterwards and resolved once the condition stops holding.
+const (
+	StateInactive = "inactive"
+	StatePending  = "pending"
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+const (
+	// DefaultEvaluationInterval is the time between two evaluations unless configured otherwise.
+	DefaultEvaluationInterval = 30 * time.Second
+	// DefaultLookback is the age of the newest data point a rule still evaluates unless configured otherwise.
+	DefaultLookback = 10 * time.Minute
+)
+
+// basePath is the directory the alert state is persisted in.
+var basePath = common.GetBasePath()
+
+var (
+	mu      sync.Mutex
+	rules   []models.AlertRule
+	alerts  map[string]*models.Alert // Alert of each rule by rule name
+	config  models.AlertingConfig
+	stopEng context.CancelFunc
+	engDone chan struct{}
+
+	notifiers  []Notifier
+	notified   map[string]notifiedGroup // Last notification of each firing group by group key
+	deliveries sync.WaitGroup
+)
+
+// Start evaluates the alert rules every interval until Stop is called, restoring the alert
+// states persisted by a previous run, and delivers the firing and resolved alerts to the
+// notifiers. Calling it again restarts the evaluation with the new rules.
+func Start(ruleSet []models.AlertRule, notifierSet []Notifier, cfg models.AlertingConfig) error {
+	if err := ValidateRules(ruleSet); err != nil {
+		return err
+	}
+	if err := ValidateNotifiers(notifierSet); err != nil {
+		return err
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultEvaluationInterval
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = DefaultLookback
+	}
+	if cfg.RepeatInterval <= 0 {
+		cfg.RepeatInterval = DefaultRepeatInterval
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+	if cfg.NotifyTimeout <= 0 {
+		cfg.NotifyTimeout = DefaultNotifyTimeout
+	}
+
+	Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	rules, notifiers, config = ruleSet, notifierSet, cfg
+	alerts = restoreAlerts(ruleSet)
+	notified = restoreNotified()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	stopEng, engDone = cancel, done
+
+	go func() {
+		defer close(done)
+		evaluate(ctx, time.Now())
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				evaluate(ctx, now)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops evaluating the alert rules, cancels the notifications being delivered and waits
+// for them to end. Safe to call multiple times.
+func Stop() {
+	mu.Lock()
+	cancel, done := stopEng, engDone
+	stopEng, engDone = nil, nil
+	mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	deliveries.Wait()
+}
+
+// GetRules returns the alert rules being evaluated.
+func GetRules() []models.AlertRule {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append([]models.AlertRule{}, rules...)
+}
+
+// GetAlerts returns the pending and firing alerts, or the alerts of every rule when all is
+// set, firing ones first.
+func GetAlerts(all bool) []models.Alert {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := []models.Alert{}
+	for _, alert := range alerts {
+		if all || alert.State == StatePending || alert.State == StateFiring {
+			result = append(result, *alert)
+		}
+	}
+
+	order := map[string]int{StateFiring: 0, StatePending: 1, StateResolved: 2, StateInactive: 3}
+	sort.Slice(result, func(i, j int) bool {
+		if order[result[i].State] != order[result[j].State] {
+			return order[result[i].State] < order[result[j].State]
+		}
+		return result[i].Rule < result[j].Rule
+	})
+	return result
+}
+
+// newAlert returns the inactive alert of a rule.
+func newAlert(rule models.AlertRule) *models.Alert {
+	return &models.Alert{
+		Rule:        rule.Name,
+		State:       StateInactive,
+		Metric:      rule.Metric,
+		Op:          rule.Op,
+		Threshold:   rule.Threshold,
+		Labels:      rule.Labels,
+		Description: rule.Description,
+	}
+}
+
+// evaluate evaluates every rule against its newest stored data point, persisting the alerts
+// when a state changed and delivering the notifications in the background until ctx is done.
+func evaluate(ctx context.Context, now time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	changed := false
+	var resolved []*models.Alert
+	for _, rule := range rules {
+		alert := alerts[rule.Name]
+		alert.LastEvaluatedAt = now
+
+		value, err := latestValue(rule, now, config.Lookback)
+		if err != nil {
+			// Without data the alert keeps its state, a gap in the metrics neither fires nor resolves it
+			alert.Value, alert.Error = nil, err.Error()
+			continue
+		}
+		alert.Value, alert.Error = &value, ""
+
+		holds, _ := compare(rule.Op, value, rule.Threshold)
+		forDuration, _ := ruleFor(rule)
+		if transition(alert, holds, forDuration, now) {
+			changed = true
+			logTransition(alert)
+			if alert.State == StateResolved {
+				resolved = append(resolved, alert)
+			}
+		}
+	}
+
+	for _, notification := range groupNotifications(resolved, now) {
+		for _, n := range notifiers {
+			deliveries.Add(1)
+			go func() {
+				defer deliveries.Done()
+				deliver(ctx, n, notification, config)
+			}()
+		}
+	}
+
+	if changed {
+		if err := saveAlerts(); err != nil {
+			logger.Log.Error("failed to persist alert states", "error", err)
+		}
+	}
+}
+
+// latestValue returns the newest value of a rule's series no older than the lookback.
+func latestValue(rule models.AlertRule, now time.Time, lookback time.Duration) (float64, error) {
+	labels := []timeseries.Label{timeseries.GetHostLabel()}
+	for name, value := range rule.Match {
+		labels = append(labels, timeseries.Label{Name: name, Value: value})
+	}
+
+	points, err := timeseries.GetDataPoints(rule.Metric, labels, now.Add(-lookback).Unix(), now.Unix()+1)
+	if err != nil && !errors.Is(err, timeseries.ErrNoDataPoints) {
+		return 0, err
+	}
+	if len(points) == 0 {
+		return 0, fmt.Errorf("no data for %s in the last %s", rule.Metric, lookback)
+	}
+
+	latest := points[0]
+	for _, p := range points[1:] {
+		if p.Timestamp >= latest.Timestamp {
+			latest = p
+		}
+	}
+	return latest.Value, nil
+}
+
+// transition advances an alert with whether its rule's condition holds at now, reporting
+// whether its state changed.
+func transition(alert *models.Alert, holds bool, forDuration time.Duration, now time.Time) bool {
+	previous := alert.State
+	at := now
+
+	if holds {
+		if alert.State != StatePending && alert.State != StateFiring {
+			alert.State, alert.ActiveAt = StatePending, &at
+		}
+		if alert.State == StatePending && now.Sub(*alert.ActiveAt) >= forDuration {
+			alert.State, alert.FiredAt = StateFiring, &at
+		}
+	} else {
+		switch alert.State {
+		case StatePending:
+			alert.State, alert.ActiveAt = StateInactive, nil
+		case StateFiring:
+			alert.State, alert.ActiveAt, alert.ResolvedAt = StateResolved, nil, &at
+		}
+	}
+	return alert.State != previous
+}
+
+// logTransition logs an alert which started or stopped firing.
+func logTransition(alert *models.Alert) {
+	switch alert.State {
+	case StateFiring:
+		logger.Log.Warn("alert firing", "rule", alert.Rule, "metric", alert.Metric, "value", *alert.Value, "op", alert.Op, "threshold", alert.Threshold)
+	case StateResolved:
+		logger.Log.Info("alert resolved", "rule", alert.Rule, "metric", alert.Metric, "value", *alert.Value)
+	}
+}
+
+// statePath returns the file the alert states are persisted in.
+func statePath() string {
+	return filepath.Join(basePath, "alerts.json")
+}
+
+// saveAlerts persists the alert states, through a temporary file so a crash never leaves a
+// partial one. Callers hold mu.
+func saveAlerts() error {
+	data, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return err
+	}
+
+	path := statePath()
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	return nil
+}
+
+// restoreAlerts returns the alerts of the rules with the states persisted by a previous run.
+// The rule definitions always come from the current rules.
+func restoreAlerts(ruleSet []models.AlertRule) map[string]*models.Alert {
+	saved := make(map[string]*models.Alert)
+	if data, err := os.ReadFile(statePath()); err == nil {
+		if err := json.Unmarshal(data, &saved); err != nil {
+			logger.Log.Warn("ignoring unreadable alert states", "error", err)
+		}
+	} else if !os.IsNotExist(err) {
+		logger.Log.Warn("failed to read alert states", "error", err)
+	}
+
+	restored := make(map[string]*models.Alert, len(ruleSet))
+	for _, rule := range ruleSet {
+		alert := newAlert(rule)
+		if s, ok := saved[rule.Name]; ok && s != nil {
+			alert.State, alert.Value = s.State, s.Value
+			alert.ActiveAt, alert.FiredAt, alert.ResolvedAt = s.ActiveAt, s.FiredAt, s.ResolvedAt
+			alert.LastEvaluatedAt = s.LastEvaluatedAt
+		}
+		restored[rule.Name] = alert
+	}
+	return restored
+}
@@ -0,0 +1,199 @@
+package alerting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+// withStateDir persists the alert states in a temporary directory for the duration of the test,
+// evaluating the rules against the in-memory storage.
+func withStateDir(t *testing.T) string {
+	t.Helper()
+	timeseries.SetStorageType("memory")
+	dir := t.TempDir()
+	previous := basePath
+	basePath = dir
+	t.Cleanup(func() {
+		Stop()
+		mu.Lock()
+		notifiers = nil
+		mu.Unlock()
+		basePath = previous
+	})
+	return dir
+}
+
+func TestTransition(t *testing.T) {
+	start := time.Now()
+	alert := &models.Alert{State: StateInactive}
+
+	steps := []struct {
+		name    string
+		holds   bool
+		elapsed time.Duration
+		want    string
+		changed bool
+	}{
+		{"condition starts holding", true, 0, StatePending, true},
+		{"still within for", true, time.Minute, StatePending, false},
+		{"held for the duration", true, 5 * time.Minute, StateFiring, true},
+		{"still firing", true, 6 * time.Minute, StateFiring, false},
+		{"condition stops holding", false, 7 * time.Minute, StateResolved, true},
+		{"holds again", true, 8 * time.Minute, StatePending, true},
+		{"stops before firing", false, 9 * time.Minute, StateInactive, true},
+	}
+	for _, step := range steps {
+		changed := transition(alert, step.holds, 5*time.Minute, start.Add(step.elapsed))
+		if alert.State != step.want || changed != step.changed {
+			t.Fatalf("%s: expected %s (changed %v), got %s (changed %v)", step.name, step.want, step.changed, alert.State, changed)
+		}
+	}
+
+	alert = &models.Alert{State: StateInactive}
+	if transition(alert, true, 0, start); alert.State != StateFiring || alert.FiredAt == nil {
+		t.Errorf("expected a rule without for to fire straight away, got %+v", alert)
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	valid := models.AlertRule{Name: "high-cpu", Metric: "service_cpu_load", Op: OpGreater, Threshold: 80, For: "5m"}
+	if err := ValidateRules([]models.AlertRule{valid}); err != nil {
+		t.Fatalf("expected a valid rule, got %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		rules []models.AlertRule
+	}{
+		{"missing name", []models.AlertRule{{Metric: "goroutines", Op: OpGreater}}},
+		{"missing metric", []models.AlertRule{{Name: "a", Op: OpGreater}}},
+		{"unknown operator", []models.AlertRule{{Name: "a", Metric: "goroutines", Op: "=>"}}},
+		{"invalid for", []models.AlertRule{{Name: "a", Metric: "goroutines", Op: OpGreater, For: "soon"}}},
+		{"duplicate name", []models.AlertRule{valid, valid}},
+	}
+	for _, tt := range tests {
+		if err := ValidateRules(tt.rules); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestLoadRulesFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"rules.json": `{"rules": [{"name": "high-cpu", "metric": "service_cpu_load", "op": ">", "threshold": 80, "for": "5m", "labels": {"severity": "page"}}]}`,
+		"rules.yaml": "rules:\n  - name: high-cpu\n    metric: service_cpu_load\n    op: \">\"\n    threshold: 80\n    for: 5m\n    labels:\n      severity: page\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		rules, err := LoadRulesFile(path)
+		if err != nil {
+			t.Fatalf("%s: LoadRulesFile error: %v", name, err)
+		}
+		if len(rules) != 1 || rules[0].Name != "high-cpu" || rules[0].Threshold != 80 || rules[0].For != "5m" || rules[0].Labels["severity"] != "page" {
+			t.Errorf("%s: unexpected rules %+v", name, rules)
+		}
+	}
+
+	path := filepath.Join(dir, "typo.yml")
+	if err := os.WriteFile(path, []byte("rules:\n  - name: a\n    treshold: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRulesFile(path); err == nil || !strings.Contains(err.Error(), "typo.yml") {
+		t.Errorf("expected an error naming the file for an unknown field, got %v", err)
+	}
+	if _, err := LoadRulesFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	withStateDir(t)
+	sto, err := timeseries.GetStorageInstance()
+	if err != nil {
+		t.Fatalf("GetStorageInstance error: %v", err)
+	}
+
+	now := time.Now()
+	if err := sto.InsertRows([]timeseries.Row{{
+		Metric:    "alerting_test_goroutines",
+		DataPoint: timeseries.DataPoint{Timestamp: now.Add(-time.Minute).Unix(), Value: 500},
+		Labels:    []timeseries.Label{timeseries.GetHostLabel()},
+	}}); err != nil {
+		t.Fatalf("InsertRows error: %v", err)
+	}
+
+	mu.Lock()
+	rules = []models.AlertRule{
+		{Name: "too-many-goroutines", Metric: "alerting_test_goroutines", Op: OpGreater, Threshold: 100, For: "1m"},
+		{Name: "no-data", Metric: "alerting_test_missing", Op: OpGreater, Threshold: 1},
+	}
+	alerts = restoreAlerts(rules)
+	config = models.AlertingConfig{Lookback: 10 * time.Minute}
+	mu.Unlock()
+
+	evaluate(context.Background(), now)
+	if active := GetAlerts(false); len(active) != 1 || active[0].State != StatePending || *active[0].Value != 500 {
+		t.Fatalf("expected a pending alert, got %+v", active)
+	}
+	evaluate(context.Background(), now.Add(2*time.Minute))
+	if active := GetAlerts(false); len(active) != 1 || active[0].State != StateFiring {
+		t.Fatalf("expected a firing alert, got %+v", active)
+	}
+
+	all := GetAlerts(true)
+	if len(all) != 2 || all[1].Rule != "no-data" || all[1].State != StateInactive || all[1].Error == "" {
+		t.Errorf("expected the rule without data to stay inactive with an error, got %+v", all)
+	}
+}
+
+func TestAlertStatePersistence(t *testing.T) {
+	withStateDir(t)
+	rule := models.AlertRule{Name: "high-cpu", Metric: "alerting_test_unused", Op: OpGreater, Threshold: 80}
+
+	firedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	mu.Lock()
+	alerts = restoreAlerts([]models.AlertRule{rule})
+	alerts["high-cpu"].State, alerts["high-cpu"].FiredAt = StateFiring, &firedAt
+	err := saveAlerts()
+	mu.Unlock()
+	if err != nil {
+		t.Fatalf("saveAlerts error: %v", err)
+	}
+
+	rule.Description = "CPU above 80%"
+	restored := restoreAlerts([]models.AlertRule{rule, {Name: "new", Metric: "goroutines", Op: OpGreater}})
+	if a := restored["high-cpu"]; a.State != StateFiring || !a.FiredAt.Equal(firedAt) || a.Description != "CPU above 80%" {
+		t.Errorf("expected the firing state with the current rule definition, got %+v", a)
+	}
+	if a := restored["new"]; a.State != StateInactive {
+		t.Errorf("expected a new rule to start inactive, got %+v", a)
+	}
+}
+
+func TestStartAndStop(t *testing.T) {
+	withStateDir(t)
+
+	if err := Start([]models.AlertRule{{Name: "a", Metric: "goroutines", Op: "~"}}, nil, models.AlertingConfig{}); err == nil {
+		t.Fatal("expected an error for an invalid rule")
+	}
+	rules := []models.AlertRule{{Name: "a", Metric: "goroutines", Op: OpGreater, Threshold: 1}}
+	if err := Start(rules, nil, models.AlertingConfig{Interval: time.Hour}); err != nil {
+		t.Fatalf("Start error: %v", err)
+	}
+	if got := GetRules(); len(got) != 1 || got[0].Name != "a" {
+		t.Errorf("expected the started rules, got %+v", got)
+	}
+	Stop()
+	Stop()
+}
@@ -0,0 +1,279 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// Notifier delivers alert notifications, e.g. to a webhook or by email.
+type Notifier interface {
+	// Name identifies the notifier in logs, unique among the notifiers
+	Name() string
+	// Notify delivers a notification, returning an error wrapped with Permanent when retrying cannot help
+	Notify(ctx context.Context, notification models.AlertNotification) error
+}
+
+const (
+	// DefaultRepeatInterval is the time before a still firing group is notified again unless configured otherwise.
+	DefaultRepeatInterval = 4 * time.Hour
+	// DefaultMaxRetries is how many times a failed notification is retried unless configured otherwise.
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff is the wait before the first retry unless configured otherwise.
+	DefaultRetryBackoff = time.Second
+	// DefaultNotifyTimeout is the timeout of a delivery attempt unless configured otherwise.
+	DefaultNotifyTimeout = 10 * time.Second
+)
+
+// Default templates of the notification title and message, executed with a models.AlertNotification.
+const (
+	DefaultTitleTemplate   = `[{{toUpper .Status}}{{if gt (len .Alerts) 1}}:{{len .Alerts}}{{end}}] {{with .Service}}{{.}}: {{end}}{{range $i, $a := .Alerts}}{{if $i}}, {{end}}{{$a.Rule}}{{end}}`
+	DefaultMessageTemplate = `{{range .Alerts}}{{.Rule}}: {{.Metric}} {{.Op}} {{.Threshold}}, value {{value .Value}}{{with .Description}} - {{.}}{{end}}
+{{end}}`
+)
+
+// templateFuncs are the functions available in the notification templates.
+var templateFuncs = template.FuncMap{
+	"toUpper": strings.ToUpper,
+	"value": func(v *float64) string {
+		if v == nil {
+			return "n/a"
+		}
+		return strconv.FormatFloat(*v, 'g', 6, 64)
+	},
+}
+
+// permanentError marks a notification error which retrying cannot fix, e.g. a rejected request.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so the notification is not retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// messageTemplates renders the title and message of a notification.
+type messageTemplates struct {
+	title   *template.Template
+	message *template.Template
+	err     error // Parse error, returned by every render
+}
+
+// newMessageTemplates parses the title and message templates, using the defaults when empty.
+func newMessageTemplates(title, message string) messageTemplates {
+	var t messageTemplates
+	t.title, t.err = template.New("title").Funcs(templateFuncs).Parse(common.DefaultIfEmpty(title, DefaultTitleTemplate))
+	if t.err != nil {
+		t.err = fmt.Errorf("invalid title template: %w", t.err)
+		return t
+	}
+	t.message, t.err = template.New("message").Funcs(templateFuncs).Parse(common.DefaultIfEmpty(message, DefaultMessageTemplate))
+	if t.err != nil {
+		t.err = fmt.Errorf("invalid message template: %w", t.err)
+	}
+	return t
+}
+
+// render returns the title and message of a notification.
+func (t messageTemplates) render(notification models.AlertNotification) (string, string, error) {
+	if t.err != nil {
+		return "", "", Permanent(t.err)
+	}
+	var title, message strings.Builder
+	if err := t.title.Execute(&title, notification); err != nil {
+		return "", "", Permanent(fmt.Errorf("failed to render title: %w", err))
+	}
+	if err := t.message.Execute(&message, notification); err != nil {
+		return "", "", Permanent(fmt.Errorf("failed to render message: %w", err))
+	}
+	return strings.TrimSpace(title.String()), strings.TrimSpace(message.String()), nil
+}
+
+// ValidateNotifiers checks that every notifier has a unique name and, for the built-in
+// notifiers, a valid configuration.
+func ValidateNotifiers(notifiers []Notifier) error {
+	names := make(map[string]bool)
+	for _, n := range notifiers {
+		if n == nil || n.Name() == "" {
+			return errors.New("notifiers must have a name")
+		}
+		if names[n.Name()] {
+			return fmt.Errorf("notifier %q is registered more than once", n.Name())
+		}
+		names[n.Name()] = true
+
+		if v, ok := n.(interface{ validate() error }); ok {
+			if err := v.validate(); err != nil {
+				return fmt.Errorf("notifier %q: %w", n.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// notifiedGroup is the last notification of a firing group.
+type notifiedGroup struct {
+	rules string // Rule names of the notified alerts
+	at    time.Time
+}
+
+// groupOf returns the key and labels of the group an alert is notified in.
+func groupOf(alert *models.Alert, groupBy []string) (string, map[string]string) {
+	labels := make(map[string]string)
+	if len(groupBy) == 0 {
+		labels["rule"] = alert.Rule
+	}
+	for _, name := range groupBy {
+		if name == "rule" {
+			labels[name] = alert.Rule
+		} else {
+			labels[name] = alert.Labels[name]
+		}
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ","), labels
+}
+
+// groupNotifications returns the notifications of an evaluation: a firing notification per
+// group whose firing alerts changed or were last notified a repeat interval ago, and a
+// resolved notification per group of the alerts resolved by the evaluation. Callers hold mu.
+func groupNotifications(resolved []*models.Alert, now time.Time) []models.AlertNotification {
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	groups := make(map[string]*models.AlertNotification)
+	add := func(status string, alert *models.Alert) {
+		key, labels := groupOf(alert, config.GroupBy)
+		key = status + "/" + key
+		if groups[key] == nil {
+			groups[key] = &models.AlertNotification{
+				Service:     common.GetServiceInfo().ServiceName,
+				Status:      status,
+				GroupLabels: labels,
+				Time:        now,
+			}
+		}
+		groups[key].Alerts = append(groups[key].Alerts, *alert)
+	}
+
+	for _, rule := range rules {
+		if alert := alerts[rule.Name]; alert.State == StateFiring {
+			add(StateFiring, alert)
+		}
+	}
+	for _, alert := range resolved {
+		add(StateResolved, alert)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// A group which stopped firing is notified straight away when it fires again
+	for key := range notified {
+		if groups[key] == nil {
+			delete(notified, key)
+		}
+	}
+
+	var notifications []models.AlertNotification
+	for _, key := range keys {
+		group := groups[key]
+		sort.Slice(group.Alerts, func(i, j int) bool { return group.Alerts[i].Rule < group.Alerts[j].Rule })
+
+		if group.Status == StateFiring {
+			names := make([]string, len(group.Alerts))
+			for i, alert := range group.Alerts {
+				names[i] = alert.Rule
+			}
+			fired := notifiedGroup{rules: strings.Join(names, ","), at: now}
+			// The same firing alerts are only notified again once the repeat interval passed
+			if last, ok := notified[key]; ok && last.rules == fired.rules && now.Sub(last.at) < config.RepeatInterval {
+				continue
+			}
+			notified[key] = fired
+		}
+		notifications = append(notifications, *group)
+	}
+	return notifications
+}
+
+// restoreNotified returns the firing groups of the restored alerts as notified when their
+// newest alert fired, so a restart doesn't notify them again before the repeat interval.
+// Callers hold mu.
+func restoreNotified() map[string]notifiedGroup {
+	names := make(map[string][]string)
+	firedAt := make(map[string]time.Time)
+	for _, rule := range rules {
+		alert := alerts[rule.Name]
+		if alert.State != StateFiring || alert.FiredAt == nil {
+			continue
+		}
+		key, _ := groupOf(alert, config.GroupBy)
+		key = StateFiring + "/" + key
+		names[key] = append(names[key], alert.Rule)
+		if alert.FiredAt.After(firedAt[key]) {
+			firedAt[key] = *alert.FiredAt
+		}
+	}
+
+	restored := make(map[string]notifiedGroup, len(names))
+	for key, group := range names {
+		sort.Strings(group)
+		restored[key] = notifiedGroup{rules: strings.Join(group, ","), at: firedAt[key]}
+	}
+	return restored
+}
+
+// deliver sends a notification, retrying failed attempts with an exponential backoff until
+// the retries are exhausted, the error is permanent or ctx is cancelled.
+func deliver(ctx context.Context, n Notifier, notification models.AlertNotification, cfg models.AlertingConfig) {
+	backoff := cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, cfg.NotifyTimeout)
+		err := n.Notify(attemptCtx, notification)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt > cfg.MaxRetries || ctx.Err() != nil {
+			logger.Log.Error("failed to deliver alert notification", "notifier", n.Name(), "status", notification.Status, "attempts", attempt, "error", err)
+			return
+		}
+		logger.Log.Warn("alert notification failed, retrying", "notifier", n.Name(), "attempt", attempt, "backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			logger.Log.Error("alert notification cancelled", "notifier", n.Name(), "status", notification.Status, "error", err)
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
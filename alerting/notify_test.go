@@ -0,0 +1,261 @@
+package alerting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+func testNotification() models.AlertNotification {
+	value := 93.5
+	return models.AlertNotification{
+		Service:     "orders",
+		Status:      StateFiring,
+		GroupLabels: map[string]string{"rule": "high-cpu"},
+		Alerts: []models.Alert{{
+			Rule: "high-cpu", State: StateFiring, Metric: "service_cpu_load", Op: OpGreater, Threshold: 80,
+			Value: &value, Description: "CPU above 80%",
+		}},
+		Time: time.Now(),
+	}
+}
+
+func testConfig() models.AlertingConfig {
+	return models.AlertingConfig{MaxRetries: 2, RetryBackoff: time.Millisecond, NotifyTimeout: time.Second, RepeatInterval: time.Hour}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var calls atomic.Int32
+	var received models.AlertNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first attempt fails to exercise the retry
+		if calls.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("expected the configured header, got %q", r.Header.Get("Authorization"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(WebhookConfig{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}})
+	deliver(context.Background(), n, testNotification(), testConfig())
+
+	if calls.Load() != 2 {
+		t.Fatalf("expected a retry after the failure, got %d calls", calls.Load())
+	}
+	if received.Title != "[FIRING] orders: high-cpu" || received.Message != "high-cpu: service_cpu_load > 80, value 93.5 - CPU above 80%" {
+		t.Errorf("unexpected rendered notification: %q / %q", received.Title, received.Message)
+	}
+	if len(received.Alerts) != 1 || *received.Alerts[0].Value != 93.5 {
+		t.Errorf("expected the alerts in the payload, got %+v", received.Alerts)
+	}
+}
+
+func TestWebhookNotifierPermanentError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(WebhookConfig{URL: server.URL})
+	var permanent *permanentError
+	if err := n.Notify(context.Background(), testNotification()); !errors.As(err, &permanent) {
+		t.Errorf("expected a permanent error for a 400, got %v", err)
+	}
+	deliver(context.Background(), n, testNotification(), testConfig())
+	if calls.Load() != 2 {
+		t.Errorf("expected no retry after a permanent error, got %d calls", calls.Load())
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	var received slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	n := NewSlackNotifier(SlackConfig{URL: server.URL, Channel: "#alerts", Message: "{{range .Alerts}}{{.Rule}} is {{.State}}{{end}}"})
+	if err := n.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+	if received.Text != "*[FIRING] orders: high-cpu*\nhigh-cpu is firing" || received.Channel != "#alerts" {
+		t.Errorf("unexpected Slack message: %+v", received)
+	}
+}
+
+// fakeSMTPServer accepts one message per connection and records its envelope and data.
+type fakeSMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	rcpt     []string
+	data     string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, port, _ := net.SplitHostPort(server.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	n := NewSMTPNotifier(SMTPConfig{
+		Host: host, Port: portNumber, From: "monigo@example.com", To: []string{"oncall@example.com", "team@example.com"},
+		Subject: "{{.Service}} alert",
+	})
+	if err := ValidateNotifiers([]Notifier{n}); err != nil {
+		t.Fatalf("ValidateNotifiers error: %v", err)
+	}
+	if err := n.Notify(context.Background(), testNotification()); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.rcpt) != 2 || server.rcpt[0] != "oncall@example.com" {
+		t.Errorf("unexpected recipients: %v", server.rcpt)
+	}
+	for _, want := range []string{"Subject: orders alert\r\n", "To: oncall@example.com, team@example.com\r\n", "\r\n\r\nhigh-cpu: service_cpu_load > 80, value 93.5"} {
+		if !strings.Contains(server.data, want) {
+			t.Errorf("expected %q in the message, got %q", want, server.data)
+		}
+	}
+}
+
+func TestValidateNotifiers(t *testing.T) {
+	valid := NewWebhookNotifier(WebhookConfig{URL: "https://example.com/hook"})
+	if err := ValidateNotifiers([]Notifier{valid, NewSlackNotifier(SlackConfig{URL: "https://hooks.slack.com/x"})}); err != nil {
+		t.Fatalf("expected valid notifiers, got %v", err)
+	}
+
+	tests := map[string][]Notifier{
+		"duplicate name":   {valid, valid},
+		"missing URL":      {NewWebhookNotifier(WebhookConfig{})},
+		"invalid template": {NewSlackNotifier(SlackConfig{URL: "https://example.com", Title: "{{.Status"})},
+		"no recipients":    {NewSMTPNotifier(SMTPConfig{Host: "smtp.example.com", From: "monigo@example.com"})},
+		"invalid sender":   {NewSMTPNotifier(SMTPConfig{Host: "smtp.example.com", From: "monigo", To: []string{"a@example.com"}})},
+	}
+	for name, notifiers := range tests {
+		if err := ValidateNotifiers(notifiers); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestGroupNotifications(t *testing.T) {
+	withStateDir(t)
+
+	mu.Lock()
+	defer mu.Unlock()
+	rules = []models.AlertRule{
+		{Name: "high-cpu", Labels: map[string]string{"team": "core"}},
+		{Name: "high-memory", Labels: map[string]string{"team": "core"}},
+		{Name: "slow-queries", Labels: map[string]string{"team": "data"}},
+	}
+	alerts = restoreAlerts(rules)
+	notifiers = []Notifier{NewWebhookNotifier(WebhookConfig{URL: "https://example.com"})}
+	notified = make(map[string]notifiedGroup)
+	config = models.AlertingConfig{GroupBy: []string{"team"}, RepeatInterval: time.Hour}
+
+	now := time.Now()
+	for _, name := range []string{"high-cpu", "high-memory", "slow-queries"} {
+		alerts[name].State = StateFiring
+	}
+	got := groupNotifications(nil, now)
+	if len(got) != 2 || len(got[0].Alerts) != 2 || got[0].GroupLabels["team"] != "core" || got[1].GroupLabels["team"] != "data" {
+		t.Fatalf("expected a notification per team, got %+v", got)
+	}
+
+	if got := groupNotifications(nil, now.Add(time.Minute)); len(got) != 0 {
+		t.Errorf("expected the unchanged groups to be deduplicated, got %+v", got)
+	}
+	if got := groupNotifications(nil, now.Add(2*time.Hour)); len(got) != 2 {
+		t.Errorf("expected the groups to be notified again after the repeat interval, got %d", len(got))
+	}
+
+	alerts["high-memory"].State = StateResolved
+	got = groupNotifications([]*models.Alert{alerts["high-memory"]}, now.Add(2*time.Hour+time.Minute))
+	if len(got) != 2 || got[0].Status != StateFiring || len(got[0].Alerts) != 1 || got[1].Status != StateResolved {
+		t.Errorf("expected the changed firing group and the resolved alert, got %+v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+	"go.yaml.in/yaml/v2"
+)
+
+// Comparison operators of the alert rules.
+const (
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+)
+
+// rulesFile is the layout of an alert rules file, in JSON or YAML.
+type rulesFile struct {
+	Rules []models.AlertRule `json:"rules" yaml:"rules"`
+}
+
+// LoadRulesFile reads alert rules from a YAML file (.yaml or .yml) or a JSON file, both
+// listing the rules under "rules".
+func LoadRulesFile(path string) ([]models.AlertRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+
+	var file rulesFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, &file)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode alert rules in %s: %w", path, err)
+	}
+	return file.Rules, nil
+}
+
+// ValidateRules checks that every rule has a unique name, a metric, a known operator and a
+// valid for duration.
+func ValidateRules(rules []models.AlertRule) error {
+	names := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("alert rules must have a name")
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("alert rule %q is defined more than once", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.Metric == "" {
+			return fmt.Errorf("alert rule %q has no metric", rule.Name)
+		}
+		if _, err := compare(rule.Op, 0, 0); err != nil {
+			return fmt.Errorf("alert rule %q: %w", rule.Name, err)
+		}
+		if _, err := ruleFor(rule); err != nil {
+			return fmt.Errorf("alert rule %q: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// ruleFor returns how long a rule's condition must hold before it fires.
+func ruleFor(rule models.AlertRule) (time.Duration, error) {
+	if rule.For == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(rule.For)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("for must be a duration such as '5m', got %q", rule.For)
+	}
+	return d, nil
+}
+
+// compare reports whether value compares to threshold with the operator.
+func compare(op string, value, threshold float64) (bool, error) {
+	switch op {
+	case OpGreater:
+		return value > threshold, nil
+	case OpGreaterEqual:
+		return value >= threshold, nil
+	case OpLess:
+		return value < threshold, nil
+	case OpLessEqual:
+		return value <= threshold, nil
+	case OpEqual:
+		return value == threshold, nil
+	case OpNotEqual:
+		return value != threshold, nil
+	}
+	return false, fmt.Errorf("unknown operator %q, expected one of >, >=, <, <=, == or !=", op)
+}
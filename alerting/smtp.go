@@ -0,0 +1,155 @@
+package alerting
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// SMTPConfig configures a notifier sending each notification by email. STARTTLS is used when
+// the server offers it, and PLAIN authentication when a username is set.
+type SMTPConfig struct {
+	Name     string   // Default is "email"
+	Host     string   // SMTP server host
+	Port     int      // Default is 587
+	Username string   // Empty disables authentication
+	Password string   // Password of the username
+	From     string   // Sender address
+	To       []string // Recipient addresses
+	Subject  string   // Subject template, default is DefaultTitleTemplate
+	Body     string   // Body template, default is DefaultMessageTemplate
+}
+
+type smtpNotifier struct {
+	config    SMTPConfig
+	templates messageTemplates
+}
+
+// NewSMTPNotifier returns a notifier sending each notification by email.
+func NewSMTPNotifier(config SMTPConfig) Notifier {
+	config.Name = common.DefaultIfEmpty(config.Name, "email")
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &smtpNotifier{config: config, templates: newMessageTemplates(config.Subject, config.Body)}
+}
+
+func (n *smtpNotifier) Name() string { return n.config.Name }
+
+func (n *smtpNotifier) validate() error {
+	if n.config.Host == "" {
+		return errors.New("an SMTP host is required")
+	}
+	if _, err := mail.ParseAddress(n.config.From); err != nil {
+		return fmt.Errorf("invalid sender address %q", n.config.From)
+	}
+	if len(n.config.To) == 0 {
+		return errors.New("at least one recipient is required")
+	}
+	for _, to := range n.config.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid recipient address %q", to)
+		}
+	}
+	return n.templates.err
+}
+
+func (n *smtpNotifier) Notify(ctx context.Context, notification models.AlertNotification) error {
+	subject, body, err := n.templates.render(notification)
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.config.Host}); err != nil {
+			return smtpError(err)
+		}
+	}
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return smtpError(err)
+		}
+	}
+	if err := client.Mail(n.config.From); err != nil {
+		return smtpError(err)
+	}
+	for _, to := range n.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return smtpError(err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(n.message(subject, body, notification.Time)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err)
+	}
+	return client.Quit()
+}
+
+// message returns the email with its headers, using CRLF line endings.
+func (n *smtpNotifier) message(subject, body string, date time.Time) []byte {
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	var msg strings.Builder
+	msg.WriteString("From: " + n.config.From + "\r\n")
+	msg.WriteString("To: " + strings.Join(n.config.To, ", ") + "\r\n")
+	msg.WriteString("Subject: " + mimeHeader(subject) + "\r\n")
+	msg.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return []byte(msg.String())
+}
+
+// mimeHeader encodes a header value containing non-ASCII characters.
+func mimeHeader(value string) string {
+	for _, r := range value {
+		if r > 127 {
+			return mime.QEncoding.Encode("utf-8", value)
+		}
+	}
+	return value
+}
+
+// smtpError marks the errors the server rejected permanently (5xx replies) as permanent.
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return Permanent(err)
+	}
+	return err
+}
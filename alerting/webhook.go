@@ -0,0 +1,153 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// WebhookConfig configures a notifier posting each notification as JSON, a
+// models.AlertNotification with the rendered title and message.
+type WebhookConfig struct {
+	Name    string            // Default is "webhook"
+	URL     string            // Endpoint the notifications are posted to
+	Headers map[string]string // Added to every request, e.g. an Authorization header
+	Title   string            // Title template, default is DefaultTitleTemplate
+	Message string            // Message template, default is DefaultMessageTemplate
+	Client  *http.Client      // Default is http.DefaultClient
+}
+
+// SlackConfig configures a notifier posting to a Slack or Mattermost incoming webhook.
+type SlackConfig struct {
+	Name      string // Default is "slack"
+	URL       string // Incoming webhook URL
+	Channel   string // Overrides the webhook's channel, e.g. "#alerts"
+	Username  string // Overrides the webhook's user name
+	IconEmoji string // Overrides the webhook's icon, e.g. ":rotating_light:"
+	Title     string // Title template, default is DefaultTitleTemplate
+	Message   string // Message template, default is DefaultMessageTemplate
+	Client    *http.Client
+}
+
+type webhookNotifier struct {
+	config    WebhookConfig
+	templates messageTemplates
+}
+
+// NewWebhookNotifier returns a notifier posting each notification as JSON to a webhook.
+func NewWebhookNotifier(config WebhookConfig) Notifier {
+	config.Name = common.DefaultIfEmpty(config.Name, "webhook")
+	return &webhookNotifier{config: config, templates: newMessageTemplates(config.Title, config.Message)}
+}
+
+func (n *webhookNotifier) Name() string { return n.config.Name }
+
+func (n *webhookNotifier) validate() error {
+	if err := validateWebhookURL(n.config.URL); err != nil {
+		return err
+	}
+	return n.templates.err
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, notification models.AlertNotification) error {
+	var err error
+	if notification.Title, notification.Message, err = n.templates.render(notification); err != nil {
+		return err
+	}
+	return postJSON(ctx, n.config.Client, n.config.URL, n.config.Headers, notification)
+}
+
+type slackNotifier struct {
+	config    SlackConfig
+	templates messageTemplates
+}
+
+// slackMessage is the payload of a Slack or Mattermost incoming webhook.
+type slackMessage struct {
+	Text      string `json:"text"`
+	Channel   string `json:"channel,omitempty"`
+	Username  string `json:"username,omitempty"`
+	IconEmoji string `json:"icon_emoji,omitempty"`
+}
+
+// NewSlackNotifier returns a notifier posting each notification to a Slack or Mattermost
+// incoming webhook, with the title in bold above the message.
+func NewSlackNotifier(config SlackConfig) Notifier {
+	config.Name = common.DefaultIfEmpty(config.Name, "slack")
+	return &slackNotifier{config: config, templates: newMessageTemplates(config.Title, config.Message)}
+}
+
+func (n *slackNotifier) Name() string { return n.config.Name }
+
+func (n *slackNotifier) validate() error {
+	if err := validateWebhookURL(n.config.URL); err != nil {
+		return err
+	}
+	return n.templates.err
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, notification models.AlertNotification) error {
+	title, message, err := n.templates.render(notification)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, n.config.Client, n.config.URL, nil, slackMessage{
+		Text:      fmt.Sprintf("*%s*\n%s", title, message),
+		Channel:   n.config.Channel,
+		Username:  n.config.Username,
+		IconEmoji: n.config.IconEmoji,
+	})
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http or https URL.
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL must be an http or https URL, got %q", raw)
+	}
+	return nil
+}
+
+// postJSON posts a JSON payload, failing on a non-2xx response. Client errors other than
+// timeouts and rate limiting are permanent.
+func postJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Permanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("webhook responded %s: %s", resp.Status, bytes.TrimSpace(detail))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
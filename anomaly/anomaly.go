@@ -0,0 +1,287 @@
+// Package anomaly learns baselines of stored metrics from their history and records the values
+// far from them as anomaly events.
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+const (
+	// DefaultInterval is the time between two detections unless configured otherwise.
+	DefaultInterval = 5 * time.Minute
+	// DefaultWindow is the history the baselines are learned from unless configured otherwise.
+	DefaultWindow = 7 * 24 * time.Hour
+	// DefaultAlpha is the EWMA smoothing factor unless configured otherwise.
+	DefaultAlpha = 0.1
+	// DefaultThreshold is the absolute z-score from which a value is anomalous unless configured otherwise.
+	DefaultThreshold = 3.0
+	// DefaultMinSamples is the number of samples a baseline needs unless configured otherwise.
+	DefaultMinSamples = 12
+)
+
+// minRelativeStdDev is the smallest standard deviation relative to the mean, so a metric
+// which never moved is not anomalous on its first small change.
+const minRelativeStdDev = 0.05
+
+// DefaultMetrics are the core metrics watched when anomaly detection is enabled.
+var DefaultMetrics = []models.AnomalyMetric{
+	{Metric: "service_cpu_load"},
+	{Metric: "service_memory_load"},
+	{Metric: "goroutines"},
+	{Metric: "gc_pause_duration"},
+}
+
+// basePath is the directory the anomaly events are recorded in.
+var basePath = common.GetBasePath()
+
+var (
+	mu          sync.Mutex
+	config      models.AnomalyConfig
+	metrics     []models.AnomalyMetric
+	statuses    map[string]*models.AnomalyStatus // Latest status of each metric by name
+	lastChecked map[string]int64                 // Timestamp of the latest scored point of each metric by name
+	prunedAt    time.Time
+	stopDet     context.CancelFunc
+	detDone     chan struct{}
+)
+
+// pruneInterval is how often expired anomaly events are removed while detecting.
+const pruneInterval = time.Hour
+
+// ValidateMetrics checks that every metric has a stored metric and a unique name.
+func ValidateMetrics(watched []models.AnomalyMetric) error {
+	names := make(map[string]bool)
+	for _, m := range watched {
+		if m.Metric == "" {
+			return errors.New("anomaly metrics must have a metric")
+		}
+		name := common.DefaultIfEmpty(m.Name, m.Metric)
+		if names[name] {
+			return fmt.Errorf("anomaly metric %q is watched more than once, set distinct names", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// Start scores the newest value of the metrics against their baselines every interval until
+// Stop is called. Calling it again restarts the detection with the new metrics.
+func Start(watched []models.AnomalyMetric, cfg models.AnomalyConfig) error {
+	if err := ValidateMetrics(watched); err != nil {
+		return err
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.Alpha <= 0 || cfg.Alpha > 1 {
+		cfg.Alpha = DefaultAlpha
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = DefaultMinSamples
+	}
+
+	Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	config = cfg
+	metrics = make([]models.AnomalyMetric, len(watched))
+	for i, m := range watched {
+		m.Name = common.DefaultIfEmpty(m.Name, m.Metric)
+		metrics[i] = m
+	}
+	statuses = make(map[string]*models.AnomalyStatus)
+	lastChecked = make(map[string]int64)
+	pruneExpiredEvents(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	stopDet, detDone = cancel, done
+
+	go func() {
+		defer close(done)
+		detect(time.Now())
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				detect(now)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the detection. Safe to call multiple times.
+func Stop() {
+	mu.Lock()
+	cancel, done := stopDet, detDone
+	stopDet, detDone = nil, nil
+	mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// GetStatuses returns the latest status of every watched metric, anomalous ones first.
+func GetStatuses() []models.AnomalyStatus {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := []models.AnomalyStatus{}
+	for _, m := range metrics {
+		if status, ok := statuses[m.Name]; ok {
+			result = append(result, *status)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Anomalous && !result[j].Anomalous })
+	return result
+}
+
+// ewma is an exponentially weighted moving mean and variance.
+type ewma struct {
+	mean     float64
+	variance float64
+	n        int
+}
+
+func (e *ewma) add(x, alpha float64) {
+	if e.n == 0 {
+		e.mean = x
+	} else {
+		diff := x - e.mean
+		incr := alpha * diff
+		e.mean += incr
+		e.variance = (1 - alpha) * (e.variance + diff*incr)
+	}
+	e.n++
+}
+
+// stdDev returns the standard deviation, at least minRelativeStdDev of the mean.
+func (e *ewma) stdDev() float64 {
+	return max(math.Sqrt(e.variance), minRelativeStdDev*math.Abs(e.mean), 1e-9)
+}
+
+// baseline learns the baseline of the hour of day from the points taken in that hour, oldest
+// first, falling back to the baseline of every point while the hour has too few samples.
+func baseline(history []timeseries.DataPoint, hour int, alpha float64, minSamples int) (ewma, bool) {
+	var overall, seasonal ewma
+	for _, p := range history {
+		overall.add(p.Value, alpha)
+		if time.Unix(p.Timestamp, 0).Hour() == hour {
+			seasonal.add(p.Value, alpha)
+		}
+	}
+	if seasonal.n >= minSamples {
+		return seasonal, true
+	}
+	return overall, false
+}
+
+// detect scores the newest point of every metric not scored yet, recording the anomalous ones.
+func detect(now time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, m := range metrics {
+		status := &models.AnomalyStatus{Metric: m.Name}
+		if previous, ok := statuses[m.Name]; ok {
+			*status = *previous
+		}
+		statuses[m.Name] = status
+
+		points, err := history(m, now)
+		if err != nil {
+			status.Error = err.Error()
+			continue
+		}
+		status.Error = ""
+		latest := points[len(points)-1]
+		if latest.Timestamp <= lastChecked[m.Name] {
+			continue
+		}
+		lastChecked[m.Name] = latest.Timestamp
+
+		at := time.Unix(latest.Timestamp, 0)
+		base, seasonal := baseline(points[:len(points)-1], at.Hour(), config.Alpha, config.MinSamples)
+		*status = models.AnomalyStatus{
+			Metric:   m.Name,
+			Value:    latest.Value,
+			Expected: base.mean,
+			StdDev:   base.stdDev(),
+			Samples:  base.n,
+			Seasonal: seasonal,
+			Learning: base.n < config.MinSamples,
+			Time:     at,
+		}
+		if status.Learning {
+			continue
+		}
+
+		status.Score = (latest.Value - base.mean) / status.StdDev
+		status.Anomalous = math.Abs(status.Score) >= config.Threshold
+		if !status.Anomalous {
+			continue
+		}
+
+		event := models.AnomalyEvent{
+			Time:     at,
+			Metric:   m.Name,
+			Value:    status.Value,
+			Expected: status.Expected,
+			StdDev:   status.StdDev,
+			Score:    status.Score,
+			Seasonal: seasonal,
+		}
+		logger.Log.Warn("anomaly detected", "metric", event.Metric, "value", event.Value, "expected", event.Expected, "score", event.Score)
+		if err := appendEvent(&event); err != nil {
+			logger.Log.Error("failed to record anomaly event", "error", err)
+		}
+	}
+
+	if now.Sub(prunedAt) >= pruneInterval {
+		pruneExpiredEvents(now)
+	}
+}
+
+// history returns the points of a metric within the window, oldest first.
+func history(m models.AnomalyMetric, now time.Time) ([]timeseries.DataPoint, error) {
+	labels := []timeseries.Label{timeseries.GetHostLabel()}
+	for name, value := range m.Match {
+		labels = append(labels, timeseries.Label{Name: name, Value: value})
+	}
+
+	points, err := timeseries.GetDataPoints(m.Metric, labels, now.Add(-config.Window).Unix(), now.Unix()+1)
+	if err != nil && !errors.Is(err, timeseries.ErrNoDataPoints) {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no data for %s", m.Metric)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+	return points, nil
+}
@@ -0,0 +1,188 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+// withEventsDir records the anomaly events in a temporary directory for the duration of the
+// test, detecting against the in-memory storage.
+func withEventsDir(t *testing.T) {
+	t.Helper()
+	timeseries.SetStorageType("memory")
+	previous := basePath
+	basePath = t.TempDir()
+	t.Cleanup(func() {
+		Stop()
+		basePath = previous
+	})
+}
+
+// configure watches the metrics without starting the detection loop.
+func configure(watched []models.AnomalyMetric, cfg models.AnomalyConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	metrics, config = watched, cfg
+	statuses = make(map[string]*models.AnomalyStatus)
+	lastChecked = make(map[string]int64)
+	prunedAt = time.Now()
+}
+
+func insert(t *testing.T, metric string, at time.Time, value float64) {
+	t.Helper()
+	sto, err := timeseries.GetStorageInstance()
+	if err != nil {
+		t.Fatalf("GetStorageInstance error: %v", err)
+	}
+	if err := sto.InsertRows([]timeseries.Row{{
+		Metric:    metric,
+		DataPoint: timeseries.DataPoint{Timestamp: at.Unix(), Value: value},
+		Labels:    []timeseries.Label{timeseries.GetHostLabel()},
+	}}); err != nil {
+		t.Fatalf("InsertRows error: %v", err)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	var history []timeseries.DataPoint
+	for d := 0; d < 14; d++ {
+		for h := 0; h < 24; h++ {
+			value := 100.0
+			if h == 3 {
+				value = 10 // Nightly batch window
+			}
+			history = append(history, timeseries.DataPoint{Timestamp: day.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour).Unix(), Value: value})
+		}
+	}
+
+	base, seasonal := baseline(history, 3, DefaultAlpha, DefaultMinSamples)
+	if !seasonal || base.n != 14 || math.Abs(base.mean-10) > 1e-9 {
+		t.Errorf("expected the hour of day's baseline, got %+v (seasonal %v)", base, seasonal)
+	}
+
+	base, seasonal = baseline(history, 3, DefaultAlpha, 20)
+	if seasonal || base.n != len(history) || base.mean < 10 || base.mean > 100 {
+		t.Errorf("expected the overall baseline while the hour has too few samples, got %+v (seasonal %v)", base, seasonal)
+	}
+
+	flat := ewma{}
+	for i := 0; i < 10; i++ {
+		flat.add(50, DefaultAlpha)
+	}
+	if flat.stdDev() != 50*minRelativeStdDev {
+		t.Errorf("expected the standard deviation floor for a flat series, got %v", flat.stdDev())
+	}
+}
+
+func TestDetect(t *testing.T) {
+	withEventsDir(t)
+
+	now := time.Now()
+	metric := "anomaly_test_goroutines"
+	for i := 30; i > 0; i-- {
+		insert(t, metric, now.Add(-time.Duration(i)*5*time.Minute), 100+float64(i%2*4-2))
+	}
+	configure([]models.AnomalyMetric{{Name: metric, Metric: metric}, {Name: "missing", Metric: "anomaly_test_missing"}},
+		models.AnomalyConfig{Window: 24 * time.Hour, Alpha: DefaultAlpha, Threshold: DefaultThreshold, MinSamples: DefaultMinSamples})
+
+	detect(now)
+	got := GetStatuses()
+	if len(got) != 2 || got[0].Anomalous || got[0].Learning || got[1].Error == "" {
+		t.Fatalf("expected a normal value and a metric without data, got %+v", got)
+	}
+
+	insert(t, metric, now.Add(time.Minute), 400)
+	detect(now.Add(2 * time.Minute))
+	got = GetStatuses()
+	if !got[0].Anomalous || got[0].Metric != metric || got[0].Score < DefaultThreshold || got[0].Value != 400 {
+		t.Fatalf("expected the spike to be anomalous, got %+v", got[0])
+	}
+
+	// The same point is not scored twice
+	detect(now.Add(3 * time.Minute))
+	events, err := GetAnomalies(models.AnomalyEventQuery{Metric: metric})
+	if err != nil {
+		t.Fatalf("GetAnomalies error: %v", err)
+	}
+	if len(events) != 1 || events[0].Value != 400 || events[0].Expected > 110 {
+		t.Errorf("expected one anomaly event for the spike, got %+v", events)
+	}
+	if events, _ := GetAnomalies(models.AnomalyEventQuery{Metric: "missing"}); len(events) != 0 {
+		t.Errorf("expected no events for another metric, got %+v", events)
+	}
+}
+
+func TestDetectLearning(t *testing.T) {
+	withEventsDir(t)
+
+	now := time.Now()
+	metric := "anomaly_test_learning"
+	for i := 3; i > 0; i-- {
+		insert(t, metric, now.Add(-time.Duration(i)*time.Minute), 1)
+	}
+	insert(t, metric, now, 1000)
+	configure([]models.AnomalyMetric{{Name: metric, Metric: metric}}, models.AnomalyConfig{Window: time.Hour, Alpha: DefaultAlpha, Threshold: DefaultThreshold, MinSamples: DefaultMinSamples})
+
+	detect(now)
+	if got := GetStatuses(); len(got) != 1 || !got[0].Learning || got[0].Anomalous || got[0].Samples != 3 {
+		t.Errorf("expected the metric to be learning, got %+v", got)
+	}
+}
+
+func TestEventRetention(t *testing.T) {
+	withEventsDir(t)
+	configure(nil, models.AnomalyConfig{Retention: time.Hour})
+
+	now := time.Now()
+	mu.Lock()
+	for _, at := range []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Minute)} {
+		if err := appendEvent(&models.AnomalyEvent{Time: at, Metric: "goroutines", Value: 1}); err != nil {
+			t.Fatalf("appendEvent error: %v", err)
+		}
+	}
+	pruneExpiredEvents(now)
+	mu.Unlock()
+
+	events, err := GetAnomalies(models.AnomalyEventQuery{})
+	if err != nil {
+		t.Fatalf("GetAnomalies error: %v", err)
+	}
+	if len(events) != 1 || !events[0].Time.After(now.Add(-time.Hour)) {
+		t.Errorf("expected only the recent event to be kept, got %+v", events)
+	}
+}
+
+func TestValidateMetrics(t *testing.T) {
+	if err := ValidateMetrics(append(append([]models.AnomalyMetric{}, DefaultMetrics...), models.AnomalyMetric{Name: "latency_p99", Metric: "http_latency", Match: map[string]string{"quantile": "0.99"}})); err != nil {
+		t.Fatalf("expected valid metrics, got %v", err)
+	}
+	for name, watched := range map[string][]models.AnomalyMetric{
+		"missing metric": {{Name: "a"}},
+		"duplicate name": {{Metric: "goroutines"}, {Metric: "goroutines"}},
+	} {
+		if err := ValidateMetrics(watched); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestStartAndStop(t *testing.T) {
+	withEventsDir(t)
+
+	if err := Start([]models.AnomalyMetric{{Metric: "anomaly_test_start"}}, models.AnomalyConfig{Interval: time.Hour}); err != nil {
+		t.Fatalf("Start error: %v", err)
+	}
+	Stop()
+	Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if config.Threshold != DefaultThreshold || config.Window != DefaultWindow || metrics[0].Name != "anomaly_test_start" {
+		t.Errorf("expected the defaults to be applied, got %+v, %+v", config, metrics)
+	}
+}
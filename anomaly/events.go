@@ -0,0 +1,133 @@
+package anomaly
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// eventsPath returns the file anomaly events are appended to, one JSON event per line.
+func eventsPath() string {
+	return filepath.Join(basePath, "anomalies.log")
+}
+
+// appendEvent appends an event to the anomaly history. Callers hold mu.
+func appendEvent(event *models.AnomalyEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(eventsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// readEvents returns the recorded anomaly events, oldest first. Callers hold mu.
+func readEvents() ([]models.AnomalyEvent, error) {
+	events := []models.AnomalyEvent{}
+
+	f, err := os.Open(eventsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return events, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event models.AnomalyEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// pruneExpiredEvents removes the events older than the retention. Callers hold mu.
+func pruneExpiredEvents(now time.Time) {
+	prunedAt = now
+	if config.Retention <= 0 {
+		return
+	}
+	if err := pruneEvents(now.Add(-config.Retention)); err != nil {
+		logger.Log.Warn("failed to remove expired anomaly events", "error", err)
+	}
+}
+
+// pruneEvents removes the events recorded before the given time, rewriting the history
+// through a temporary file. Callers hold mu.
+func pruneEvents(before time.Time) error {
+	events, err := readEvents()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	kept := 0
+	for _, event := range events {
+		if event.Time.Before(before) {
+			continue
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(data, '\n'))
+		kept++
+	}
+	if kept == len(events) {
+		return nil
+	}
+
+	path := eventsPath()
+	if err := os.WriteFile(path+".tmp", buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	return nil
+}
+
+// GetAnomalies returns the anomaly events matching the query, oldest first.
+func GetAnomalies(query models.AnomalyEventQuery) ([]models.AnomalyEvent, error) {
+	mu.Lock()
+	events, err := readEvents()
+	mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []models.AnomalyEvent{}
+	for _, event := range events {
+		if query.Metric != "" && event.Metric != query.Metric {
+			continue
+		}
+		if (!query.From.IsZero() && event.Time.Before(query.From)) || (!query.To.IsZero() && event.Time.After(query.To)) {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered, nil
+}
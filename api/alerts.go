@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/iyashjayesh/monigo/alerting"
+)
+
+// GetAlerts lists the pending and firing alerts, firing ones first. With state=all it lists
+// the alerts of every rule, including the inactive and resolved ones.
+// GET /monigo/api/v1/alerts?state=all
+func GetAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	if state != "" && state != "all" && state != "active" {
+		http.Error(w, "Invalid state, expected active or all", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(alerting.GetAlerts(state == "all")); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// GetAlertRules lists the alert rules being evaluated.
+// GET /monigo/api/v1/alerts/rules
+func GetAlertRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(alerting.GetRules()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
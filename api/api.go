@@ -2,8 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +16,11 @@ import (
 	"github.com/iyashjayesh/monigo/timeseries"
 )
 
+const (
+	defaultGoroutinePageSize = 100
+	maxGoroutinePageSize     = 1000
+)
+
 var (
 	fieldDescription = map[string]string{}
 	fieldDesOnce     = sync.Once{}
@@ -36,14 +44,52 @@ func GetServiceInfoAPI(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// GetServiceStatistics returns the service metrics detailed information
+// GetBuildInfo returns the build of the running binary: module versions, VCS revision,
+// build settings and the runtime environment
+// GET /monigo/api/v1/build-info
+func GetBuildInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(core.GetBuildInfo()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// GetServiceStatistics returns the latest snapshot of the service metrics detailed information
 func GetServiceStatistics(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(core.GetServiceStats(r.Context())); err != nil {
+	if err := json.NewEncoder(w).Encode(core.GetLatestServiceStats()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// GetCollectors returns the configuration and last run of every registered collector
+func GetCollectors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(core.GetCollectorStatuses()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// GetCustomMetrics returns the current value of every custom application metric series
+func GetCustomMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(core.GetCustomMetrics()); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
@@ -54,12 +100,58 @@ func GetGoRoutinesStats(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	query, err := parseGoroutineQuery(r)
+	if err != nil {
+		http.Error(w, "Invalid goroutine query: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(core.CollectGoRoutinesInfo()); err != nil {
+	if err := json.NewEncoder(w).Encode(core.QueryGoroutines(query)); err != nil {
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 	}
 }
 
+// parseGoroutineQuery reads the goroutine filters from the query parameters:
+// view, state, search, signature, min_wait (e.g. "5m"), page and page_size.
+func parseGoroutineQuery(r *http.Request) (models.GoroutineQuery, error) {
+	params := r.URL.Query()
+	query := models.GoroutineQuery{
+		View:      params.Get("view"),
+		State:     params.Get("state"),
+		Search:    params.Get("search"),
+		Signature: params.Get("signature"),
+		Page:      1,
+		PageSize:  defaultGoroutinePageSize,
+	}
+
+	if query.View != "" && query.View != core.GoroutineViewGoroutines && query.View != core.GoroutineViewGroups {
+		return query, fmt.Errorf("view must be %q or %q", core.GoroutineViewGoroutines, core.GoroutineViewGroups)
+	}
+	if v := params.Get("min_wait"); v != "" {
+		minWait, err := time.ParseDuration(v)
+		if err != nil || minWait < 0 {
+			return query, errors.New("min_wait must be a non-negative duration")
+		}
+		query.MinWait = minWait
+	}
+	if v := params.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return query, errors.New("page must be a positive integer")
+		}
+		query.Page = page
+	}
+	if v := params.Get("page_size"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil || pageSize < 1 || pageSize > maxGoroutinePageSize {
+			return query, fmt.Errorf("page_size must be between 1 and %d", maxGoroutinePageSize)
+		}
+		query.PageSize = pageSize
+	}
+	return query, nil
+}
+
 var NameMap = map[string]string{
 	"heap_alloc":      "HeapAlloc",
 	"heap_sys":        "HeapSys",
@@ -111,12 +203,24 @@ func GetServiceMetricsFromStorage(w http.ResponseWriter, r *http.Request) {
 		startTime = serviceStartTime
 	}
 
-	hostLabel := timeseries.GetHostLabel()
+	aggregation := common.DefaultIfEmpty(req.Aggregation, timeseries.AggregationAvg)
+	switch aggregation {
+	case timeseries.AggregationMin, timeseries.AggregationMax, timeseries.AggregationAvg, timeseries.AggregationLast, timeseries.AggregationCount:
+	default:
+		http.Error(w, "Invalid aggregation", http.StatusBadRequest)
+		return
+	}
+
+	labels := []timeseries.Label{timeseries.GetHostLabel()}
+	for name, value := range req.Labels {
+		labels = append(labels, timeseries.Label{Name: name, Value: value})
+	}
 
 	dataByTimestamp := make(map[int64]map[string]float64)
 
 	for _, fieldName := range req.FieldName {
-		datapoints, err := timeseries.GetDataPoints(fieldName, []timeseries.Label{hostLabel}, startTime.Unix(), endTime.Unix())
+		// Long ranges are read from the rollups
+		datapoints, _, err := timeseries.GetDownsampledDataPoints(fieldName, labels, aggregation, startTime.Unix(), endTime.Unix(), timeseries.DefaultMaxQueryPoints)
 		if err != nil {
 			http.Error(w, "Failed to get data points", http.StatusInternalServerError)
 			return
@@ -194,7 +298,9 @@ func GetReportData(w http.ResponseWriter, r *http.Request) {
 	case "MemoryProfile":
 		fieldNameList = []string{"heap_alloc_by_service", "heap_alloc_by_system", "total_alloc_by_service", "total_memory_by_os"}
 	case "NetworkIO":
-		fieldNameList = []string{"bytes_sent", "bytes_received"}
+		fieldNameList = []string{"bytes_sent", "bytes_received", "bytes_sent_per_sec", "bytes_received_per_sec"}
+	case "ProcessStatistics":
+		fieldNameList = []string{"process_open_fds", "process_max_fds", "process_fd_usage_percent", "process_threads", "process_voluntary_ctx_switches", "process_involuntary_ctx_switches", "process_minor_page_faults", "process_major_page_faults", "process_resident_memory", "process_virtual_memory"}
 	case "OverallHealth":
 		fieldNameList = []string{"service_health_percent", "system_health_percent"}
 	default:
@@ -206,7 +312,7 @@ func GetReportData(w http.ResponseWriter, r *http.Request) {
 
 	dataByTimestamp := make(map[int64]map[string]float64)
 	for _, fieldName := range fieldNameList {
-		datapoints, err := timeseries.GetDataPoints(fieldName, []timeseries.Label{hostLabel}, startTime.Unix(), endTime.Unix())
+		datapoints, _, err := timeseries.GetDownsampledDataPoints(fieldName, []timeseries.Label{hostLabel}, timeseries.AggregationAvg, startTime.Unix(), endTime.Unix(), timeseries.DefaultMaxQueryPoints)
 		if err != nil {
 			http.Error(w, "Failed to get data points", http.StatusInternalServerError)
 			return
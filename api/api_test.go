@@ -2,16 +2,21 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/iyashjayesh/monigo/common"
 	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/internal/registry"
 	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
 )
 
 func init() {
@@ -54,6 +59,29 @@ func TestGetServiceInfoAPI_WrongMethod(t *testing.T) {
 	}
 }
 
+func TestGetBuildInfo(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/build-info", nil)
+	w := httptest.NewRecorder()
+	GetBuildInfo(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var info models.BuildInfo
+	if err := json.NewDecoder(w.Body).Decode(&info); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if info.GoVersion == "" || info.Runtime.GOOS == "" || info.Dependencies == nil {
+		t.Errorf("unexpected build info: %+v", info)
+	}
+
+	w = httptest.NewRecorder()
+	GetBuildInfo(w, httptest.NewRequest(http.MethodPost, "/monigo/api/v1/build-info", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
 func TestGetServiceStatistics(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/metrics", nil)
 	w := httptest.NewRecorder()
@@ -82,6 +110,61 @@ func TestGetServiceStatistics_WrongMethod(t *testing.T) {
 	}
 }
 
+func TestGetCollectors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/collectors", nil)
+	w := httptest.NewRecorder()
+	GetCollectors(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+
+	var statuses []models.CollectorStatus
+	if err := json.NewDecoder(w.Body).Decode(&statuses); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	found := false
+	for _, s := range statuses {
+		if s.Name == core.CollectorDisk && s.Builtin {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected built-in disk collector in response")
+	}
+}
+
+func TestGetCustomMetrics(t *testing.T) {
+	registry.Default().IncrementCounter("api_test_orders_total", 2, map[string]string{"region": "eu"})
+	defer registry.Default().Delete("api_test_orders_total")
+
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/custom-metrics", nil)
+	w := httptest.NewRecorder()
+	GetCustomMetrics(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200, got %d", w.Code)
+	}
+
+	var metrics []models.CustomMetric
+	if err := json.NewDecoder(w.Body).Decode(&metrics); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(metrics) != 1 || metrics[0].Type != "counter" || metrics[0].Value != 2 || metrics[0].Labels["region"] != "eu" {
+		t.Errorf("unexpected custom metrics: %+v", metrics)
+	}
+}
+
+func TestGetCustomMetrics_WrongMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/monigo/api/v1/custom-metrics", nil)
+	w := httptest.NewRecorder()
+	GetCustomMetrics(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
 func TestGetGoRoutinesStats(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/go-routines-stats", nil)
 	w := httptest.NewRecorder()
@@ -100,6 +183,39 @@ func TestGetGoRoutinesStats(t *testing.T) {
 	}
 }
 
+func TestGetGoRoutinesStats_Query(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/go-routines-stats?view=groups&page_size=1", nil)
+	w := httptest.NewRecorder()
+	GetGoRoutinesStats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var stats models.GoRoutinesStatistic
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if stats.View != "groups" || stats.PageSize != 1 || len(stats.Groups) != 1 {
+		t.Errorf("expected a single group on the page, got view %q with %d groups", stats.View, len(stats.Groups))
+	}
+	if stats.Groups[0].Count <= 0 || stats.Groups[0].Signature == "" {
+		t.Errorf("unexpected group: %+v", stats.Groups[0])
+	}
+}
+
+func TestGetGoRoutinesStats_InvalidQuery(t *testing.T) {
+	for _, query := range []string{"view=tree", "page=0", "page_size=5000", "min_wait=soon"} {
+		req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/go-routines-stats?"+query, nil)
+		w := httptest.NewRecorder()
+		GetGoRoutinesStats(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
 func TestGetFunctionTraceDetails(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/function", nil)
 	w := httptest.NewRecorder()
@@ -160,6 +276,17 @@ func TestGetServiceMetricsFromStorage_InvalidBody(t *testing.T) {
 	}
 }
 
+func TestGetServiceMetricsFromStorage_InvalidAggregation(t *testing.T) {
+	body := `{"field_name":["goroutines"],"aggregation":"median","start_time":"2026-01-01T00:00:00Z","end_time":"2026-01-02T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/monigo/api/v1/service-metrics", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	GetServiceMetricsFromStorage(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown aggregation, got %d", w.Code)
+	}
+}
+
 func TestGetReportData_WrongMethod(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/reports", nil)
 	w := httptest.NewRecorder()
@@ -190,3 +317,444 @@ func TestGetReportData_InvalidBody(t *testing.T) {
 		t.Errorf("expected 400, got %d", w.Code)
 	}
 }
+
+func TestCaptureProfile_RequiresAuthentication(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/monigo/api/v1/profiles/capture?type=heap", nil)
+	w := httptest.NewRecorder()
+	CaptureProfile(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without authentication, got %d", w.Code)
+	}
+}
+
+func TestCaptureProfile_WrongMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/profiles/capture?type=heap", nil)
+	w := httptest.NewRecorder()
+	CaptureProfile(w, WithAuthenticated(req))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestCaptureProfile_InvalidParams(t *testing.T) {
+	for _, query := range []string{"type=bogus", "type=cpu&seconds=-1", "type=cpu&seconds=3600", "type=mutex&rate=x"} {
+		req := httptest.NewRequest(http.MethodPost, "/monigo/api/v1/profiles/capture?"+query, nil)
+		w := httptest.NewRecorder()
+		CaptureProfile(w, WithAuthenticated(req))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestCaptureProfile_Download(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/monigo/api/v1/profiles/capture?type=goroutine", nil)
+	w := httptest.NewRecorder()
+	CaptureProfile(w, WithAuthenticated(req))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if disposition := w.Header().Get("Content-Disposition"); !strings.Contains(disposition, "goroutine-") {
+		t.Errorf("expected a goroutine profile attachment, got %q", disposition)
+	}
+	if body := w.Body.Bytes(); len(body) < 2 || body[0] != 0x1f || body[1] != 0x8b {
+		t.Error("expected a gzipped pprof profile")
+	}
+}
+
+func TestDownloadProfile_NotFound(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/profiles/download?id=../secret", nil)
+	w := httptest.NewRecorder()
+	DownloadProfile(w, WithAuthenticated(req))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestDeleteProfile_WrongMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/profiles/delete?id=1", nil)
+	w := httptest.NewRecorder()
+	DeleteProfile(w, WithAuthenticated(req))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestGetProfileTimeline(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/profiles/timeline?type=cpu", nil)
+	w := httptest.NewRecorder()
+	GetProfileTimeline(w, WithAuthenticated(req))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var timeline models.ProfileTimeline
+	if err := json.Unmarshal(w.Body.Bytes(), &timeline); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if timeline.Profiles == nil || !timeline.From.Before(timeline.To) {
+		t.Errorf("unexpected timeline: %+v", timeline)
+	}
+}
+
+func TestGetProfileTimeline_InvalidRange(t *testing.T) {
+	for _, query := range []string{"from=yesterday", "from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z"} {
+		req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/profiles/timeline?"+query, nil)
+		w := httptest.NewRecorder()
+		GetProfileTimeline(w, WithAuthenticated(req))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetIncidents(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/incidents", nil)
+	w := httptest.NewRecorder()
+	GetIncidents(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var incidents []models.Incident
+	if err := json.Unmarshal(w.Body.Bytes(), &incidents); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+}
+
+func TestDeleteIncident(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/monigo/api/v1/incidents/delete?id=1", nil)
+	w := httptest.NewRecorder()
+	DeleteIncident(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without authentication, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	DeleteIncident(w, WithAuthenticated(req))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown incident, got %d", w.Code)
+	}
+}
+
+func TestGetGCInsights(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/monigo/api/v1/gc", nil)
+	w := httptest.NewRecorder()
+	GetGCInsights(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var insights models.GCInsights
+	if err := json.Unmarshal(w.Body.Bytes(), &insights); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if insights.Statistics.HeapGoal == 0 || insights.Suggestions == nil || insights.Audit == nil || insights.ControlsEnabled {
+		t.Errorf("unexpected insights: %+v", insights)
+	}
+
+	// The dashboard enables the controls for authenticated requests only
+	w = httptest.NewRecorder()
+	GetGCInsights(w, WithAuthenticated(req))
+	if err := json.Unmarshal(w.Body.Bytes(), &insights); err != nil || !insights.ControlsEnabled {
+		t.Errorf("expected the controls to be enabled, got %+v (%v)", insights, err)
+	}
+}
+
+func TestGCControls_RequireAuthentication(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"/monigo/api/v1/gc/percent?value=200":    SetGCPercent,
+		"/monigo/api/v1/gc/memory-limit?value=0": SetMemoryLimit,
+		"/monigo/api/v1/gc/run":                  RunGC,
+		"/monigo/api/v1/gc/free-os-memory":       FreeOSMemory,
+	}
+	for path, handler := range handlers {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodPost, path, nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403 without authentication, got %d", path, w.Code)
+		}
+
+		w = httptest.NewRecorder()
+		handler(w, WithAuthenticated(httptest.NewRequest(http.MethodGet, path, nil)))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s: expected 405, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestGCControls_InvalidValue(t *testing.T) {
+	for path, handler := range map[string]http.HandlerFunc{
+		"/monigo/api/v1/gc/percent?value=high":    SetGCPercent,
+		"/monigo/api/v1/gc/percent?value=-5":      SetGCPercent,
+		"/monigo/api/v1/gc/memory-limit?value=1G": SetMemoryLimit,
+	} {
+		w := httptest.NewRecorder()
+		handler(w, WithAuthenticated(httptest.NewRequest(http.MethodPost, path, nil)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestProbes(t *testing.T) {
+	w := httptest.NewRecorder()
+	Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while the sampler is stopped, got %d", w.Code)
+	}
+
+	core.GetLatestServiceStats() // The first snapshot, refreshed by the sampler
+	core.StartSampler(time.Hour)
+	defer core.StopSampler()
+	w = httptest.NewRecorder()
+	Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var result models.ProbeResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Status != core.ProbeStatusOK || len(result.Checks) == 0 {
+		t.Errorf("unexpected liveness result: %+v", result)
+	}
+
+	if err := core.RegisterDependencyCheck("database", func(context.Context) error { return errors.New("connection refused") }, models.DependencyCheckConfig{}); err != nil {
+		t.Fatalf("RegisterDependencyCheck error: %v", err)
+	}
+	defer core.UnregisterDependencyCheck("database")
+
+	w = httptest.NewRecorder()
+	Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "connection refused") {
+		t.Errorf("expected 503 with the failing dependency, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	Readiness(w, httptest.NewRequest(http.MethodHead, "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable || w.Body.Len() != 0 {
+		t.Errorf("expected 503 without a body for HEAD, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	Liveness(w, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestGetHealthEvents(t *testing.T) {
+	w := httptest.NewRecorder()
+	GetHealthEvents(w, httptest.NewRequest(http.MethodGet, "/monigo/api/v1/health/events?scope=service", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var events []models.HealthEvent
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	for _, query := range []string{"scope=database", "from=yesterday", "from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z"} {
+		w = httptest.NewRecorder()
+		GetHealthEvents(w, httptest.NewRequest(http.MethodGet, "/monigo/api/v1/health/events?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetAlerts(t *testing.T) {
+	for _, target := range []string{"/monigo/api/v1/alerts", "/monigo/api/v1/alerts?state=all"} {
+		w := httptest.NewRecorder()
+		GetAlerts(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d: %s", target, w.Code, w.Body.String())
+		}
+		var alerts []models.Alert
+		if err := json.Unmarshal(w.Body.Bytes(), &alerts); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	GetAlerts(w, httptest.NewRequest(http.MethodGet, "/monigo/api/v1/alerts?state=firing", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown state, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	GetAlertRules(w, httptest.NewRequest(http.MethodPost, "/monigo/api/v1/alerts/rules", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestGetAnomalies(t *testing.T) {
+	w := httptest.NewRecorder()
+	GetAnomalies(w, httptest.NewRequest(http.MethodGet, "/monigo/api/v1/anomalies?metric=goroutines", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var events []models.AnomalyEvent
+	if err := json.Unmarshal(w.Body.Bytes(), &events); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	for _, target := range []string{
+		"/monigo/api/v1/anomalies?from=yesterday",
+		"/monigo/api/v1/anomalies?from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z",
+	} {
+		w := httptest.NewRecorder()
+		GetAnomalies(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", target, w.Code)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	GetAnomalyStatus(w, httptest.NewRequest(http.MethodGet, "/monigo/api/v1/anomalies/status", nil))
+	var statuses []models.AnomalyStatus
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &statuses) != nil {
+		t.Errorf("expected a status list, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	GetAnomalyStatus(w, httptest.NewRequest(http.MethodPost, "/monigo/api/v1/anomalies/status", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestGetSLOs(t *testing.T) {
+	w := httptest.NewRecorder()
+	GetSLOs(w, httptest.NewRequest(http.MethodGet, "/monigo/api/v1/slos", nil))
+	var statuses []models.SLOStatus
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &statuses) != nil {
+		t.Errorf("expected a status list, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	GetSLOs(w, httptest.NewRequest(http.MethodPost, "/monigo/api/v1/slos", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	timeseries.SetStorageType("memory")
+
+	tests := []struct {
+		url  string
+		code int
+	}{
+		{"/monigo/api/v1/query", http.StatusBadRequest},
+		{"/monigo/api/v1/query?query=sum(", http.StatusBadRequest},
+		{"/monigo/api/v1/query?query=goroutines&step=forever", http.StatusBadRequest},
+		{"/monigo/api/v1/query?query=goroutines&step=1s&from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z", http.StatusBadRequest},
+		{"/monigo/api/v1/query?query=sum(rate(goroutines[5m]))", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		Query(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d: %s", tt.url, tt.code, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	Query(w, httptest.NewRequest(http.MethodPost, "/monigo/api/v1/query?query=goroutines", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestExport(t *testing.T) {
+	timeseries.SetStorageType("memory")
+
+	tests := []struct {
+		url         string
+		code        int
+		contentType string
+	}{
+		{"/monigo/api/v1/export?format=xml", http.StatusBadRequest, ""},
+		{"/monigo/api/v1/export?match=route", http.StatusBadRequest, ""},
+		{"/monigo/api/v1/export?from=yesterday", http.StatusBadRequest, ""},
+		{"/monigo/api/v1/export?metric=goroutines", http.StatusOK, "text/csv; charset=utf-8"},
+		{"/monigo/api/v1/export?format=ndjson&metric=goroutines,heap_alloc", http.StatusOK, "application/x-ndjson"},
+		{"/monigo/api/v1/export?format=openmetrics&match=service=api", http.StatusOK, "application/openmetrics-text; version=1.0.0; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		Export(w, WithAuthenticated(httptest.NewRequest(http.MethodGet, tt.url, nil)))
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d: %s", tt.url, tt.code, w.Code, w.Body.String())
+			continue
+		}
+		if tt.code == http.StatusOK {
+			if ct := w.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("%s: expected content type %q, got %q", tt.url, tt.contentType, ct)
+			}
+			if disposition := w.Header().Get("Content-Disposition"); !strings.Contains(disposition, "monigo-") {
+				t.Errorf("%s: expected an attachment, got %q", tt.url, disposition)
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	Export(w, httptest.NewRequest(http.MethodGet, "/monigo/api/v1/export?metric=goroutines", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without authentication, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	Export(w, httptest.NewRequest(http.MethodPost, "/monigo/api/v1/export", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestImport(t *testing.T) {
+	timeseries.SetStorageType("memory")
+	body := "# TYPE api_import_test unknown\napi_import_test{host=\"a\"} 1 1700000000\napi_import_test{host=\"a\"} 2 1700000060\n# EOF\n"
+
+	w := httptest.NewRecorder()
+	Import(w, httptest.NewRequest(http.MethodPost, "/monigo/api/v1/import?format=openmetrics", strings.NewReader(body)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without authentication, got %d", w.Code)
+	}
+
+	tests := []struct {
+		method string
+		url    string
+		body   string
+		code   int
+	}{
+		{http.MethodGet, "/monigo/api/v1/import?format=openmetrics", "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/monigo/api/v1/import", body, http.StatusBadRequest},
+		{http.MethodPost, "/monigo/api/v1/import?format=openmetrics&rename=host", body, http.StatusBadRequest},
+		{http.MethodPost, "/monigo/api/v1/import?format=csv", "metric,labels\n", http.StatusBadRequest},
+		{http.MethodPost, "/monigo/api/v1/import?format=openmetrics&rename=host:source_host&label=source:test", body, http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		Import(w, WithAuthenticated(httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))))
+		if w.Code != tt.code {
+			t.Errorf("%s %s: expected %d, got %d: %s", tt.method, tt.url, tt.code, w.Code, w.Body.String())
+		}
+	}
+
+	w = httptest.NewRecorder()
+	Import(w, WithAuthenticated(httptest.NewRequest(http.MethodPost, "/monigo/api/v1/import?format=openmetrics", strings.NewReader(body))))
+	var result models.ImportResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil || result.Imported != 2 {
+		t.Errorf("expected 2 imported points, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+// maxImportSize is the largest request body accepted by Import.
+const maxImportSize = 256 << 20
+
+// exportContentTypes maps the export formats to their content type and file extension.
+var exportContentTypes = map[string][2]string{
+	timeseries.FormatCSV:         {"text/csv; charset=utf-8", "csv"},
+	timeseries.FormatNDJSON:      {"application/x-ndjson", "ndjson"},
+	timeseries.FormatOpenMetrics: {"application/openmetrics-text; version=1.0.0; charset=utf-8", "txt"},
+}
+
+// Export streams the stored points of the selected metrics and range as a file download
+// GET /monigo/api/v1/export?format=csv&metric=cpu_usage&match=service=api&from=2024-01-01T00:00:00Z
+func Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Exporting metrics") {
+		return
+	}
+
+	params := r.URL.Query()
+	format := params.Get("format")
+	if format == "" {
+		format = timeseries.FormatCSV
+	}
+	if !timeseries.IsExportFormat(format) {
+		http.Error(w, "Invalid format, expected csv, ndjson or openmetrics", http.StatusBadRequest)
+		return
+	}
+	from, to, err := parseTimeRange(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := timeseries.ExportOptions{Start: from.Unix(), End: to.Unix()}
+	for _, v := range params["metric"] {
+		for _, metric := range strings.Split(v, ",") {
+			if metric = strings.TrimSpace(metric); metric != "" {
+				opts.Metrics = append(opts.Metrics, metric)
+			}
+		}
+	}
+	for _, v := range params["match"] {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			http.Error(w, fmt.Sprintf("Invalid match %q, expected name=value", v), http.StatusBadRequest)
+			return
+		}
+		if opts.Match == nil {
+			opts.Match = make(map[string]string)
+		}
+		opts.Match[name] = value
+	}
+
+	name := fmt.Sprintf("monigo-%s-%s.%s", from.UTC().Format("20060102-150405"), to.UTC().Format("20060102-150405"), exportContentTypes[format][1])
+	out := &startedWriter{ResponseWriter: w, header: func(h http.Header) {
+		h.Set("Content-Type", exportContentTypes[format][0])
+		h.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	}}
+	if _, err := timeseries.Export(out, format, opts); err != nil {
+		if !out.started {
+			http.Error(w, fmt.Sprintf("Failed to export metrics: %v", err), http.StatusInternalServerError)
+			return
+		}
+		// The status is sent already, the download ends truncated
+		logger.Log.Error("exporting metrics", "format", format, "error", err)
+		return
+	}
+	if !out.started {
+		out.Write(nil) // Sends the headers of an empty export
+	}
+}
+
+// startedWriter sets the download headers on the first write, so an export failing before
+// writing anything can still answer with an error.
+type startedWriter struct {
+	http.ResponseWriter
+	header  func(http.Header)
+	started bool
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.header(w.Header())
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Import inserts points in the format of an export into the storage, with its labels remapped
+// POST /monigo/api/v1/import?format=ndjson&rename=host:source_host&label=source:prod
+func Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Importing metrics") {
+		return
+	}
+
+	params := r.URL.Query()
+	format := params.Get("format")
+	if !timeseries.IsExportFormat(format) {
+		http.Error(w, "Invalid format, expected csv, ndjson or openmetrics", http.StatusBadRequest)
+		return
+	}
+	var opts timeseries.ImportOptions
+	for param, m := range map[string]*map[string]string{"rename": &opts.RenameLabels, "label": &opts.Labels} {
+		for _, v := range params[param] {
+			name, value, ok := strings.Cut(v, ":")
+			if !ok || name == "" {
+				http.Error(w, fmt.Sprintf("Invalid %s %q, expected name:value", param, v), http.StatusBadRequest)
+				return
+			}
+			if *m == nil {
+				*m = make(map[string]string)
+			}
+			(*m)[name] = value
+		}
+	}
+
+	imported, err := timeseries.Import(http.MaxBytesReader(w, r.Body, maxImportSize), format, opts)
+	result := models.ImportResult{Imported: imported}
+	status := http.StatusOK
+	if err != nil {
+		result.Error = err.Error()
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
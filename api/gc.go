@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// GetGCInsights returns the GC settings and activity, tuning suggestions and recent changes
+// GET /monigo/api/v1/gc
+func GetGCInsights(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	insights := core.GetGCInsights()
+	insights.ControlsEnabled = isAuthenticated(r)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(insights); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// SetGCPercent sets GOGC at runtime, -1 turns the GC off
+// POST /monigo/api/v1/gc/percent?value=200
+func SetGCPercent(w http.ResponseWriter, r *http.Request) {
+	if !requireGCControl(w, r) {
+		return
+	}
+
+	percent, err := strconv.Atoi(r.URL.Query().Get("value"))
+	if err != nil {
+		http.Error(w, "value must be an integer GC percent, -1 turns the GC off", http.StatusBadRequest)
+		return
+	}
+	entry, err := core.SetGCPercent(percent, gcActor(r))
+	writeGCChange(w, entry, err)
+}
+
+// SetMemoryLimit sets GOMEMLIMIT at runtime in bytes, 0 or "off" removes the limit
+// POST /monigo/api/v1/gc/memory-limit?value=536870912
+func SetMemoryLimit(w http.ResponseWriter, r *http.Request) {
+	if !requireGCControl(w, r) {
+		return
+	}
+
+	value := r.URL.Query().Get("value")
+	var limit int64
+	if value != "off" {
+		var err error
+		if limit, err = strconv.ParseInt(value, 10, 64); err != nil {
+			http.Error(w, "value must be a memory limit in bytes, 0 or off removes it", http.StatusBadRequest)
+			return
+		}
+	}
+	entry, err := core.SetMemoryLimit(limit, gcActor(r))
+	writeGCChange(w, entry, err)
+}
+
+// RunGC runs a garbage collection
+// POST /monigo/api/v1/gc/run
+func RunGC(w http.ResponseWriter, r *http.Request) {
+	if !requireGCControl(w, r) {
+		return
+	}
+	entry, err := core.RunGC(gcActor(r))
+	writeGCChange(w, entry, err)
+}
+
+// FreeOSMemory runs a garbage collection and returns as much memory as possible to the OS
+// POST /monigo/api/v1/gc/free-os-memory
+func FreeOSMemory(w http.ResponseWriter, r *http.Request) {
+	if !requireGCControl(w, r) {
+		return
+	}
+	entry, err := core.FreeOSMemory(gcActor(r))
+	writeGCChange(w, entry, err)
+}
+
+// requireGCControl checks the method and authentication of the GC control endpoints.
+func requireGCControl(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	return requireAuthentication(w, r, "Changing the GC")
+}
+
+// gcActor identifies who changed the GC for the audit trail.
+func gcActor(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		return fmt.Sprintf("%s (%s)", user, r.RemoteAddr)
+	}
+	return r.RemoteAddr
+}
+
+// writeGCChange responds with the audit entry of a change. The change is applied even when
+// recording it failed, so that is reported alongside the entry.
+func writeGCChange(w http.ResponseWriter, entry models.GCAuditEntry, err error) {
+	if err != nil && entry.Action == "" {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := struct {
+		models.GCAuditEntry
+		AuditError string `json:"audit_error,omitempty"`
+	}{GCAuditEntry: entry}
+	if err != nil {
+		response.AuditError = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
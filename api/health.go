@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// GetHealthEvents lists the health level transitions in a time range, oldest first. The range
+// defaults to the last day and the times are RFC 3339, e.g. 2026-03-01T03:00:00Z.
+// GET /monigo/api/v1/health/events?from=...&to=...&scope=service
+func GetHealthEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	query := models.HealthEventQuery{Scope: params.Get("scope")}
+	for name, t := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if v := params.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid %s time, expected RFC 3339", name), http.StatusBadRequest)
+				return
+			}
+			*t = parsed
+		}
+	}
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-24 * time.Hour)
+	}
+	if query.To.Before(query.From) {
+		http.Error(w, "to must not be before from", http.StatusBadRequest)
+		return
+	}
+	if query.Scope != "" && query.Scope != core.HealthScopeService && query.Scope != core.HealthScopeSystem {
+		http.Error(w, "Invalid scope, expected service or system", http.StatusBadRequest)
+		return
+	}
+
+	events, err := core.GetHealthEvents(query)
+	if err != nil {
+		http.Error(w, "Failed to read health events", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/iyashjayesh/monigo/core"
+)
+
+// GetIncidents lists the incidents captured when a health threshold was crossed, newest first
+// GET /monigo/api/v1/incidents
+func GetIncidents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	incidents, err := core.ListIncidents()
+	if err != nil {
+		http.Error(w, "Failed to list incidents", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(incidents); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DeleteIncident removes an incident and its profiles
+// DELETE /monigo/api/v1/incidents/delete?id=...
+func DeleteIncident(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Deleting incidents") {
+		return
+	}
+
+	if err := core.DeleteIncident(r.URL.Query().Get("id")); err != nil {
+		if errors.Is(err, core.ErrIncidentNotFound) {
+			http.Error(w, "Incident not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete incident: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
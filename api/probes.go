@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// Liveness serves the liveness probe, 200 while the service is alive and 503 otherwise
+// GET /healthz
+func Liveness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeProbeResult(w, r, core.Liveness())
+}
+
+// Readiness serves the readiness probe, 200 while every dependency check passes and 503 otherwise
+// GET /readyz
+func Readiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeProbeResult(w, r, core.Readiness(r.Context()))
+}
+
+// writeProbeResult writes a probe result with the status code probes act on.
+func writeProbeResult(w http.ResponseWriter, r *http.Request, result models.ProbeResult) {
+	status := http.StatusOK
+	if result.Status != core.ProbeStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
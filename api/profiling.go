@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+type authenticatedKey struct{}
+
+// WithAuthenticated marks a request as having passed authentication. The dashboard's auth
+// function and the built-in BasicAuth and APIKey middleware mark the requests they let through;
+// custom authentication middleware passes the marked request to the next handler. Profiling,
+// GC controls, incident deletion and imports refuse requests without the mark.
+func WithAuthenticated(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), authenticatedKey{}, true))
+}
+
+// isAuthenticated reports whether the request was marked by WithAuthenticated.
+func isAuthenticated(r *http.Request) bool {
+	authenticated, _ := r.Context().Value(authenticatedKey{}).(bool)
+	return authenticated
+}
+
+// requireAuthentication writes 403 and returns false for requests not served behind authentication,
+// naming what is refused in the message, e.g. "Profiling".
+func requireAuthentication(w http.ResponseWriter, r *http.Request, what string) bool {
+	if isAuthenticated(r) {
+		return true
+	}
+	http.Error(w, fmt.Sprintf("%s requires authentication, configure WithAuthFunction() or authentication middleware", what), http.StatusForbidden)
+	return false
+}
+
+// CaptureProfile captures a profile and either downloads it or stores it for the dashboard
+// POST /monigo/api/v1/profiles/capture?type=cpu&seconds=30&rate=5&gc=1&store=1
+func CaptureProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Profiling") {
+		return
+	}
+
+	params := r.URL.Query()
+	req := models.ProfileRequest{
+		Type: params.Get("type"),
+		GC:   params.Get("gc") == "1" || params.Get("gc") == "true",
+	}
+	if !core.IsProfileType(req.Type) {
+		http.Error(w, "Unknown profile type", http.StatusBadRequest)
+		return
+	}
+	if v := params.Get("seconds"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds < 0 || time.Duration(seconds)*time.Second > core.MaxProfileDuration {
+			http.Error(w, fmt.Sprintf("seconds must be between 0 and %d", int(core.MaxProfileDuration.Seconds())), http.StatusBadRequest)
+			return
+		}
+		req.Duration = time.Duration(seconds) * time.Second
+	}
+	if v := params.Get("rate"); v != "" {
+		rate, err := strconv.Atoi(v)
+		if err != nil || rate < 0 {
+			http.Error(w, "rate must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		req.Rate = rate
+	}
+
+	start := time.Now()
+	data, err := core.CaptureProfile(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, core.ErrProfileInProgress):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case r.Context().Err() != nil:
+			// The client went away, nothing to respond to
+		default:
+			http.Error(w, "Failed to capture profile: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if store := params.Get("store"); store != "1" && store != "true" {
+		writeProfile(w, req.Type, start, data)
+		return
+	}
+
+	record, err := core.StoreProfile(core.ProfileSourceOnDemand, req.Type, start, time.Since(start).Round(time.Millisecond), data)
+	if err != nil {
+		http.Error(w, "Failed to store profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(record); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// writeProfile sends a profile as a file download.
+func writeProfile(w http.ResponseWriter, profileType string, createdAt time.Time, data []byte) {
+	name := fmt.Sprintf("%s-%s.pb.gz", profileType, createdAt.Format("20060102-150405"))
+	if profileType == core.ProfileTrace {
+		name = fmt.Sprintf("trace-%s.out", createdAt.Format("20060102-150405"))
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+// GetProfiles lists the stored profiles, newest first
+// GET /monigo/api/v1/profiles?type=cpu&source=on-demand
+func GetProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Profiling") {
+		return
+	}
+
+	params := r.URL.Query()
+	profiles, err := core.ListProfiles(models.ProfileQuery{Type: params.Get("type"), Source: params.Get("source")})
+	if err != nil {
+		http.Error(w, "Failed to list profiles", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(profiles); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// GetProfileTimeline lists the stored profiles captured in a time range, oldest first. The range
+// defaults to the last day and the times are RFC 3339, e.g. 2026-03-01T03:00:00Z.
+// GET /monigo/api/v1/profiles/timeline?from=...&to=...&type=cpu&source=continuous
+func GetProfileTimeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Profiling") {
+		return
+	}
+
+	params := r.URL.Query()
+	query := models.ProfileQuery{Type: params.Get("type"), Source: params.Get("source")}
+	for name, t := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if v := params.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Invalid %s time, expected RFC 3339", name), http.StatusBadRequest)
+				return
+			}
+			*t = parsed
+		}
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		http.Error(w, "to must not be before from", http.StatusBadRequest)
+		return
+	}
+
+	timeline, err := core.GetProfileTimeline(query)
+	if err != nil {
+		http.Error(w, "Failed to list profiles", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(timeline); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DownloadProfile downloads a stored profile
+// GET /monigo/api/v1/profiles/download?id=...
+func DownloadProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Profiling") {
+		return
+	}
+
+	record, data, err := core.ReadProfile(r.URL.Query().Get("id"))
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	writeProfile(w, record.Type, record.CreatedAt, data)
+}
+
+// ViewProfile renders a stored profile with pprof
+// GET /monigo/api/v1/profiles/view?id=...&reportType=top
+func ViewProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Profiling") {
+		return
+	}
+
+	reportType := r.URL.Query().Get("reportType")
+	if reportType == "" {
+		reportType = "top"
+	}
+	report, err := core.GetProfileReport(r.URL.Query().Get("id"), reportType)
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DeleteProfile removes a stored profile
+// DELETE /monigo/api/v1/profiles/delete?id=...
+func DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireAuthentication(w, r, "Profiling") {
+		return
+	}
+
+	if err := core.DeleteProfile(r.URL.Query().Get("id")); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeProfileError maps stored profile errors to HTTP status codes.
+func writeProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, core.ErrProfileNotFound):
+		http.Error(w, "Profile not found", http.StatusNotFound)
+		return
+	case errors.Is(err, core.ErrInvalidProfileReport):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func init() {
-	prometheus.MustRegister(exporters.NewMonigoCollector())
+	prometheus.MustRegister(exporters.NewMonigoCollector(), exporters.NewCustomMetricsCollector())
 }
 
 func GetPrometheusHandler() http.Handler {
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/iyashjayesh/monigo/query"
+)
+
+// defaultQuerySteps is the number of steps a query range is divided in when no step is given.
+const defaultQuerySteps = 250
+
+// Query evaluates a query over the stored metrics at every step of a time range, returning
+// the values of every series at the aligned timestamps. The range defaults to the last day
+// and the times are RFC 3339, e.g. 2026-03-01T03:00:00Z; the step is a duration and defaults
+// to a 250th of the range.
+// GET /monigo/api/v1/query?query=sum(rate(http_requests_total[5m])) by (route)&from=...&to=...&step=1m
+func Query(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	if params.Get("query") == "" {
+		http.Error(w, "Missing query", http.StatusBadRequest)
+		return
+	}
+	expr, err := query.Parse(params.Get("query"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := parseTimeRange(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	step := max(to.Sub(from)/defaultQuerySteps, time.Second).Truncate(time.Second)
+	if v := params.Get("step"); v != "" {
+		if step, err = time.ParseDuration(v); err != nil || step < time.Second {
+			http.Error(w, "Invalid step, expected a duration of at least a second, e.g. '1m'", http.StatusBadRequest)
+			return
+		}
+	}
+	if to.Sub(from)/step+1 > query.MaxPoints {
+		http.Error(w, fmt.Sprintf("The range holds more than %d steps, increase the step", query.MaxPoints), http.StatusBadRequest)
+		return
+	}
+
+	result, err := query.Range(expr, from, to, step)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to evaluate query: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// parseTimeRange returns the range between the from and to RFC 3339 times, the last day by default.
+func parseTimeRange(params url.Values) (from, to time.Time, err error) {
+	for name, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		if v := params.Get(name); v != "" {
+			if *t, err = time.Parse(time.RFC3339, v); err != nil {
+				return from, to, fmt.Errorf("Invalid %s time, expected RFC 3339", name)
+			}
+		}
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-24 * time.Hour)
+	}
+	if to.Before(from) {
+		return from, to, errors.New("to must not be before from")
+	}
+	return from, to, nil
+}
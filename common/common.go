@@ -259,8 +259,8 @@ func GetServiceStartTime() time.Time {
 	return serviceInfo.ServiceStartTime
 }
 
-// parseDuration parses the duration string.
-func parseDuration(input string) (time.Duration, error) {
+// ParseDuration parses a duration which may also be given in days, e.g. "30d", or in months of 30 days, e.g. "1month".
+func ParseDuration(input string) (time.Duration, error) {
 	if strings.HasSuffix(input, "d") {
 		daysStr := strings.TrimSuffix(input, "d")
 		days, err := strconv.Atoi(daysStr)
@@ -288,7 +288,7 @@ func GetDataRetentionPeriod() time.Duration {
 		period = "7d"
 	}
 
-	duration, err := parseDuration(period)
+	duration, err := ParseDuration(period)
 	if err != nil {
 		logger.Log.Error("parsing retention period, using default retention period (7d)", "error", err)
 		duration = 7 * 24 * time.Hour
@@ -325,6 +325,9 @@ func DefaultIntIfZero(val, def int) int {
 func ConvertToMB(value string) (float64, error) {
 	value = strings.TrimSpace(value)
 	value = strings.Replace(value, " ", "", -1)
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid memory value: %q", value)
+	}
 	unit := strings.ToUpper(value[len(value)-2:])
 	val, err := strconv.ParseFloat(value[:len(value)-2], 64)
 	if err != nil {
@@ -1,10 +1,17 @@
 package monigo
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/iyashjayesh/monigo/alerting"
+	"github.com/iyashjayesh/monigo/anomaly"
+	"github.com/iyashjayesh/monigo/core"
 	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/slo"
+	"github.com/iyashjayesh/monigo/timeseries"
 )
 
 // MonigoBuilder is the builder for the Monigo struct
@@ -43,6 +50,12 @@ func (b *MonigoBuilder) WithDataPointsSyncFrequency(frequency string) *MonigoBui
 	return b
 }
 
+// WithCollectionInterval sets how often the background sampler refreshes the stats snapshot (e.g. "15s")
+func (b *MonigoBuilder) WithCollectionInterval(interval string) *MonigoBuilder {
+	b.config.CollectionInterval = interval
+	return b
+}
+
 // WithTimeZone sets the time zone
 func (b *MonigoBuilder) WithTimeZone(timeZone string) *MonigoBuilder {
 	b.config.TimeZone = timeZone
@@ -73,6 +86,167 @@ func (b *MonigoBuilder) WithMaxGoRoutines(routines int) *MonigoBuilder {
 	return b
 }
 
+// WithMaxFDUsage sets the max open file descriptors as a percentage of RLIMIT_NOFILE
+func (b *MonigoBuilder) WithMaxFDUsage(usage float64) *MonigoBuilder {
+	b.config.MaxFDUsage = usage
+	return b
+}
+
+// WithMaxThreads sets the max OS threads
+func (b *MonigoBuilder) WithMaxThreads(threads int) *MonigoBuilder {
+	b.config.MaxThreads = threads
+	return b
+}
+
+// WithExcludeLoopbackInterfaces sets whether loopback interfaces are excluded from network I/O collection
+func (b *MonigoBuilder) WithExcludeLoopbackInterfaces(exclude bool) *MonigoBuilder {
+	b.config.ExcludeLoopbackInterfaces = exclude
+	return b
+}
+
+// WithExcludeVirtualInterfaces sets whether virtual interfaces (docker, veth, bridges, ...) are excluded from network I/O collection
+func (b *MonigoBuilder) WithExcludeVirtualInterfaces(exclude bool) *MonigoBuilder {
+	b.config.ExcludeVirtualInterfaces = exclude
+	return b
+}
+
+// WithExcludedInterfaces sets interface names or glob patterns (e.g. "docker*") excluded from network I/O collection
+func (b *MonigoBuilder) WithExcludedInterfaces(interfaces ...string) *MonigoBuilder {
+	b.config.ExcludedInterfaces = interfaces
+	return b
+}
+
+// WithCollector registers a custom collector whose metrics are stored and charted with the built-in statistics
+func (b *MonigoBuilder) WithCollector(collector Collector) *MonigoBuilder {
+	b.config.Collectors = append(b.config.Collectors, collector)
+	return b
+}
+
+// WithHealthCheck registers a custom check scoring part of the service's health
+func (b *MonigoBuilder) WithHealthCheck(check HealthCheck) *MonigoBuilder {
+	b.config.HealthChecks = append(b.config.HealthChecks, check)
+	return b
+}
+
+// WithHealthCheckWeight sets the weight of the named health check in the service or system health (e.g. "memory", 2); checks default to 1
+func (b *MonigoBuilder) WithHealthCheckWeight(name string, weight float64) *MonigoBuilder {
+	if b.config.HealthCheckWeights == nil {
+		b.config.HealthCheckWeights = make(map[string]float64)
+	}
+	b.config.HealthCheckWeights[name] = weight
+	return b
+}
+
+// WithCriticalHealthChecks sets health checks which force the health status to critical when they fail, whatever the average
+func (b *MonigoBuilder) WithCriticalHealthChecks(names ...string) *MonigoBuilder {
+	b.config.CriticalHealthChecks = append(b.config.CriticalHealthChecks, names...)
+	return b
+}
+
+// WithHealthHysteresis sets how many percentage points the health must move past a level boundary before a transition is recorded (default 5)
+func (b *MonigoBuilder) WithHealthHysteresis(points float64) *MonigoBuilder {
+	b.config.HealthHysteresis = points
+	return b
+}
+
+// WithDependencyCheck registers a dependency which must be reachable for /readyz to pass, with its timeout and cache TTL (e.g. "2s", "5s"); empty durations use the defaults
+func (b *MonigoBuilder) WithDependencyCheck(name string, check DependencyCheckFunc, timeout, cacheTTL string) *MonigoBuilder {
+	b.config.DependencyChecks = append(b.config.DependencyChecks, DependencyCheck{Name: name, Check: check, Timeout: timeout, CacheTTL: cacheTTL})
+	return b
+}
+
+// WithAlertRule adds rules firing an alert when a stored metric crosses a threshold for a duration
+func (b *MonigoBuilder) WithAlertRule(rules ...AlertRule) *MonigoBuilder {
+	b.config.AlertRules = append(b.config.AlertRules, rules...)
+	return b
+}
+
+// WithAlertRulesFile loads more alert rules from a JSON or YAML (.yaml, .yml) file listing them under "rules"
+func (b *MonigoBuilder) WithAlertRulesFile(path string) *MonigoBuilder {
+	b.config.AlertRulesFile = path
+	return b
+}
+
+// WithAlertEvaluationInterval sets how often the alert rules are evaluated (default "30s")
+func (b *MonigoBuilder) WithAlertEvaluationInterval(interval string) *MonigoBuilder {
+	b.config.AlertEvaluationInterval = interval
+	return b
+}
+
+// WithNotifier adds notifiers the firing and resolved alerts are delivered to, e.g. NewSlackNotifier(SlackConfig{URL: ...})
+func (b *MonigoBuilder) WithNotifier(notifiers ...Notifier) *MonigoBuilder {
+	b.config.Notifiers = append(b.config.Notifiers, notifiers...)
+	return b
+}
+
+// WithAlertGrouping sets alert labels ("rule" for the rule name) whose alerts are notified together; by default each rule is notified on its own
+func (b *MonigoBuilder) WithAlertGrouping(labels ...string) *MonigoBuilder {
+	b.config.AlertGroupBy = append(b.config.AlertGroupBy, labels...)
+	return b
+}
+
+// WithAlertRepeatInterval sets how long a still firing group waits before it is notified again (default "4h")
+func (b *MonigoBuilder) WithAlertRepeatInterval(interval string) *MonigoBuilder {
+	b.config.AlertRepeatInterval = interval
+	return b
+}
+
+// WithAnomalyDetection enables learning baselines of CPU, memory, goroutines and GC pause from their history and recording the values far from them; metrics adds more series, e.g. the p99 of a latency histogram
+func (b *MonigoBuilder) WithAnomalyDetection(metrics ...AnomalyMetric) *MonigoBuilder {
+	b.config.AnomalyDetection = true
+	b.config.AnomalyMetrics = append(b.config.AnomalyMetrics, metrics...)
+	return b
+}
+
+// WithAnomalyThreshold sets the absolute z-score from which a value is anomalous (default 3)
+func (b *MonigoBuilder) WithAnomalyThreshold(threshold float64) *MonigoBuilder {
+	b.config.AnomalyThreshold = threshold
+	return b
+}
+
+// WithAnomalyWindow sets the history the anomaly baselines are learned from (default "168h")
+func (b *MonigoBuilder) WithAnomalyWindow(window string) *MonigoBuilder {
+	b.config.AnomalyWindow = window
+	return b
+}
+
+// WithSLO adds service level objectives whose attainment, error budget and burn rates are tracked over a rolling window
+func (b *MonigoBuilder) WithSLO(slos ...SLO) *MonigoBuilder {
+	b.config.SLOs = append(b.config.SLOs, slos...)
+	return b
+}
+
+// WithDisabledCollectors sets collectors which are not run (e.g. "disk", "network")
+func (b *MonigoBuilder) WithDisabledCollectors(names ...string) *MonigoBuilder {
+	b.config.DisabledCollectors = append(b.config.DisabledCollectors, names...)
+	return b
+}
+
+// WithCollectorInterval sets how often the named collector runs (e.g. "disk", "1m"); by default collectors run at the collection interval and "goroutines" every minute
+func (b *MonigoBuilder) WithCollectorInterval(name, interval string) *MonigoBuilder {
+	if b.config.CollectorIntervals == nil {
+		b.config.CollectorIntervals = make(map[string]string)
+	}
+	b.config.CollectorIntervals[name] = interval
+	return b
+}
+
+// WithCollectorTimeout sets how long a single run of the named collector may take (e.g. "network", "2s")
+func (b *MonigoBuilder) WithCollectorTimeout(name, timeout string) *MonigoBuilder {
+	if b.config.CollectorTimeouts == nil {
+		b.config.CollectorTimeouts = make(map[string]string)
+	}
+	b.config.CollectorTimeouts[name] = timeout
+	return b
+}
+
+// WithGoroutineLeakDetection sets the window over which a stack signature's goroutine count must keep growing, by at least minGrowth, to be reported as a suspected leak
+func (b *MonigoBuilder) WithGoroutineLeakDetection(window string, minGrowth int) *MonigoBuilder {
+	b.config.GoroutineLeakWindow = window
+	b.config.GoroutineLeakMinGrowth = minGrowth
+	return b
+}
+
 // WithDashboardMiddleware sets the dashboard middleware
 func (b *MonigoBuilder) WithDashboardMiddleware(middleware ...func(http.Handler) http.Handler) *MonigoBuilder {
 	b.config.DashboardMiddleware = middleware
@@ -97,12 +271,36 @@ func (b *MonigoBuilder) WithSamplingRate(rate int) *MonigoBuilder {
 	return b
 }
 
-// WithStorageType sets the storage type ("disk" or "memory")
+// WithStorageType sets the storage type ("disk", "memory" or "bolt", a single-file database)
 func (b *MonigoBuilder) WithStorageType(storageType string) *MonigoBuilder {
 	b.config.StorageType = storageType
 	return b
 }
 
+// WithMaxMemoryPoints sets the number of points the "memory" storage keeps at most (default 1000000)
+func (b *MonigoBuilder) WithMaxMemoryPoints(n int) *MonigoBuilder {
+	b.config.MaxMemoryPoints = n
+	return b
+}
+
+// WithRollupTiers sets the resolutions the metrics are rolled up to and how long each is kept (default 1m for 3d, 10m for 30d and 1h for 365d)
+func (b *MonigoBuilder) WithRollupTiers(tiers ...RollupTier) *MonigoBuilder {
+	b.config.RollupTiers = tiers
+	return b
+}
+
+// WithRemoteWrite sets a Prometheus remote write receiver the stored metrics are also sent to, in batches queued on disk until sent
+func (b *MonigoBuilder) WithRemoteWrite(config RemoteWriteConfig) *MonigoBuilder {
+	b.config.RemoteWrite = &config
+	return b
+}
+
+// WithDisableRollups sets whether the metrics are only stored at the sync frequency, without rollups
+func (b *MonigoBuilder) WithDisableRollups(disable bool) *MonigoBuilder {
+	b.config.DisableRollups = disable
+	return b
+}
+
 // WithHeadless sets whether the dashboard should be started
 func (b *MonigoBuilder) WithHeadless(headless bool) *MonigoBuilder {
 	b.config.Headless = headless
@@ -121,6 +319,21 @@ func (b *MonigoBuilder) WithOTelHeaders(headers map[string]string) *MonigoBuilde
 	return b
 }
 
+// WithContinuousProfiling captures a CPU profile of cpuDuration and a heap profile every interval, e.g. ("10m", "10s")
+func (b *MonigoBuilder) WithContinuousProfiling(interval, cpuDuration string) *MonigoBuilder {
+	b.config.ContinuousProfilingInterval = interval
+	b.config.ContinuousProfilingCPUDuration = cpuDuration
+	return b
+}
+
+// WithIncidentCapture captures a CPU profile of cpuDuration, a heap profile and a goroutine profile when a health threshold is crossed, at most once per cooldown, e.g. ("15m", "10s")
+func (b *MonigoBuilder) WithIncidentCapture(cooldown, cpuDuration string) *MonigoBuilder {
+	b.config.IncidentCapture = true
+	b.config.IncidentCooldown = cooldown
+	b.config.IncidentCPUProfileDuration = cpuDuration
+	return b
+}
+
 // WithLogLevel sets the log level for monigo's structured logger
 func (b *MonigoBuilder) WithLogLevel(level slog.Level) *MonigoBuilder {
 	logger.Init(level)
@@ -145,8 +358,216 @@ func (b *MonigoBuilder) Build() *Monigo {
 	if b.config.SamplingRate < 0 {
 		panic("[MoniGo] Build() failed: SamplingRate must be >= 0")
 	}
-	if b.config.StorageType != "" && b.config.StorageType != "disk" && b.config.StorageType != "memory" {
-		panic("[MoniGo] Build() failed: StorageType must be 'disk' or 'memory'")
+	if b.config.StorageType != "" && b.config.StorageType != "disk" && b.config.StorageType != "memory" && b.config.StorageType != "bolt" {
+		panic("[MoniGo] Build() failed: StorageType must be 'disk', 'memory' or 'bolt'")
+	}
+	if b.config.MaxMemoryPoints < 0 {
+		panic("[MoniGo] Build() failed: MaxMemoryPoints must be >= 0")
+	}
+	if b.config.CollectionInterval != "" {
+		if d, err := time.ParseDuration(b.config.CollectionInterval); err != nil || d <= 0 {
+			panic("[MoniGo] Build() failed: CollectionInterval must be a positive duration, e.g. '15s'")
+		}
+	}
+	if b.config.GoroutineLeakWindow != "" {
+		if d, err := time.ParseDuration(b.config.GoroutineLeakWindow); err != nil || d <= 0 {
+			panic("[MoniGo] Build() failed: GoroutineLeakWindow must be a positive duration, e.g. '30m'")
+		}
+	}
+	if b.config.GoroutineLeakMinGrowth < 0 {
+		panic("[MoniGo] Build() failed: GoroutineLeakMinGrowth must be >= 0")
+	}
+	if b.config.HealthHysteresis < 0 || b.config.HealthHysteresis >= 50 {
+		panic("[MoniGo] Build() failed: HealthHysteresis must be between 0 and 50 percentage points")
 	}
+	if b.config.ContinuousProfilingInterval != "" {
+		if d, err := time.ParseDuration(b.config.ContinuousProfilingInterval); err != nil || d <= 0 {
+			panic("[MoniGo] Build() failed: ContinuousProfilingInterval must be a positive duration, e.g. '10m'")
+		}
+	}
+	if b.config.ContinuousProfilingCPUDuration != "" {
+		if d, err := time.ParseDuration(b.config.ContinuousProfilingCPUDuration); err != nil || d <= 0 || d > core.MaxProfileDuration {
+			panic("[MoniGo] Build() failed: ContinuousProfilingCPUDuration must be a positive duration of at most 5m, e.g. '10s'")
+		}
+	}
+	if b.config.IncidentCooldown != "" {
+		if d, err := time.ParseDuration(b.config.IncidentCooldown); err != nil || d <= 0 {
+			panic("[MoniGo] Build() failed: IncidentCooldown must be a positive duration, e.g. '15m'")
+		}
+	}
+	if b.config.IncidentCPUProfileDuration != "" {
+		if d, err := time.ParseDuration(b.config.IncidentCPUProfileDuration); err != nil || d <= 0 || d > core.MaxProfileDuration {
+			panic("[MoniGo] Build() failed: IncidentCPUProfileDuration must be a positive duration of at most 5m, e.g. '10s'")
+		}
+	}
+	b.validateCollectors()
+	b.validateHealthChecks()
+	b.validateDependencyChecks()
+	b.validateAlertRules()
+	b.validateAnomalyDetection()
+	b.validateSLOs()
+	b.validateRollupTiers()
+	b.validateRemoteWrite()
 	return b.config
 }
+
+// validateCollectors panics if a collector setting refers to an unknown collector or has an invalid duration.
+func (b *MonigoBuilder) validateCollectors() {
+	known := make(map[string]bool)
+	for _, name := range core.BuiltinCollectorNames() {
+		known[name] = true
+	}
+	for _, c := range b.config.Collectors {
+		if c == nil || c.Name() == "" {
+			panic("[MoniGo] Build() failed: custom collectors must have a name")
+		}
+		if known[c.Name()] {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: collector %q is already registered", c.Name()))
+		}
+		known[c.Name()] = true
+	}
+
+	for _, name := range b.config.DisabledCollectors {
+		if !known[name] {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: unknown collector %q in DisabledCollectors", name))
+		}
+	}
+	for setting, values := range map[string]map[string]string{
+		"CollectorIntervals": b.config.CollectorIntervals,
+		"CollectorTimeouts":  b.config.CollectorTimeouts,
+	} {
+		for name, value := range values {
+			if !known[name] {
+				panic(fmt.Sprintf("[MoniGo] Build() failed: unknown collector %q in %s", name, setting))
+			}
+			if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+				panic(fmt.Sprintf("[MoniGo] Build() failed: %s[%q] must be a positive duration, e.g. '30s'", setting, name))
+			}
+		}
+	}
+}
+
+// validateHealthChecks panics if a health check setting refers to an unknown check or has an invalid weight.
+func (b *MonigoBuilder) validateHealthChecks() {
+	known := map[string]bool{
+		core.HealthCheckCPU: true, core.HealthCheckMemory: true, core.HealthCheckGoroutines: true,
+		core.HealthCheckFDs: true, core.HealthCheckThreads: true, core.HealthCheckSystemCPU: true,
+		core.HealthCheckSystemMemory: true,
+	}
+	for _, c := range b.config.HealthChecks {
+		if c == nil || c.Name() == "" {
+			panic("[MoniGo] Build() failed: custom health checks must have a name")
+		}
+		if known[c.Name()] {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: health check %q is already registered", c.Name()))
+		}
+		known[c.Name()] = true
+	}
+
+	for name, weight := range b.config.HealthCheckWeights {
+		if !known[name] {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: unknown health check %q in HealthCheckWeights", name))
+		}
+		if weight <= 0 {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: HealthCheckWeights[%q] must be positive", name))
+		}
+	}
+	for _, name := range b.config.CriticalHealthChecks {
+		if !known[name] {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: unknown health check %q in CriticalHealthChecks", name))
+		}
+	}
+}
+
+// validateDependencyChecks panics if a dependency check has no name or function, a duplicate name or an invalid duration.
+func (b *MonigoBuilder) validateDependencyChecks() {
+	names := make(map[string]bool)
+	for _, d := range b.config.DependencyChecks {
+		if d.Name == "" || d.Check == nil {
+			panic("[MoniGo] Build() failed: dependency checks must have a name and a check function")
+		}
+		if names[d.Name] {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: dependency check %q is already registered", d.Name))
+		}
+		names[d.Name] = true
+
+		for setting, value := range map[string]string{"Timeout": d.Timeout, "CacheTTL": d.CacheTTL} {
+			if value == "" {
+				continue
+			}
+			if v, err := time.ParseDuration(value); err != nil || v <= 0 {
+				panic(fmt.Sprintf("[MoniGo] Build() failed: %s of dependency check %q must be a positive duration, e.g. '2s'", setting, d.Name))
+			}
+		}
+	}
+}
+
+// validateAlertRules panics if an alert rule or notifier is invalid, the rules file cannot be loaded or an interval is invalid.
+func (b *MonigoBuilder) validateAlertRules() {
+	if b.config.AlertEvaluationInterval != "" {
+		if d, err := time.ParseDuration(b.config.AlertEvaluationInterval); err != nil || d <= 0 {
+			panic("[MoniGo] Build() failed: AlertEvaluationInterval must be a positive duration, e.g. '30s'")
+		}
+	}
+
+	if b.config.AlertRepeatInterval != "" {
+		if d, err := time.ParseDuration(b.config.AlertRepeatInterval); err != nil || d <= 0 {
+			panic("[MoniGo] Build() failed: AlertRepeatInterval must be a positive duration, e.g. '4h'")
+		}
+	}
+	if err := alerting.ValidateNotifiers(b.config.Notifiers); err != nil {
+		panic(fmt.Sprintf("[MoniGo] Build() failed: %v", err))
+	}
+
+	rules := b.config.AlertRules
+	if b.config.AlertRulesFile != "" {
+		fileRules, err := alerting.LoadRulesFile(b.config.AlertRulesFile)
+		if err != nil {
+			panic(fmt.Sprintf("[MoniGo] Build() failed: %v", err))
+		}
+		rules = append(append([]AlertRule{}, rules...), fileRules...)
+	}
+	if err := alerting.ValidateRules(rules); err != nil {
+		panic(fmt.Sprintf("[MoniGo] Build() failed: %v", err))
+	}
+}
+
+// validateAnomalyDetection panics if an anomaly metric, the threshold or the window is invalid.
+func (b *MonigoBuilder) validateAnomalyDetection() {
+	if b.config.AnomalyThreshold < 0 {
+		panic("[MoniGo] Build() failed: AnomalyThreshold must be positive")
+	}
+	if b.config.AnomalyWindow != "" {
+		if d, err := time.ParseDuration(b.config.AnomalyWindow); err != nil || d <= 0 {
+			panic("[MoniGo] Build() failed: AnomalyWindow must be a positive duration, e.g. '168h'")
+		}
+	}
+	metrics := append(append([]AnomalyMetric{}, anomaly.DefaultMetrics...), b.config.AnomalyMetrics...)
+	if err := anomaly.ValidateMetrics(metrics); err != nil {
+		panic(fmt.Sprintf("[MoniGo] Build() failed: %v", err))
+	}
+}
+
+// validateSLOs panics if an SLO is invalid or defined more than once.
+func (b *MonigoBuilder) validateSLOs() {
+	if err := slo.ValidateSLOs(b.config.SLOs); err != nil {
+		panic(fmt.Sprintf("[MoniGo] Build() failed: %v", err))
+	}
+}
+
+// validateRollupTiers panics if a rollup tier is invalid or its resolution is configured more than once.
+func (b *MonigoBuilder) validateRollupTiers() {
+	if err := timeseries.ValidateRollupTiers(b.config.RollupTiers); err != nil {
+		panic(fmt.Sprintf("[MoniGo] Build() failed: %v", err))
+	}
+}
+
+// validateRemoteWrite panics if the remote write URL, sizes or durations are invalid.
+func (b *MonigoBuilder) validateRemoteWrite() {
+	if b.config.RemoteWrite == nil {
+		return
+	}
+	if err := timeseries.ValidateRemoteWrite(*b.config.RemoteWrite); err != nil {
+		panic(fmt.Sprintf("[MoniGo] Build() failed: %v", err))
+	}
+}
@@ -1,7 +1,12 @@
 package monigo
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/iyashjayesh/monigo/models"
 )
 
 func TestBuilderValidBuild(t *testing.T) {
@@ -56,6 +61,16 @@ func TestBuilderInvalidStorageType(t *testing.T) {
 	NewBuilder().WithServiceName("test").WithStorageType("redis").Build()
 }
 
+func TestBuilderInvalidMaxMemoryPoints(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for negative max memory points")
+		}
+	}()
+
+	NewBuilder().WithServiceName("test").WithStorageType("memory").WithMaxMemoryPoints(-1).Build()
+}
+
 func TestBuilderDefaultStorageType(t *testing.T) {
 	// Empty storage type should be allowed (defaults at runtime)
 	m := NewBuilder().WithServiceName("test").Build()
@@ -93,3 +108,298 @@ func TestBuilderAllOptions(t *testing.T) {
 		t.Errorf("expected '/custom/api', got %q", m.CustomBaseAPIPath)
 	}
 }
+
+func TestBuilderInvalidCollectionInterval(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for invalid collection interval")
+		}
+	}()
+
+	NewBuilder().WithServiceName("test").WithCollectionInterval("soon").Build()
+}
+
+func TestBuilderCollectorSettings(t *testing.T) {
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithDisabledCollectors("disk").
+		WithCollectorInterval("network", "1m").
+		WithCollectorTimeout("cpu", "2s").
+		Build()
+
+	if len(cfg.DisabledCollectors) != 1 || cfg.DisabledCollectors[0] != "disk" {
+		t.Errorf("unexpected disabled collectors: %v", cfg.DisabledCollectors)
+	}
+	if cfg.CollectorIntervals["network"] != "1m" {
+		t.Errorf("expected network interval 1m, got %q", cfg.CollectorIntervals["network"])
+	}
+	if cfg.CollectorTimeouts["cpu"] != "2s" {
+		t.Errorf("expected cpu timeout 2s, got %q", cfg.CollectorTimeouts["cpu"])
+	}
+}
+
+func TestBuilderGoroutineLeakDetection(t *testing.T) {
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithGoroutineLeakDetection("1h", 25).
+		WithCollectorInterval("goroutines", "30s").
+		Build()
+
+	if cfg.GoroutineLeakWindow != "1h" || cfg.GoroutineLeakMinGrowth != 25 {
+		t.Errorf("unexpected leak detection settings: %q, %d", cfg.GoroutineLeakWindow, cfg.GoroutineLeakMinGrowth)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for invalid leak window")
+		}
+	}()
+	NewBuilder().WithServiceName("test").WithGoroutineLeakDetection("soon", 10).Build()
+}
+
+func TestBuilderContinuousProfiling(t *testing.T) {
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithContinuousProfiling("15m", "20s").
+		Build()
+
+	if cfg.ContinuousProfilingInterval != "15m" || cfg.ContinuousProfilingCPUDuration != "20s" {
+		t.Errorf("unexpected continuous profiling settings: %q, %q", cfg.ContinuousProfilingInterval, cfg.ContinuousProfilingCPUDuration)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for a CPU duration above the maximum")
+		}
+	}()
+	NewBuilder().WithServiceName("test").WithContinuousProfiling("1h", "10m").Build()
+}
+
+func TestBuilderIncidentCapture(t *testing.T) {
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithIncidentCapture("30m", "5s").
+		Build()
+
+	if !cfg.IncidentCapture || cfg.IncidentCooldown != "30m" || cfg.IncidentCPUProfileDuration != "5s" {
+		t.Errorf("unexpected incident capture settings: %v, %q, %q", cfg.IncidentCapture, cfg.IncidentCooldown, cfg.IncidentCPUProfileDuration)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for an invalid cooldown")
+		}
+	}()
+	NewBuilder().WithServiceName("test").WithIncidentCapture("later", "").Build()
+}
+
+func TestBuilderUnknownCollector(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for unknown collector")
+		}
+	}()
+
+	NewBuilder().WithServiceName("test").WithDisabledCollectors("gpu").Build()
+}
+
+func TestBuilderHealthChecks(t *testing.T) {
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithHealthCheck(namedHealthCheck("database")).
+		WithHealthCheckWeight("database", 3).
+		WithHealthCheckWeight("memory", 2).
+		WithCriticalHealthChecks("database").
+		WithHealthHysteresis(3).
+		Build()
+
+	if len(cfg.HealthChecks) != 1 || cfg.HealthCheckWeights["database"] != 3 || cfg.HealthCheckWeights["memory"] != 2 {
+		t.Errorf("unexpected health check settings: %v, %v", cfg.HealthChecks, cfg.HealthCheckWeights)
+	}
+	if len(cfg.CriticalHealthChecks) != 1 || cfg.CriticalHealthChecks[0] != "database" {
+		t.Errorf("unexpected critical health checks: %v", cfg.CriticalHealthChecks)
+	}
+	if cfg.HealthHysteresis != 3 {
+		t.Errorf("expected a health hysteresis of 3, got %v", cfg.HealthHysteresis)
+	}
+
+	for name, build := range map[string]func(){
+		"unknown critical check": func() { NewBuilder().WithServiceName("test").WithCriticalHealthChecks("database").Build() },
+		"non-positive weight":    func() { NewBuilder().WithServiceName("test").WithHealthCheckWeight("cpu", 0).Build() },
+		"built-in name":          func() { NewBuilder().WithServiceName("test").WithHealthCheck(namedHealthCheck("cpu")).Build() },
+		"negative hysteresis":    func() { NewBuilder().WithServiceName("test").WithHealthHysteresis(-1).Build() },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic for %s", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+func TestBuilderDependencyChecks(t *testing.T) {
+	ping := func(context.Context) error { return nil }
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithDependencyCheck("database", ping, "1s", "").
+		WithDependencyCheck("payments", HTTPDependencyCheck("http://payments/healthz"), "", "10s").
+		Build()
+
+	if len(cfg.DependencyChecks) != 2 || cfg.DependencyChecks[0].Timeout != "1s" || cfg.DependencyChecks[1].CacheTTL != "10s" {
+		t.Errorf("unexpected dependency checks: %+v", cfg.DependencyChecks)
+	}
+
+	for name, build := range map[string]func(){
+		"duplicate name": func() {
+			NewBuilder().WithServiceName("test").WithDependencyCheck("db", ping, "", "").WithDependencyCheck("db", ping, "", "").Build()
+		},
+		"missing function": func() { NewBuilder().WithServiceName("test").WithDependencyCheck("db", nil, "", "").Build() },
+		"invalid timeout":  func() { NewBuilder().WithServiceName("test").WithDependencyCheck("db", ping, "soon", "").Build() },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic for %s", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+func TestBuilderAlertRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  - name: high-cpu\n    metric: service_cpu_load\n    op: \">\"\n    threshold: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithAlertRule(AlertRule{Name: "goroutines", Metric: "goroutines", Op: ">", Threshold: 1000, For: "5m"}).
+		WithAlertRulesFile(path).
+		WithAlertEvaluationInterval("1m").
+		WithNotifier(NewSlackNotifier(SlackConfig{URL: "https://hooks.slack.com/services/x"})).
+		WithAlertGrouping("severity").
+		WithAlertRepeatInterval("1h").
+		Build()
+	if len(cfg.AlertRules) != 1 || cfg.AlertRulesFile != path || cfg.AlertEvaluationInterval != "1m" {
+		t.Errorf("unexpected alerting settings: %+v, %q, %q", cfg.AlertRules, cfg.AlertRulesFile, cfg.AlertEvaluationInterval)
+	}
+	if len(cfg.Notifiers) != 1 || len(cfg.AlertGroupBy) != 1 || cfg.AlertRepeatInterval != "1h" {
+		t.Errorf("unexpected notification settings: %v, %v, %q", cfg.Notifiers, cfg.AlertGroupBy, cfg.AlertRepeatInterval)
+	}
+
+	for name, build := range map[string]func(){
+		"invalid operator": func() {
+			NewBuilder().WithServiceName("test").WithAlertRule(AlertRule{Name: "a", Metric: "goroutines", Op: "=>"}).Build()
+		},
+		"duplicate across file": func() {
+			NewBuilder().WithServiceName("test").WithAlertRule(AlertRule{Name: "high-cpu", Metric: "goroutines", Op: ">"}).WithAlertRulesFile(path).Build()
+		},
+		"missing file":     func() { NewBuilder().WithServiceName("test").WithAlertRulesFile(path + ".missing").Build() },
+		"invalid interval": func() { NewBuilder().WithServiceName("test").WithAlertEvaluationInterval("often").Build() },
+		"invalid notifier": func() { NewBuilder().WithServiceName("test").WithNotifier(NewWebhookNotifier(WebhookConfig{})).Build() },
+		"invalid repeat":   func() { NewBuilder().WithServiceName("test").WithAlertRepeatInterval("0s").Build() },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic for %s", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+func TestBuilderAnomalyDetection(t *testing.T) {
+	latency := AnomalyMetric{Name: "latency_p99", Metric: "http_latency", Match: map[string]string{"collector": "custom", "quantile": "0.99"}}
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithAnomalyDetection(latency).
+		WithAnomalyThreshold(4).
+		WithAnomalyWindow("72h").
+		Build()
+	if !cfg.AnomalyDetection || len(cfg.AnomalyMetrics) != 1 || cfg.AnomalyThreshold != 4 || cfg.AnomalyWindow != "72h" {
+		t.Errorf("unexpected anomaly detection settings: %v, %+v, %v, %q", cfg.AnomalyDetection, cfg.AnomalyMetrics, cfg.AnomalyThreshold, cfg.AnomalyWindow)
+	}
+
+	for name, build := range map[string]func(){
+		"negative threshold": func() { NewBuilder().WithServiceName("test").WithAnomalyThreshold(-1).Build() },
+		"invalid window":     func() { NewBuilder().WithServiceName("test").WithAnomalyWindow("a week").Build() },
+		"missing metric":     func() { NewBuilder().WithServiceName("test").WithAnomalyDetection(AnomalyMetric{Name: "a"}).Build() },
+		"core metric twice": func() {
+			NewBuilder().WithServiceName("test").WithAnomalyDetection(AnomalyMetric{Metric: "goroutines"}).Build()
+		},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic for %s", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+func TestBuilderSLOs(t *testing.T) {
+	cfg := NewBuilder().
+		WithServiceName("test").
+		WithSLO(
+			SLO{Name: "checkout", Type: "availability", Objective: 99.9, Metric: "http_requests_total", ErrorMatch: map[string]string{"code": "5.."}},
+			SLO{Name: "checkout-latency", Type: "latency", Objective: 99, Window: "7d", Metric: "http_request_duration_seconds", Threshold: 0.5},
+		).
+		Build()
+	if len(cfg.SLOs) != 2 {
+		t.Errorf("expected 2 SLOs, got %+v", cfg.SLOs)
+	}
+
+	for name, build := range map[string]func(){
+		"invalid objective": func() {
+			NewBuilder().WithServiceName("test").WithSLO(SLO{Name: "a", Type: "availability", Objective: 100, Function: "main.f"}).Build()
+		},
+		"duplicate name": func() {
+			s := SLO{Name: "a", Type: "availability", Objective: 99, Function: "main.f"}
+			NewBuilder().WithServiceName("test").WithSLO(s, s).Build()
+		},
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic for %s", name)
+				}
+			}()
+			build()
+		}()
+	}
+}
+
+type namedHealthCheck string
+
+func (c namedHealthCheck) Name() string { return string(c) }
+
+func (namedHealthCheck) Check(context.Context, *models.ServiceStats) models.HealthCheckResult {
+	return models.HealthCheckResult{Score: 100}
+}
+
+func TestBuilderInvalidRollupTiers(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for an invalid rollup retention")
+		}
+	}()
+	NewBuilder().WithServiceName("test").WithRollupTiers(RollupTier{Resolution: "1h", Retention: "0d"}).Build()
+}
+
+func TestBuilderInvalidRemoteWrite(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for an invalid remote write URL")
+		}
+	}()
+	NewBuilder().WithServiceName("test").WithRemoteWrite(RemoteWriteConfig{URL: "prometheus:9090"}).Build()
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// runtimeEnvVars are the environment variables read by the Go runtime. Other variables are
+// never reported, they may hold secrets.
+var runtimeEnvVars = []string{"GOGC", "GOMEMLIMIT", "GOMAXPROCS", "GODEBUG", "GOTRACEBACK", "GORACE"}
+
+var (
+	buildInfoOnce sync.Once
+	buildInfo     models.BuildInfo
+)
+
+// readBuildInfo converts the build information embedded in the binary.
+func readBuildInfo() models.BuildInfo {
+	info := models.BuildInfo{
+		GoVersion:    runtime.Version(),
+		Settings:     []models.BuildSetting{},
+		Dependencies: []models.ModuleVersion{},
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Available = true
+	info.GoVersion = bi.GoVersion
+	info.Path = bi.Path
+	info.Main = moduleVersion(&bi.Main)
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs":
+			info.VCS.System = s.Value
+		case "vcs.revision":
+			info.VCS.Revision = s.Value
+		case "vcs.time":
+			info.VCS.Time = s.Value
+		case "vcs.modified":
+			info.VCS.Modified = s.Value == "true"
+		}
+		info.Settings = append(info.Settings, models.BuildSetting{Key: s.Key, Value: s.Value})
+	}
+
+	for _, dep := range bi.Deps {
+		info.Dependencies = append(info.Dependencies, moduleVersion(dep))
+	}
+	sort.Slice(info.Dependencies, func(i, j int) bool {
+		return info.Dependencies[i].Path < info.Dependencies[j].Path
+	})
+	return info
+}
+
+// moduleVersion converts a module recorded in the build information.
+func moduleVersion(m *debug.Module) models.ModuleVersion {
+	v := models.ModuleVersion{Path: m.Path, Version: m.Version, Sum: m.Sum}
+	if m.Replace != nil {
+		replace := moduleVersion(m.Replace)
+		v.Replace = &replace
+	}
+	return v
+}
+
+// GetBuildInfo returns the build of the running binary and its runtime environment.
+// The build information is read once; GOMAXPROCS and the environment are read on every call.
+func GetBuildInfo() models.BuildInfo {
+	buildInfoOnce.Do(func() {
+		buildInfo = readBuildInfo()
+	})
+
+	info := buildInfo
+	info.Runtime = models.RuntimeInfo{
+		GOOS:        runtime.GOOS,
+		GOARCH:      runtime.GOARCH,
+		Compiler:    runtime.Compiler,
+		GOMAXPROCS:  runtime.GOMAXPROCS(0),
+		NumCPU:      runtime.NumCPU(),
+		GODEBUG:     os.Getenv("GODEBUG"),
+		Environment: map[string]string{},
+	}
+	for _, name := range runtimeEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			info.Runtime.Environment[name] = value
+		}
+	}
+	return info
+}
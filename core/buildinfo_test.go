@@ -0,0 +1,33 @@
+package core
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	t.Setenv("GOGC", "150")
+	t.Setenv("MONIGO_TEST_SECRET", "hidden")
+
+	info := GetBuildInfo()
+	if !info.Available {
+		t.Fatal("expected build information in a test binary")
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("expected Go version %s, got %s", runtime.Version(), info.GoVersion)
+	}
+	if info.Runtime.GOOS != runtime.GOOS || info.Runtime.GOARCH != runtime.GOARCH || info.Runtime.GOMAXPROCS < 1 {
+		t.Errorf("unexpected runtime info: %+v", info.Runtime)
+	}
+	if info.Runtime.Environment["GOGC"] != "150" {
+		t.Errorf("expected GOGC in the environment, got %v", info.Runtime.Environment)
+	}
+	if _, ok := info.Runtime.Environment["MONIGO_TEST_SECRET"]; ok {
+		t.Error("expected only Go runtime variables in the environment")
+	}
+	for i := 1; i < len(info.Dependencies); i++ {
+		if info.Dependencies[i-1].Path > info.Dependencies[i].Path {
+			t.Fatalf("expected dependencies sorted by path, got %s before %s", info.Dependencies[i-1].Path, info.Dependencies[i].Path)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// Names of the built-in collectors.
+const (
+	CollectorLoad       = "load"
+	CollectorMemory     = "memory"
+	CollectorCPU        = "cpu"
+	CollectorMemStats   = "memstats"
+	CollectorNetwork    = "network"
+	CollectorDisk       = "disk"
+	CollectorProcess    = "process"
+	CollectorCustom     = "custom"
+	CollectorGoroutines = "goroutines"
+	CollectorGC         = "gc"
+)
+
+// DefaultCollectorTimeout is the default time a single collector run may take before its result is discarded.
+const DefaultCollectorTimeout = 10 * time.Second
+
+// Collector is a source of metrics sampled alongside the built-in statistics.
+// The registry never runs a collector concurrently with itself.
+type Collector interface {
+	// Name uniquely identifies the collector and is stored as the "collector" label.
+	Name() string
+	// Collect returns the current values. ctx is cancelled once the collector's timeout elapses.
+	Collect(ctx context.Context) ([]models.CollectorMetric, error)
+}
+
+// collectFunc gathers a result and returns a function applying it to a stats snapshot.
+type collectFunc func(ctx context.Context) (func(*models.ServiceStats), error)
+
+// collectorEntry is a registered collector along with its configuration and latest result.
+type collectorEntry struct {
+	name            string
+	builtin         bool
+	collect         collectFunc
+	config          models.CollectorConfig
+	defaultInterval time.Duration // Used when config.Interval is zero; zero uses the sampler interval
+
+	running      bool
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastErr      error
+	metrics      []models.CollectorMetric
+	apply        func(*models.ServiceStats)
+}
+
+var (
+	collectorsMu sync.Mutex
+	collectors   = builtinCollectors()
+)
+
+// BuiltinCollectorNames returns the names of the built-in collectors.
+func BuiltinCollectorNames() []string {
+	var names []string
+	for _, e := range builtinCollectors() {
+		names = append(names, e.name)
+	}
+	return names
+}
+
+// builtinCollectors returns the collectors filling the fixed sections of ServiceStats.
+func builtinCollectors() []*collectorEntry {
+	builtin := func(name string, collect collectFunc) *collectorEntry {
+		return &collectorEntry{name: name, builtin: true, collect: collect}
+	}
+
+	return []*collectorEntry{
+		builtin(CollectorLoad, func(context.Context) (func(*models.ServiceStats), error) {
+			load := GetLoadStatistics()
+			return func(s *models.ServiceStats) { s.LoadStatistics = load }, nil
+		}),
+		builtin(CollectorMemory, func(context.Context) (func(*models.ServiceStats), error) {
+			memory := GetMemoryStatistics()
+			return func(s *models.ServiceStats) { s.MemoryStatistics = memory }, nil
+		}),
+		builtin(CollectorCPU, func(context.Context) (func(*models.ServiceStats), error) {
+			cpuStats := GetCPUStatistics()
+			return func(s *models.ServiceStats) { s.CPUStatistics = cpuStats }, nil
+		}),
+		builtin(CollectorMemStats, func(context.Context) (func(*models.ServiceStats), error) {
+			memStats := ReadMemStats()
+			return func(s *models.ServiceStats) {
+				s.HeapAllocByService = common.BytesToUnit(memStats.HeapAlloc)
+				s.HeapAllocBySystem = common.BytesToUnit(memStats.HeapSys)
+				s.TotalAllocByService = common.BytesToUnit(memStats.TotalAlloc)
+				s.TotalMemoryByOS = common.BytesToUnit(memStats.Sys)
+				s.HeapAllocByServiceRaw = memStats.HeapAlloc
+				s.HeapAllocBySystemRaw = memStats.HeapSys
+				s.TotalAllocByServiceRaw = memStats.TotalAlloc
+				s.TotalMemoryByOSRaw = memStats.Sys
+			}, nil
+		}),
+		builtin(CollectorNetwork, func(context.Context) (func(*models.ServiceStats), error) {
+			network, err := collectNetworkStatistics()
+			if err != nil {
+				return nil, err
+			}
+			return func(s *models.ServiceStats) {
+				s.NetworkStatistics = network
+				for _, iface := range network.Interfaces {
+					s.NetworkIO.BytesReceived += float64(iface.BytesReceived)
+					s.NetworkIO.BytesSent += float64(iface.BytesSent)
+				}
+			}, nil
+		}),
+		builtin(CollectorDisk, func(context.Context) (func(*models.ServiceStats), error) {
+			readBytes, writeBytes, err := collectDiskIO()
+			if err != nil {
+				return nil, err
+			}
+			return func(s *models.ServiceStats) {
+				s.DiskIO.ReadBytes, s.DiskIO.WriteBytes = readBytes, writeBytes
+			}, nil
+		}),
+		builtin(CollectorProcess, func(context.Context) (func(*models.ServiceStats), error) {
+			process := GetProcessStatistics()
+			return func(s *models.ServiceStats) { s.ProcessStatistics = process }, nil
+		}),
+		builtin(CollectorGC, func(context.Context) (func(*models.ServiceStats), error) {
+			gc := GetGCStatistics()
+			return func(s *models.ServiceStats) { s.GCStatistics = gc }, nil
+		}),
+		builtin(CollectorCustom, func(context.Context) (func(*models.ServiceStats), error) {
+			custom := GetCustomMetrics()
+			return func(s *models.ServiceStats) { s.CustomMetrics = custom }, nil
+		}),
+		{
+			name:            CollectorGoroutines,
+			builtin:         true,
+			defaultInterval: DefaultGoroutinesInterval,
+			collect: func(context.Context) (func(*models.ServiceStats), error) {
+				signatures := sampleGoroutineSignatures()
+				return func(s *models.ServiceStats) { s.GoroutineSignatures = signatures }, nil
+			},
+		},
+	}
+}
+
+// findCollector returns the entry registered under name. Callers must hold collectorsMu.
+func findCollector(name string) *collectorEntry {
+	for _, e := range collectors {
+		if e.name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// RegisterCollector adds a custom collector. Its metrics are sampled with the built-in
+// statistics and exposed under ServiceStats.CollectorMetrics.
+func RegisterCollector(c Collector, config models.CollectorConfig) error {
+	if c == nil || c.Name() == "" {
+		return fmt.Errorf("collector name is required")
+	}
+
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+
+	name := c.Name()
+	if findCollector(name) != nil {
+		return fmt.Errorf("collector %q is already registered", name)
+	}
+
+	collectors = append(collectors, &collectorEntry{
+		name:   name,
+		config: config,
+		collect: func(ctx context.Context) (func(*models.ServiceStats), error) {
+			metrics, err := c.Collect(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return func(s *models.ServiceStats) { s.CollectorMetrics[name] = metrics }, nil
+		},
+	})
+	return nil
+}
+
+// UnregisterCollector removes a custom collector. Built-in collectors can only be disabled.
+func UnregisterCollector(name string) error {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+
+	for i, e := range collectors {
+		if e.name != name {
+			continue
+		}
+		if e.builtin {
+			return fmt.Errorf("built-in collector %q cannot be unregistered", name)
+		}
+		collectors = append(collectors[:i], collectors[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("unknown collector %q", name)
+}
+
+// ConfigureCollector sets whether and how often the named collector runs.
+func ConfigureCollector(name string, config models.CollectorConfig) error {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+
+	e := findCollector(name)
+	if e == nil {
+		return fmt.Errorf("unknown collector %q", name)
+	}
+
+	e.config = config
+	if config.Disabled {
+		e.apply, e.metrics, e.lastErr = nil, nil, nil
+	}
+	return nil
+}
+
+// GetCollectorStatuses returns the configuration and last run of every registered collector.
+func GetCollectorStatuses() []models.CollectorStatus {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+
+	statuses := make([]models.CollectorStatus, 0, len(collectors))
+	for _, e := range collectors {
+		status := models.CollectorStatus{
+			Name:           e.name,
+			Builtin:        e.builtin,
+			Enabled:        !e.config.Disabled,
+			Interval:       e.interval().String(),
+			Timeout:        e.timeout().String(),
+			LastRun:        e.lastRun,
+			LastDurationMs: common.RoundFloat64(float64(e.lastDuration)/float64(time.Millisecond), 3),
+			Metrics:        e.metrics,
+		}
+		if e.lastErr != nil {
+			status.LastError = e.lastErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// minCollectorInterval returns the shortest interval of the enabled collectors, capped at limit.
+func minCollectorInterval(limit time.Duration) time.Duration {
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+
+	for _, e := range collectors {
+		if interval := e.interval(); !e.config.Disabled && interval > 0 && interval < limit {
+			limit = interval
+		}
+	}
+	return limit
+}
+
+// interval returns the configured interval, the collector default or the sampler's collection
+// interval, so a faster collector does not speed up the others. Callers must hold collectorsMu.
+func (e *collectorEntry) interval() time.Duration {
+	if e.config.Interval > 0 {
+		return e.config.Interval
+	}
+	if e.defaultInterval > 0 {
+		return e.defaultInterval
+	}
+	return time.Duration(collectionInterval.Load())
+}
+
+// timeout returns the configured timeout or the default. Callers must hold collectorsMu.
+func (e *collectorEntry) timeout() time.Duration {
+	if e.config.Timeout > 0 {
+		return e.config.Timeout
+	}
+	return DefaultCollectorTimeout
+}
+
+// isDue reports whether the collector should run at now. A tenth of the interval is
+// tolerated so collectors on the sampler interval are not skipped due to ticker jitter.
+// Callers must hold collectorsMu.
+func (e *collectorEntry) isDue(now time.Time) bool {
+	if e.config.Disabled || e.running {
+		return false
+	}
+	interval := e.interval()
+	if e.lastRun.IsZero() {
+		return true
+	}
+	return now.Sub(e.lastRun) >= interval-interval/10
+}
+
+// run collects from the entry, waiting at most its timeout. A collector that overruns
+// keeps its previous result and is not run again until the late call returns.
+// The entry must have been marked as running by the caller.
+func (e *collectorEntry) run(ctx context.Context) {
+	collectorsMu.Lock()
+	timeout := e.timeout()
+	collectorsMu.Unlock()
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		apply, err := e.collect(runCtx)
+
+		collectorsMu.Lock()
+		defer collectorsMu.Unlock()
+		e.running = false
+		e.lastDuration = time.Since(start)
+		if runCtx.Err() != nil && err == nil {
+			// Results arriving after the timeout are stale; keep the previous one.
+			return
+		}
+		e.recordResult(apply, err)
+	}()
+
+	select {
+	case <-done:
+	case <-runCtx.Done():
+		collectorsMu.Lock()
+		e.recordResult(nil, fmt.Errorf("collection aborted after %s: %w", time.Since(start).Round(time.Millisecond), runCtx.Err()))
+		collectorsMu.Unlock()
+	}
+}
+
+// recordResult stores the outcome of a run. Errors are logged once when a collector
+// starts failing rather than on every sample. Callers must hold collectorsMu.
+func (e *collectorEntry) recordResult(apply func(*models.ServiceStats), err error) {
+	if err != nil {
+		if e.lastErr == nil {
+			logger.Log.Warn("collector failed", "collector", e.name, "error", err)
+		} else {
+			logger.Log.Debug("collector failed", "collector", e.name, "error", err)
+		}
+		e.lastErr = err
+		return
+	}
+
+	if e.lastErr != nil {
+		logger.Log.Info("collector recovered", "collector", e.name)
+	}
+	e.lastErr = nil
+	e.apply = apply
+	if !e.builtin {
+		stats := models.ServiceStats{CollectorMetrics: make(map[string][]models.CollectorMetric)}
+		apply(&stats)
+		e.metrics = stats.CollectorMetrics[e.name]
+	}
+}
+
+// collectStats runs the enabled collectors that are due, or all of them when force is set,
+// and assembles a snapshot from their latest results.
+func collectStats(ctx context.Context, force bool) models.ServiceStats {
+	now := time.Now()
+
+	collectorsMu.Lock()
+	var due []*collectorEntry
+	for _, e := range collectors {
+		if e.isDue(now) || (force && !e.config.Disabled && !e.running) {
+			e.running = true
+			e.lastRun = now
+			due = append(due, e)
+		}
+	}
+	collectorsMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, e := range due {
+		wg.Add(1)
+		go func(e *collectorEntry) {
+			defer wg.Done()
+			e.run(ctx)
+		}(e)
+	}
+	wg.Wait()
+
+	stats := models.ServiceStats{CoreStatistics: GetCoreStatistics()}
+
+	collectorsMu.Lock()
+	for _, e := range collectors {
+		if e.config.Disabled || e.apply == nil {
+			continue
+		}
+		if !e.builtin && stats.CollectorMetrics == nil {
+			stats.CollectorMetrics = make(map[string][]models.CollectorMetric)
+		}
+		e.apply(&stats)
+	}
+	collectorsMu.Unlock()
+
+	stats.Health = GetServiceHealth(&stats)
+	return stats
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+type fakeCollector struct {
+	name  string
+	calls atomic.Int32
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeCollector) Name() string { return f.name }
+
+func (f *fakeCollector) Collect(ctx context.Context) ([]models.CollectorMetric, error) {
+	n := f.calls.Add(1)
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []models.CollectorMetric{{Name: "calls", Value: float64(n), Labels: map[string]string{"kind": "fake"}}}, nil
+}
+
+// withoutBuiltinCollectors empties the registry for the duration of the test so only
+// the collectors registered by the test run.
+func withoutBuiltinCollectors(t *testing.T) {
+	t.Helper()
+	collectorsMu.Lock()
+	saved := collectors
+	collectors = nil
+	collectorsMu.Unlock()
+
+	t.Cleanup(func() {
+		collectorsMu.Lock()
+		collectors = saved
+		collectorsMu.Unlock()
+	})
+}
+
+func TestRegisterCollector(t *testing.T) {
+	withoutBuiltinCollectors(t)
+
+	fc := &fakeCollector{name: "fake"}
+	if err := RegisterCollector(fc, models.CollectorConfig{}); err != nil {
+		t.Fatalf("RegisterCollector: %v", err)
+	}
+	if err := RegisterCollector(fc, models.CollectorConfig{}); err == nil {
+		t.Error("expected error registering a duplicate collector")
+	}
+	if err := RegisterCollector(&fakeCollector{}, models.CollectorConfig{}); err == nil {
+		t.Error("expected error registering a collector without a name")
+	}
+
+	stats := collectStats(context.Background(), true)
+	metrics := stats.CollectorMetrics["fake"]
+	if len(metrics) != 1 || metrics[0].Name != "calls" || metrics[0].Value != 1 {
+		t.Fatalf("unexpected collector metrics: %+v", stats.CollectorMetrics)
+	}
+
+	statuses := GetCollectorStatuses()
+	if len(statuses) != 1 || statuses[0].Name != "fake" || statuses[0].Builtin || !statuses[0].Enabled {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if statuses[0].LastRun.IsZero() || len(statuses[0].Metrics) != 1 {
+		t.Errorf("expected last run and metrics in status, got %+v", statuses[0])
+	}
+
+	if err := UnregisterCollector("fake"); err != nil {
+		t.Errorf("UnregisterCollector: %v", err)
+	}
+	if err := UnregisterCollector("fake"); err == nil {
+		t.Error("expected error unregistering an unknown collector")
+	}
+}
+
+func TestUnregisterBuiltinCollector(t *testing.T) {
+	if err := UnregisterCollector(CollectorDisk); err == nil {
+		t.Error("expected error unregistering a built-in collector")
+	}
+}
+
+func TestBuiltinCollectorNames(t *testing.T) {
+	names := BuiltinCollectorNames()
+	collectorsMu.Lock()
+	defer collectorsMu.Unlock()
+	for _, name := range names {
+		if e := findCollector(name); e == nil || !e.builtin {
+			t.Errorf("expected %q to be a registered built-in collector", name)
+		}
+	}
+	if len(names) != 10 {
+		t.Errorf("expected the 10 built-in collectors, got %v", names)
+	}
+}
+
+func TestConfigureUnknownCollector(t *testing.T) {
+	if err := ConfigureCollector("unknown", models.CollectorConfig{}); err == nil {
+		t.Error("expected error configuring an unknown collector")
+	}
+}
+
+func TestCollectorInterval(t *testing.T) {
+	withoutBuiltinCollectors(t)
+
+	fc := &fakeCollector{name: "slow-changing"}
+	if err := RegisterCollector(fc, models.CollectorConfig{Interval: time.Hour}); err != nil {
+		t.Fatalf("RegisterCollector: %v", err)
+	}
+
+	collectStats(context.Background(), false)
+	stats := collectStats(context.Background(), false)
+	if got := fc.calls.Load(); got != 1 {
+		t.Errorf("expected collector to run once within its interval, ran %d times", got)
+	}
+	if len(stats.CollectorMetrics["slow-changing"]) != 1 {
+		t.Error("expected the previous result to be reused while the collector is not due")
+	}
+
+	collectStats(context.Background(), true)
+	if got := fc.calls.Load(); got != 2 {
+		t.Errorf("expected forced collection to run the collector, ran %d times", got)
+	}
+}
+
+func TestDisabledCollector(t *testing.T) {
+	withoutBuiltinCollectors(t)
+
+	fc := &fakeCollector{name: "disabled"}
+	if err := RegisterCollector(fc, models.CollectorConfig{Disabled: true}); err != nil {
+		t.Fatalf("RegisterCollector: %v", err)
+	}
+
+	stats := collectStats(context.Background(), true)
+	if fc.calls.Load() != 0 {
+		t.Error("expected disabled collector not to run")
+	}
+	if _, ok := stats.CollectorMetrics["disabled"]; ok {
+		t.Error("expected no metrics from a disabled collector")
+	}
+	if statuses := GetCollectorStatuses(); statuses[0].Enabled {
+		t.Error("expected collector status to be disabled")
+	}
+}
+
+func TestCollectorTimeout(t *testing.T) {
+	withoutBuiltinCollectors(t)
+
+	fc := &fakeCollector{name: "hanging", delay: 200 * time.Millisecond}
+	if err := RegisterCollector(fc, models.CollectorConfig{Timeout: 20 * time.Millisecond}); err != nil {
+		t.Fatalf("RegisterCollector: %v", err)
+	}
+
+	start := time.Now()
+	stats := collectStats(context.Background(), true)
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("expected collection to stop waiting after the timeout, took %v", elapsed)
+	}
+	if _, ok := stats.CollectorMetrics["hanging"]; ok {
+		t.Error("expected no metrics from a collector that timed out")
+	}
+	if status := GetCollectorStatuses()[0]; status.LastError == "" {
+		t.Error("expected the timeout to be reported in the collector status")
+	}
+
+	// The late call is still running, so the collector is not started again
+	collectStats(context.Background(), true)
+	if got := fc.calls.Load(); got != 1 {
+		t.Errorf("expected overrunning collector not to be restarted, ran %d times", got)
+	}
+}
+
+func TestCollectorErrorKeepsPreviousResult(t *testing.T) {
+	withoutBuiltinCollectors(t)
+
+	fc := &fakeCollector{name: "flaky"}
+	if err := RegisterCollector(fc, models.CollectorConfig{}); err != nil {
+		t.Fatalf("RegisterCollector: %v", err)
+	}
+	collectStats(context.Background(), true)
+
+	fc.err = errors.New("permission denied")
+	stats := collectStats(context.Background(), true)
+	if metrics := stats.CollectorMetrics["flaky"]; len(metrics) != 1 || metrics[0].Value != 1 {
+		t.Errorf("expected the last successful result to be kept, got %+v", metrics)
+	}
+	if status := GetCollectorStatuses()[0]; status.LastError != "permission denied" {
+		t.Errorf("expected last error to be reported, got %q", status.LastError)
+	}
+}
+
+func TestFastCollectorKeepsDefaultCadence(t *testing.T) {
+	withoutBuiltinCollectors(t)
+	saved := collectionInterval.Load()
+	t.Cleanup(func() { collectionInterval.Store(saved) })
+
+	fast := &fakeCollector{name: "fast"}
+	if err := RegisterCollector(fast, models.CollectorConfig{Interval: 20 * time.Millisecond}); err != nil {
+		t.Fatalf("RegisterCollector: %v", err)
+	}
+	// Like the built-in collectors, this one has no interval of its own
+	slow := &fakeCollector{name: "default"}
+	if err := RegisterCollector(slow, models.CollectorConfig{}); err != nil {
+		t.Fatalf("RegisterCollector: %v", err)
+	}
+
+	StartSampler(time.Hour)
+	time.Sleep(200 * time.Millisecond)
+	StopSampler()
+
+	if got := fast.calls.Load(); got < 3 {
+		t.Errorf("expected the fast collector to run on every tick, ran %d times", got)
+	}
+	if got := slow.calls.Load(); got != 1 {
+		t.Errorf("expected the collector without an interval to run once per collection interval, ran %d times", got)
+	}
+	for _, status := range GetCollectorStatuses() {
+		if status.Name == "default" && status.Interval != time.Hour.String() {
+			t.Errorf("expected the collection interval to be reported, got %q", status.Interval)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// ProfileSourceContinuous marks profiles captured by the continuous profiler.
+const ProfileSourceContinuous = "continuous"
+
+// DefaultContinuousCPUDuration is the length of each continuous CPU profile unless configured otherwise.
+const DefaultContinuousCPUDuration = 10 * time.Second
+
+var (
+	continuousMu     sync.Mutex
+	continuousConfig models.ContinuousProfilingConfig
+	continuousCancel context.CancelFunc
+	continuousDone   chan struct{}
+)
+
+// StartContinuousProfiler starts capturing a CPU and a heap profile every interval and
+// removing continuous profiles older than the retention. Calling it again restarts the
+// profiler with the new configuration.
+func StartContinuousProfiler(config *models.ContinuousProfilingConfig) {
+	StopContinuousProfiler()
+	if config.Interval <= 0 {
+		return
+	}
+
+	continuousMu.Lock()
+	defer continuousMu.Unlock()
+
+	continuousConfig = *config
+	if continuousConfig.CPUDuration <= 0 {
+		continuousConfig.CPUDuration = DefaultContinuousCPUDuration
+	}
+	// The CPU profile has to finish before the next capture is due
+	continuousConfig.CPUDuration = min(continuousConfig.CPUDuration, continuousConfig.Interval/2, MaxProfileDuration)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	continuousCancel, continuousDone = cancel, done
+	cfg := continuousConfig
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			captureContinuousProfiles(ctx, &cfg, time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	logger.Log.Info("continuous profiler started", "interval", cfg.Interval, "cpu_duration", cfg.CPUDuration)
+}
+
+// StopContinuousProfiler stops the continuous profiler and waits for a running capture to end.
+// Safe to call multiple times.
+func StopContinuousProfiler() {
+	continuousMu.Lock()
+	cancel, done := continuousCancel, continuousDone
+	continuousCancel, continuousDone = nil, nil
+	continuousConfig = models.ContinuousProfilingConfig{}
+	continuousMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// continuousProfilingInterval returns the continuous profiling interval, 0 when it is not running.
+func continuousProfilingInterval() time.Duration {
+	continuousMu.Lock()
+	defer continuousMu.Unlock()
+	return continuousConfig.Interval
+}
+
+// captureContinuousProfiles stores a CPU and a heap profile, then removes the continuous
+// profiles older than the retention.
+func captureContinuousProfiles(ctx context.Context, config *models.ContinuousProfilingConfig, now time.Time) {
+	requests := []models.ProfileRequest{
+		{Type: ProfileCPU, Duration: config.CPUDuration},
+		{Type: ProfileHeap},
+	}
+	for _, req := range requests {
+		start := time.Now()
+		data, err := CaptureProfile(ctx, req)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, ErrProfileInProgress):
+			// Someone is profiling on demand, the next interval captures again
+			logger.Log.Debug("skipping continuous profile, another is in progress", "type", req.Type)
+			continue
+		case err != nil:
+			logger.Log.Warn("failed to capture continuous profile", "type", req.Type, "error", err)
+			continue
+		}
+		if _, err := StoreProfile(ProfileSourceContinuous, req.Type, start, time.Since(start).Round(time.Millisecond), data); err != nil {
+			logger.Log.Warn("failed to store continuous profile", "type", req.Type, "error", err)
+		}
+	}
+
+	if config.Retention > 0 {
+		if _, err := PruneProfiles(ProfileSourceContinuous, now.Add(-config.Retention)); err != nil {
+			logger.Log.Warn("failed to remove expired profiles", "error", err)
+		}
+	}
+}
+
+// GetProfileTimeline returns the stored profiles captured between query.From and query.To, oldest first.
+// A zero To is now, and a zero From is one day before To.
+func GetProfileTimeline(query models.ProfileQuery) (models.ProfileTimeline, error) {
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-24 * time.Hour)
+	}
+
+	profiles, err := ListProfiles(query)
+	if err != nil {
+		return models.ProfileTimeline{}, err
+	}
+	for i, j := 0, len(profiles)-1; i < j; i, j = i+1, j-1 {
+		profiles[i], profiles[j] = profiles[j], profiles[i]
+	}
+
+	return models.ProfileTimeline{
+		From:     query.From,
+		To:       query.To,
+		Interval: continuousProfilingInterval().Seconds(),
+		Profiles: profiles,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+func TestCaptureContinuousProfiles(t *testing.T) {
+	withProfileStore(t)
+
+	now := time.Now()
+	expired, err := StoreProfile(ProfileSourceContinuous, ProfileHeap, now.Add(-2*time.Hour), 0, []byte("old"))
+	if err != nil {
+		t.Fatalf("failed to store profile: %v", err)
+	}
+	onDemand, err := StoreProfile(ProfileSourceOnDemand, ProfileHeap, now.Add(-2*time.Hour), 0, []byte("kept"))
+	if err != nil {
+		t.Fatalf("failed to store profile: %v", err)
+	}
+
+	config := models.ContinuousProfilingConfig{Interval: time.Minute, CPUDuration: 20 * time.Millisecond, Retention: time.Hour}
+	captureContinuousProfiles(context.Background(), &config, now)
+
+	profiles, err := ListProfiles(models.ProfileQuery{Source: ProfileSourceContinuous})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	types := map[string]bool{}
+	for _, p := range profiles {
+		if p.ID == expired.ID {
+			t.Error("expected the expired continuous profile to be removed")
+		}
+		types[p.Type] = true
+	}
+	if !types[ProfileCPU] || !types[ProfileHeap] || len(profiles) != 2 {
+		t.Errorf("expected a CPU and a heap profile, got %+v", profiles)
+	}
+	if _, _, err := ReadProfile(onDemand.ID); err != nil {
+		t.Errorf("expected on-demand profiles to be kept, got %v", err)
+	}
+}
+
+func TestStartContinuousProfiler(t *testing.T) {
+	withProfileStore(t)
+
+	StartContinuousProfiler(&models.ContinuousProfilingConfig{Interval: time.Hour, CPUDuration: 20 * time.Millisecond})
+	defer StopContinuousProfiler()
+
+	if interval := continuousProfilingInterval(); interval != time.Hour {
+		t.Errorf("expected an interval of 1h while running, got %v", interval)
+	}
+
+	// The first capture runs right away
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		profiles, _ := ListProfiles(models.ProfileQuery{Source: ProfileSourceContinuous})
+		if len(profiles) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a CPU and a heap profile, got %+v", profiles)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	StopContinuousProfiler()
+	if interval := continuousProfilingInterval(); interval != 0 {
+		t.Errorf("expected no interval once stopped, got %v", interval)
+	}
+}
+
+func TestGetProfileTimeline(t *testing.T) {
+	withProfileStore(t)
+
+	now := time.Now()
+	for _, age := range []time.Duration{48 * time.Hour, 3 * time.Hour, time.Hour} {
+		if _, err := StoreProfile(ProfileSourceContinuous, ProfileHeap, now.Add(-age), 0, []byte("heap")); err != nil {
+			t.Fatalf("failed to store profile: %v", err)
+		}
+	}
+
+	timeline, err := GetProfileTimeline(models.ProfileQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(timeline.Profiles) != 2 {
+		t.Fatalf("expected the profiles of the last day, got %+v", timeline.Profiles)
+	}
+	if !timeline.Profiles[0].CreatedAt.Before(timeline.Profiles[1].CreatedAt) {
+		t.Error("expected the timeline oldest first")
+	}
+
+	timeline, _ = GetProfileTimeline(models.ProfileQuery{From: now.Add(-49 * time.Hour), To: now.Add(-2 * time.Hour)})
+	if len(timeline.Profiles) != 2 {
+		t.Errorf("expected the profiles in the range, got %+v", timeline.Profiles)
+	}
+}
@@ -3,9 +3,9 @@ package core
 import (
 	"context"
 	"fmt"
+	"math"
 	"runtime"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/iyashjayesh/monigo/common"
@@ -16,63 +16,11 @@ import (
 	"github.com/shirou/gopsutil/net"
 )
 
-// GetServiceStats collects statistics related to service and system performance.
-func GetServiceStats(_ context.Context) models.ServiceStats {
-	var stats models.ServiceStats
-	stats.CoreStatistics = GetCoreStatistics()
-
-	var wg sync.WaitGroup
-	wg.Add(6)
-
-	// Goroutine to fetch load statistics
-	go func() {
-		defer wg.Done()
-		stats.LoadStatistics = GetLoadStatistics()
-	}()
-
-	// Goroutine to fetch memory statistics
-	go func() {
-		defer wg.Done()
-		stats.MemoryStatistics = GetMemoryStatistics()
-	}()
-
-	// Goroutine to fetch CPU statistics
-	go func() {
-		defer wg.Done()
-		stats.CPUStatistics = GetCPUStatistics()
-	}()
-
-	// Goroutine to fetch memory allocation statistics
-	go func() {
-		defer wg.Done()
-		memStats := ReadMemStats()
-		stats.HeapAllocByService = common.BytesToUnit(memStats.HeapAlloc)
-		stats.HeapAllocBySystem = common.BytesToUnit(memStats.HeapSys)
-		stats.TotalAllocByService = common.BytesToUnit(memStats.TotalAlloc)
-		stats.TotalMemoryByOS = common.BytesToUnit(memStats.Sys)
-		stats.HeapAllocByServiceRaw = memStats.HeapAlloc
-		stats.HeapAllocBySystemRaw = memStats.HeapSys
-		stats.TotalAllocByServiceRaw = memStats.TotalAlloc
-		stats.TotalMemoryByOSRaw = memStats.Sys
-	}()
-
-	// Goroutine to fetch network I/O statistics
-	go func() {
-		defer wg.Done()
-		stats.NetworkIO.BytesReceived, stats.NetworkIO.BytesSent = GetNetworkIO()
-	}()
-
-	// Goroutine to fetch disk I/O statistics
-	go func() {
-		defer wg.Done()
-		stats.DiskIO.ReadBytes, stats.DiskIO.WriteBytes = GetDiskIO()
-	}()
-
-	wg.Wait()
-
-	stats.Health = GetServiceHealth(&stats)
-
-	return stats
+// GetServiceStats runs every enabled collector and assembles the service and system statistics.
+// Collection blocks while CPU usage is sampled; use GetLatestServiceStats to read the
+// snapshot maintained by the background sampler instead.
+func GetServiceStats(ctx context.Context) models.ServiceStats {
+	return collectStats(ctx, true)
 }
 
 // formatUptime returns a formatted string based on the service uptime duration
@@ -112,7 +60,7 @@ func GetCoreStatistics() models.CoreStatistics {
 func GetLoadStatistics() models.LoadStatistics {
 
 	// Fetch CPU load statistics
-	serviceCPULoad, systemCPULoad, totalCPULoad, serviceCPUF, systemCPUF, _ := common.GetCPULoad()
+	serviceCPULoad, systemCPULoad, totalCPULoad, serviceCPUF, systemCPUF, totalCPUF := common.GetCPULoad()
 
 	// Fetch memory load statistics
 	serviceMemLoad, systemMemLoad, totalMemAvailable, serviceMemF, systemMemF, _ := common.GetMemoryLoad()
@@ -135,6 +83,7 @@ func GetLoadStatistics() models.LoadStatistics {
 		TotalDiskLoad:           totalDisk,
 		ServiceCPULoadRaw:       serviceCPUF,
 		SystemCPULoadRaw:        systemCPUF,
+		TotalCPULoadRaw:         totalCPUF,
 		ServiceMemLoadRaw:       serviceMemF,
 		SystemMemLoadRaw:        systemMemF,
 		OverallLoadOfServiceRaw: overallLoadF,
@@ -285,55 +234,49 @@ func GetNetworkIO() (float64, float64) {
 	return totalBytesReceived, totalBytesSent
 }
 
-// getStatusMessage returns a status message based on the health score.
-func getStatusMessage(healthScore float64) string {
+// healthLevels grade health scores from best to worst, each from its minimum score.
+var healthLevels = []struct {
+	name    string
+	min     float64
+	message string
+}{
+	{"Excellent", 90, "Service health is optimal. All systems are operating within normal parameters."},
+	{"Good", 85, "Service health is performing well with minor optimizations recommended."},
+	{"Satisfactory", 70, "Service health is stable with room for performance improvements."},
+	{"Fair", 50, "Service health is functional but requires attention to resource utilization."},
+	{"Poor", 30, "Service health is degraded. Immediate investigation and remediation required."},
+	{"Critical", math.Inf(-1), "Service health is severely compromised. Urgent intervention necessary."},
+}
 
-	var message string
-	switch {
-	case healthScore >= 90:
-		message = "[Excellent] Service health is optimal. All systems are operating within normal parameters."
-	case healthScore >= 85:
-		message = "[Good] Service health is performing well with minor optimizations recommended."
-	case healthScore >= 70:
-		message = "[Satisfactory] Service health is stable with room for performance improvements."
-	case healthScore >= 50:
-		message = "[Fair] Service health is functional but requires attention to resource utilization."
-	case healthScore >= 30:
-		message = "[Poor] Service health is degraded. Immediate investigation and remediation required."
-	default:
-		message = "[Critical] Service health is severely compromised. Urgent intervention necessary."
+// healthLevel returns the index in healthLevels of the level of a health score.
+func healthLevel(healthScore float64) int {
+	for i, l := range healthLevels {
+		if healthScore >= l.min {
+			return i
+		}
 	}
+	return len(healthLevels) - 1
+}
 
-	return message
+// getStatusMessage returns a status message based on the health score.
+func getStatusMessage(healthScore float64) string {
+	l := healthLevels[healthLevel(healthScore)]
+	return fmt.Sprintf("[%s] %s", l.name, l.message)
 }
 
 // GetServiceHealth retrieves the service health statistics.
 func GetServiceHealth(serviceStats *models.ServiceStats) models.ServiceHealth {
-	healthInPercent, err := CalculateHealthScore(serviceStats)
-	if err != nil {
-		return models.ServiceHealth{
-			SystemHealth:  models.Health{Percent: 0, Healthy: false, Message: "Error: Unable to calculate health score. Please check system configuration."},
-			ServiceHealth: models.Health{Percent: 0, Healthy: false, Message: "Error: Unable to calculate health score. Please check system configuration."},
+	evaluate := func(system bool) models.Health {
+		health, err := evaluateHealth(context.Background(), serviceStats, system)
+		if err != nil {
+			return models.Health{Percent: 0, Healthy: false, Status: HealthStatusCritical, Message: "Error: Unable to calculate health score. Please check system configuration."}
 		}
+		return health
 	}
-
-	var healthData models.ServiceHealth
-	healthData.ServiceHealth.Percent = healthInPercent.ServiceHealth.Percentage
-	healthData.SystemHealth.Percent = healthInPercent.SystemHealth.Percentage
-
-	healthData.ServiceHealth = models.Health{
-		Percent: healthData.ServiceHealth.Percent,
-		Healthy: healthData.ServiceHealth.Percent > 50,
-		Message: getStatusMessage(healthData.ServiceHealth.Percent),
-		IconMsg: healthInPercent.ServiceHealth.Message,
-	}
-	healthData.SystemHealth = models.Health{
-		Percent: healthData.SystemHealth.Percent,
-		Healthy: healthData.SystemHealth.Percent > 50,
-		Message: getStatusMessage(healthData.SystemHealth.Percent),
-		IconMsg: healthInPercent.SystemHealth.Message,
+	return models.ServiceHealth{
+		SystemHealth:  evaluate(true),
+		ServiceHealth: evaluate(false),
 	}
-	return healthData
 }
 
 // ConstructRawMemStats constructs a list of raw memory statistics records.
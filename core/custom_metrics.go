@@ -0,0 +1,37 @@
+package core
+
+import (
+	"strconv"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/registry"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// CustomMetricQuantiles are the quantiles estimated for custom histograms.
+var CustomMetricQuantiles = []float64{0.5, 0.95, 0.99}
+
+// GetCustomMetrics returns the current value of every custom application metric series.
+func GetCustomMetrics() []models.CustomMetric {
+	values := registry.Default().GetAll()
+	metrics := make([]models.CustomMetric, 0, len(values))
+	for _, v := range values {
+		metric := models.CustomMetric{
+			Name:      v.Name,
+			Type:      v.Type.String(),
+			Labels:    v.Labels,
+			Value:     v.Value,
+			UpdatedAt: v.Timestamp,
+		}
+		if v.Type == registry.Histogram {
+			metric.Count = v.Count
+			metric.Sum = v.Sum
+			metric.Quantiles = make(map[string]float64, len(CustomMetricQuantiles))
+			for _, q := range CustomMetricQuantiles {
+				metric.Quantiles[strconv.FormatFloat(q, 'f', -1, 64)] = common.RoundFloat64(v.Quantile(q), 6)
+			}
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
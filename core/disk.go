@@ -7,11 +7,19 @@ import (
 
 // GetDiskIO retrieves the disk I/O statistics (Read/Write bytes).
 func GetDiskIO() (uint64, uint64) {
+	readBytes, writeBytes, err := collectDiskIO()
+	if err != nil {
+		logger.Log.Warn("Error fetching disk I/O statistics", "error", err)
+	}
+	return readBytes, writeBytes
+}
+
+// collectDiskIO is GetDiskIO returning the error instead of logging it.
+func collectDiskIO() (uint64, uint64, error) {
 	// fetching IO counters for all disks
 	ioCounters, err := disk.IOCounters()
 	if err != nil {
-		logger.Log.Warn("Error fetching disk I/O statistics", "error", err)
-		return 0, 0
+		return 0, 0, err
 	}
 
 	var totalReadBytes, totalWriteBytes uint64
@@ -20,5 +28,5 @@ func GetDiskIO() (uint64, uint64) {
 		totalWriteBytes += io.WriteBytes
 	}
 
-	return totalReadBytes, totalWriteBytes
+	return totalReadBytes, totalWriteBytes, nil
 }
@@ -33,6 +33,30 @@ func init() {
 	samplingRate.Store(100)
 }
 
+// FunctionCallObserver is notified of every traced call with its duration and whether the
+// function returned a non-nil error as its last result.
+type FunctionCallObserver func(name string, elapsed time.Duration, failed bool)
+
+var functionCallObserver atomic.Pointer[FunctionCallObserver]
+
+// SetFunctionCallObserver sets the observer notified of every traced call, nil removes it.
+func SetFunctionCallObserver(observer FunctionCallObserver) {
+	if observer == nil {
+		functionCallObserver.Store(nil)
+		return
+	}
+	functionCallObserver.Store(&observer)
+}
+
+// returnedError reports whether the last result of a call is a non-nil error.
+func returnedError(results []reflect.Value) bool {
+	if len(results) == 0 {
+		return false
+	}
+	last := results[len(results)-1]
+	return last.Type() == reflect.TypeFor[error]() && !last.IsNil()
+}
+
 // SetSamplingRate sets the sampling rate for function tracing
 func SetSamplingRate(rate int) {
 	if rate < 1 {
@@ -44,7 +68,10 @@ func SetSamplingRate(rate int) {
 // TraceFunction traces the function and captures the metrics
 func TraceFunction(_ context.Context, f func()) {
 	name := strings.ReplaceAll(runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name(), "/", "-")
-	executeFunctionWithProfiling(name, f)
+	executeFunctionWithProfiling(name, func() bool {
+		f()
+		return false
+	})
 }
 
 // FunctionTraceDetails returns a snapshot copy of the function trace details (thread-safe)
@@ -89,8 +116,8 @@ func TraceFunctionWithArgs(_ context.Context, f interface{}, args ...interface{}
 
 	name := generateFunctionName(fnValue, fnType)
 
-	executeFunctionWithProfiling(name, func() {
-		fnValue.Call(argValues)
+	executeFunctionWithProfiling(name, func() bool {
+		return returnedError(fnValue.Call(argValues))
 	})
 }
 
@@ -133,12 +160,13 @@ func TraceFunctionWithReturns(_ context.Context, f interface{}, args ...interfac
 	name := generateFunctionName(fnValue, fnType)
 
 	var results []interface{}
-	executeFunctionWithProfiling(name, func() {
+	executeFunctionWithProfiling(name, func() bool {
 		reflectResults := fnValue.Call(argValues)
 		results = make([]interface{}, len(reflectResults))
 		for i, result := range reflectResults {
 			results[i] = result.Interface()
 		}
+		return returnedError(reflectResults)
 	})
 
 	return results
@@ -178,7 +206,8 @@ func sanitizeFileName(name string) string {
 	return replacer.Replace(name)
 }
 
-func executeFunctionWithProfiling(name string, fn func()) {
+// executeFunctionWithProfiling runs fn, which reports whether the call failed, recording its metrics.
+func executeFunctionWithProfiling(name string, fn func() bool) {
 	countersMu.Lock()
 	if len(callCounters) > maxTrackedFunctions {
 		// Evict oldest entries to prevent unbounded growth.
@@ -216,15 +245,22 @@ func executeFunctionWithProfiling(name string, fn func()) {
 		cpuProfileFile, err = StartCPUProfile(cpuProfFilePath)
 		if err != nil {
 			logger.Log.Warn("failed to start CPU profile", "error", err)
+			cpuProfFilePath = ""
 		}
 	}
 
 	start := time.Now()
-	fn()
+	failed := fn()
 	elapsed := time.Since(start)
+	if observer := functionCallObserver.Load(); observer != nil {
+		(*observer)(name, elapsed, failed)
+	}
 
 	if shouldProfile {
-		StopCPUProfile(cpuProfileFile)
+		// Stopping without a profile of our own would end one captured on demand
+		if cpuProfileFile != nil {
+			StopCPUProfile(cpuProfileFile)
+		}
 		if err := WriteHeapProfile(memProfFilePath); err != nil {
 			logger.Log.Warn("failed to write heap profile", "error", err)
 		}
@@ -2,11 +2,15 @@ package core
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTraceFunction(t *testing.T) {
-	SetSamplingRate(1) // Trace every call
+	withProfileStore(t) // Sampled calls write their profiles under the base path
+	SetSamplingRate(1)  // Trace every call
 	called := false
 	TraceFunction(context.Background(), func() { called = true })
 
@@ -21,6 +25,7 @@ func TestTraceFunction(t *testing.T) {
 }
 
 func TestTraceFunctionWithArgs(t *testing.T) {
+	withProfileStore(t)
 	SetSamplingRate(1)
 	var got string
 	fn := func(s string) { got = s }
@@ -32,6 +37,7 @@ func TestTraceFunctionWithArgs(t *testing.T) {
 }
 
 func TestTraceFunctionWithArgs_WrongArgCount(t *testing.T) {
+	withProfileStore(t)
 	SetSamplingRate(1)
 	fn := func(a, b string) {}
 	// Should not panic, just log and return
@@ -39,12 +45,14 @@ func TestTraceFunctionWithArgs_WrongArgCount(t *testing.T) {
 }
 
 func TestTraceFunctionWithArgs_NotAFunction(t *testing.T) {
+	withProfileStore(t)
 	SetSamplingRate(1)
 	// Should not panic when passed a non-function
 	TraceFunctionWithArgs(context.Background(), "not-a-function")
 }
 
 func TestTraceFunctionWithReturn(t *testing.T) {
+	withProfileStore(t)
 	SetSamplingRate(1)
 	fn := func(a, b int) int { return a + b }
 	result := TraceFunctionWithReturn(context.Background(), fn, 3, 4)
@@ -55,6 +63,7 @@ func TestTraceFunctionWithReturn(t *testing.T) {
 }
 
 func TestTraceFunctionWithReturns(t *testing.T) {
+	withProfileStore(t)
 	SetSamplingRate(1)
 	fn := func(s string) (string, int) { return s + "!", len(s) }
 	results := TraceFunctionWithReturns(context.Background(), fn, "hi")
@@ -94,6 +103,7 @@ func TestSetSamplingRate(t *testing.T) {
 }
 
 func TestFunctionTraceDetailsReturnsCopy(t *testing.T) {
+	withProfileStore(t)
 	SetSamplingRate(1)
 	TraceFunction(context.Background(), func() {})
 
@@ -109,3 +119,31 @@ func TestFunctionTraceDetailsReturnsCopy(t *testing.T) {
 		t.Error("expected FunctionTraceDetails to return independent copies")
 	}
 }
+
+func TestFunctionCallObserver(t *testing.T) {
+	SetSamplingRate(1000) // The observer is notified of calls which aren't sampled too
+	defer SetSamplingRate(1)
+
+	failures := make(map[string]bool)
+	SetFunctionCallObserver(func(name string, elapsed time.Duration, failed bool) {
+		failures[name] = failed
+	})
+	defer SetFunctionCallObserver(nil)
+
+	TraceFunctionWithReturns(context.Background(), func(fail bool) error {
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	}, true)
+	TraceFunctionWithArgs(context.Background(), func(n int) (int, error) { return n, nil }, 1)
+
+	if len(failures) != 2 {
+		t.Fatalf("expected the observer to be notified of both calls, got %v", failures)
+	}
+	for name, failed := range failures {
+		if failed != strings.HasSuffix(name, "(bool)->(error)") {
+			t.Errorf("unexpected failure %v for %s", failed, name)
+		}
+	}
+}
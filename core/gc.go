@@ -0,0 +1,359 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// Actions recorded in the GC audit trail.
+const (
+	GCActionSetGCPercent   = "set_gc_percent"
+	GCActionSetMemoryLimit = "set_memory_limit"
+	GCActionRunGC          = "run_gc"
+	GCActionFreeOSMemory   = "free_os_memory"
+)
+
+// gcAuditEntries is the number of audit entries returned with the GC insights.
+const gcAuditEntries = 50
+
+// gcMetricNames are the runtime/metrics read for the GC statistics, in gcSample order.
+var gcMetricNames = []string{
+	"/gc/gogc:percent",
+	"/gc/gomemlimit:bytes",
+	"/gc/heap/goal:bytes",
+	"/gc/heap/live:bytes",
+	"/gc/cycles/total:gc-cycles",
+	"/gc/cycles/forced:gc-cycles",
+	"/cpu/classes/gc/total:cpu-seconds",
+	"/cpu/classes/total:cpu-seconds",
+}
+
+// gcSample is a reading of the GC runtime metrics.
+type gcSample struct {
+	at                 time.Time
+	gcPercent          int
+	memoryLimit        int64
+	heapGoal, heapLive uint64
+	cycles, forced     uint64
+	gcCPU, totalCPU    float64
+}
+
+var (
+	gcMu         sync.Mutex
+	lastGCSample *gcSample // Previous sample of the gc collector, for rates
+	gcAuditMu    sync.Mutex
+)
+
+// readGCSample reads the GC runtime metrics. Unlike runtime.ReadMemStats it does not stop the world.
+func readGCSample() gcSample {
+	samples := make([]metrics.Sample, len(gcMetricNames))
+	for i, name := range gcMetricNames {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	value := func(i int) uint64 {
+		if samples[i].Value.Kind() != metrics.KindUint64 {
+			return 0
+		}
+		return samples[i].Value.Uint64()
+	}
+	seconds := func(i int) float64 {
+		if samples[i].Value.Kind() != metrics.KindFloat64 {
+			return 0
+		}
+		return samples[i].Value.Float64()
+	}
+
+	sample := gcSample{
+		at: time.Now(),
+		// The runtime reports GOGC=off as the int32 -1 widened to uint64
+		gcPercent: int(int32(value(0))),
+		heapGoal:  value(2),
+		heapLive:  value(3),
+		cycles:    value(4),
+		forced:    value(5),
+		gcCPU:     seconds(6),
+		totalCPU:  seconds(7),
+	}
+	if limit := value(1); limit < math.MaxInt64 {
+		sample.memoryLimit = int64(limit)
+	}
+	return sample
+}
+
+// gcStatistics builds the GC statistics of a sample, with rates relative to prev when given.
+func gcStatistics(sample, prev *gcSample) models.GCStatistics {
+	stats := models.GCStatistics{
+		GCPercent:    sample.gcPercent,
+		MemoryLimit:  sample.memoryLimit,
+		HeapGoal:     sample.heapGoal,
+		HeapLive:     sample.heapLive,
+		Cycles:       sample.cycles,
+		ForcedCycles: sample.forced,
+	}
+
+	var gcStats debug.GCStats
+	debug.ReadGCStats(&gcStats)
+	stats.LastGC = gcStats.LastGC
+
+	if prev == nil {
+		return stats
+	}
+	if elapsed := sample.at.Sub(prev.at); elapsed > 0 && sample.cycles >= prev.cycles {
+		stats.CyclesPerMinute = common.RoundFloat64(float64(sample.cycles-prev.cycles)/elapsed.Minutes(), 2)
+	}
+	if total := sample.totalCPU - prev.totalCPU; total > 0 {
+		stats.CPUPercent = common.RoundFloat64((sample.gcCPU-prev.gcCPU)/total*100, 2)
+	}
+	return stats
+}
+
+// GetGCStatistics returns the GC statistics with rates since the previous call. It backs the gc collector.
+func GetGCStatistics() models.GCStatistics {
+	sample := readGCSample()
+
+	gcMu.Lock()
+	prev := lastGCSample
+	lastGCSample = &sample
+	gcMu.Unlock()
+
+	return gcStatistics(&sample, prev)
+}
+
+// GetGCInsights returns the current GC settings and heap, the GC activity of the latest
+// sample, tuning suggestions and the most recent runtime changes.
+func GetGCInsights() models.GCInsights {
+	sample := readGCSample()
+	stats := gcStatistics(&sample, nil)
+	// Rates need two samples, taken from the latest snapshot
+	sampled := GetLatestServiceStats().GCStatistics
+	stats.CyclesPerMinute, stats.CPUPercent = sampled.CyclesPerMinute, sampled.CPUPercent
+
+	containerLimit := containerMemoryLimit()
+	audit, err := GetGCAudit(gcAuditEntries)
+	if err != nil {
+		logger.Log.Warn("failed to read GC audit trail", "error", err)
+		audit = []models.GCAuditEntry{}
+	}
+
+	return models.GCInsights{
+		Statistics:           stats,
+		ContainerMemoryLimit: containerLimit,
+		Suggestions:          suggestGCSettings(&stats, containerLimit),
+		Audit:                audit,
+	}
+}
+
+// suggestGCSettings derives tuning hints from the GC statistics and the container memory limit.
+func suggestGCSettings(stats *models.GCStatistics, containerLimit int64) []models.GCSuggestion {
+	suggestions := []models.GCSuggestion{}
+	add := func(severity, setting, message, value string) {
+		suggestions = append(suggestions, models.GCSuggestion{Severity: severity, Setting: setting, Message: message, SuggestedValue: value})
+	}
+
+	if stats.GCPercent < 0 && stats.MemoryLimit == 0 {
+		add("critical", "GOMEMLIMIT", "The GC is off and no memory limit is set, so the heap grows until the process runs out of memory.", "")
+	}
+
+	if stats.MemoryLimit > 0 && float64(stats.HeapLive) > 0.9*float64(stats.MemoryLimit) {
+		add("warning", "GOMEMLIMIT",
+			fmt.Sprintf("The live heap (%s) is above 90%% of the memory limit (%s). The GC runs almost continuously to stay under it.",
+				common.BytesToUnit(stats.HeapLive), common.BytesToUnit(uint64(stats.MemoryLimit))),
+			strconv.FormatInt(int64(float64(stats.HeapLive)*1.5), 10))
+	}
+
+	if containerLimit > 0 && stats.MemoryLimit == 0 {
+		add("info", "GOMEMLIMIT",
+			fmt.Sprintf("The container is limited to %s but GOMEMLIMIT is not set. A limit of about 90%% lets the GC act before the container is killed.",
+				common.BytesToUnit(uint64(containerLimit))),
+			strconv.FormatInt(containerLimit/10*9, 10))
+	}
+
+	switch {
+	case stats.CPUPercent > 25:
+		add("warning", "GOGC", fmt.Sprintf("The GC used %.1f%% of the CPU time. Raising GOGC trades memory for less GC work.", stats.CPUPercent), gcPercentSuggestion(stats.GCPercent))
+	case stats.CPUPercent > 10:
+		add("info", "GOGC", fmt.Sprintf("The GC used %.1f%% of the CPU time. Raising GOGC would reduce it if memory allows.", stats.CPUPercent), gcPercentSuggestion(stats.GCPercent))
+	case stats.CyclesPerMinute > 120:
+		add("info", "GOGC", fmt.Sprintf("The GC runs %.0f times per minute. A higher GOGC makes cycles less frequent.", stats.CyclesPerMinute), gcPercentSuggestion(stats.GCPercent))
+	}
+
+	return suggestions
+}
+
+// gcPercentSuggestion doubles the GC percent, the default when it is unset or off.
+func gcPercentSuggestion(gcPercent int) string {
+	if gcPercent <= 0 {
+		return "200"
+	}
+	return strconv.Itoa(gcPercent * 2)
+}
+
+// containerMemoryLimit returns the cgroup (v2 or v1) memory limit in bytes, 0 when there is none.
+func containerMemoryLimit() int64 {
+	if runtime.GOOS != "linux" {
+		return 0
+	}
+	for _, path := range []string{"/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/memory/memory.limit_in_bytes"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		// "max" or the page-aligned maximum of cgroup v1 mean unlimited
+		if err != nil || limit <= 0 || limit >= math.MaxInt64/2 {
+			return 0
+		}
+		return limit
+	}
+	return 0
+}
+
+// SetGCPercent sets GOGC at runtime, -1 turning the GC off, and records the change.
+func SetGCPercent(percent int, actor string) (models.GCAuditEntry, error) {
+	if percent < -1 {
+		return models.GCAuditEntry{}, fmt.Errorf("GC percent must be -1 (off) or greater")
+	}
+	old := debug.SetGCPercent(percent)
+	return recordGCChange(GCActionSetGCPercent, actor, strconv.Itoa(old), strconv.Itoa(percent))
+}
+
+// SetMemoryLimit sets GOMEMLIMIT at runtime and records the change. A limit of 0 removes it.
+func SetMemoryLimit(limit int64, actor string) (models.GCAuditEntry, error) {
+	if limit < 0 {
+		return models.GCAuditEntry{}, fmt.Errorf("memory limit must not be negative")
+	}
+	if limit == 0 {
+		limit = math.MaxInt64
+	}
+	old := debug.SetMemoryLimit(limit)
+	return recordGCChange(GCActionSetMemoryLimit, actor, formatMemoryLimit(old), formatMemoryLimit(limit))
+}
+
+// formatMemoryLimit formats a memory limit in bytes, or "off" when unlimited.
+func formatMemoryLimit(limit int64) string {
+	if limit == math.MaxInt64 {
+		return "off"
+	}
+	return strconv.FormatInt(limit, 10)
+}
+
+// RunGC runs a garbage collection and records the live heap before and after.
+func RunGC(actor string) (models.GCAuditEntry, error) {
+	before := readGCSample().heapLive
+	runtime.GC()
+	after := readGCSample().heapLive
+	return recordGCChange(GCActionRunGC, actor, strconv.FormatUint(before, 10), strconv.FormatUint(after, 10))
+}
+
+// FreeOSMemory forces a garbage collection and returns as much memory to the OS as possible,
+// recording the heap memory held from the OS before and after.
+func FreeOSMemory(actor string) (models.GCAuditEntry, error) {
+	before := heapHeldFromOS()
+	debug.FreeOSMemory()
+	after := heapHeldFromOS()
+	return recordGCChange(GCActionFreeOSMemory, actor, strconv.FormatUint(before, 10), strconv.FormatUint(after, 10))
+}
+
+// heapHeldFromOS returns the heap memory mapped from the OS and not yet released.
+func heapHeldFromOS() uint64 {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/memory/classes/heap/unused:bytes"},
+		{Name: "/memory/classes/heap/free:bytes"},
+	}
+	metrics.Read(samples)
+
+	var held uint64
+	for _, s := range samples {
+		if s.Value.Kind() == metrics.KindUint64 {
+			held += s.Value.Uint64()
+		}
+	}
+	return held
+}
+
+// gcAuditPath returns the file the GC audit trail is appended to, one JSON entry per line.
+func gcAuditPath() string {
+	return filepath.Join(basePath, "gc_audit.log")
+}
+
+// recordGCChange appends a change to the audit trail and logs it.
+func recordGCChange(action, actor, oldValue, newValue string) (models.GCAuditEntry, error) {
+	entry := models.GCAuditEntry{Time: time.Now(), Action: action, Actor: actor, OldValue: oldValue, NewValue: newValue}
+	logger.Log.Info("GC setting changed at runtime", "action", action, "actor", actor, "old", oldValue, "new", newValue)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+
+	gcAuditMu.Lock()
+	defer gcAuditMu.Unlock()
+
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return entry, fmt.Errorf("failed to record GC change: %w", err)
+	}
+	f, err := os.OpenFile(gcAuditPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return entry, fmt.Errorf("failed to record GC change: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return entry, fmt.Errorf("failed to record GC change: %w", err)
+	}
+	return entry, nil
+}
+
+// GetGCAudit returns up to limit of the most recent GC changes, newest first. A limit of 0 returns all.
+func GetGCAudit(limit int) ([]models.GCAuditEntry, error) {
+	entries := []models.GCAuditEntry{}
+
+	gcAuditMu.Lock()
+	defer gcAuditMu.Unlock()
+
+	f, err := os.Open(gcAuditPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry models.GCAuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+func TestGCStatisticsRates(t *testing.T) {
+	start := time.Now()
+	prev := gcSample{at: start, cycles: 10, gcCPU: 1, totalCPU: 10}
+	sample := gcSample{at: start.Add(30 * time.Second), gcPercent: 100, cycles: 40, gcCPU: 2, totalCPU: 20}
+
+	stats := gcStatistics(&sample, &prev)
+	if stats.CyclesPerMinute != 60 {
+		t.Errorf("expected 60 cycles per minute, got %v", stats.CyclesPerMinute)
+	}
+	if stats.CPUPercent != 10 {
+		t.Errorf("expected a GC CPU share of 10%%, got %v", stats.CPUPercent)
+	}
+	if stats := gcStatistics(&sample, nil); stats.CyclesPerMinute != 0 || stats.CPUPercent != 0 {
+		t.Errorf("expected no rates without a previous sample, got %+v", stats)
+	}
+}
+
+func TestReadGCSample(t *testing.T) {
+	// The live heap and the CPU time are only updated by a GC cycle
+	runtime.GC()
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	sample := readGCSample()
+	if sample.gcPercent != -1 {
+		t.Errorf("expected GOGC=off to be reported as -1, got %d", sample.gcPercent)
+	}
+	if sample.memoryLimit != 0 {
+		t.Errorf("expected no memory limit, got %d", sample.memoryLimit)
+	}
+	if sample.heapLive == 0 || sample.cycles == 0 || sample.totalCPU == 0 {
+		t.Errorf("expected the live heap, cycles and CPU time to be read, got %+v", sample)
+	}
+}
+
+func TestSuggestGCSettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		stats          models.GCStatistics
+		containerLimit int64
+		want           []string // Severity and setting of each suggestion
+	}{
+		{"healthy", models.GCStatistics{GCPercent: 100, CPUPercent: 2, CyclesPerMinute: 5}, 0, nil},
+		{"gc off without limit", models.GCStatistics{GCPercent: -1}, 0, []string{"critical GOMEMLIMIT"}},
+		{"near memory limit", models.GCStatistics{GCPercent: 100, MemoryLimit: 100 << 20, HeapLive: 95 << 20}, 0, []string{"warning GOMEMLIMIT"}},
+		{"container without limit", models.GCStatistics{GCPercent: 100}, 1 << 30, []string{"info GOMEMLIMIT"}},
+		{"gc cpu heavy", models.GCStatistics{GCPercent: 100, CPUPercent: 30}, 0, []string{"warning GOGC"}},
+		{"gc frequent", models.GCStatistics{GCPercent: 100, CyclesPerMinute: 300}, 0, []string{"info GOGC"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions := suggestGCSettings(&tt.stats, tt.containerLimit)
+			if len(suggestions) != len(tt.want) {
+				t.Fatalf("expected %v, got %+v", tt.want, suggestions)
+			}
+			for i, s := range suggestions {
+				if got := s.Severity + " " + s.Setting; got != tt.want[i] {
+					t.Errorf("expected %q, got %q", tt.want[i], got)
+				}
+			}
+		})
+	}
+
+	suggestions := suggestGCSettings(&models.GCStatistics{GCPercent: 100}, 1000)
+	if suggestions[0].SuggestedValue != "900" {
+		t.Errorf("expected a limit of 90%% of the container, got %q", suggestions[0].SuggestedValue)
+	}
+}
+
+func TestGCControlsAudit(t *testing.T) {
+	withProfileStore(t)
+	defer debug.SetGCPercent(debug.SetGCPercent(100))
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(math.MaxInt64))
+
+	if _, err := SetGCPercent(-2, "tester"); err == nil {
+		t.Error("expected an error for a GC percent below -1")
+	}
+	if _, err := SetMemoryLimit(-1, "tester"); err == nil {
+		t.Error("expected an error for a negative memory limit")
+	}
+
+	if _, err := SetGCPercent(150, "tester"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if percent := readGCSample().gcPercent; percent != 150 {
+		t.Errorf("expected GOGC to be 150, got %d", percent)
+	}
+	if _, err := SetMemoryLimit(1<<40, "tester"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, err := SetMemoryLimit(0, "tester")
+	if err != nil || entry.OldValue != "1099511627776" || entry.NewValue != "off" {
+		t.Errorf("unexpected memory limit change: %+v (%v)", entry, err)
+	}
+	if _, err := RunGC("tester"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := FreeOSMemory("tester"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	audit, err := GetGCAudit(3)
+	if err != nil {
+		t.Fatalf("failed to read audit trail: %v", err)
+	}
+	if len(audit) != 3 || audit[0].Action != GCActionFreeOSMemory || audit[1].Action != GCActionRunGC || audit[2].Action != GCActionSetMemoryLimit {
+		t.Errorf("expected the latest changes newest first, got %+v", audit)
+	}
+	if all, _ := GetGCAudit(0); len(all) != 5 || all[4].Action != GCActionSetGCPercent || all[4].Actor != "tester" {
+		t.Errorf("expected all five changes, got %+v", all)
+	}
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// DefaultGoroutinesInterval is how often the goroutines collector dumps and groups all stacks
+// unless configured otherwise. Dumping stacks briefly stops the world, so it runs less often
+// than the other collectors.
+const DefaultGoroutinesInterval = time.Minute
+
+// goroutineSignatureSeries is the number of largest stack signatures stored as series.
+const goroutineSignatureSeries = 20
+
+// leakSample is the goroutine count of a signature at a point in time.
+type leakSample struct {
+	at    time.Time
+	count int
+}
+
+// signatureHistory is the recent counts of a stack signature and its latest group.
+type signatureHistory struct {
+	samples    []leakSample
+	group      models.GoroutineGroup
+	detectedAt time.Time // Zero while the signature is not flagged
+}
+
+var (
+	leakDetectorMu sync.Mutex
+	leakConfig     = models.GoroutineLeakConfig{Window: 30 * time.Minute, MinGrowth: 10}
+	leakHistory    = make(map[string]*signatureHistory)
+)
+
+// ConfigureGoroutineLeakDetection sets the window and growth after which a stack signature
+// is reported as a suspected leak.
+func ConfigureGoroutineLeakDetection(config *models.GoroutineLeakConfig) {
+	leakDetectorMu.Lock()
+	defer leakDetectorMu.Unlock()
+	leakConfig = *config
+}
+
+// sampleGoroutineSignatures groups all goroutines by stack signature, updates the leak
+// detector and returns the counts of the largest signatures.
+func sampleGoroutineSignatures() []models.GoroutineSignatureCount {
+	groups := GroupGoroutines(ParseGoroutines(dumpGoroutineStacks()))
+	return recordGoroutineGroups(groups, time.Now())
+}
+
+// recordGoroutineGroups adds a sample of every signature to the history and flags those whose
+// count did not decrease over the window while growing by at least MinGrowth. Signatures
+// no longer present are forgotten, since a count dropping to zero is not a leak.
+func recordGoroutineGroups(groups []models.GoroutineGroup, now time.Time) []models.GoroutineSignatureCount {
+	leakDetectorMu.Lock()
+	defer leakDetectorMu.Unlock()
+
+	current := make(map[string]*signatureHistory, len(groups))
+	for _, group := range groups {
+		h := leakHistory[group.Signature]
+		if h == nil {
+			h = &signatureHistory{}
+		}
+		h.group = group
+		h.samples = trimLeakSamples(append(h.samples, leakSample{at: now, count: group.Count}), now.Add(-leakConfig.Window))
+		current[group.Signature] = h
+
+		leaking := isLeaking(h.samples, now, &leakConfig)
+		switch {
+		case leaking && h.detectedAt.IsZero():
+			h.detectedAt = now
+			logger.Log.Warn("suspected goroutine leak", "signature", group.Signature,
+				"function", topFunction(&group), "count", group.Count,
+				"growth", group.Count-h.samples[0].count, "window", leakConfig.Window.String())
+		case !leaking && !h.detectedAt.IsZero():
+			h.detectedAt = time.Time{}
+			logger.Log.Info("goroutine leak no longer suspected", "signature", group.Signature, "count", group.Count)
+		}
+	}
+	leakHistory = current
+
+	counts := make([]models.GoroutineSignatureCount, 0, min(len(groups), goroutineSignatureSeries))
+	for _, group := range groups {
+		if len(counts) >= goroutineSignatureSeries {
+			break
+		}
+		counts = append(counts, models.GoroutineSignatureCount{
+			Signature: group.Signature,
+			Function:  topFunction(&group),
+			Count:     group.Count,
+		})
+	}
+	return counts
+}
+
+// trimLeakSamples drops samples older than start, keeping the newest of them so the
+// remaining samples span the whole window.
+func trimLeakSamples(samples []leakSample, start time.Time) []leakSample {
+	first := 0
+	for first+1 < len(samples) && !samples[first+1].at.After(start) {
+		first++
+	}
+	return samples[first:]
+}
+
+// isLeaking reports whether the samples span the window, never decrease and grow by at least MinGrowth.
+func isLeaking(samples []leakSample, now time.Time, config *models.GoroutineLeakConfig) bool {
+	if len(samples) < 2 || now.Sub(samples[0].at) < config.Window {
+		return false
+	}
+	for i := 1; i < len(samples); i++ {
+		if samples[i].count < samples[i-1].count {
+			return false
+		}
+	}
+	return samples[len(samples)-1].count-samples[0].count >= max(config.MinGrowth, 1)
+}
+
+// topFunction returns the function at the top of the group's stack.
+func topFunction(group *models.GoroutineGroup) string {
+	if len(group.Frames) == 0 {
+		return ""
+	}
+	return group.Frames[0].Function
+}
+
+// GetGoroutineLeaks returns the stack signatures currently suspected of leaking, largest growth first.
+func GetGoroutineLeaks() []models.GoroutineLeak {
+	leakDetectorMu.Lock()
+	defer leakDetectorMu.Unlock()
+
+	leaks := []models.GoroutineLeak{}
+	for _, h := range leakHistory {
+		if h.detectedAt.IsZero() {
+			continue
+		}
+		first, last := h.samples[0], h.samples[len(h.samples)-1]
+		growth := last.count - first.count
+		leaks = append(leaks, models.GoroutineLeak{
+			Signature:     h.group.Signature,
+			Count:         last.count,
+			Growth:        growth,
+			GrowthPerHour: common.RoundFloat64(float64(growth)/last.at.Sub(first.at).Hours(), 2),
+			Since:         first.at,
+			DetectedAt:    h.detectedAt,
+			CreatedBy:     h.group.CreatedBy,
+			Frames:        h.group.Frames,
+		})
+	}
+
+	sort.Slice(leaks, func(i, j int) bool {
+		if leaks[i].Growth != leaks[j].Growth {
+			return leaks[i].Growth > leaks[j].Growth
+		}
+		return leaks[i].Signature < leaks[j].Signature
+	})
+	return leaks
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// withLeakDetector resets the leak detector with the given config for the duration of the test.
+func withLeakDetector(t *testing.T, config models.GoroutineLeakConfig) {
+	t.Helper()
+	leakDetectorMu.Lock()
+	savedConfig, savedHistory := leakConfig, leakHistory
+	leakConfig, leakHistory = config, make(map[string]*signatureHistory)
+	leakDetectorMu.Unlock()
+
+	t.Cleanup(func() {
+		leakDetectorMu.Lock()
+		leakConfig, leakHistory = savedConfig, savedHistory
+		leakDetectorMu.Unlock()
+	})
+}
+
+func leakGroup(signature string, count int) models.GoroutineGroup {
+	return models.GoroutineGroup{
+		Signature: signature,
+		Count:     count,
+		Frames:    []models.GoroutineFrame{{Function: "main." + signature, File: "/app/main.go", Line: 1}},
+	}
+}
+
+func TestGoroutineLeakDetection(t *testing.T) {
+	withLeakDetector(t, models.GoroutineLeakConfig{Window: 10 * time.Minute, MinGrowth: 5})
+
+	start := time.Now()
+	for i := 0; i <= 10; i++ {
+		groups := []models.GoroutineGroup{
+			leakGroup("leaking", 10+2*i),     // grows by 2 every minute
+			leakGroup("fluctuating", 10+i%3), // goes up and down
+			leakGroup("steady", 10),
+		}
+		recordGoroutineGroups(groups, start.Add(time.Duration(i)*time.Minute))
+
+		if i < 10 && len(GetGoroutineLeaks()) != 0 {
+			t.Fatalf("expected no leak before the window is covered, got one at minute %d", i)
+		}
+	}
+
+	leaks := GetGoroutineLeaks()
+	if len(leaks) != 1 {
+		t.Fatalf("expected exactly one suspected leak, got %+v", leaks)
+	}
+	leak := leaks[0]
+	if leak.Signature != "leaking" || leak.Count != 30 || leak.Growth != 20 {
+		t.Errorf("unexpected leak: %+v", leak)
+	}
+	if leak.GrowthPerHour != 120 {
+		t.Errorf("expected growth of 120 per hour, got %v", leak.GrowthPerHour)
+	}
+	if len(leak.Frames) != 1 || leak.Frames[0].Function != "main.leaking" {
+		t.Errorf("expected the leaking stack, got %+v", leak.Frames)
+	}
+
+	// A decrease clears the suspicion
+	recordGoroutineGroups([]models.GoroutineGroup{leakGroup("leaking", 25)}, start.Add(11*time.Minute))
+	if leaks := GetGoroutineLeaks(); len(leaks) != 0 {
+		t.Errorf("expected the leak to be cleared after the count dropped, got %+v", leaks)
+	}
+}
+
+func TestGoroutineLeakMinGrowth(t *testing.T) {
+	withLeakDetector(t, models.GoroutineLeakConfig{Window: 10 * time.Minute, MinGrowth: 50})
+
+	start := time.Now()
+	for i := 0; i <= 10; i++ {
+		recordGoroutineGroups([]models.GoroutineGroup{leakGroup("slow", 10+i)}, start.Add(time.Duration(i)*time.Minute))
+	}
+	if leaks := GetGoroutineLeaks(); len(leaks) != 0 {
+		t.Errorf("expected growth below MinGrowth not to be flagged, got %+v", leaks)
+	}
+}
+
+func TestTrimLeakSamples(t *testing.T) {
+	start := time.Now()
+	var samples []leakSample
+	for i := 0; i < 5; i++ {
+		samples = append(samples, leakSample{at: start.Add(time.Duration(i) * time.Minute), count: i})
+	}
+
+	trimmed := trimLeakSamples(samples, start.Add(150*time.Second))
+	if len(trimmed) != 3 || trimmed[0].count != 2 {
+		t.Errorf("expected the samples from minute 2 to be kept, got %+v", trimmed)
+	}
+}
+
+func TestRecordGoroutineGroupsLimitsSeries(t *testing.T) {
+	withLeakDetector(t, models.GoroutineLeakConfig{Window: time.Minute, MinGrowth: 1})
+
+	groups := make([]models.GoroutineGroup, 0, goroutineSignatureSeries+5)
+	for i := 0; i < goroutineSignatureSeries+5; i++ {
+		groups = append(groups, leakGroup(string(rune('a'+i)), 100-i))
+	}
+	counts := recordGoroutineGroups(groups, time.Now())
+	if len(counts) != goroutineSignatureSeries {
+		t.Fatalf("expected %d signature series, got %d", goroutineSignatureSeries, len(counts))
+	}
+	if counts[0].Signature != "a" || counts[0].Function != "main.a" || counts[0].Count != 100 {
+		t.Errorf("unexpected first signature: %+v", counts[0])
+	}
+}
@@ -0,0 +1,313 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+const (
+	// GoroutineViewGoroutines lists individual goroutines.
+	GoroutineViewGoroutines = "goroutines"
+	// GoroutineViewGroups lists goroutines grouped by stack signature.
+	GoroutineViewGroups = "groups"
+
+	// goroutineGroupMaxIDs is the number of goroutine IDs kept per group.
+	goroutineGroupMaxIDs = 100
+)
+
+// CollectGoRoutinesInfo returns every running goroutine parsed from a full stack dump.
+func CollectGoRoutinesInfo() models.GoRoutinesStatistic {
+	return QueryGoroutines(models.GoroutineQuery{})
+}
+
+// QueryGoroutines parses a full stack dump and returns the page of goroutines,
+// or groups of goroutines with the same stack signature, matching the query.
+func QueryGoroutines(query models.GoroutineQuery) models.GoRoutinesStatistic {
+	goroutines := ParseGoroutines(dumpGoroutineStacks())
+
+	stats := models.GoRoutinesStatistic{
+		NumberOfGoroutines: runtime.NumGoroutine(),
+		States:             make(map[string]int),
+		View:               query.View,
+		Page:               query.Page,
+		PageSize:           query.PageSize,
+		Leaks:              GetGoroutineLeaks(),
+	}
+	if stats.View == "" {
+		stats.View = GoroutineViewGoroutines
+	}
+	if stats.Page < 1 {
+		stats.Page = 1
+	}
+
+	matched := goroutines[:0:0]
+	for i := range goroutines {
+		stats.States[goroutines[i].State]++
+		if matchGoroutine(&goroutines[i], &query) {
+			matched = append(matched, goroutines[i])
+		}
+	}
+
+	if stats.View == GoroutineViewGroups {
+		groups := GroupGoroutines(matched)
+		stats.Total = len(groups)
+		start, end := pageBounds(len(groups), stats.Page, stats.PageSize)
+		stats.Groups = groups[start:end]
+		return stats
+	}
+
+	stats.Total = len(matched)
+	start, end := pageBounds(len(matched), stats.Page, stats.PageSize)
+	stats.Goroutines = matched[start:end]
+	stats.StackView = make([]string, 0, len(stats.Goroutines))
+	for _, g := range stats.Goroutines {
+		stats.StackView = append(stats.StackView, g.Stack)
+	}
+	return stats
+}
+
+// dumpGoroutineStacks returns the stacks of all goroutines in the runtime.Stack format.
+// Unlike runtime.Stack with a fixed buffer, the dump is never truncated.
+func dumpGoroutineStacks() string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		logger.Log.Error("Error dumping goroutine stacks", "error", err)
+	}
+	return buf.String()
+}
+
+// pageBounds returns the slice bounds of the given 1-based page. A page size of 0 selects everything.
+func pageBounds(total, page, pageSize int) (int, int) {
+	if pageSize <= 0 {
+		return 0, total
+	}
+	start := min((page-1)*pageSize, total)
+	return start, min(start+pageSize, total)
+}
+
+// matchGoroutine reports whether the goroutine satisfies every filter of the query.
+func matchGoroutine(g *models.GoroutineInfo, query *models.GoroutineQuery) bool {
+	if query.State != "" && !strings.EqualFold(g.State, query.State) {
+		return false
+	}
+	if query.Signature != "" && g.Signature != query.Signature {
+		return false
+	}
+	if query.MinWait > 0 && float64(g.WaitMinutes) < query.MinWait.Minutes() {
+		return false
+	}
+	if query.Search == "" {
+		return true
+	}
+
+	search := strings.ToLower(query.Search)
+	contains := func(frame *models.GoroutineFrame) bool {
+		return strings.Contains(strings.ToLower(frame.Function), search) ||
+			strings.Contains(strings.ToLower(frame.File), search)
+	}
+	for i := range g.Frames {
+		if contains(&g.Frames[i]) {
+			return true
+		}
+	}
+	return g.CreatedBy != nil && contains(g.CreatedBy)
+}
+
+// SplitGoroutines splits the input stack trace into separate goroutine blocks based on new lines and "goroutine" identifiers.
+func SplitGoroutines(stackTrace string) []string {
+	var goroutines []string
+	var currentGoroutine strings.Builder
+
+	lines := strings.Split(stackTrace, "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "goroutine ") {
+			if currentGoroutine.Len() > 0 {
+				goroutines = append(goroutines, currentGoroutine.String())
+				currentGoroutine.Reset()
+			}
+		}
+		currentGoroutine.WriteString(line + "\n")
+	}
+
+	// Appening the last goroutine block if there's any content
+	if currentGoroutine.Len() > 0 {
+		goroutines = append(goroutines, currentGoroutine.String())
+	}
+
+	return goroutines
+}
+
+// ParseGoroutines parses a stack dump in the runtime.Stack format into goroutine records,
+// sorted by ID. Blocks that do not start with a goroutine header are skipped.
+func ParseGoroutines(stackTrace string) []models.GoroutineInfo {
+	blocks := SplitGoroutines(stackTrace)
+	goroutines := make([]models.GoroutineInfo, 0, len(blocks))
+	for _, block := range blocks {
+		if g, ok := parseGoroutine(block); ok {
+			goroutines = append(goroutines, g)
+		}
+	}
+	sort.Slice(goroutines, func(i, j int) bool {
+		return goroutines[i].ID < goroutines[j].ID
+	})
+	return goroutines
+}
+
+// parseGoroutine parses a single goroutine block:
+//
+//	goroutine 18 [chan receive, 5 minutes]:
+//	main.worker(0xc000012345)
+//		/app/main.go:42 +0x1d
+//	created by main.start in goroutine 1
+//		/app/main.go:30 +0x5e
+func parseGoroutine(block string) (models.GoroutineInfo, bool) {
+	stack := strings.TrimRight(block, "\n")
+	lines := strings.Split(stack, "\n")
+
+	g, ok := parseGoroutineHeader(lines[0])
+	if !ok {
+		return g, false
+	}
+	g.Stack = stack + "\n"
+
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "...") {
+			continue
+		}
+
+		frame := &models.GoroutineFrame{}
+		if created, ok := strings.CutPrefix(line, "created by "); ok {
+			function, creator, found := strings.Cut(created, " in goroutine ")
+			if found {
+				g.CreatorID, _ = strconv.ParseInt(creator, 10, 64)
+			}
+			frame.Function = function
+			g.CreatedBy = frame
+		} else {
+			frame.Function = trimCallArguments(line)
+		}
+
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			frame.File, frame.Line = parseFrameLocation(lines[i+1])
+			i++
+		}
+		if g.CreatedBy != frame {
+			g.Frames = append(g.Frames, *frame)
+		}
+	}
+
+	g.Signature = goroutineSignature(&g)
+	return g, true
+}
+
+// parseGoroutineHeader parses "goroutine 18 [chan receive, 5 minutes, locked to thread]:".
+func parseGoroutineHeader(line string) (models.GoroutineInfo, bool) {
+	var g models.GoroutineInfo
+
+	rest, ok := strings.CutPrefix(line, "goroutine ")
+	if !ok {
+		return g, false
+	}
+	id, rest, _ := strings.Cut(rest, " ")
+	var err error
+	if g.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return g, false
+	}
+
+	open, closing := strings.Index(rest, "["), strings.LastIndex(rest, "]")
+	if open < 0 || closing < open {
+		return g, false
+	}
+	for i, part := range strings.Split(rest[open+1:closing], ", ") {
+		switch {
+		case i == 0:
+			g.State = part
+		case part == "locked to thread":
+			g.LockedToThread = true
+		case strings.HasSuffix(part, " minutes") || strings.HasSuffix(part, " minute"):
+			g.WaitMinutes, _ = strconv.Atoi(strings.Fields(part)[0])
+		}
+	}
+	return g, true
+}
+
+// trimCallArguments strips the argument list from "main.(*T).run(0xc000010000, 0x1)".
+func trimCallArguments(line string) string {
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndex(line, "("); i > 0 {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseFrameLocation parses "\t/app/main.go:42 +0x1d" into the file and line.
+func parseFrameLocation(line string) (string, int) {
+	location, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+	i := strings.LastIndex(location, ":")
+	if i < 0 {
+		return location, 0
+	}
+	lineNumber, err := strconv.Atoi(location[i+1:])
+	if err != nil {
+		return location, 0
+	}
+	return location[:i], lineNumber
+}
+
+// goroutineSignature hashes the functions and lines of the stack and the creating call,
+// so goroutines parked at the same place share a signature regardless of arguments.
+func goroutineSignature(g *models.GoroutineInfo) string {
+	h := fnv.New64a()
+	for _, f := range g.Frames {
+		fmt.Fprintf(h, "%s:%d\n", f.Function, f.Line)
+	}
+	if g.CreatedBy != nil {
+		fmt.Fprintf(h, "created by %s:%d\n", g.CreatedBy.Function, g.CreatedBy.Line)
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// GroupGoroutines groups goroutines by stack signature, largest groups first.
+func GroupGoroutines(goroutines []models.GoroutineInfo) []models.GoroutineGroup {
+	index := make(map[string]int)
+	var groups []models.GoroutineGroup
+	for _, g := range goroutines {
+		i, ok := index[g.Signature]
+		if !ok {
+			i = len(groups)
+			index[g.Signature] = i
+			groups = append(groups, models.GoroutineGroup{
+				Signature:      g.Signature,
+				States:         make(map[string]int),
+				MinWaitMinutes: g.WaitMinutes,
+				CreatedBy:      g.CreatedBy,
+				Frames:         g.Frames,
+			})
+		}
+
+		group := &groups[i]
+		group.Count++
+		group.States[g.State]++
+		group.MinWaitMinutes = min(group.MinWaitMinutes, g.WaitMinutes)
+		group.MaxWaitMinutes = max(group.MaxWaitMinutes, g.WaitMinutes)
+		if len(group.IDs) < goroutineGroupMaxIDs {
+			group.IDs = append(group.IDs, g.ID)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Count > groups[j].Count
+	})
+	return groups
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+const sampleStackDump = `goroutine 7 [chan receive, 12 minutes]:
+main.worker(0xc000012345, 0x2)
+	/app/worker.go:42 +0x1d
+created by main.startWorkers in goroutine 1
+	/app/main.go:30 +0x5e
+
+goroutine 1 [running]:
+main.main()
+	/app/main.go:12 +0x25
+
+goroutine 9 [chan receive, 3 minutes, locked to thread]:
+main.worker(0xc000099999, 0x5)
+	/app/worker.go:42 +0x1d
+created by main.startWorkers in goroutine 1
+	/app/main.go:30 +0x5e
+
+goroutine 12 [select]:
+net/http.(*persistConn).writeLoop(0xc0001b2000)
+	/usr/local/go/src/net/http/transport.go:2421 +0xe5
+...additional frames elided...
+created by net/http.(*Transport).dialConn in goroutine 11
+	/usr/local/go/src/net/http/transport.go:1777 +0x16f1
+`
+
+func TestParseGoroutines(t *testing.T) {
+	goroutines := ParseGoroutines(sampleStackDump)
+	if len(goroutines) != 4 {
+		t.Fatalf("expected 4 goroutines, got %d", len(goroutines))
+	}
+
+	for i, id := range []int64{1, 7, 9, 12} {
+		if goroutines[i].ID != id {
+			t.Errorf("expected goroutine %d at position %d, got %d", id, i, goroutines[i].ID)
+		}
+	}
+
+	worker := goroutines[1]
+	if worker.State != "chan receive" || worker.WaitMinutes != 12 || worker.LockedToThread {
+		t.Errorf("unexpected header fields: %+v", worker)
+	}
+	want := models.GoroutineFrame{Function: "main.worker", File: "/app/worker.go", Line: 42}
+	if len(worker.Frames) != 1 || worker.Frames[0] != want {
+		t.Errorf("unexpected frames: %+v", worker.Frames)
+	}
+	if worker.CreatedBy == nil || worker.CreatedBy.Function != "main.startWorkers" || worker.CreatedBy.Line != 30 || worker.CreatorID != 1 {
+		t.Errorf("unexpected creator: %+v (goroutine %d)", worker.CreatedBy, worker.CreatorID)
+	}
+	if !strings.HasPrefix(worker.Stack, "goroutine 7 [chan receive, 12 minutes]:\n") {
+		t.Errorf("unexpected raw stack: %q", worker.Stack)
+	}
+
+	if !goroutines[2].LockedToThread || goroutines[2].WaitMinutes != 3 {
+		t.Errorf("expected locked goroutine waiting 3 minutes, got %+v", goroutines[2])
+	}
+	if goroutines[1].Signature != goroutines[2].Signature {
+		t.Error("expected goroutines parked at the same place to share a signature")
+	}
+	if goroutines[0].Signature == goroutines[1].Signature {
+		t.Error("expected different stacks to have different signatures")
+	}
+
+	transport := goroutines[3]
+	if len(transport.Frames) != 1 || transport.Frames[0].Function != "net/http.(*persistConn).writeLoop" {
+		t.Errorf("unexpected frames for method call: %+v", transport.Frames)
+	}
+}
+
+func TestGroupGoroutines(t *testing.T) {
+	groups := GroupGoroutines(ParseGoroutines(sampleStackDump))
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+
+	workers := groups[0]
+	if workers.Count != 2 || workers.States["chan receive"] != 2 {
+		t.Errorf("expected the two workers in the first group, got %+v", workers)
+	}
+	if workers.MinWaitMinutes != 3 || workers.MaxWaitMinutes != 12 {
+		t.Errorf("expected wait range 3-12 minutes, got %d-%d", workers.MinWaitMinutes, workers.MaxWaitMinutes)
+	}
+	if len(workers.IDs) != 2 || workers.IDs[0] != 7 || workers.IDs[1] != 9 {
+		t.Errorf("unexpected group IDs: %v", workers.IDs)
+	}
+}
+
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		total, page, pageSize int
+		start, end            int
+	}{
+		{10, 1, 0, 0, 10},
+		{10, 1, 4, 0, 4},
+		{10, 3, 4, 8, 10},
+		{10, 4, 4, 10, 10},
+	}
+	for _, tt := range tests {
+		start, end := pageBounds(tt.total, tt.page, tt.pageSize)
+		if start != tt.start || end != tt.end {
+			t.Errorf("pageBounds(%d, %d, %d) = %d, %d, want %d, %d",
+				tt.total, tt.page, tt.pageSize, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func parkedGoroutineForTest(release <-chan struct{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+	<-release
+}
+
+func TestQueryGoroutines(t *testing.T) {
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go parkedGoroutineForTest(release, &wg)
+	}
+	t.Cleanup(func() {
+		close(release)
+		wg.Wait()
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	query := models.GoroutineQuery{Search: "parkedGoroutineForTest", PageSize: 10, Page: 3}
+	stats := QueryGoroutines(query)
+	if stats.Total != 25 {
+		t.Fatalf("expected 25 matching goroutines, got %d", stats.Total)
+	}
+	if len(stats.Goroutines) != 5 || len(stats.StackView) != 5 {
+		t.Errorf("expected the last page to hold 5 goroutines, got %d", len(stats.Goroutines))
+	}
+	if stats.States["chan receive"] < 25 {
+		t.Errorf("expected at least 25 goroutines in chan receive, got %v", stats.States)
+	}
+
+	query.View = GoroutineViewGroups
+	query.Page = 1
+	groups := QueryGoroutines(query)
+	if groups.Total != 1 || groups.Groups[0].Count != 25 {
+		t.Fatalf("expected one group of 25 goroutines, got %+v", groups.Groups)
+	}
+
+	bySignature := QueryGoroutines(models.GoroutineQuery{Signature: groups.Groups[0].Signature, State: "CHAN RECEIVE"})
+	if bySignature.Total != 25 {
+		t.Errorf("expected signature filter to match 25 goroutines, got %d", bySignature.Total)
+	}
+
+	if waited := QueryGoroutines(models.GoroutineQuery{Search: "parkedGoroutineForTest", MinWait: time.Minute}); waited.Total != 0 {
+		t.Errorf("expected no goroutine blocked for a minute, got %d", waited.Total)
+	}
+}
@@ -1,146 +1,391 @@
 package core
 
 import (
+	"context"
 	"fmt"
-	"runtime"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/iyashjayesh/monigo/common"
 	"github.com/iyashjayesh/monigo/models"
 )
 
-// getProcessCPUUsage returns the CPU usage of the process
-func getServiceCPUUsage() (float64, error) {
-	return common.GetProcessObject().CPUPercent()
+// Names of the built-in health checks. The service's health is scored by the service checks
+// and the custom checks, the system's health by the system checks.
+const (
+	HealthCheckCPU          = "cpu"
+	HealthCheckMemory       = "memory"
+	HealthCheckGoroutines   = "goroutines"
+	HealthCheckFDs          = "fds"
+	HealthCheckThreads      = "threads"
+	HealthCheckSystemCPU    = "system_cpu"
+	HealthCheckSystemMemory = "system_memory"
+)
+
+// Health check statuses. Skipped checks do not count towards the score.
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusWarning  = "warning"
+	HealthStatusCritical = "critical"
+	HealthStatusSkipped  = "skipped"
+)
+
+// DefaultHealthCheckTimeout is how long a custom health check may take before it is reported critical.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// healthCheckTimeout is the timeout of custom health checks, shortened by tests.
+var healthCheckTimeout = DefaultHealthCheckTimeout
+
+// HealthCheck scores one aspect of the service's health.
+type HealthCheck interface {
+	// Name uniquely identifies the check, e.g. "queue_backlog".
+	Name() string
+	// Check returns a score from 0 (unhealthy) to 100 (healthy), a status and a message. An empty
+	// status is derived from the score. ctx is cancelled once DefaultHealthCheckTimeout elapses.
+	Check(ctx context.Context, stats *models.ServiceStats) models.HealthCheckResult
 }
 
-// getServiceGoroutines returns the number of goroutines in the service
-func getServiceGoroutines() int {
-	return runtime.NumGoroutine()
+// healthCheckEntry is a registered health check along with its configuration.
+type healthCheckEntry struct {
+	check   HealthCheck
+	builtin bool
+	system  bool // Scores the system's health instead of the service's
+	config  models.HealthCheckConfig
+	running *atomic.Bool // Set while a custom check runs, shared by the copies of the entry
 }
 
-// calculateMemoryUsagePercentage calculates memory usage percentage
-func calculateMemoryUsagePercentage(usedMemory, totalMemory string) (float64, error) {
-	totalMemoryMB, err := common.ConvertToMB(totalMemory)
-	if err != nil {
-		return 0, err
+var (
+	healthChecksMu sync.Mutex
+	healthChecks   = builtinHealthChecks()
+)
+
+// thresholdCheck scores a usage against its health threshold: 100 when idle, 0 at or above the threshold.
+type thresholdCheck struct {
+	name   string
+	format string // Message format taking the usage and the threshold
+	usage  func(stats *models.ServiceStats, thresholds models.ServiceHealthThresholds) (value, threshold float64, ok bool)
+}
+
+func (c thresholdCheck) Name() string { return c.name }
+
+func (c thresholdCheck) Check(_ context.Context, stats *models.ServiceStats) models.HealthCheckResult {
+	value, threshold, ok := c.usage(stats, serviceHealthThresholds)
+	if !ok || threshold <= 0 {
+		return models.HealthCheckResult{Status: HealthStatusSkipped, Message: "not available"}
 	}
-	usedMemoryMB, err := common.ConvertToMB(usedMemory)
-	if err != nil {
-		return 0, err
+	score := math.Max(0, 100-value/threshold*100)
+	return models.HealthCheckResult{
+		Score:   score,
+		Status:  statusForScore(score),
+		Message: fmt.Sprintf(c.format, value, threshold),
 	}
-	return (usedMemoryMB / totalMemoryMB) * 100, nil
 }
 
-// calculateServiceHealth calculates service health based on CPU, memory, and goroutines
-func calculateServiceHealth(stats *models.ServiceStats) (float64, string, error) {
-	cpuUsage, err := getServiceCPUUsage()
-	if err != nil {
-		return 0, "", fmt.Errorf("failed to get service CPU usage: %w", err)
+// builtinHealthChecks returns the checks scoring usage against the configured health thresholds.
+func builtinHealthChecks() []*healthCheckEntry {
+	builtin := func(system bool, check thresholdCheck) *healthCheckEntry {
+		return &healthCheckEntry{check: check, builtin: true, system: system}
 	}
 
-	totalAvailableCores := stats.CPUStatistics.TotalCores
-	cpuUsagePercentage := (cpuUsage / float64(totalAvailableCores)) * 100
+	return []*healthCheckEntry{
+		builtin(false, thresholdCheck{
+			name:   HealthCheckCPU,
+			format: "CPU Usage %.2f%% / %.2f%%",
+			usage: func(s *models.ServiceStats, t models.ServiceHealthThresholds) (float64, float64, bool) {
+				// Total cores are unknown when the CPU collector is disabled
+				cores := s.CPUStatistics.TotalCores
+				if cores <= 0 {
+					return 0, 0, false
+				}
+				return s.LoadStatistics.ServiceCPULoadRaw / float64(cores) * 100, t.MaxCPUUsage, true
+			},
+		}),
+		builtin(false, thresholdCheck{
+			name:   HealthCheckMemory,
+			format: "Memory Usage %.2f%% / %.2f%%",
+			usage: func(s *models.ServiceStats, t models.ServiceHealthThresholds) (float64, float64, bool) {
+				memory, err := calculateMemoryUsagePercentage(s.MemoryStatistics.MemoryUsedByService, s.MemoryStatistics.TotalSystemMemory)
+				return memory, t.MaxMemoryUsage, err == nil
+			},
+		}),
+		builtin(false, thresholdCheck{
+			name:   HealthCheckGoroutines,
+			format: "Goroutines %.0f / %.0f",
+			usage: func(s *models.ServiceStats, t models.ServiceHealthThresholds) (float64, float64, bool) {
+				return float64(s.CoreStatistics.Goroutines), float64(t.MaxGoRoutines), true
+			},
+		}),
+		builtin(false, thresholdCheck{
+			name:   HealthCheckFDs,
+			format: "File Descriptors %.2f%% / %.2f%%",
+			usage: func(s *models.ServiceStats, t models.ServiceHealthThresholds) (float64, float64, bool) {
+				// Only reported on platforms exposing the file descriptor limit
+				return s.ProcessStatistics.FDUsagePercent, t.MaxFDUsage, s.ProcessStatistics.MaxFDs > 0
+			},
+		}),
+		builtin(false, thresholdCheck{
+			name:   HealthCheckThreads,
+			format: "Threads %.0f / %.0f",
+			usage: func(s *models.ServiceStats, t models.ServiceHealthThresholds) (float64, float64, bool) {
+				return float64(s.ProcessStatistics.Threads), float64(t.MaxThreads), s.ProcessStatistics.Threads > 0
+			},
+		}),
+		builtin(true, thresholdCheck{
+			name:   HealthCheckSystemCPU,
+			format: "CPU Usage %.2f%% / %.2f%%",
+			usage: func(s *models.ServiceStats, t models.ServiceHealthThresholds) (float64, float64, bool) {
+				return s.LoadStatistics.TotalCPULoadRaw, t.MaxCPUUsage, true
+			},
+		}),
+		builtin(true, thresholdCheck{
+			name:   HealthCheckSystemMemory,
+			format: "Memory Usage %.2f%% / %.2f%%",
+			usage: func(s *models.ServiceStats, t models.ServiceHealthThresholds) (float64, float64, bool) {
+				memory, err := calculateMemoryUsagePercentage(s.MemoryStatistics.MemoryUsedBySystem, s.MemoryStatistics.TotalSystemMemory)
+				return memory, t.MaxMemoryUsage, err == nil
+			},
+		}),
+	}
+}
 
-	// Calculating memory usage percentage for the service
-	memoryUsagePercentage, err := calculateMemoryUsagePercentage(
-		stats.MemoryStatistics.MemoryUsedByService,
-		stats.MemoryStatistics.TotalSystemMemory,
-	)
-	if err != nil {
-		return 0, "", fmt.Errorf("failed to calculate memory usage percentage: %w", err)
-	}
-
-	// Calculating the health ratios for CPU, memory, and goroutines
-	cpuUsageRatio := (cpuUsagePercentage / serviceHealthThresholds.MaxCPUUsage) * 100
-	memoryUsageRatio := (memoryUsagePercentage / serviceHealthThresholds.MaxMemoryUsage) * 100
-	goRoutinesRatio := (float64(getServiceGoroutines()) / float64(serviceHealthThresholds.MaxGoRoutines)) * 100
-	finalScore := (cpuUsageRatio + memoryUsageRatio + goRoutinesRatio) / 3
-
-	var message string
-	if finalScore > 100 {
-		finalScore = 100
-		message = fmt.Sprintf(
-			"Service usage exceeds allowed limits: CPU Usage %.2f%% / %.2f%%, Memory Usage %.2f%% / %.2f%%, Goroutines %.2f / %d",
-			cpuUsageRatio, serviceHealthThresholds.MaxCPUUsage,
-			memoryUsageRatio, serviceHealthThresholds.MaxMemoryUsage,
-			goRoutinesRatio, serviceHealthThresholds.MaxGoRoutines,
-		)
+// statusForScore returns the status of a score: critical at 0, i.e. at or above the threshold,
+// and warning within 20% of it.
+func statusForScore(score float64) string {
+	switch {
+	case score <= 0:
+		return HealthStatusCritical
+	case score <= 20:
+		return HealthStatusWarning
+	default:
+		return HealthStatusOK
+	}
+}
+
+// findHealthCheck returns the entry registered under name. Callers must hold healthChecksMu.
+func findHealthCheck(name string) *healthCheckEntry {
+	for _, e := range healthChecks {
+		if e.check.Name() == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// RegisterHealthCheck adds a custom check to the service's health.
+func RegisterHealthCheck(check HealthCheck, config models.HealthCheckConfig) error {
+	if check == nil || check.Name() == "" {
+		return fmt.Errorf("health check name is required")
+	}
+
+	healthChecksMu.Lock()
+	defer healthChecksMu.Unlock()
+
+	if findHealthCheck(check.Name()) != nil {
+		return fmt.Errorf("health check %q is already registered", check.Name())
+	}
+	healthChecks = append(healthChecks, &healthCheckEntry{check: check, config: config, running: new(atomic.Bool)})
+	return nil
+}
+
+// UnregisterHealthCheck removes a custom health check. Built-in checks can only be reweighted.
+func UnregisterHealthCheck(name string) error {
+	healthChecksMu.Lock()
+	defer healthChecksMu.Unlock()
+
+	for i, e := range healthChecks {
+		if e.check.Name() != name {
+			continue
+		}
+		if e.builtin {
+			return fmt.Errorf("built-in health check %q cannot be unregistered", name)
+		}
+		healthChecks = append(healthChecks[:i], healthChecks[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("unknown health check %q", name)
+}
+
+// ConfigureHealthCheck sets the weight of the named check and whether it is critical.
+func ConfigureHealthCheck(name string, config models.HealthCheckConfig) error {
+	if config.Weight < 0 {
+		return fmt.Errorf("health check %q weight must not be negative", name)
+	}
+
+	healthChecksMu.Lock()
+	defer healthChecksMu.Unlock()
+
+	e := findHealthCheck(name)
+	if e == nil {
+		return fmt.Errorf("unknown health check %q", name)
+	}
+	e.config = config
+	return nil
+}
+
+// runHealthCheck runs a check and completes its result. Custom checks run with a timeout,
+// reporting critical when they exceed it or panic. A check ignoring the cancellation of its
+// context is not started again until its late run returns, reporting critical meanwhile.
+func runHealthCheck(ctx context.Context, e healthCheckEntry, stats models.ServiceStats) models.HealthCheckResult {
+	var result models.HealthCheckResult
+	if e.builtin {
+		result = e.check.Check(ctx, &stats)
+	} else if !e.running.CompareAndSwap(false, true) {
+		result = models.HealthCheckResult{Status: HealthStatusCritical, Message: "check is still running after timing out"}
 	} else {
-		finalScore = 100 - finalScore
-		message = fmt.Sprintf(
-			"Service usage is within limits: CPU Usage %.2f%% / %.2f%%, Memory Usage %.2f%% / %.2f%%, Goroutines %.2f / %d",
-			cpuUsageRatio, serviceHealthThresholds.MaxCPUUsage,
-			memoryUsageRatio, serviceHealthThresholds.MaxMemoryUsage,
-			goRoutinesRatio, serviceHealthThresholds.MaxGoRoutines,
-		)
+		ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+
+		done := make(chan models.HealthCheckResult, 1)
+		go func() {
+			defer e.running.Store(false)
+			defer func() {
+				if r := recover(); r != nil {
+					done <- models.HealthCheckResult{Status: HealthStatusCritical, Message: fmt.Sprintf("check panicked: %v", r)}
+				}
+			}()
+			done <- e.check.Check(ctx, &stats)
+		}()
+
+		select {
+		case result = <-done:
+		case <-ctx.Done():
+			result = models.HealthCheckResult{Status: HealthStatusCritical, Message: fmt.Sprintf("check did not finish: %v", ctx.Err())}
+		}
 	}
 
-	return finalScore, message, nil
+	result.Name = e.check.Name()
+	result.Score = common.RoundFloat64(math.Min(100, math.Max(0, result.Score)), 2)
+	switch result.Status {
+	case HealthStatusOK, HealthStatusWarning, HealthStatusCritical, HealthStatusSkipped:
+	default:
+		result.Status = statusForScore(result.Score)
+	}
+	result.Weight = e.config.Weight
+	if result.Weight == 0 {
+		result.Weight = 1
+	}
+	result.Critical = e.config.Critical
+	return result
 }
 
-// calculateSystemHealth calculates system health based on CPU and memory
-func calculateSystemHealth(stats *models.ServiceStats) (float64, string, error) {
+// evaluateHealth runs the service or system checks and combines their scores into a weighted
+// average. A critical check in critical status forces the overall status to critical and caps
+// the percentage at its score, whatever the average.
+func evaluateHealth(ctx context.Context, stats *models.ServiceStats, system bool) (models.Health, error) {
+	healthChecksMu.Lock()
+	var entries []healthCheckEntry
+	for _, e := range healthChecks {
+		if e.system == system {
+			entries = append(entries, *e)
+		}
+	}
+	healthChecksMu.Unlock()
 
-	// Calculating cpu & memory usage percentage for the system
-	cpuUsagePercentage, err := GetCPUPrecent()
-	if err != nil {
-		return 0, "", fmt.Errorf("failed to get CPU percent: %w", err)
+	results := make([]models.HealthCheckResult, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, e, *stats)
+		}()
 	}
-	memoryUsagePercentage, err := calculateMemoryUsagePercentage(
-		stats.MemoryStatistics.MemoryUsedBySystem,
-		stats.MemoryStatistics.TotalSystemMemory,
-	)
-	if err != nil {
-		return 0, "", fmt.Errorf("failed to calculate memory usage percentage: %w", err)
-	}
-
-	cpuUsageRatio := (cpuUsagePercentage / serviceHealthThresholds.MaxCPUUsage) * 100
-	memoryUsageRatio := (memoryUsagePercentage / serviceHealthThresholds.MaxMemoryUsage) * 100
-	finalScore := (cpuUsageRatio + memoryUsageRatio) / 2
-	var message string
-	if finalScore > 100 {
-		finalScore = 0
-		message = fmt.Sprintf(
-			"System usage exceeds allowed limits: CPU Usage %.2f%% / %.2f%%, Memory Usage %.2f%% / %.2f%%",
-			cpuUsageRatio, serviceHealthThresholds.MaxCPUUsage,
-			memoryUsageRatio, serviceHealthThresholds.MaxMemoryUsage,
-		)
+	wg.Wait()
+
+	var weighted, weights float64
+	var failed, messages []string
+	var forced *models.HealthCheckResult
+	exceeded := false
+	for i, r := range results {
+		if r.Status == HealthStatusSkipped {
+			continue
+		}
+		weighted += r.Score * r.Weight
+		weights += r.Weight
+		messages = append(messages, r.Message)
+		if r.Status != HealthStatusOK {
+			failed = append(failed, r.Name)
+		}
+		if r.Status == HealthStatusCritical {
+			exceeded = true
+			if r.Critical && (forced == nil || r.Score < forced.Score) {
+				forced = &results[i]
+			}
+		}
+	}
+	if weights == 0 {
+		return models.Health{}, fmt.Errorf("no health check reported a score")
+	}
+
+	health := models.Health{Percent: weighted / weights, Checks: results}
+	if forced != nil {
+		health.Percent = math.Min(health.Percent, forced.Score)
+	}
+	health.Percent = common.RoundFloat64(health.Percent, 2)
+	health.Healthy = health.Percent > 50 && forced == nil
+	health.Message = getStatusMessage(health.Percent)
+
+	switch {
+	case forced != nil:
+		health.Status = HealthStatusCritical
+		health.Message = fmt.Sprintf("[Critical] Critical health check %s failed: %s", forced.Name, forced.Message)
+	case health.Percent <= 30:
+		health.Status = HealthStatusCritical
+	case !health.Healthy || len(failed) > 0:
+		health.Status = HealthStatusWarning
+	default:
+		health.Status = HealthStatusOK
+	}
+
+	scope := "Service"
+	if system {
+		scope = "System"
+	}
+	if exceeded {
+		health.IconMsg = fmt.Sprintf("%s usage exceeds allowed limits: %s", scope, strings.Join(messages, ", "))
 	} else {
-		finalScore = 100 - finalScore
-		message = fmt.Sprintf(
-			"System usage is within limits: CPU Usage %.2f%% / %.2f%%, Memory Usage %.2f%% / %.2f%%",
-			cpuUsageRatio, serviceHealthThresholds.MaxCPUUsage,
-			memoryUsageRatio, serviceHealthThresholds.MaxMemoryUsage,
-		)
+		health.IconMsg = fmt.Sprintf("%s usage is within limits: %s", scope, strings.Join(messages, ", "))
 	}
+	return health, nil
+}
 
-	return finalScore, message, nil
+// calculateMemoryUsagePercentage calculates memory usage percentage
+func calculateMemoryUsagePercentage(usedMemory, totalMemory string) (float64, error) {
+	totalMemoryMB, err := common.ConvertToMB(totalMemory)
+	if err != nil {
+		return 0, err
+	}
+	usedMemoryMB, err := common.ConvertToMB(usedMemory)
+	if err != nil {
+		return 0, err
+	}
+	return (usedMemoryMB / totalMemoryMB) * 100, nil
 }
 
 // CalculateHealthScore calculates the health score of both the system and service
 func CalculateHealthScore(serviceStats *models.ServiceStats) (*models.SystemHealthInPercent, error) {
-	// Calculating system health
-	systemScore, systemMsg, err := calculateSystemHealth(serviceStats)
+	systemHealth, err := evaluateHealth(context.Background(), serviceStats, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate system health: %w", err)
 	}
-
-	// CalcCalculating service health
-	serviceScore, serviceMsg, err := calculateServiceHealth(serviceStats)
+	serviceHealth, err := evaluateHealth(context.Background(), serviceStats, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate service health: %w", err)
 	}
 
 	return &models.SystemHealthInPercent{
 		SystemHealth: models.HealthFields{
-			Percentage:    common.RoundFloat64(systemScore, 2),
+			Percentage:    systemHealth.Percent,
 			AllowedByUser: serviceHealthThresholds.MaxCPUUsage,
-			Message:       systemMsg,
+			Message:       systemHealth.IconMsg,
 		},
 		ServiceHealth: models.HealthFields{
-			Percentage:    common.RoundFloat64(serviceScore, 2),
+			Percentage:    serviceHealth.Percent,
 			AllowedByUser: serviceHealthThresholds.MaxCPUUsage,
-			Message:       serviceMsg,
+			Message:       serviceHealth.IconMsg,
 		},
 	}, nil
 }
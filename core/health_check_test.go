@@ -0,0 +1,192 @@
+package core
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+type staticHealthCheck struct {
+	name   string
+	result models.HealthCheckResult
+}
+
+func (c staticHealthCheck) Name() string { return c.name }
+
+func (c staticHealthCheck) Check(context.Context, *models.ServiceStats) models.HealthCheckResult {
+	return c.result
+}
+
+type panickingHealthCheck struct{}
+
+func (panickingHealthCheck) Name() string { return "panicking" }
+
+func (panickingHealthCheck) Check(context.Context, *models.ServiceStats) models.HealthCheckResult {
+	panic("boom")
+}
+
+// blockingHealthCheck ignores its context and returns once release is closed.
+type blockingHealthCheck struct {
+	release chan struct{}
+}
+
+func (blockingHealthCheck) Name() string { return "blocking" }
+
+func (c blockingHealthCheck) Check(context.Context, *models.ServiceStats) models.HealthCheckResult {
+	<-c.release
+	return models.HealthCheckResult{Score: 100}
+}
+
+// withHealthCheck registers a custom health check for the duration of the test.
+func withHealthCheck(t *testing.T, check HealthCheck, config models.HealthCheckConfig) {
+	t.Helper()
+	if err := RegisterHealthCheck(check, config); err != nil {
+		t.Fatalf("RegisterHealthCheck error: %v", err)
+	}
+	t.Cleanup(func() { UnregisterHealthCheck(check.Name()) })
+}
+
+// checkResult returns the named check's result from a health evaluation.
+func checkResult(t *testing.T, health models.Health, name string) models.HealthCheckResult {
+	t.Helper()
+	for _, r := range health.Checks {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no result for health check %q in %+v", name, health.Checks)
+	return models.HealthCheckResult{}
+}
+
+func TestThresholdHealthCheck(t *testing.T) {
+	withThresholds(t, models.ServiceHealthThresholds{MaxCPUUsage: 80, MaxMemoryUsage: 80, MaxGoRoutines: 100})
+
+	tests := []struct {
+		goroutines int
+		score      float64
+		status     string
+	}{
+		{50, 50, HealthStatusOK},
+		{90, 10, HealthStatusWarning},
+		{150, 0, HealthStatusCritical},
+	}
+	for _, tt := range tests {
+		health, err := evaluateHealth(context.Background(), incidentStats(tt.goroutines), false)
+		if err != nil {
+			t.Fatalf("evaluateHealth error: %v", err)
+		}
+		r := checkResult(t, health, HealthCheckGoroutines)
+		if r.Score != tt.score || r.Status != tt.status {
+			t.Errorf("%d goroutines: expected score %v and status %s, got %+v", tt.goroutines, tt.score, tt.status, r)
+		}
+	}
+
+	// Memory is not reported in these stats and must not count towards the score
+	health, _ := evaluateHealth(context.Background(), incidentStats(50), false)
+	if r := checkResult(t, health, HealthCheckMemory); r.Status != HealthStatusSkipped {
+		t.Errorf("expected memory check to be skipped, got %+v", r)
+	}
+}
+
+func TestEvaluateHealthWeights(t *testing.T) {
+	withThresholds(t, models.ServiceHealthThresholds{MaxCPUUsage: 100, MaxMemoryUsage: 80, MaxGoRoutines: 100})
+	withHealthCheck(t, staticHealthCheck{name: "database", result: models.HealthCheckResult{Score: 0, Message: "ping failed"}}, models.HealthCheckConfig{Weight: 2})
+
+	// CPU scores 50, goroutines 50 and the database 0 with twice the weight
+	health, err := evaluateHealth(context.Background(), incidentStats(50), false)
+	if err != nil {
+		t.Fatalf("evaluateHealth error: %v", err)
+	}
+	if health.Percent != 25 {
+		t.Errorf("expected weighted score 25, got %v", health.Percent)
+	}
+	if r := checkResult(t, health, "database"); r.Status != HealthStatusCritical || r.Weight != 2 || r.Critical {
+		t.Errorf("unexpected database result: %+v", r)
+	}
+	if health.Status != HealthStatusCritical || health.Healthy {
+		t.Errorf("expected an unhealthy critical status at 25%%, got %+v", health)
+	}
+}
+
+func TestEvaluateHealthCriticalCheck(t *testing.T) {
+	withThresholds(t, models.ServiceHealthThresholds{MaxCPUUsage: 80, MaxMemoryUsage: 80, MaxGoRoutines: 1000})
+	withHealthCheck(t, staticHealthCheck{name: "database", result: models.HealthCheckResult{Score: 0, Message: "ping failed"}}, models.HealthCheckConfig{Weight: 0.1})
+
+	health, _ := evaluateHealth(context.Background(), incidentStats(10), false)
+	if !health.Healthy || health.Status != HealthStatusWarning {
+		t.Fatalf("expected a failing low-weight check to only degrade the status, got %+v", health)
+	}
+
+	if err := ConfigureHealthCheck("database", models.HealthCheckConfig{Weight: 0.1, Critical: true}); err != nil {
+		t.Fatalf("ConfigureHealthCheck error: %v", err)
+	}
+	health, _ = evaluateHealth(context.Background(), incidentStats(10), false)
+	if health.Healthy || health.Status != HealthStatusCritical || health.Percent != 0 {
+		t.Errorf("expected a failing critical check to force the status down, got %+v", health)
+	}
+}
+
+func TestCustomHealthCheckPanic(t *testing.T) {
+	withThresholds(t, models.ServiceHealthThresholds{MaxCPUUsage: 80, MaxMemoryUsage: 80, MaxGoRoutines: 100})
+	withHealthCheck(t, panickingHealthCheck{}, models.HealthCheckConfig{})
+
+	health, err := evaluateHealth(context.Background(), incidentStats(10), false)
+	if err != nil {
+		t.Fatalf("evaluateHealth error: %v", err)
+	}
+	if r := checkResult(t, health, "panicking"); r.Status != HealthStatusCritical || r.Weight != 1 {
+		t.Errorf("expected a panicking check to be critical with the default weight, got %+v", r)
+	}
+}
+
+func TestHealthCheckRegistry(t *testing.T) {
+	if err := RegisterHealthCheck(staticHealthCheck{name: HealthCheckCPU}, models.HealthCheckConfig{}); err == nil {
+		t.Error("expected an error registering a check under a built-in name")
+	}
+	if err := UnregisterHealthCheck(HealthCheckCPU); err == nil {
+		t.Error("expected an error unregistering a built-in check")
+	}
+	if err := ConfigureHealthCheck("unknown", models.HealthCheckConfig{}); err == nil {
+		t.Error("expected an error configuring an unknown check")
+	}
+	if err := ConfigureHealthCheck(HealthCheckCPU, models.HealthCheckConfig{Weight: -1}); err == nil {
+		t.Error("expected an error for a negative weight")
+	}
+}
+
+func TestBlockingHealthCheckIsNotRestarted(t *testing.T) {
+	withThresholds(t, models.ServiceHealthThresholds{MaxCPUUsage: 80, MaxMemoryUsage: 80, MaxGoRoutines: 100})
+	saved := healthCheckTimeout
+	healthCheckTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { healthCheckTimeout = saved })
+	check := blockingHealthCheck{release: make(chan struct{})}
+	withHealthCheck(t, check, models.HealthCheckConfig{})
+
+	before := runtime.NumGoroutine()
+	for range 20 {
+		health, _ := evaluateHealth(context.Background(), incidentStats(10), false)
+		if r := checkResult(t, health, "blocking"); r.Status != HealthStatusCritical {
+			t.Fatalf("expected a check that does not finish to be critical, got %+v", r)
+		}
+	}
+	if after := runtime.NumGoroutine(); after > before+2 {
+		t.Errorf("expected a single run of the blocked check, goroutines grew from %d to %d", before, after)
+	}
+
+	// Once the late run returns, the check runs again
+	close(check.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		health, _ := evaluateHealth(context.Background(), incidentStats(10), false)
+		if checkResult(t, health, "blocking").Status == HealthStatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the check to run again after returning")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
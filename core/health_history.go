@@ -0,0 +1,267 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// Health scopes of the recorded health events.
+const (
+	HealthScopeService = "service"
+	HealthScopeSystem  = "system"
+)
+
+// DefaultHealthHysteresis is how many percentage points the health must move past a level
+// boundary before its level changes, unless configured otherwise.
+const DefaultHealthHysteresis = 5.0
+
+var (
+	healthHistoryMu     sync.Mutex
+	healthHistoryConfig *models.HealthHistoryConfig // Nil while health history is disabled
+	healthLevelByScope  map[string]int              // Current level index in healthLevels per scope
+	healthPrunedAt      time.Time
+)
+
+// healthPruneInterval is how often expired health events are removed while recording.
+const healthPruneInterval = time.Hour
+
+// ConfigureHealthHistory enables recording the health level transitions of the service and the
+// system, and removes the events older than the retention.
+func ConfigureHealthHistory(config *models.HealthHistoryConfig) {
+	cfg := *config
+	if cfg.Hysteresis <= 0 {
+		cfg.Hysteresis = DefaultHealthHysteresis
+	}
+
+	healthHistoryMu.Lock()
+	defer healthHistoryMu.Unlock()
+
+	healthHistoryConfig = &cfg
+	healthLevelByScope = make(map[string]int)
+	pruneExpiredHealthEvents(time.Now())
+}
+
+// pruneExpiredHealthEvents removes the events older than the retention. Callers hold healthHistoryMu.
+func pruneExpiredHealthEvents(now time.Time) {
+	healthPrunedAt = now
+	if healthHistoryConfig.Retention <= 0 {
+		return
+	}
+	if err := pruneHealthEvents(now.Add(-healthHistoryConfig.Retention)); err != nil {
+		logger.Log.Warn("failed to remove expired health events", "error", err)
+	}
+}
+
+// StopHealthHistory disables recording health level transitions.
+func StopHealthHistory() {
+	healthHistoryMu.Lock()
+	defer healthHistoryMu.Unlock()
+
+	healthHistoryConfig, healthLevelByScope = nil, nil
+}
+
+// nextHealthLevel returns the level of a health given its current level. The level only changes
+// once the score leaves the current level's range widened by the hysteresis on both sides, so a
+// score hovering around a boundary does not flap. A critical health check failing forces the
+// critical level straight away, while a low score goes through the hysteresis like any other.
+func nextHealthLevel(current int, health models.Health, hysteresis float64) int {
+	level := healthLevel(health.Percent)
+	if criticalCheckFailed(health) {
+		return len(healthLevels) - 1
+	}
+
+	upper := math.Inf(1)
+	if current > 0 {
+		upper = healthLevels[current-1].min
+	}
+	if health.Percent >= healthLevels[current].min-hysteresis && health.Percent < upper+hysteresis {
+		return current
+	}
+	return level
+}
+
+// criticalCheckFailed reports whether a health check configured as critical is in critical status.
+func criticalCheckFailed(health models.Health) bool {
+	for _, c := range health.Checks {
+		if c.Critical && c.Status == HealthStatusCritical {
+			return true
+		}
+	}
+	return false
+}
+
+// recordHealthTransitions records an event for the service's and the system's health when its
+// level changed since the previous snapshot. The first snapshot only sets the levels.
+func recordHealthTransitions(health *models.ServiceHealth, now time.Time) {
+	healthHistoryMu.Lock()
+	defer healthHistoryMu.Unlock()
+
+	if healthHistoryConfig == nil {
+		return
+	}
+
+	for _, scoped := range []struct {
+		scope  string
+		health *models.Health
+	}{
+		{HealthScopeService, &health.ServiceHealth},
+		{HealthScopeSystem, &health.SystemHealth},
+	} {
+		current, ok := healthLevelByScope[scoped.scope]
+		if !ok {
+			healthLevelByScope[scoped.scope] = nextHealthLevel(healthLevel(scoped.health.Percent), *scoped.health, 0)
+			continue
+		}
+
+		next := nextHealthLevel(current, *scoped.health, healthHistoryConfig.Hysteresis)
+		if next == current {
+			continue
+		}
+		healthLevelByScope[scoped.scope] = next
+
+		event := models.HealthEvent{
+			Time:    now,
+			Scope:   scoped.scope,
+			From:    healthLevels[current].name,
+			To:      healthLevels[next].name,
+			Percent: scoped.health.Percent,
+			Status:  scoped.health.Status,
+			Message: scoped.health.Message,
+			Checks:  scoped.health.Checks,
+		}
+		if event.Checks == nil {
+			event.Checks = []models.HealthCheckResult{}
+		}
+		logger.Log.Info("health level changed", "scope", event.Scope, "from", event.From, "to", event.To, "percent", event.Percent)
+		if err := appendHealthEvent(&event); err != nil {
+			logger.Log.Error("failed to record health event", "error", err)
+		}
+	}
+
+	if now.Sub(healthPrunedAt) >= healthPruneInterval {
+		pruneExpiredHealthEvents(now)
+	}
+}
+
+// healthEventsPath returns the file health events are appended to, one JSON event per line.
+func healthEventsPath() string {
+	return filepath.Join(basePath, "health_events.log")
+}
+
+// appendHealthEvent appends an event to the health history. Callers hold healthHistoryMu.
+func appendHealthEvent(event *models.HealthEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(healthEventsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// readHealthEvents returns the recorded health events, oldest first. Callers hold healthHistoryMu.
+func readHealthEvents() ([]models.HealthEvent, error) {
+	events := []models.HealthEvent{}
+
+	f, err := os.Open(healthEventsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return events, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event models.HealthEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// pruneHealthEvents removes the events recorded before the given time, rewriting the history
+// through a temporary file. Callers hold healthHistoryMu.
+func pruneHealthEvents(before time.Time) error {
+	events, err := readHealthEvents()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	kept := 0
+	for _, event := range events {
+		if event.Time.Before(before) {
+			continue
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(data, '\n'))
+		kept++
+	}
+	if kept == len(events) {
+		return nil
+	}
+
+	path := healthEventsPath()
+	if err := os.WriteFile(path+".tmp", buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	return nil
+}
+
+// GetHealthEvents returns the health level transitions matching the query, oldest first.
+func GetHealthEvents(query models.HealthEventQuery) ([]models.HealthEvent, error) {
+	if query.Scope != "" && query.Scope != HealthScopeService && query.Scope != HealthScopeSystem {
+		return nil, fmt.Errorf("unknown health scope %q, expected %s or %s", query.Scope, HealthScopeService, HealthScopeSystem)
+	}
+
+	healthHistoryMu.Lock()
+	events, err := readHealthEvents()
+	healthHistoryMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []models.HealthEvent{}
+	for _, event := range events {
+		if query.Scope != "" && event.Scope != query.Scope {
+			continue
+		}
+		if (!query.From.IsZero() && event.Time.Before(query.From)) || (!query.To.IsZero() && event.Time.After(query.To)) {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered, nil
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// withHealthHistory records health transitions in a temporary directory for the duration of the test.
+func withHealthHistory(t *testing.T, config models.HealthHistoryConfig) {
+	t.Helper()
+	withProfileStore(t)
+	ConfigureHealthHistory(&config)
+	t.Cleanup(StopHealthHistory)
+}
+
+func TestNextHealthLevel(t *testing.T) {
+	good, satisfactory, fair, poor, critical := healthLevel(87), healthLevel(75), healthLevel(60), healthLevel(40), len(healthLevels)-1
+	failing := []models.HealthCheckResult{{Name: "database", Status: HealthStatusCritical, Critical: true}}
+
+	tests := []struct {
+		name    string
+		current int
+		health  models.Health
+		want    int
+	}{
+		{"within the level", good, models.Health{Percent: 87, Status: HealthStatusOK}, good},
+		{"just below the boundary", good, models.Health{Percent: 82, Status: HealthStatusOK}, good},
+		{"past the hysteresis", good, models.Health{Percent: 79, Status: HealthStatusOK}, satisfactory},
+		{"just above the boundary", satisfactory, models.Health{Percent: 88, Status: HealthStatusOK}, satisfactory},
+		{"recovered past the hysteresis", satisfactory, models.Health{Percent: 91, Status: HealthStatusOK}, healthLevel(91)},
+		{"critical check failing", good, models.Health{Percent: 87, Status: HealthStatusCritical, Checks: failing}, critical},
+		{"critical status without a critical check", good, models.Health{Percent: 87, Status: HealthStatusCritical}, good},
+		{"poor at 30", poor, models.Health{Percent: 30, Status: HealthStatusCritical}, poor},
+		{"poor just below 30", poor, models.Health{Percent: 29.9, Status: HealthStatusCritical}, poor},
+		{"poor past the hysteresis below 30", poor, models.Health{Percent: 24.9, Status: HealthStatusCritical}, critical},
+		{"fair down to 30", fair, models.Health{Percent: 30, Status: HealthStatusCritical}, poor},
+		{"fair down to 29.9", fair, models.Health{Percent: 29.9, Status: HealthStatusCritical}, critical},
+		{"critical up to 30", critical, models.Health{Percent: 30, Status: HealthStatusCritical}, critical},
+		{"critical past the hysteresis above 30", critical, models.Health{Percent: 35, Status: HealthStatusWarning}, poor},
+		{"satisfactory at 70", satisfactory, models.Health{Percent: 70, Status: HealthStatusWarning}, satisfactory},
+		{"satisfactory just below 70", satisfactory, models.Health{Percent: 69.9, Status: HealthStatusWarning}, satisfactory},
+		{"satisfactory past the hysteresis below 70", satisfactory, models.Health{Percent: 64.9, Status: HealthStatusWarning}, fair},
+		{"fair up to 70", fair, models.Health{Percent: 70, Status: HealthStatusWarning}, fair},
+		{"fair past the hysteresis above 70", fair, models.Health{Percent: 75, Status: HealthStatusOK}, satisfactory},
+	}
+	for _, tt := range tests {
+		if got := nextHealthLevel(tt.current, tt.health, 5); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, healthLevels[tt.want].name, healthLevels[got].name)
+		}
+	}
+}
+
+func TestRecordHealthTransitions(t *testing.T) {
+	withHealthHistory(t, models.HealthHistoryConfig{Hysteresis: 5})
+
+	start := time.Now()
+	checks := []models.HealthCheckResult{{Name: HealthCheckGoroutines, Score: 10, Status: HealthStatusWarning}}
+	for i, percent := range []float64{95, 88, 80, 72, 68, 60} {
+		recordHealthTransitions(&models.ServiceHealth{
+			ServiceHealth: models.Health{Percent: percent, Status: HealthStatusOK, Checks: checks},
+			SystemHealth:  models.Health{Percent: 95, Status: HealthStatusOK},
+		}, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	events, err := GetHealthEvents(models.HealthEventQuery{})
+	if err != nil {
+		t.Fatalf("GetHealthEvents error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 transitions, got %+v", events)
+	}
+	if e := events[0]; e.Scope != HealthScopeService || e.From != "Excellent" || e.To != "Satisfactory" || e.Percent != 80 || len(e.Checks) != 1 {
+		t.Errorf("unexpected first transition: %+v", e)
+	}
+	if e := events[1]; e.From != "Satisfactory" || e.To != "Fair" {
+		t.Errorf("unexpected second transition: %+v", e)
+	}
+
+	events, _ = GetHealthEvents(models.HealthEventQuery{From: start.Add(3 * time.Minute)})
+	if len(events) != 1 || events[0].To != "Fair" {
+		t.Errorf("expected the transitions after the from time, got %+v", events)
+	}
+	events, _ = GetHealthEvents(models.HealthEventQuery{Scope: HealthScopeSystem})
+	if len(events) != 0 {
+		t.Errorf("expected no system transitions, got %+v", events)
+	}
+	if _, err := GetHealthEvents(models.HealthEventQuery{Scope: "database"}); err == nil {
+		t.Error("expected an error for an unknown scope")
+	}
+}
+
+func TestHealthHistoryRetention(t *testing.T) {
+	withHealthHistory(t, models.HealthHistoryConfig{})
+
+	healthHistoryMu.Lock()
+	for _, age := range []time.Duration{48 * time.Hour, time.Hour} {
+		if err := appendHealthEvent(&models.HealthEvent{Time: time.Now().Add(-age), Scope: HealthScopeService}); err != nil {
+			t.Fatalf("appendHealthEvent error: %v", err)
+		}
+	}
+	healthHistoryMu.Unlock()
+
+	ConfigureHealthHistory(&models.HealthHistoryConfig{Retention: 24 * time.Hour})
+	events, err := GetHealthEvents(models.HealthEventQuery{})
+	if err != nil {
+		t.Fatalf("GetHealthEvents error: %v", err)
+	}
+	if len(events) != 1 || time.Since(events[0].Time) > 2*time.Hour {
+		t.Errorf("expected only the event within the retention, got %+v", events)
+	}
+}
+
+func TestRecordHealthTransitionsDisabled(t *testing.T) {
+	withProfileStore(t)
+
+	for _, percent := range []float64{95, 10} {
+		recordHealthTransitions(&models.ServiceHealth{ServiceHealth: models.Health{Percent: percent}}, time.Now())
+	}
+	if events, _ := GetHealthEvents(models.HealthEventQuery{}); len(events) != 0 {
+		t.Errorf("expected no events while health history is disabled, got %+v", events)
+	}
+}
@@ -0,0 +1,304 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// ProfileSourceIncident marks profiles captured for an incident.
+const ProfileSourceIncident = "incident"
+
+const (
+	// DefaultIncidentCooldown is the minimum time between two incidents unless configured otherwise.
+	DefaultIncidentCooldown = 15 * time.Minute
+	// DefaultIncidentCPUDuration is the length of an incident's CPU profile unless configured otherwise.
+	DefaultIncidentCPUDuration = 10 * time.Second
+)
+
+// ErrIncidentNotFound is returned when no incident has the requested ID.
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// Incidents are stored as <unix nano>.json, their profiles in the profile store.
+var incidentIDRegex = regexp.MustCompile(`^\d+$`)
+
+var (
+	incidentMu        sync.Mutex
+	incidentConfig    *models.IncidentConfig // Nil while incident capture is disabled
+	lastIncidentAt    time.Time
+	incidentCapturing bool
+	incidentCtx       context.Context
+	incidentCancel    context.CancelFunc
+	incidentWG        sync.WaitGroup
+)
+
+// ConfigureIncidentCapture enables capturing a CPU profile, a heap profile and a goroutine
+// profile whenever a health threshold is crossed, at most once per cooldown.
+func ConfigureIncidentCapture(config *models.IncidentConfig) {
+	StopIncidentCapture()
+
+	incidentMu.Lock()
+	defer incidentMu.Unlock()
+
+	cfg := *config
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultIncidentCooldown
+	}
+	if cfg.CPUDuration <= 0 {
+		cfg.CPUDuration = DefaultIncidentCPUDuration
+	}
+	cfg.CPUDuration = min(cfg.CPUDuration, MaxProfileDuration)
+	incidentConfig = &cfg
+	incidentCtx, incidentCancel = context.WithCancel(context.Background())
+}
+
+// StopIncidentCapture disables incident capture and waits for a running capture to end.
+// Safe to call multiple times.
+func StopIncidentCapture() {
+	incidentMu.Lock()
+	cancel := incidentCancel
+	incidentConfig, incidentCtx, incidentCancel = nil, nil, nil
+	incidentMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	incidentWG.Wait()
+}
+
+// incidentMetrics compares the service's usage with the health thresholds. File descriptors
+// and threads are only compared when a threshold is set and the platform reports them.
+func incidentMetrics(stats *models.ServiceStats) []models.IncidentMetric {
+	thresholds := serviceHealthThresholds
+	var metrics []models.IncidentMetric
+	add := func(name string, value, threshold float64) {
+		metrics = append(metrics, models.IncidentMetric{
+			Name:      name,
+			Value:     common.RoundFloat64(value, 2),
+			Threshold: threshold,
+			Breached:  threshold > 0 && value > threshold,
+		})
+	}
+
+	// Total cores are unknown when the CPU collector is disabled
+	if cores := stats.CPUStatistics.TotalCores; cores > 0 {
+		add("cpu_usage", stats.LoadStatistics.ServiceCPULoadRaw/float64(cores)*100, thresholds.MaxCPUUsage)
+	}
+	if memory, err := calculateMemoryUsagePercentage(stats.MemoryStatistics.MemoryUsedByService, stats.MemoryStatistics.TotalSystemMemory); err == nil {
+		add("memory_usage", memory, thresholds.MaxMemoryUsage)
+	}
+	add("goroutines", float64(stats.CoreStatistics.Goroutines), float64(thresholds.MaxGoRoutines))
+	if thresholds.MaxFDUsage > 0 && stats.ProcessStatistics.MaxFDs > 0 {
+		add("fd_usage", stats.ProcessStatistics.FDUsagePercent, thresholds.MaxFDUsage)
+	}
+	if thresholds.MaxThreads > 0 && stats.ProcessStatistics.Threads > 0 {
+		add("threads", float64(stats.ProcessStatistics.Threads), float64(thresholds.MaxThreads))
+	}
+	return metrics
+}
+
+// checkIncident starts capturing an incident in the background when a health threshold is
+// crossed, incident capture is enabled and the previous incident is older than the cooldown.
+func checkIncident(stats *models.ServiceStats, now time.Time) {
+	incidentMu.Lock()
+	defer incidentMu.Unlock()
+
+	if incidentConfig == nil || incidentCapturing || now.Sub(lastIncidentAt) < incidentConfig.Cooldown {
+		return
+	}
+
+	metrics := incidentMetrics(stats)
+	var breached []string
+	for _, m := range metrics {
+		if m.Breached {
+			breached = append(breached, m.Name)
+		}
+	}
+	if len(breached) == 0 {
+		return
+	}
+
+	lastIncidentAt, incidentCapturing = now, true
+	ctx, config := incidentCtx, *incidentConfig
+	logger.Log.Warn("health threshold crossed, capturing incident profiles", "metrics", strings.Join(breached, ","))
+
+	incidentWG.Add(1)
+	go func() {
+		defer incidentWG.Done()
+		defer func() {
+			incidentMu.Lock()
+			incidentCapturing = false
+			incidentMu.Unlock()
+		}()
+
+		if _, err := captureIncident(ctx, &config, metrics, now); err != nil {
+			logger.Log.Error("failed to store incident", "error", err)
+		}
+	}()
+}
+
+// captureIncident captures and stores the profiles of an incident, then removes the incidents
+// older than the retention.
+func captureIncident(ctx context.Context, config *models.IncidentConfig, metrics []models.IncidentMetric, now time.Time) (models.Incident, error) {
+	incident := models.Incident{
+		ID:        fmt.Sprintf("%d", now.UnixNano()),
+		CreatedAt: now,
+		Metrics:   metrics,
+		Profiles:  []models.ProfileRecord{},
+	}
+
+	requests := []models.ProfileRequest{
+		{Type: ProfileCPU, Duration: config.CPUDuration},
+		{Type: ProfileHeap},
+		{Type: ProfileGoroutine},
+	}
+	for _, req := range requests {
+		start := time.Now()
+		data, err := CaptureProfile(ctx, req)
+		if err == nil {
+			var record models.ProfileRecord
+			record, err = StoreProfile(ProfileSourceIncident, req.Type, start, time.Since(start).Round(time.Millisecond), data)
+			incident.Profiles = append(incident.Profiles, record)
+		}
+		if err != nil {
+			incident.Errors = append(incident.Errors, fmt.Sprintf("%s: %v", req.Type, err))
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if err := writeIncident(&incident); err != nil {
+		for _, p := range incident.Profiles {
+			DeleteProfile(p.ID)
+		}
+		return incident, err
+	}
+	logger.Log.Info("incident captured", "id", incident.ID, "profiles", len(incident.Profiles))
+
+	if config.Retention > 0 {
+		if err := pruneIncidents(now.Add(-config.Retention)); err != nil {
+			logger.Log.Warn("failed to remove expired incidents", "error", err)
+		}
+	}
+	return incident, nil
+}
+
+// incidentDir returns the directory holding stored incidents.
+func incidentDir() string {
+	return filepath.Join(basePath, "incidents")
+}
+
+// writeIncident stores an incident, through a temporary file so listings never see a partial one.
+func writeIncident(incident *models.Incident) error {
+	data, err := json.Marshal(incident)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(incidentDir(), os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create incident directory: %w", err)
+	}
+
+	path := filepath.Join(incidentDir(), incident.ID+".json")
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	return nil
+}
+
+// readIncident returns a stored incident.
+func readIncident(id string) (models.Incident, error) {
+	if !incidentIDRegex.MatchString(id) {
+		return models.Incident{}, ErrIncidentNotFound
+	}
+	data, err := os.ReadFile(filepath.Join(incidentDir(), id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return models.Incident{}, ErrIncidentNotFound
+		}
+		return models.Incident{}, err
+	}
+
+	var incident models.Incident
+	if err := json.Unmarshal(data, &incident); err != nil {
+		return models.Incident{}, fmt.Errorf("failed to decode incident %s: %w", id, err)
+	}
+	return incident, nil
+}
+
+// ListIncidents returns the stored incidents, newest first.
+func ListIncidents() ([]models.Incident, error) {
+	incidents := []models.Incident{}
+
+	entries, err := os.ReadDir(incidentDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return incidents, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		incident, err := readIncident(id)
+		if err != nil {
+			logger.Log.Warn("skipping unreadable incident", "id", id, "error", err)
+			continue
+		}
+		incidents = append(incidents, incident)
+	}
+
+	sort.Slice(incidents, func(i, j int) bool {
+		return incidents[i].CreatedAt.After(incidents[j].CreatedAt)
+	})
+	return incidents, nil
+}
+
+// DeleteIncident removes a stored incident and its profiles.
+func DeleteIncident(id string) error {
+	incident, err := readIncident(id)
+	if err != nil {
+		return err
+	}
+	for _, p := range incident.Profiles {
+		if err := DeleteProfile(p.ID); err != nil && !errors.Is(err, ErrProfileNotFound) {
+			return err
+		}
+	}
+	return os.Remove(filepath.Join(incidentDir(), id+".json"))
+}
+
+// pruneIncidents removes the incidents captured before the given time.
+func pruneIncidents(before time.Time) error {
+	incidents, err := ListIncidents()
+	if err != nil {
+		return err
+	}
+	for _, incident := range incidents {
+		if incident.CreatedAt.Before(before) {
+			if err := DeleteIncident(incident.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
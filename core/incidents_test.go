@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// withThresholds sets the health thresholds for the duration of the test.
+func withThresholds(t *testing.T, thresholds models.ServiceHealthThresholds) {
+	t.Helper()
+	saved := serviceHealthThresholds
+	serviceHealthThresholds = thresholds
+	t.Cleanup(func() { serviceHealthThresholds = saved })
+}
+
+func incidentStats(goroutines int) *models.ServiceStats {
+	stats := &models.ServiceStats{}
+	stats.CoreStatistics.Goroutines = goroutines
+	stats.CPUStatistics.TotalCores = 4
+	stats.LoadStatistics.ServiceCPULoadRaw = 2
+	return stats
+}
+
+func TestIncidentMetrics(t *testing.T) {
+	withThresholds(t, models.ServiceHealthThresholds{MaxCPUUsage: 80, MaxMemoryUsage: 80, MaxGoRoutines: 100})
+
+	metrics := incidentMetrics(incidentStats(150))
+	byName := map[string]models.IncidentMetric{}
+	for _, m := range metrics {
+		byName[m.Name] = m
+	}
+
+	if cpu := byName["cpu_usage"]; cpu.Value != 50 || cpu.Threshold != 80 || cpu.Breached {
+		t.Errorf("unexpected cpu metric: %+v", cpu)
+	}
+	if g := byName["goroutines"]; g.Value != 150 || !g.Breached {
+		t.Errorf("expected the goroutine threshold to be breached: %+v", g)
+	}
+	if _, ok := byName["memory_usage"]; ok {
+		t.Error("expected memory usage to be skipped without memory statistics")
+	}
+	if _, ok := byName["fd_usage"]; ok {
+		t.Error("expected file descriptors to be skipped without a threshold")
+	}
+}
+
+// waitForIncidentCapture waits until no incident is being captured.
+func waitForIncidentCapture(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		incidentMu.Lock()
+		capturing := incidentCapturing
+		incidentMu.Unlock()
+		if !capturing {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("incident capture did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCheckIncident(t *testing.T) {
+	withProfileStore(t)
+	withThresholds(t, models.ServiceHealthThresholds{MaxCPUUsage: 80, MaxMemoryUsage: 80, MaxGoRoutines: 100})
+
+	now := time.Now()
+	checkIncident(incidentStats(150), now)
+	if incidents, _ := ListIncidents(); len(incidents) != 0 {
+		t.Fatalf("expected no incident while capture is disabled, got %d", len(incidents))
+	}
+
+	ConfigureIncidentCapture(&models.IncidentConfig{Cooldown: time.Hour, CPUDuration: 20 * time.Millisecond})
+	defer StopIncidentCapture()
+
+	checkIncident(incidentStats(50), now)
+	checkIncident(incidentStats(150), now)
+	waitForIncidentCapture(t)
+	checkIncident(incidentStats(150), now.Add(time.Minute)) // within the cooldown
+	waitForIncidentCapture(t)
+
+	incidents, err := ListIncidents()
+	if err != nil || len(incidents) != 1 {
+		t.Fatalf("expected one incident, got %+v (%v)", incidents, err)
+	}
+	incident := incidents[0]
+	if len(incident.Profiles) != 3 || len(incident.Errors) != 0 {
+		t.Errorf("expected a CPU, heap and goroutine profile, got %+v, errors %v", incident.Profiles, incident.Errors)
+	}
+	for _, p := range incident.Profiles {
+		if p.Source != ProfileSourceIncident {
+			t.Errorf("expected profiles from the incident source, got %q", p.Source)
+		}
+	}
+
+	checkIncident(incidentStats(150), now.Add(2*time.Hour))
+	StopIncidentCapture()
+	if incidents, _ := ListIncidents(); len(incidents) != 2 {
+		t.Errorf("expected a second incident after the cooldown, got %d", len(incidents))
+	}
+}
+
+func TestDeleteIncident(t *testing.T) {
+	withProfileStore(t)
+
+	config := models.IncidentConfig{CPUDuration: 20 * time.Millisecond, Retention: time.Hour}
+	old, err := captureIncident(context.Background(), &config, nil, time.Now().Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to capture incident: %v", err)
+	}
+	incident, err := captureIncident(context.Background(), &config, nil, time.Now())
+	if err != nil {
+		t.Fatalf("failed to capture incident: %v", err)
+	}
+
+	incidents, _ := ListIncidents()
+	if len(incidents) != 1 || incidents[0].ID != incident.ID {
+		t.Fatalf("expected the expired incident to be removed, got %+v", incidents)
+	}
+	if _, _, err := ReadProfile(old.Profiles[0].ID); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected the expired incident's profiles to be removed, got %v", err)
+	}
+
+	if err := DeleteIncident(incident.ID); err != nil {
+		t.Fatalf("failed to delete incident: %v", err)
+	}
+	if profiles, _ := ListProfiles(models.ProfileQuery{Source: ProfileSourceIncident}); len(profiles) != 0 {
+		t.Errorf("expected the incident's profiles to be removed, got %+v", profiles)
+	}
+	for _, id := range []string{incident.ID, "../profiles", ""} {
+		if err := DeleteIncident(id); !errors.Is(err, ErrIncidentNotFound) {
+			t.Errorf("%q: expected ErrIncidentNotFound, got %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package core
+
+import (
+	gonet "net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/shirou/gopsutil/net"
+)
+
+// minRateInterval is the minimum time between two samples for rates to be recomputed.
+// Calls closer together than this reuse the previously computed rates.
+const minRateInterval = time.Second
+
+// virtualInterfacePrefixes are name prefixes of common virtual interfaces, used
+// when the kernel does not expose whether an interface is virtual.
+var virtualInterfacePrefixes = []string{
+	"docker", "veth", "br-", "virbr", "vmnet", "vboxnet", "cni", "flannel",
+	"cali", "weave", "tun", "tap", "utun", "kube-", "lxc", "lxdbr", "zt",
+}
+
+var (
+	networkFilterMu sync.RWMutex
+	networkFilter   models.NetworkInterfaceFilter
+
+	networkSamplerMu sync.Mutex
+	lastNetworkTime  time.Time
+	lastNetworkStats = make(map[string]models.NetworkInterfaceStats)
+)
+
+// ConfigureNetworkInterfaces sets which network interfaces are excluded from collection.
+func ConfigureNetworkInterfaces(filter *models.NetworkInterfaceFilter) {
+	networkFilterMu.Lock()
+	defer networkFilterMu.Unlock()
+	networkFilter = *filter
+}
+
+// GetNetworkStatistics retrieves per-interface network counters along with per-second
+// rates computed from the previous sample.
+func GetNetworkStatistics() models.NetworkStatistics {
+	stats, err := collectNetworkStatistics()
+	if err != nil {
+		logger.Log.Error("Error fetching network I/O statistics", "error", err)
+	}
+	return stats
+}
+
+// collectNetworkStatistics is GetNetworkStatistics returning the error instead of logging it.
+func collectNetworkStatistics() (models.NetworkStatistics, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return models.NetworkStatistics{}, err
+	}
+
+	networkFilterMu.RLock()
+	filter := networkFilter
+	networkFilterMu.RUnlock()
+
+	var loopbacks map[string]bool
+	if filter.ExcludeLoopback {
+		loopbacks = loopbackInterfaces()
+	}
+
+	now := time.Now()
+
+	networkSamplerMu.Lock()
+	defer networkSamplerMu.Unlock()
+
+	elapsed := now.Sub(lastNetworkTime)
+	refresh := lastNetworkTime.IsZero() || elapsed >= minRateInterval
+
+	var result models.NetworkStatistics
+	current := make(map[string]models.NetworkInterfaceStats, len(counters))
+	for _, c := range counters {
+		if isInterfaceExcluded(c.Name, &filter, loopbacks) {
+			continue
+		}
+
+		stat := models.NetworkInterfaceStats{
+			Name:            c.Name,
+			BytesSent:       c.BytesSent,
+			BytesReceived:   c.BytesRecv,
+			PacketsSent:     c.PacketsSent,
+			PacketsReceived: c.PacketsRecv,
+			ErrorsIn:        c.Errin,
+			ErrorsOut:       c.Errout,
+			DropsIn:         c.Dropin,
+			DropsOut:        c.Dropout,
+		}
+
+		if prev, ok := lastNetworkStats[c.Name]; ok {
+			if refresh {
+				computeInterfaceRates(&stat, &prev, elapsed)
+			} else {
+				copyInterfaceRates(&stat, &prev)
+			}
+		}
+
+		current[c.Name] = stat
+		result.Interfaces = append(result.Interfaces, stat)
+		result.BytesSentPerSec += stat.BytesSentPerSec
+		result.BytesReceivedPerSec += stat.BytesReceivedPerSec
+	}
+
+	if refresh {
+		lastNetworkTime = now
+		lastNetworkStats = current
+	}
+
+	sort.Slice(result.Interfaces, func(i, j int) bool {
+		return result.Interfaces[i].Name < result.Interfaces[j].Name
+	})
+
+	return result, nil
+}
+
+// computeInterfaceRates fills the per-second rates of cur from the difference to prev.
+func computeInterfaceRates(cur, prev *models.NetworkInterfaceStats, elapsed time.Duration) {
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		return
+	}
+	cur.BytesSentPerSec = counterRate(prev.BytesSent, cur.BytesSent, seconds)
+	cur.BytesReceivedPerSec = counterRate(prev.BytesReceived, cur.BytesReceived, seconds)
+	cur.PacketsSentPerSec = counterRate(prev.PacketsSent, cur.PacketsSent, seconds)
+	cur.PacketsReceivedPerSec = counterRate(prev.PacketsReceived, cur.PacketsReceived, seconds)
+	cur.ErrorsInPerSec = counterRate(prev.ErrorsIn, cur.ErrorsIn, seconds)
+	cur.ErrorsOutPerSec = counterRate(prev.ErrorsOut, cur.ErrorsOut, seconds)
+	cur.DropsInPerSec = counterRate(prev.DropsIn, cur.DropsIn, seconds)
+	cur.DropsOutPerSec = counterRate(prev.DropsOut, cur.DropsOut, seconds)
+}
+
+// copyInterfaceRates carries the previously computed rates over to cur.
+func copyInterfaceRates(cur, prev *models.NetworkInterfaceStats) {
+	cur.BytesSentPerSec = prev.BytesSentPerSec
+	cur.BytesReceivedPerSec = prev.BytesReceivedPerSec
+	cur.PacketsSentPerSec = prev.PacketsSentPerSec
+	cur.PacketsReceivedPerSec = prev.PacketsReceivedPerSec
+	cur.ErrorsInPerSec = prev.ErrorsInPerSec
+	cur.ErrorsOutPerSec = prev.ErrorsOutPerSec
+	cur.DropsInPerSec = prev.DropsInPerSec
+	cur.DropsOutPerSec = prev.DropsOutPerSec
+}
+
+// counterRate returns the per-second increase of a monotonic counter.
+// A counter lower than its previous value is treated as reset (interface
+// re-created or wrapped), in which case the current value is the increase since the reset.
+func counterRate(prev, cur uint64, seconds float64) float64 {
+	if cur < prev {
+		return float64(cur) / seconds
+	}
+	return float64(cur-prev) / seconds
+}
+
+// isInterfaceExcluded reports whether the named interface is filtered out by the configuration.
+func isInterfaceExcluded(name string, filter *models.NetworkInterfaceFilter, loopbacks map[string]bool) bool {
+	if filter.ExcludeLoopback && loopbacks[name] {
+		return true
+	}
+	if filter.ExcludeVirtual && isVirtualInterface(name) {
+		return true
+	}
+	for _, pattern := range filter.ExcludeInterfaces {
+		if pattern == name {
+			return true
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// loopbackInterfaces returns the set of interface names flagged as loopback by the OS.
+func loopbackInterfaces() map[string]bool {
+	result := make(map[string]bool)
+	ifaces, err := gonet.Interfaces()
+	if err != nil {
+		logger.Log.Warn("Error listing network interfaces", "error", err)
+		return result
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&gonet.FlagLoopback != 0 {
+			result[iface.Name] = true
+		}
+	}
+	return result
+}
+
+// isVirtualInterface reports whether the interface is virtual. On Linux the kernel
+// links virtual devices under /sys/devices/virtual; elsewhere well-known name prefixes are used.
+func isVirtualInterface(name string) bool {
+	if target, err := filepath.EvalSymlinks(filepath.Join("/sys/class/net", name)); err == nil {
+		return strings.Contains(target, "/devices/virtual/")
+	} else if !os.IsNotExist(err) {
+		logger.Log.Debug("Error resolving network interface device", "interface", name, "error", err)
+	}
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
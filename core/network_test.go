@@ -0,0 +1,88 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+func TestCounterRate(t *testing.T) {
+	tests := []struct {
+		prev, cur uint64
+		seconds   float64
+		want      float64
+	}{
+		{100, 300, 2, 100},
+		{100, 100, 5, 0},
+		{1000, 50, 5, 10}, // counter reset
+	}
+	for _, tt := range tests {
+		if got := counterRate(tt.prev, tt.cur, tt.seconds); got != tt.want {
+			t.Errorf("counterRate(%d, %d, %v) = %v, want %v", tt.prev, tt.cur, tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestComputeInterfaceRates(t *testing.T) {
+	prev := models.NetworkInterfaceStats{Name: "eth0", BytesSent: 1000, BytesReceived: 5000, PacketsSent: 10, DropsIn: 4}
+	cur := models.NetworkInterfaceStats{Name: "eth0", BytesSent: 3000, BytesReceived: 6000, PacketsSent: 30, DropsIn: 1}
+
+	computeInterfaceRates(&cur, &prev, 10*time.Second)
+
+	if cur.BytesSentPerSec != 200 {
+		t.Errorf("expected BytesSentPerSec 200, got %v", cur.BytesSentPerSec)
+	}
+	if cur.BytesReceivedPerSec != 100 {
+		t.Errorf("expected BytesReceivedPerSec 100, got %v", cur.BytesReceivedPerSec)
+	}
+	if cur.PacketsSentPerSec != 2 {
+		t.Errorf("expected PacketsSentPerSec 2, got %v", cur.PacketsSentPerSec)
+	}
+	if cur.DropsInPerSec != 0.1 {
+		t.Errorf("expected DropsInPerSec 0.1 after reset, got %v", cur.DropsInPerSec)
+	}
+}
+
+func TestIsInterfaceExcluded(t *testing.T) {
+	filter := &models.NetworkInterfaceFilter{
+		ExcludeLoopback:   true,
+		ExcludeInterfaces: []string{"docker*", "wg0"},
+	}
+	loopbacks := map[string]bool{"lo": true}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"lo", true},
+		{"docker0", true},
+		{"wg0", true},
+		{"eth0", false},
+	}
+	for _, tt := range tests {
+		if got := isInterfaceExcluded(tt.name, filter, loopbacks); got != tt.want {
+			t.Errorf("isInterfaceExcluded(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetNetworkStatistics(t *testing.T) {
+	ConfigureNetworkInterfaces(&models.NetworkInterfaceFilter{})
+	defer ConfigureNetworkInterfaces(&models.NetworkInterfaceFilter{})
+
+	stats := GetNetworkStatistics()
+	for i := 1; i < len(stats.Interfaces); i++ {
+		if stats.Interfaces[i-1].Name > stats.Interfaces[i].Name {
+			t.Errorf("expected interfaces sorted by name, got %q before %q", stats.Interfaces[i-1].Name, stats.Interfaces[i].Name)
+		}
+	}
+	if stats.BytesSentPerSec < 0 || stats.BytesReceivedPerSec < 0 {
+		t.Errorf("expected non-negative rates, got sent=%v recv=%v", stats.BytesSentPerSec, stats.BytesReceivedPerSec)
+	}
+
+	ConfigureNetworkInterfaces(&models.NetworkInterfaceFilter{ExcludeInterfaces: []string{"*"}})
+	if excluded := GetNetworkStatistics(); len(excluded.Interfaces) != 0 {
+		t.Errorf("expected all interfaces excluded, got %d", len(excluded.Interfaces))
+	}
+}
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+const (
+	// DefaultDependencyCheckTimeout is how long a dependency check may take unless configured otherwise.
+	DefaultDependencyCheckTimeout = 2 * time.Second
+	// DefaultDependencyCheckCacheTTL is how long a dependency check result is reused unless configured otherwise.
+	DefaultDependencyCheckCacheTTL = 5 * time.Second
+)
+
+// Probe statuses.
+const (
+	ProbeStatusOK   = "ok"
+	ProbeStatusFail = "fail"
+)
+
+// DependencyCheckFunc reports whether a dependency of the service is reachable, e.g. a
+// database's PingContext. ctx is cancelled once the check's timeout elapses.
+type DependencyCheckFunc func(ctx context.Context) error
+
+// dependencyEntry is a registered dependency check along with its latest result.
+type dependencyEntry struct {
+	name   string
+	check  DependencyCheckFunc
+	config models.DependencyCheckConfig
+
+	mu      sync.Mutex // Held while checking so concurrent probes share one run
+	result  models.ProbeCheck
+	running atomic.Bool // Set while a check runs, past its timeout included
+	started time.Time   // Start of the running check
+}
+
+var (
+	dependenciesMu sync.Mutex
+	dependencies   []*dependencyEntry
+)
+
+// RegisterDependencyCheck adds a check that must pass for the service to be ready.
+func RegisterDependencyCheck(name string, check DependencyCheckFunc, config models.DependencyCheckConfig) error {
+	if name == "" || check == nil {
+		return fmt.Errorf("dependency check name and function are required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultDependencyCheckTimeout
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultDependencyCheckCacheTTL
+	}
+
+	dependenciesMu.Lock()
+	defer dependenciesMu.Unlock()
+
+	for _, e := range dependencies {
+		if e.name == name {
+			return fmt.Errorf("dependency check %q is already registered", name)
+		}
+	}
+	dependencies = append(dependencies, &dependencyEntry{name: name, check: check, config: config})
+	return nil
+}
+
+// UnregisterDependencyCheck removes a dependency check.
+func UnregisterDependencyCheck(name string) error {
+	dependenciesMu.Lock()
+	defer dependenciesMu.Unlock()
+
+	for i, e := range dependencies {
+		if e.name == name {
+			dependencies = append(dependencies[:i], dependencies[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown dependency check %q", name)
+}
+
+// HTTPDependencyCheck returns a check requesting url with GET, failing on errors and on
+// responses other than 2xx and 3xx.
+func HTTPDependencyCheck(url string) DependencyCheckFunc {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("%s returned %s", url, resp.Status)
+		}
+		return nil
+	}
+}
+
+// run returns the cached result while it is younger than the cache TTL, and checks the
+// dependency otherwise. Checks ignoring their context are abandoned once the timeout elapses,
+// and fail without being started again until they return.
+func (e *dependencyEntry) run(ctx context.Context) models.ProbeCheck {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.result.CheckedAt.IsZero() && time.Since(e.result.CheckedAt) < e.config.CacheTTL {
+		result := e.result
+		result.Cached = true
+		return result
+	}
+	if !e.running.CompareAndSwap(false, true) {
+		return models.ProbeCheck{
+			Name:      e.name,
+			Status:    ProbeStatusFail,
+			Message:   fmt.Sprintf("check is still running since %s", e.started.Format(time.RFC3339)),
+			CheckedAt: time.Now(),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	e.started = start
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("check panicked: %v", r)
+			}
+			// Cleared first, so the next probe after the result can check again
+			e.running.Store(false)
+			done <- err
+		}()
+		err = e.check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not finish within %s: %w", e.config.Timeout, ctx.Err())
+	}
+
+	result := models.ProbeCheck{
+		Name:       e.name,
+		Status:     ProbeStatusOK,
+		DurationMs: common.RoundFloat64(float64(time.Since(start).Microseconds())/1000, 3),
+		CheckedAt:  start,
+	}
+	if err != nil {
+		result.Status, result.Message = ProbeStatusFail, err.Error()
+	}
+	// A request cancelled by its client says nothing about the dependency
+	if ctx.Err() != context.Canceled {
+		e.result = result
+	}
+	return result
+}
+
+// probeResult returns a probe response failing when any of its checks fails.
+func probeResult(checks []models.ProbeCheck) models.ProbeResult {
+	result := models.ProbeResult{Status: ProbeStatusOK, Checks: checks}
+	for _, c := range checks {
+		if c.Status != ProbeStatusOK {
+			result.Status = ProbeStatusFail
+		}
+	}
+	return result
+}
+
+// Liveness reports whether the service is alive: the background sampler is running and keeps
+// refreshing the statistics, and no critical health check fails. Dependencies are left to Readiness, so that
+// an unreachable database does not get the service restarted.
+func Liveness() models.ProbeResult {
+	now := time.Now()
+	checks := []models.ProbeCheck{{Name: "sampler", Status: ProbeStatusOK, CheckedAt: now}}
+
+	snap := latestSnapshot.Load()
+	if snap == nil || !isSamplerRunning() {
+		// Without the sampler the health data is stale, so nothing vouches for the service
+		checks[0].Status = ProbeStatusFail
+		checks[0].Message = "sampler is not running, statistics are stale"
+		return probeResult(checks)
+	}
+
+	interval := time.Duration(collectionInterval.Load())
+	if age := now.Sub(snap.collectedAt); age > 3*interval+DefaultCollectorTimeout {
+		checks[0].Status = ProbeStatusFail
+		checks[0].Message = fmt.Sprintf("statistics have not been refreshed for %s", age.Round(time.Second))
+	}
+
+	for _, c := range snap.stats.Health.ServiceHealth.Checks {
+		if !c.Critical {
+			continue
+		}
+		check := models.ProbeCheck{Name: c.Name, Status: ProbeStatusOK, Message: c.Message, CheckedAt: snap.collectedAt, Cached: true}
+		if c.Status == HealthStatusCritical {
+			check.Status = ProbeStatusFail
+		}
+		checks = append(checks, check)
+	}
+	return probeResult(checks)
+}
+
+// Readiness reports whether the service can take traffic: every dependency check passes.
+// Dependencies are checked concurrently and their results cached for their cache TTL.
+func Readiness(ctx context.Context) models.ProbeResult {
+	dependenciesMu.Lock()
+	entries := append([]*dependencyEntry(nil), dependencies...)
+	dependenciesMu.Unlock()
+
+	checks := make([]models.ProbeCheck, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checks[i] = e.run(ctx)
+		}()
+	}
+	wg.Wait()
+	return probeResult(checks)
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// withDependencyCheck registers a dependency check for the duration of the test.
+func withDependencyCheck(t *testing.T, name string, check DependencyCheckFunc, config models.DependencyCheckConfig) {
+	t.Helper()
+	if err := RegisterDependencyCheck(name, check, config); err != nil {
+		t.Fatalf("RegisterDependencyCheck error: %v", err)
+	}
+	t.Cleanup(func() { UnregisterDependencyCheck(name) })
+}
+
+func TestReadiness(t *testing.T) {
+	if r := Readiness(context.Background()); r.Status != ProbeStatusOK || len(r.Checks) != 0 {
+		t.Fatalf("expected readiness without dependencies, got %+v", r)
+	}
+
+	var failing atomic.Bool
+	withDependencyCheck(t, "database", func(context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, models.DependencyCheckConfig{CacheTTL: time.Nanosecond})
+
+	if r := Readiness(context.Background()); r.Status != ProbeStatusOK || r.Checks[0].Name != "database" {
+		t.Errorf("expected a passing dependency, got %+v", r)
+	}
+	failing.Store(true)
+	r := Readiness(context.Background())
+	if r.Status != ProbeStatusFail || r.Checks[0].Message != "connection refused" {
+		t.Errorf("expected a failing dependency, got %+v", r)
+	}
+}
+
+func TestReadinessTimeoutAndCache(t *testing.T) {
+	var calls atomic.Int32
+	withDependencyCheck(t, "slow", func(ctx context.Context) error {
+		calls.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}, models.DependencyCheckConfig{Timeout: 20 * time.Millisecond, CacheTTL: time.Minute})
+
+	r := Readiness(context.Background())
+	if r.Status != ProbeStatusFail || r.Checks[0].Cached {
+		t.Fatalf("expected the check to time out, got %+v", r)
+	}
+	r = Readiness(context.Background())
+	if r.Status != ProbeStatusFail || !r.Checks[0].Cached || calls.Load() != 1 {
+		t.Errorf("expected the failure to be served from the cache, got %+v after %d calls", r, calls.Load())
+	}
+}
+
+func TestBlockingDependencyCheckIsNotRestarted(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	withDependencyCheck(t, "blocking", func(context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	}, models.DependencyCheckConfig{Timeout: 10 * time.Millisecond, CacheTTL: time.Nanosecond})
+
+	before := runtime.NumGoroutine()
+	if r := Readiness(context.Background()); r.Status != ProbeStatusFail {
+		t.Fatalf("expected a check that does not finish to fail, got %+v", r)
+	}
+	for range 20 {
+		r := Readiness(context.Background())
+		if r.Status != ProbeStatusFail || !strings.HasPrefix(r.Checks[0].Message, "check is still running since") {
+			t.Fatalf("expected the running check to be reported, got %+v", r)
+		}
+	}
+	if after := runtime.NumGoroutine(); calls.Load() != 1 || after > before+2 {
+		t.Errorf("expected a single run of the blocked check, got %d runs and goroutines grew from %d to %d", calls.Load(), before, after)
+	}
+
+	// Once the late run returns, the check runs again
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for Readiness(context.Background()).Status != ProbeStatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the check to run again after returning")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDependencyCheckRegistry(t *testing.T) {
+	withDependencyCheck(t, "database", func(context.Context) error { return nil }, models.DependencyCheckConfig{})
+
+	if err := RegisterDependencyCheck("database", func(context.Context) error { return nil }, models.DependencyCheckConfig{}); err == nil {
+		t.Error("expected an error registering a duplicate check")
+	}
+	if err := RegisterDependencyCheck("cache", nil, models.DependencyCheckConfig{}); err == nil {
+		t.Error("expected an error registering a check without a function")
+	}
+	if err := UnregisterDependencyCheck("unknown"); err == nil {
+		t.Error("expected an error unregistering an unknown check")
+	}
+}
+
+func TestHTTPDependencyCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	if err := HTTPDependencyCheck(srv.URL + "/up")(context.Background()); err != nil {
+		t.Errorf("expected the check to pass, got %v", err)
+	}
+	if err := HTTPDependencyCheck(srv.URL + "/down")(context.Background()); err == nil {
+		t.Error("expected the check to fail on a 503 response")
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	StopSampler()
+	if r := Liveness(); r.Status != ProbeStatusFail || r.Checks[0].Status != ProbeStatusFail {
+		t.Fatalf("expected a stopped sampler to fail liveness, got %+v", r)
+	}
+
+	StartSampler(time.Hour)
+	t.Cleanup(StopSampler)
+	deadline := time.Now().Add(10 * time.Second)
+	for latestSnapshot.Load() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if r := Liveness(); r.Status != ProbeStatusOK {
+		t.Errorf("expected liveness with a fresh snapshot, got %+v", r)
+	}
+
+	stale := *latestSnapshot.Load()
+	stale.collectedAt = time.Now().Add(-4 * time.Hour)
+	stale.stats.Health.ServiceHealth.Checks = []models.HealthCheckResult{{Name: "database", Status: HealthStatusCritical, Critical: true}}
+	latestSnapshot.Store(&stale)
+
+	r := Liveness()
+	if r.Status != ProbeStatusFail || len(r.Checks) != 2 {
+		t.Fatalf("expected a stale sampler and critical check to fail liveness, got %+v", r)
+	}
+	if r.Checks[0].Status != ProbeStatusFail || r.Checks[1].Status != ProbeStatusFail {
+		t.Errorf("expected both checks to fail, got %+v", r.Checks)
+	}
+}
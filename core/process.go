@@ -0,0 +1,76 @@
+package core
+
+import (
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/shirou/gopsutil/process"
+)
+
+// GetProcessStatistics retrieves file descriptor, thread, context switch, page fault
+// and memory usage of the service process. Values the platform does not support are left at zero.
+func GetProcessStatistics() models.ProcessStatistics {
+	var stats models.ProcessStatistics
+
+	proc := common.GetProcessObject()
+	if proc == nil {
+		return stats
+	}
+
+	if fds, err := proc.NumFDs(); err == nil {
+		stats.OpenFDs = fds
+	} else {
+		logger.Log.Debug("Error fetching open file descriptors", "error", err)
+	}
+
+	stats.MaxFDs = getMaxFDs(proc)
+	if stats.MaxFDs > 0 {
+		stats.FDUsagePercent = common.RoundFloat64(float64(stats.OpenFDs)/float64(stats.MaxFDs)*100, 2)
+	}
+
+	if threads, err := proc.NumThreads(); err == nil {
+		stats.Threads = threads
+	} else {
+		logger.Log.Debug("Error fetching thread count", "error", err)
+	}
+
+	if ctxSwitches, err := proc.NumCtxSwitches(); err == nil && ctxSwitches != nil {
+		stats.VoluntaryCtxSwitches = ctxSwitches.Voluntary
+		stats.InvoluntaryCtxSwitches = ctxSwitches.Involuntary
+	} else if err != nil {
+		logger.Log.Debug("Error fetching context switches", "error", err)
+	}
+
+	if faults, err := proc.PageFaults(); err == nil && faults != nil {
+		stats.MinorPageFaults = faults.MinorFaults
+		stats.MajorPageFaults = faults.MajorFaults
+	} else if err != nil {
+		logger.Log.Debug("Error fetching page faults", "error", err)
+	}
+
+	if memInfo, err := proc.MemoryInfo(); err == nil && memInfo != nil {
+		stats.ResidentMemoryRaw = memInfo.RSS
+		stats.VirtualMemoryRaw = memInfo.VMS
+	} else if err != nil {
+		logger.Log.Debug("Error fetching process memory info", "error", err)
+	}
+	stats.ResidentMemory = common.BytesToUnit(stats.ResidentMemoryRaw)
+	stats.VirtualMemory = common.BytesToUnit(stats.VirtualMemoryRaw)
+
+	return stats
+}
+
+// getMaxFDs returns the soft RLIMIT_NOFILE of the process, or 0 when it is unknown or unlimited.
+func getMaxFDs(proc *process.Process) int64 {
+	limits, err := proc.Rlimit()
+	if err != nil {
+		logger.Log.Debug("Error fetching resource limits", "error", err)
+		return 0
+	}
+	for _, limit := range limits {
+		if limit.Resource == process.RLIMIT_NOFILE && limit.Soft > 0 {
+			return int64(limit.Soft)
+		}
+	}
+	return 0
+}
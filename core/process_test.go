@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+func TestGetProcessStatistics(t *testing.T) {
+	ps := GetProcessStatistics()
+	if ps.OpenFDs < 0 {
+		t.Errorf("expected OpenFDs >= 0, got %d", ps.OpenFDs)
+	}
+	if ps.MaxFDs > 0 && ps.FDUsagePercent <= 0 && ps.OpenFDs > 0 {
+		t.Errorf("expected FDUsagePercent > 0 with %d/%d fds open", ps.OpenFDs, ps.MaxFDs)
+	}
+	if ps.ResidentMemory == "" || ps.VirtualMemory == "" {
+		t.Error("expected formatted resident and virtual memory")
+	}
+}
+
+func TestCalculateServiceHealth_ProcessThresholds(t *testing.T) {
+	defer ConfigureServiceThresholds(&models.ServiceHealthThresholds{
+		MaxCPUUsage:    95,
+		MaxMemoryUsage: 95,
+		MaxGoRoutines:  1000,
+	})
+	stats := models.ServiceStats{
+		CoreStatistics:    models.CoreStatistics{Goroutines: 10},
+		ProcessStatistics: models.ProcessStatistics{OpenFDs: 50, MaxFDs: 100, FDUsagePercent: 50, Threads: 10},
+	}
+
+	// The process thresholds are opt-in
+	ConfigureServiceThresholds(&models.ServiceHealthThresholds{MaxGoRoutines: 1000})
+	health, err := evaluateHealth(context.Background(), &stats, false)
+	if err != nil {
+		t.Fatalf("evaluateHealth error: %v", err)
+	}
+	if strings.Contains(health.IconMsg, "File Descriptors") || strings.Contains(health.IconMsg, "Threads") {
+		t.Errorf("expected no file descriptor or thread usage without thresholds, got %q", health.IconMsg)
+	}
+
+	ConfigureServiceThresholds(&models.ServiceHealthThresholds{MaxGoRoutines: 1000, MaxFDUsage: 80, MaxThreads: 100})
+	health, err = evaluateHealth(context.Background(), &stats, false)
+	if err != nil {
+		t.Fatalf("evaluateHealth error: %v", err)
+	}
+	if !strings.Contains(health.IconMsg, "File Descriptors") || !strings.Contains(health.IconMsg, "Threads") {
+		t.Errorf("expected file descriptor and thread usage in message, got %q", health.IconMsg)
+	}
+}
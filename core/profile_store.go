@@ -0,0 +1,247 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// ProfileSourceOnDemand marks profiles captured through the profiling API.
+const ProfileSourceOnDemand = "on-demand"
+
+var (
+	// ErrProfileNotFound is returned when no stored profile has the requested ID.
+	ErrProfileNotFound = errors.New("profile not found")
+	// ErrInvalidProfileReport is returned when a profile cannot be rendered in the requested report type.
+	ErrInvalidProfileReport = errors.New("invalid profile report")
+)
+
+// Stored profiles are named <unix nano>_<source>_<type>_<duration ms> plus an extension,
+// so the directory itself is the index by time and type.
+var profileIDRegex = regexp.MustCompile(`^(\d+)_([a-z-]+)_([a-z]+)_(\d+)$`)
+
+// profileReportTypes are the pprof report formats a stored profile can be rendered in.
+var profileReportTypes = map[string]bool{"text": true, "top": true, "tree": true, "traces": true, "raw": true}
+
+// profileStoreDir returns the directory holding stored profiles.
+func profileStoreDir() string {
+	return filepath.Join(basePath, "profiles", "store")
+}
+
+// profileExtension returns the file extension of a stored profile. pprof profiles are
+// already gzipped; execution traces are compressed when stored.
+func profileExtension(profileType string) string {
+	if profileType == ProfileTrace {
+		return ".trace.gz"
+	}
+	return ".pb.gz"
+}
+
+// StoreProfile stores a profile returned by CaptureProfile and returns its record.
+func StoreProfile(source, profileType string, createdAt time.Time, duration time.Duration, data []byte) (models.ProfileRecord, error) {
+	id := fmt.Sprintf("%d_%s_%s_%d", createdAt.UnixNano(), source, profileType, duration.Milliseconds())
+	if !profileIDRegex.MatchString(id) || !IsProfileType(profileType) {
+		return models.ProfileRecord{}, fmt.Errorf("invalid profile source %q or type %q", source, profileType)
+	}
+
+	if profileType == ProfileTrace {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return models.ProfileRecord{}, err
+		}
+		if err := zw.Close(); err != nil {
+			return models.ProfileRecord{}, err
+		}
+		data = buf.Bytes()
+	}
+
+	dir := profileStoreDir()
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return models.ProfileRecord{}, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	// Written to a temporary file first so listings never see a partial profile
+	path := filepath.Join(dir, id+profileExtension(profileType))
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return models.ProfileRecord{}, err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return models.ProfileRecord{}, err
+	}
+
+	record, _ := parseProfileFileName(filepath.Base(path))
+	record.SizeBytes = int64(len(data))
+	return record, nil
+}
+
+// parseProfileFileName returns the record encoded in a stored profile's file name.
+func parseProfileFileName(name string) (models.ProfileRecord, bool) {
+	id, ok := strings.CutSuffix(name, ".pb.gz")
+	if !ok {
+		if id, ok = strings.CutSuffix(name, ".trace.gz"); !ok {
+			return models.ProfileRecord{}, false
+		}
+	}
+
+	match := profileIDRegex.FindStringSubmatch(id)
+	if match == nil || !IsProfileType(match[3]) || (match[3] == ProfileTrace) != strings.HasSuffix(name, ".trace.gz") {
+		return models.ProfileRecord{}, false
+	}
+	nanos, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return models.ProfileRecord{}, false
+	}
+	millis, _ := strconv.ParseInt(match[4], 10, 64)
+
+	return models.ProfileRecord{
+		ID:              id,
+		Type:            match[3],
+		Source:          match[2],
+		CreatedAt:       time.Unix(0, nanos),
+		DurationSeconds: float64(millis) / 1000,
+	}, true
+}
+
+// ListProfiles returns the stored profiles matching the query, newest first.
+func ListProfiles(query models.ProfileQuery) ([]models.ProfileRecord, error) {
+	records := []models.ProfileRecord{}
+
+	entries, err := os.ReadDir(profileStoreDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		record, ok := parseProfileFileName(entry.Name())
+		if !ok {
+			continue
+		}
+		if (query.Type != "" && record.Type != query.Type) ||
+			(query.Source != "" && record.Source != query.Source) ||
+			(!query.From.IsZero() && record.CreatedAt.Before(query.From)) ||
+			(!query.To.IsZero() && record.CreatedAt.After(query.To)) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			record.SizeBytes = info.Size()
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+	return records, nil
+}
+
+// findProfile returns the record and path of a stored profile.
+func findProfile(id string) (models.ProfileRecord, string, error) {
+	if !profileIDRegex.MatchString(id) {
+		return models.ProfileRecord{}, "", ErrProfileNotFound
+	}
+	for _, ext := range []string{".pb.gz", ".trace.gz"} {
+		record, ok := parseProfileFileName(id + ext)
+		if !ok {
+			continue
+		}
+		path := filepath.Join(profileStoreDir(), id+ext)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		record.SizeBytes = info.Size()
+		return record, path, nil
+	}
+	return models.ProfileRecord{}, "", ErrProfileNotFound
+}
+
+// ReadProfile returns a stored profile in the format CaptureProfile produced it.
+func ReadProfile(id string) (models.ProfileRecord, []byte, error) {
+	record, path, err := findProfile(id)
+	if err != nil {
+		return record, nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return record, nil, err
+	}
+	if record.Type != ProfileTrace {
+		return record, data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return record, nil, err
+	}
+	defer zr.Close()
+	data, err = io.ReadAll(zr)
+	return record, data, err
+}
+
+// DeleteProfile removes a stored profile.
+func DeleteProfile(id string) error {
+	_, path, err := findProfile(id)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// PruneProfiles removes the stored profiles of source captured before the given time
+// and returns how many were removed.
+func PruneProfiles(source string, before time.Time) (int, error) {
+	profiles, err := ListProfiles(models.ProfileQuery{Source: source, To: before})
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, profile := range profiles {
+		if err := DeleteProfile(profile.ID); err != nil && !errors.Is(err, ErrProfileNotFound) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// GetProfileReport renders a stored pprof profile with `go tool pprof`, e.g. as a "top" report.
+func GetProfileReport(id, reportType string) (models.ProfileReport, error) {
+	record, path, err := findProfile(id)
+	if err != nil {
+		return models.ProfileReport{}, err
+	}
+	if record.Type == ProfileTrace {
+		return models.ProfileReport{}, fmt.Errorf("%w: execution traces cannot be rendered, download them and use 'go tool trace'", ErrInvalidProfileReport)
+	}
+	if !profileReportTypes[reportType] {
+		return models.ProfileReport{}, fmt.Errorf("%w: unknown report type %q", ErrInvalidProfileReport, reportType)
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		return models.ProfileReport{}, fmt.Errorf("'go' command not found, pprof reports require the Go SDK")
+	}
+
+	output, err := exec.Command("go", "tool", "pprof", "-"+reportType, path).CombinedOutput()
+	if err != nil {
+		return models.ProfileReport{}, fmt.Errorf("error executing pprof: %v: %s", err, output)
+	}
+	return models.ProfileReport{Profile: record, ReportType: reportType, Report: string(output)}, nil
+}
@@ -0,0 +1,146 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// Profile types that can be captured on demand.
+const (
+	ProfileCPU          = "cpu"
+	ProfileTrace        = "trace"
+	ProfileHeap         = "heap"
+	ProfileAllocs       = "allocs"
+	ProfileGoroutine    = "goroutine"
+	ProfileMutex        = "mutex"
+	ProfileBlock        = "block"
+	ProfileThreadCreate = "threadcreate"
+)
+
+const (
+	// DefaultCPUProfileDuration is how long a CPU profile runs when no duration is given.
+	DefaultCPUProfileDuration = 30 * time.Second
+	// DefaultTraceDuration is how long an execution trace runs when no duration is given.
+	DefaultTraceDuration = 5 * time.Second
+	// MaxProfileDuration is the longest a single capture may run.
+	MaxProfileDuration = 5 * time.Minute
+
+	// DefaultMutexProfileFraction reports on average 1 in 5 mutex contention events while temporarily enabled.
+	DefaultMutexProfileFraction = 5
+	// DefaultBlockProfileRate samples one blocking event per 10µs spent blocked while temporarily enabled.
+	DefaultBlockProfileRate = 10000
+)
+
+// ErrProfileInProgress is returned when a profile that can only run once at a time is already running,
+// e.g. a CPU profile started by function tracing.
+var ErrProfileInProgress = errors.New("a profile of this type is already being captured")
+
+// contentionMu is held while mutex or block profiling is temporarily enabled.
+var contentionMu sync.Mutex
+
+// IsProfileType reports whether t is a profile type that can be captured.
+func IsProfileType(t string) bool {
+	switch t {
+	case ProfileCPU, ProfileTrace, ProfileHeap, ProfileAllocs, ProfileGoroutine, ProfileMutex, ProfileBlock, ProfileThreadCreate:
+		return true
+	}
+	return false
+}
+
+// CaptureProfile captures the requested profile and returns it in the gzipped pprof format,
+// or the runtime/trace format for execution traces. CPU profiles and traces run for the
+// requested duration; mutex and block profiles are temporarily enabled for the duration
+// when one is given. Cancelling ctx ends a running capture early and returns ctx's error.
+func CaptureProfile(ctx context.Context, req models.ProfileRequest) ([]byte, error) {
+	if req.Duration < 0 || req.Duration > MaxProfileDuration {
+		return nil, fmt.Errorf("profile duration must be between 0 and %s", MaxProfileDuration)
+	}
+
+	var buf bytes.Buffer
+	switch req.Type {
+	case ProfileCPU:
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrProfileInProgress, err)
+		}
+		waitContext(ctx, positiveOrDefault(req.Duration, DefaultCPUProfileDuration))
+		pprof.StopCPUProfile()
+
+	case ProfileTrace:
+		if err := trace.Start(&buf); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrProfileInProgress, err)
+		}
+		waitContext(ctx, positiveOrDefault(req.Duration, DefaultTraceDuration))
+		trace.Stop()
+
+	case ProfileMutex, ProfileBlock:
+		if req.Duration > 0 {
+			if !contentionMu.TryLock() {
+				return nil, ErrProfileInProgress
+			}
+			defer contentionMu.Unlock()
+
+			restore := enableContentionProfile(req.Type, req.Rate)
+			waitContext(ctx, req.Duration)
+			defer restore()
+		}
+		if err := pprof.Lookup(req.Type).WriteTo(&buf, 0); err != nil {
+			return nil, err
+		}
+
+	case ProfileHeap, ProfileAllocs, ProfileGoroutine, ProfileThreadCreate:
+		if req.GC && req.Type == ProfileHeap {
+			runtime.GC()
+		}
+		if err := pprof.Lookup(req.Type).WriteTo(&buf, 0); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown profile type %q", req.Type)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// enableContentionProfile enables mutex or block profiling at rate, or the default rate,
+// and returns a function restoring the previous setting. The runtime does not expose the
+// block profile rate, so block profiling is disabled again afterwards.
+func enableContentionProfile(profileType string, rate int) func() {
+	if profileType == ProfileMutex {
+		previous := runtime.SetMutexProfileFraction(positiveOrDefault(rate, DefaultMutexProfileFraction))
+		return func() { runtime.SetMutexProfileFraction(previous) }
+	}
+
+	runtime.SetBlockProfileRate(positiveOrDefault(rate, DefaultBlockProfileRate))
+	return func() { runtime.SetBlockProfileRate(0) }
+}
+
+// positiveOrDefault returns value, or def when value is not positive.
+func positiveOrDefault[T time.Duration | int](value, def T) T {
+	if value > 0 {
+		return value
+	}
+	return def
+}
+
+// waitContext waits for d to elapse or ctx to be cancelled.
+func waitContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// withProfileStore points the base path, holding the profile store and the profiles of traced
+// calls, at a temporary directory for the duration of the test.
+func withProfileStore(t *testing.T) {
+	t.Helper()
+	saved := basePath
+	basePath = t.TempDir()
+	t.Cleanup(func() { basePath = saved })
+}
+
+// isGzip reports whether data starts with the gzip magic number, as pprof profiles do.
+func isGzip(data []byte) bool {
+	return len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+func TestCaptureProfileSnapshots(t *testing.T) {
+	for _, profileType := range []string{ProfileHeap, ProfileAllocs, ProfileGoroutine, ProfileThreadCreate, ProfileMutex, ProfileBlock} {
+		data, err := CaptureProfile(context.Background(), models.ProfileRequest{Type: profileType, GC: true})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", profileType, err)
+		}
+		if !isGzip(data) {
+			t.Errorf("%s: expected a gzipped pprof profile", profileType)
+		}
+	}
+}
+
+func TestCaptureProfileInvalid(t *testing.T) {
+	if _, err := CaptureProfile(context.Background(), models.ProfileRequest{Type: "bogus"}); err == nil {
+		t.Error("expected an error for an unknown profile type")
+	}
+	if _, err := CaptureProfile(context.Background(), models.ProfileRequest{Type: ProfileCPU, Duration: MaxProfileDuration + time.Second}); err == nil {
+		t.Error("expected an error for a duration above the maximum")
+	}
+}
+
+func TestCaptureCPUProfileConflict(t *testing.T) {
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		t.Skipf("CPU profiling unavailable: %v", err)
+	}
+	defer pprof.StopCPUProfile()
+
+	_, err := CaptureProfile(context.Background(), models.ProfileRequest{Type: ProfileCPU, Duration: 10 * time.Millisecond})
+	if !errors.Is(err, ErrProfileInProgress) {
+		t.Errorf("expected ErrProfileInProgress while another CPU profile runs, got %v", err)
+	}
+}
+
+func TestCaptureProfileCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	_, err := CaptureProfile(ctx, models.ProfileRequest{Type: ProfileCPU, Duration: time.Minute})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("expected a cancelled capture to end early")
+	}
+}
+
+func TestProfileStoreRoundTrip(t *testing.T) {
+	withProfileStore(t)
+
+	data, err := CaptureProfile(context.Background(), models.ProfileRequest{Type: ProfileGoroutine})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	createdAt := time.Now()
+	record, err := StoreProfile(ProfileSourceOnDemand, ProfileGoroutine, createdAt, 0, data)
+	if err != nil {
+		t.Fatalf("failed to store profile: %v", err)
+	}
+	if record.Type != ProfileGoroutine || record.Source != ProfileSourceOnDemand || !record.CreatedAt.Equal(createdAt) {
+		t.Errorf("unexpected record: %+v", record)
+	}
+
+	traceData := []byte("go 1.24 trace")
+	traceRecord, err := StoreProfile(ProfileSourceOnDemand, ProfileTrace, createdAt.Add(time.Second), 1500*time.Millisecond, traceData)
+	if err != nil {
+		t.Fatalf("failed to store trace: %v", err)
+	}
+	if traceRecord.DurationSeconds != 1.5 {
+		t.Errorf("expected a duration of 1.5s, got %v", traceRecord.DurationSeconds)
+	}
+
+	profiles, err := ListProfiles(models.ProfileQuery{})
+	if err != nil || len(profiles) != 2 || profiles[0].ID != traceRecord.ID {
+		t.Fatalf("expected both profiles newest first, got %+v (%v)", profiles, err)
+	}
+	if profiles, _ := ListProfiles(models.ProfileQuery{Type: ProfileGoroutine}); len(profiles) != 1 || profiles[0].ID != record.ID {
+		t.Errorf("expected the type filter to keep the goroutine profile, got %+v", profiles)
+	}
+	if profiles, _ := ListProfiles(models.ProfileQuery{From: createdAt.Add(time.Millisecond)}); len(profiles) != 1 || profiles[0].ID != traceRecord.ID {
+		t.Errorf("expected the time filter to keep the trace, got %+v", profiles)
+	}
+
+	if _, read, err := ReadProfile(record.ID); err != nil || !bytes.Equal(read, data) {
+		t.Errorf("expected the stored profile back, got %d bytes (%v)", len(read), err)
+	}
+	if _, read, err := ReadProfile(traceRecord.ID); err != nil || !bytes.Equal(read, traceData) {
+		t.Errorf("expected the trace decompressed, got %q (%v)", read, err)
+	}
+	if _, err := GetProfileReport(traceRecord.ID, "top"); !errors.Is(err, ErrInvalidProfileReport) {
+		t.Errorf("expected traces not to be rendered, got %v", err)
+	}
+	if _, err := GetProfileReport(record.ID, "svg"); !errors.Is(err, ErrInvalidProfileReport) {
+		t.Errorf("expected an unknown report type to be refused, got %v", err)
+	}
+
+	if err := DeleteProfile(record.ID); err != nil {
+		t.Fatalf("failed to delete profile: %v", err)
+	}
+	if _, _, err := ReadProfile(record.ID); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected ErrProfileNotFound after deleting, got %v", err)
+	}
+}
+
+func TestProfileStoreInvalidIDs(t *testing.T) {
+	withProfileStore(t)
+
+	for _, id := range []string{"", "../../etc/passwd", "1_on-demand_cpu", "1_on-demand_bogus_0"} {
+		if _, _, err := ReadProfile(id); !errors.Is(err, ErrProfileNotFound) {
+			t.Errorf("%q: expected ErrProfileNotFound, got %v", id, err)
+		}
+	}
+	if _, err := StoreProfile("on demand", ProfileHeap, time.Now(), 0, nil); err == nil {
+		t.Error("expected an invalid source to be refused")
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+)
+
+// DefaultCollectionInterval is the default interval at which the background sampler refreshes the stats snapshot.
+const DefaultCollectionInterval = 15 * time.Second
+
+// statsSnapshot is an immutable service statistics sample shared between readers.
+type statsSnapshot struct {
+	stats       models.ServiceStats
+	collectedAt time.Time
+}
+
+var (
+	latestSnapshot     atomic.Pointer[statsSnapshot]
+	collectionInterval atomic.Int64
+	refreshMu          sync.Mutex
+
+	samplerMu     sync.Mutex
+	samplerCancel context.CancelFunc
+	samplerDone   chan struct{}
+)
+
+func init() {
+	collectionInterval.Store(int64(DefaultCollectionInterval))
+}
+
+// StartSampler starts the background sampler which refreshes the service statistics
+// snapshot every interval. Calling it again restarts the sampler with the new interval.
+func StartSampler(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCollectionInterval
+	}
+
+	StopSampler()
+
+	samplerMu.Lock()
+	defer samplerMu.Unlock()
+
+	collectionInterval.Store(int64(interval))
+	// Ticking at the shortest collector interval so faster collectors are not held back
+	tick := minCollectorInterval(interval)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	samplerCancel, samplerDone = cancel, done
+
+	go func() {
+		defer close(done)
+		refreshSnapshot(ctx, time.Now())
+
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refreshSnapshot(ctx, time.Now())
+			}
+		}
+	}()
+
+	logger.Log.Debug("stats sampler started", "interval", interval, "tick", tick)
+}
+
+// StopSampler stops the background sampler and waits for it to exit. Safe to call multiple times.
+func StopSampler() {
+	samplerMu.Lock()
+	cancel, done := samplerCancel, samplerDone
+	samplerCancel, samplerDone = nil, nil
+	samplerMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// isSamplerRunning reports whether the background sampler is active.
+func isSamplerRunning() bool {
+	samplerMu.Lock()
+	defer samplerMu.Unlock()
+	return samplerCancel != nil
+}
+
+// GetLatestServiceStats returns the most recent service statistics snapshot without blocking on
+// collection. A snapshot is collected synchronously only when none exists yet, or when the
+// background sampler is not running and the current one is older than the collection interval.
+func GetLatestServiceStats() models.ServiceStats {
+	snap := latestSnapshot.Load()
+	interval := time.Duration(collectionInterval.Load())
+	if snap == nil || (time.Since(snap.collectedAt) > interval && !isSamplerRunning()) {
+		snap = refreshSnapshot(context.Background(), time.Now())
+	}
+
+	stats := snap.stats
+	stats.CollectedAt = snap.collectedAt
+	stats.SnapshotAgeSeconds = common.RoundFloat64(time.Since(snap.collectedAt).Seconds(), 3)
+	return stats
+}
+
+// refreshSnapshot collects a new snapshot unless another caller already refreshed it
+// after requestedAt, in which case that snapshot is returned instead.
+func refreshSnapshot(ctx context.Context, requestedAt time.Time) *statsSnapshot {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+
+	if snap := latestSnapshot.Load(); snap != nil && !snap.collectedAt.Before(requestedAt) {
+		return snap
+	}
+
+	snap := &statsSnapshot{
+		stats:       collectStats(ctx, false),
+		collectedAt: time.Now(),
+	}
+	latestSnapshot.Store(snap)
+	checkIncident(&snap.stats, snap.collectedAt)
+	recordHealthTransitions(&snap.stats.Health, snap.collectedAt)
+	return snap
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetLatestServiceStats_CollectsWhenEmpty(t *testing.T) {
+	stats := GetLatestServiceStats()
+	if stats.CollectedAt.IsZero() {
+		t.Fatal("expected CollectedAt to be set")
+	}
+	if stats.SnapshotAgeSeconds < 0 {
+		t.Errorf("expected non-negative snapshot age, got %v", stats.SnapshotAgeSeconds)
+	}
+	if stats.CoreStatistics.Goroutines <= 0 {
+		t.Error("expected goroutines > 0")
+	}
+}
+
+func TestGetLatestServiceStats_ServesCachedSnapshot(t *testing.T) {
+	first := GetLatestServiceStats()
+
+	start := time.Now()
+	second := GetLatestServiceStats()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected cached read to be non-blocking, took %v", elapsed)
+	}
+	if !second.CollectedAt.Equal(first.CollectedAt) {
+		t.Errorf("expected same snapshot, got %v and %v", first.CollectedAt, second.CollectedAt)
+	}
+}
+
+func TestStartStopSampler(t *testing.T) {
+	StartSampler(50 * time.Millisecond)
+	if !isSamplerRunning() {
+		t.Fatal("expected sampler to be running")
+	}
+	before := GetLatestServiceStats().CollectedAt
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if GetLatestServiceStats().CollectedAt.After(before) {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if !GetLatestServiceStats().CollectedAt.After(before) {
+		t.Error("expected sampler to refresh the snapshot")
+	}
+
+	StopSampler()
+	StopSampler() // safe to call twice
+	if isSamplerRunning() {
+		t.Error("expected sampler to be stopped")
+	}
+}
@@ -17,19 +17,18 @@ import (
 
 // OTelExporter implements the internal exporter.Exporter interface
 // and pushes metrics to an OpenTelemetry Collector via OTLP/gRPC.
+// Every metric is exported through an observable instrument reporting the
+// latest exported value of each series, so cumulative counters are not re-added.
 type OTelExporter struct {
 	provider *metric.MeterProvider
 	meter    otelmetric.Meter
 
-	mu       sync.RWMutex
-	gauges   map[string]otelmetric.Float64ObservableGauge
-	counters map[string]otelmetric.Float64Counter
-
-	// Latest gauge values, read by callbacks registered once per gauge.
-	gaugeValues sync.Map // map[string]gaugeSnapshot
+	mu           sync.Mutex
+	instruments  map[string]otelmetric.Float64Observable
+	observations map[string][]observation // instrument name to the series observed on the next collection
 }
 
-type gaugeSnapshot struct {
+type observation struct {
 	value float64
 	attrs []attribute.KeyValue
 }
@@ -66,94 +65,84 @@ func NewOTelExporter(ctx context.Context, cfg OTelConfig) (*OTelExporter, error)
 	meter := provider.Meter("monigo")
 
 	return &OTelExporter{
-		provider: provider,
-		meter:    meter,
-		gauges:   make(map[string]otelmetric.Float64ObservableGauge),
-		counters: make(map[string]otelmetric.Float64Counter),
+		provider:     provider,
+		meter:        meter,
+		instruments:  make(map[string]otelmetric.Float64Observable),
+		observations: make(map[string][]observation),
 	}, nil
 }
 
-// Export sends metrics to the OTel collector.
-// Instruments are created once and reused on subsequent calls.
-func (o *OTelExporter) Export(ctx context.Context, metrics []*registry.MetricValue) error {
-	var firstErr error
+// Export records the latest value of every series, reported on the next collection.
+// Instruments are created once and reused on subsequent calls. Histograms are exported
+// as <name>_count, <name>_sum and <name>_bucket counters, the latter with an "le" attribute.
+func (o *OTelExporter) Export(_ context.Context, metrics []*registry.MetricValue) error {
+	observations := make(map[string][]observation)
+	kinds := make(map[string]registry.MetricType)
+	add := func(name string, kind registry.MetricType, value float64, attrs []attribute.KeyValue) {
+		kinds[name] = kind
+		observations[name] = append(observations[name], observation{value: value, attrs: attrs})
+	}
+
 	for _, m := range metrics {
+		attrs := labelsToAttributes(m.Labels)
 		switch m.Type {
-		case registry.Gauge:
-			if err := o.exportGauge(m); err != nil && firstErr == nil {
-				firstErr = err
-			}
-		case registry.Counter:
-			if err := o.exportCounter(ctx, m); err != nil && firstErr == nil {
-				firstErr = err
-			}
 		case registry.Histogram:
-			logger.Log.Warn("histogram export not yet implemented", "metric", m.Name)
+			add(m.Name+"_count", registry.Counter, float64(m.Count), attrs)
+			add(m.Name+"_sum", registry.Counter, m.Sum, attrs)
+			for i, upperBound := range m.Buckets {
+				bucketAttrs := append(attrs[:len(attrs):len(attrs)], attribute.Float64("le", upperBound))
+				add(m.Name+"_bucket", registry.Counter, float64(m.BucketCounts[i]), bucketAttrs)
+			}
+		default:
+			add(m.Name, m.Type, m.Value, attrs)
 		}
 	}
-	return firstErr
-}
 
-func (o *OTelExporter) exportGauge(m *registry.MetricValue) error {
-	o.mu.RLock()
-	_, exists := o.gauges[m.Name]
-	o.mu.RUnlock()
+	o.mu.Lock()
+	defer o.mu.Unlock()
 
-	if !exists {
-		o.mu.Lock()
-		if _, exists = o.gauges[m.Name]; !exists {
-			gauge, err := o.meter.Float64ObservableGauge(m.Name)
-			if err != nil {
-				o.mu.Unlock()
-				logger.Log.Error("failed to create OTel gauge", "metric", m.Name, "error", err)
-				return err
-			}
-			name := m.Name
-			_, err = o.meter.RegisterCallback(func(_ context.Context, observer otelmetric.Observer) error {
-				if snap, ok := o.gaugeValues.Load(name); ok {
-					s := snap.(gaugeSnapshot)
-					observer.ObserveFloat64(gauge, s.value, otelmetric.WithAttributes(s.attrs...))
-				}
-				return nil
-			}, gauge)
-			if err != nil {
-				o.mu.Unlock()
-				logger.Log.Error("failed to register OTel callback", "metric", m.Name, "error", err)
-				return err
-			}
-			o.gauges[m.Name] = gauge
+	var firstErr error
+	for name, kind := range kinds {
+		if err := o.ensureInstrument(name, kind); err != nil && firstErr == nil {
+			firstErr = err
 		}
-		o.mu.Unlock()
 	}
-
-	o.gaugeValues.Store(m.Name, gaugeSnapshot{
-		value: m.Value,
-		attrs: labelsToAttributes(m.Labels),
-	})
-	return nil
+	o.observations = observations
+	return firstErr
 }
 
-func (o *OTelExporter) exportCounter(ctx context.Context, m *registry.MetricValue) error {
-	o.mu.RLock()
-	counter, exists := o.counters[m.Name]
-	o.mu.RUnlock()
+// ensureInstrument creates the observable instrument for name unless it exists. Callers must hold o.mu.
+func (o *OTelExporter) ensureInstrument(name string, kind registry.MetricType) error {
+	if _, exists := o.instruments[name]; exists {
+		return nil
+	}
 
-	if !exists {
+	var instrument otelmetric.Float64Observable
+	var err error
+	if kind == registry.Counter {
+		instrument, err = o.meter.Float64ObservableCounter(name)
+	} else {
+		instrument, err = o.meter.Float64ObservableGauge(name)
+	}
+	if err != nil {
+		logger.Log.Error("failed to create OTel instrument", "metric", name, "error", err)
+		return err
+	}
+
+	_, err = o.meter.RegisterCallback(func(_ context.Context, observer otelmetric.Observer) error {
 		o.mu.Lock()
-		if counter, exists = o.counters[m.Name]; !exists {
-			var err error
-			counter, err = o.meter.Float64Counter(m.Name)
-			if err != nil {
-				o.mu.Unlock()
-				logger.Log.Error("failed to create OTel counter", "metric", m.Name, "error", err)
-				return err
-			}
-			o.counters[m.Name] = counter
+		defer o.mu.Unlock()
+		for _, obs := range o.observations[name] {
+			observer.ObserveFloat64(instrument, obs.value, otelmetric.WithAttributes(obs.attrs...))
 		}
-		o.mu.Unlock()
+		return nil
+	}, instrument)
+	if err != nil {
+		logger.Log.Error("failed to register OTel callback", "metric", name, "error", err)
+		return err
 	}
 
-	counter.Add(ctx, m.Value, otelmetric.WithAttributes(labelsToAttributes(m.Labels)...))
+	o.instruments[name] = instrument
 	return nil
 }
 
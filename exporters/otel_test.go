@@ -0,0 +1,96 @@
+package exporters
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/iyashjayesh/monigo/internal/registry"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newTestOTelExporter returns an exporter whose metrics are read by the returned manual reader.
+func newTestOTelExporter() (*OTelExporter, *metric.ManualReader) {
+	reader := metric.NewManualReader()
+	provider := metric.NewMeterProvider(metric.WithReader(reader))
+	return &OTelExporter{
+		provider:     provider,
+		meter:        provider.Meter("monigo"),
+		instruments:  make(map[string]otelmetric.Float64Observable),
+		observations: make(map[string][]observation),
+	}, reader
+}
+
+// collectSums returns the summed data points of every counter and gauge by metric name.
+func collectSums(t *testing.T, reader *metric.ManualReader) map[string]float64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	sums := map[string]float64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[float64]:
+				for _, dp := range data.DataPoints {
+					sums[m.Name] += dp.Value
+				}
+			case metricdata.Gauge[float64]:
+				for _, dp := range data.DataPoints {
+					sums[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	return sums
+}
+
+func TestOTelExporterCountersAreCumulative(t *testing.T) {
+	exp, reader := newTestOTelExporter()
+	ctx := context.Background()
+
+	r := registry.NewRegistry()
+	r.IncrementCounter("orders_total", 3, map[string]string{"region": "eu"})
+	r.IncrementCounter("orders_total", 1, map[string]string{"region": "us"})
+	if err := exp.Export(ctx, r.GetAll()); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	r.IncrementCounter("orders_total", 2, map[string]string{"region": "eu"})
+	if err := exp.Export(ctx, r.GetAll()); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	if got := collectSums(t, reader)["orders_total"]; got != 6 {
+		t.Errorf("expected orders_total 6 across series, got %v", got)
+	}
+}
+
+func TestOTelExporterGaugesAndHistograms(t *testing.T) {
+	exp, reader := newTestOTelExporter()
+
+	r := registry.NewRegistry()
+	r.SetGauge("queue_depth", 7, nil)
+	r.RecordHistogramWithBuckets("checkout_seconds", 0.4, []float64{0.5, 1}, nil)
+	r.RecordHistogramWithBuckets("checkout_seconds", 0.8, []float64{0.5, 1}, nil)
+	if err := exp.Export(context.Background(), r.GetAll()); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	sums := collectSums(t, reader)
+	want := map[string]float64{
+		"queue_depth":             7,
+		"checkout_seconds_count":  2,
+		"checkout_seconds_sum":    1.2,
+		"checkout_seconds_bucket": 3, // cumulative counts 1 (le=0.5) + 2 (le=1)
+	}
+	for name, v := range want {
+		if got := sums[name]; math.Abs(got-v) > 1e-9 {
+			t.Errorf("expected %s = %v, got %v", name, v, got)
+		}
+	}
+}
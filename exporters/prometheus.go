@@ -1,10 +1,12 @@
 package exporters
 
 import (
-	"context"
+	"sort"
 	"sync"
 
 	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/internal/registry"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -69,7 +71,7 @@ func (c *MonigoCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect is called by the Prometheus registry when collecting metrics.
 func (c *MonigoCollector) Collect(ch chan<- prometheus.Metric) {
-	stats := core.GetServiceStats(context.Background())
+	stats := core.GetLatestServiceStats()
 
 	// CPU Load - use raw float64 values directly, no string parsing
 	ch <- prometheus.MustNewConstMetric(
@@ -104,3 +106,55 @@ func (c *MonigoCollector) Collect(ch chan<- prometheus.Metric) {
 		float64(stats.DiskIO.WriteBytes),
 	)
 }
+
+// CustomMetricsCollector exports the custom application metrics recorded through
+// monigo.Counter, monigo.Gauge and monigo.Histogram. It is an unchecked collector
+// since the set of metrics is only known at collection time.
+type CustomMetricsCollector struct {
+	registry *registry.Registry
+}
+
+// NewCustomMetricsCollector returns a collector exporting the default custom metrics registry.
+func NewCustomMetricsCollector() *CustomMetricsCollector {
+	return &CustomMetricsCollector{registry: registry.Default()}
+}
+
+// Describe sends no descriptors, marking the collector as unchecked.
+func (c *CustomMetricsCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *CustomMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range c.registry.GetAll() {
+		labelNames := make([]string, 0, len(m.Labels))
+		for name := range m.Labels {
+			labelNames = append(labelNames, name)
+		}
+		sort.Strings(labelNames)
+		labelValues := make([]string, len(labelNames))
+		for i, name := range labelNames {
+			labelValues[i] = m.Labels[name]
+		}
+
+		desc := prometheus.NewDesc(m.Name, "Custom application metric.", labelNames, nil)
+
+		var metric prometheus.Metric
+		var err error
+		switch m.Type {
+		case registry.Counter:
+			metric, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, m.Value, labelValues...)
+		case registry.Histogram:
+			buckets := make(map[float64]uint64, len(m.Buckets))
+			for i, upperBound := range m.Buckets {
+				buckets[upperBound] = m.BucketCounts[i]
+			}
+			metric, err = prometheus.NewConstHistogram(desc, m.Count, m.Sum, buckets, labelValues...)
+		default:
+			metric, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, m.Value, labelValues...)
+		}
+		if err != nil {
+			logger.Log.Debug("failed to export custom metric", "metric", m.Name, "error", err)
+			continue
+		}
+		ch <- metric
+	}
+}
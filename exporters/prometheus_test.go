@@ -3,7 +3,9 @@ package exporters
 import (
 	"testing"
 
+	"github.com/iyashjayesh/monigo/internal/registry"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 )
 
 func TestNewMonigoCollector(t *testing.T) {
@@ -54,3 +56,46 @@ func TestCollect(t *testing.T) {
 		t.Errorf("expected 5 metrics, got %d", count)
 	}
 }
+
+func TestCustomMetricsCollector(t *testing.T) {
+	r := registry.NewRegistry()
+	r.IncrementCounter("orders_total", 3, map[string]string{"region": "eu"})
+	r.SetGauge("queue_depth", 7, nil)
+	r.RecordHistogramWithBuckets("checkout_seconds", 0.4, []float64{0.5, 1}, nil)
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(&CustomMetricsCollector{registry: r})
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	types := map[string]dto.MetricType{}
+	for _, f := range families {
+		types[f.GetName()] = f.GetType()
+		if f.GetName() == "orders_total" {
+			m := f.GetMetric()[0]
+			if m.GetCounter().GetValue() != 3 || m.GetLabel()[0].GetValue() != "eu" {
+				t.Errorf("unexpected orders_total: %v", m)
+			}
+		}
+		if f.GetName() == "checkout_seconds" {
+			h := f.GetMetric()[0].GetHistogram()
+			if h.GetSampleCount() != 1 || h.GetBucket()[0].GetCumulativeCount() != 1 {
+				t.Errorf("unexpected checkout_seconds histogram: %v", h)
+			}
+		}
+	}
+
+	want := map[string]dto.MetricType{
+		"orders_total":     dto.MetricType_COUNTER,
+		"queue_depth":      dto.MetricType_GAUGE,
+		"checkout_seconds": dto.MetricType_HISTOGRAM,
+	}
+	for name, typ := range want {
+		if got, ok := types[name]; !ok || got != typ {
+			t.Errorf("expected %s of type %v, got %v (present: %v)", name, typ, got, ok)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package registry
 
 import (
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,17 +16,68 @@ const (
 	Histogram
 )
 
+// String returns the lowercase name of the metric type.
+func (t MetricType) String() string {
+	switch t {
+	case Counter:
+		return "counter"
+	case Histogram:
+		return "histogram"
+	default:
+		return "gauge"
+	}
+}
+
+// DefaultBuckets are the histogram upper bounds used when none are given, suited to latencies in seconds.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
 type MetricValue struct {
 	Name      string
 	Value     float64
 	Labels    map[string]string
 	Timestamp time.Time
 	Type      MetricType
+
+	// Histogram state. Buckets are the upper bounds and BucketCounts the cumulative
+	// number of observations less than or equal to each bound.
+	Buckets      []float64
+	BucketCounts []uint64
+	Count        uint64
+	Sum          float64
+}
+
+// Quantile estimates the q-quantile (0 <= q <= 1) of a histogram by linear interpolation
+// within the bucket containing it. Observations above the highest bound are reported at that bound.
+func (m *MetricValue) Quantile(q float64) float64 {
+	if m.Type != Histogram || m.Count == 0 || len(m.Buckets) == 0 {
+		return 0
+	}
+
+	rank := q * float64(m.Count)
+	lowerBound, lowerCount := 0.0, uint64(0)
+	for i, upperBound := range m.Buckets {
+		count := m.BucketCounts[i]
+		if float64(count) >= rank {
+			if count == lowerCount {
+				return upperBound
+			}
+			return lowerBound + (upperBound-lowerBound)*(rank-float64(lowerCount))/float64(count-lowerCount)
+		}
+		lowerBound, lowerCount = upperBound, count
+	}
+	return m.Buckets[len(m.Buckets)-1]
 }
 
 type Registry struct {
 	mu      sync.RWMutex
-	metrics map[string]*MetricValue
+	metrics map[string]*MetricValue // keyed by name and labels
+}
+
+var defaultRegistry = NewRegistry()
+
+// Default returns the process-wide registry backing the public custom metrics API.
+func Default() *Registry {
+	return defaultRegistry
 }
 
 func NewRegistry() *Registry {
@@ -32,13 +86,67 @@ func NewRegistry() *Registry {
 	}
 }
 
+// seriesKey identifies a series by its name and sorted labels.
+func seriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteByte(0)
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(labels[k])
+	}
+	return b.String()
+}
+
+// copyLabels returns a copy of labels so callers can reuse their map.
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	cp := make(map[string]string, len(labels))
+	for k, v := range labels {
+		cp[k] = v
+	}
+	return cp
+}
+
 func (r *Registry) SetGauge(name string, value float64, labels map[string]string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.metrics[name] = &MetricValue{
+	r.metrics[seriesKey(name, labels)] = &MetricValue{
 		Name:      name,
 		Value:     value,
-		Labels:    labels,
+		Labels:    copyLabels(labels),
+		Timestamp: time.Now(),
+		Type:      Gauge,
+	}
+}
+
+// AddGauge atomically adds delta (which may be negative) to a gauge metric.
+func (r *Registry) AddGauge(name string, delta float64, labels map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := seriesKey(name, labels)
+	if m, ok := r.metrics[key]; ok && m.Type == Gauge {
+		m.Value += delta
+		m.Timestamp = time.Now()
+		return
+	}
+	r.metrics[key] = &MetricValue{
+		Name:      name,
+		Value:     delta,
+		Labels:    copyLabels(labels),
 		Timestamp: time.Now(),
 		Type:      Gauge,
 	}
@@ -48,50 +156,89 @@ func (r *Registry) SetGauge(name string, value float64, labels map[string]string
 func (r *Registry) IncrementCounter(name string, delta float64, labels map[string]string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if m, ok := r.metrics[name]; ok && m.Type == Counter {
+	key := seriesKey(name, labels)
+	if m, ok := r.metrics[key]; ok && m.Type == Counter {
 		m.Value += delta
 		m.Timestamp = time.Now()
 	} else {
-		r.metrics[name] = &MetricValue{
+		r.metrics[key] = &MetricValue{
 			Name:      name,
 			Value:     delta,
-			Labels:    labels,
+			Labels:    copyLabels(labels),
 			Timestamp: time.Now(),
 			Type:      Counter,
 		}
 	}
 }
 
-// RecordHistogram records a histogram observation.
-// For the OTel exporter this is a no-op placeholder; values are exported as gauges.
+// RecordHistogram records a histogram observation into the DefaultBuckets.
 func (r *Registry) RecordHistogram(name string, value float64, labels map[string]string) {
+	r.RecordHistogramWithBuckets(name, value, DefaultBuckets, labels)
+}
+
+// RecordHistogramWithBuckets records a histogram observation. buckets are the upper bounds
+// and only apply when the series is created; Value holds the latest observation.
+func (r *Registry) RecordHistogramWithBuckets(name string, value float64, buckets []float64, labels map[string]string) {
+	if math.IsNaN(value) {
+		return
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.metrics[name] = &MetricValue{
-		Name:      name,
-		Value:     value,
-		Labels:    labels,
-		Timestamp: time.Now(),
-		Type:      Histogram,
+	key := seriesKey(name, labels)
+	m, ok := r.metrics[key]
+	if !ok || m.Type != Histogram {
+		m = &MetricValue{
+			Name:         name,
+			Labels:       copyLabels(labels),
+			Type:         Histogram,
+			Buckets:      append([]float64(nil), buckets...),
+			BucketCounts: make([]uint64, len(buckets)),
+		}
+		sort.Float64s(m.Buckets)
+		r.metrics[key] = m
+	}
+
+	for i, upperBound := range m.Buckets {
+		if value <= upperBound {
+			m.BucketCounts[i]++
+		}
 	}
+	m.Count++
+	m.Sum += value
+	m.Value = value
+	m.Timestamp = time.Now()
 }
 
-// GetAll returns a snapshot copy of all metrics.
+// GetAll returns a snapshot copy of all metrics, sorted by name and labels.
 func (r *Registry) GetAll() []*MetricValue {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	keys := make([]string, 0, len(r.metrics))
+	for k := range r.metrics {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	values := make([]*MetricValue, 0, len(r.metrics))
-	for _, v := range r.metrics {
-		cp := *v
+	for _, k := range keys {
+		cp := *r.metrics[k]
+		cp.Labels = copyLabels(cp.Labels)
+		cp.Buckets = append([]float64(nil), cp.Buckets...)
+		cp.BucketCounts = append([]uint64(nil), cp.BucketCounts...)
 		values = append(values, &cp)
 	}
 	return values
 }
 
-// Delete removes a metric by name.
+// Delete removes every series of the named metric.
 func (r *Registry) Delete(name string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	delete(r.metrics, name)
+	for k, m := range r.metrics {
+		if m.Name == name {
+			delete(r.metrics, k)
+		}
+	}
 }
@@ -115,3 +115,81 @@ func TestConcurrentAccess(t *testing.T) {
 		t.Errorf("expected 2 metrics, got %d", len(metrics))
 	}
 }
+
+func TestSeriesKeyedByLabels(t *testing.T) {
+	r := NewRegistry()
+	r.IncrementCounter("requests", 1, map[string]string{"method": "GET"})
+	r.IncrementCounter("requests", 2, map[string]string{"method": "POST"})
+	r.IncrementCounter("requests", 3, map[string]string{"method": "GET"})
+
+	metrics := r.GetAll()
+	if len(metrics) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(metrics))
+	}
+	byMethod := map[string]float64{}
+	for _, m := range metrics {
+		byMethod[m.Labels["method"]] = m.Value
+	}
+	if byMethod["GET"] != 4 || byMethod["POST"] != 2 {
+		t.Errorf("unexpected counter values: %v", byMethod)
+	}
+
+	r.Delete("requests")
+	if len(r.GetAll()) != 0 {
+		t.Error("expected Delete to remove every series of the metric")
+	}
+}
+
+func TestLabelsAreCopied(t *testing.T) {
+	r := NewRegistry()
+	labels := map[string]string{"region": "eu"}
+	r.SetGauge("queue", 1, labels)
+	labels["region"] = "us"
+
+	if got := r.GetAll()[0].Labels["region"]; got != "eu" {
+		t.Errorf("expected stored labels to be unaffected by caller, got %q", got)
+	}
+}
+
+func TestAddGauge(t *testing.T) {
+	r := NewRegistry()
+	r.AddGauge("inflight", 3, nil)
+	r.AddGauge("inflight", -1, nil)
+
+	if got := r.GetAll()[0].Value; got != 2 {
+		t.Errorf("expected gauge value 2, got %f", got)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	r := NewRegistry()
+	for _, v := range []float64{0.5, 1.5, 2.5, 3.5} {
+		r.RecordHistogramWithBuckets("size", v, []float64{4, 1, 2, 3}, nil)
+	}
+
+	m := r.GetAll()[0]
+	if m.Count != 4 || m.Sum != 8 {
+		t.Errorf("expected count 4 and sum 8, got %d and %f", m.Count, m.Sum)
+	}
+	wantCounts := []uint64{1, 2, 3, 4}
+	for i, want := range wantCounts {
+		if m.BucketCounts[i] != want {
+			t.Errorf("bucket %v: expected cumulative count %d, got %d", m.Buckets[i], want, m.BucketCounts[i])
+		}
+	}
+	if q := m.Quantile(0.5); q != 2 {
+		t.Errorf("expected median 2, got %f", q)
+	}
+	if q := m.Quantile(0.75); q != 3 {
+		t.Errorf("expected 75th percentile 3, got %f", q)
+	}
+}
+
+func TestHistogramQuantileAboveHighestBucket(t *testing.T) {
+	r := NewRegistry()
+	r.RecordHistogramWithBuckets("size", 100, []float64{1, 10}, nil)
+
+	if q := r.GetAll()[0].Quantile(0.99); q != 10 {
+		t.Errorf("expected quantile to be capped at the highest bound, got %f", q)
+	}
+}
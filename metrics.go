@@ -0,0 +1,196 @@
+package monigo
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/internal/registry"
+)
+
+var (
+	metricNameRegex = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRegex  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	customMetricsMu sync.Mutex
+	customMetrics   = make(map[string]customMetricSchema)
+)
+
+// customMetricSchema is the type and label names a custom metric was first declared with.
+// Exporters require every series of a metric to share them.
+type customMetricSchema struct {
+	metricType registry.MetricType
+	labelNames []string
+}
+
+// CounterMetric is a custom metric which only increases, e.g. the number of orders placed.
+type CounterMetric struct {
+	name   string
+	labels map[string]string
+	valid  bool
+}
+
+// GaugeMetric is a custom metric which can go up and down, e.g. the size of a queue.
+type GaugeMetric struct {
+	name   string
+	labels map[string]string
+	valid  bool
+}
+
+// HistogramMetric is a custom metric which counts observations into buckets, e.g. request durations.
+type HistogramMetric struct {
+	name    string
+	labels  map[string]string
+	buckets []float64
+	valid   bool
+}
+
+// Counter returns the counter with the given name and labels, passed as key/value pairs:
+//
+//	monigo.Counter("orders_total", "region", "eu").Inc()
+//
+// Values are stored with the runtime metrics, shown on the custom metrics dashboard page
+// and exported through Prometheus and OpenTelemetry. An invalid declaration is logged and
+// the returned counter drops its values.
+func Counter(name string, labels ...string) *CounterMetric {
+	labelMap, valid := declareCustomMetric(name, registry.Counter, labels)
+	return &CounterMetric{name: name, labels: labelMap, valid: valid}
+}
+
+// Inc increments the counter by 1.
+func (c *CounterMetric) Inc() {
+	c.Add(1)
+}
+
+// Add increases the counter by delta. Negative deltas are ignored since counters only increase.
+func (c *CounterMetric) Add(delta float64) {
+	if !c.valid || delta < 0 {
+		return
+	}
+	registry.Default().IncrementCounter(c.name, delta, c.labels)
+}
+
+// Gauge returns the gauge with the given name and labels, passed as key/value pairs:
+//
+//	monigo.Gauge("queue_depth", "queue", "emails").Set(42)
+func Gauge(name string, labels ...string) *GaugeMetric {
+	labelMap, valid := declareCustomMetric(name, registry.Gauge, labels)
+	return &GaugeMetric{name: name, labels: labelMap, valid: valid}
+}
+
+// Set sets the gauge to value.
+func (g *GaugeMetric) Set(value float64) {
+	if g.valid {
+		registry.Default().SetGauge(g.name, value, g.labels)
+	}
+}
+
+// Inc increments the gauge by 1.
+func (g *GaugeMetric) Inc() {
+	g.Add(1)
+}
+
+// Dec decrements the gauge by 1.
+func (g *GaugeMetric) Dec() {
+	g.Add(-1)
+}
+
+// Add adds delta, which may be negative, to the gauge.
+func (g *GaugeMetric) Add(delta float64) {
+	if g.valid {
+		registry.Default().AddGauge(g.name, delta, g.labels)
+	}
+}
+
+// Histogram returns the histogram with the given name and labels, passed as key/value pairs,
+// using buckets suited to latencies in seconds:
+//
+//	monigo.Histogram("checkout_duration_seconds", "method", "card").Observe(0.42)
+func Histogram(name string, labels ...string) *HistogramMetric {
+	return HistogramWithBuckets(name, registry.DefaultBuckets, labels...)
+}
+
+// HistogramWithBuckets is like Histogram with custom bucket upper bounds.
+// Buckets only take effect the first time a series is observed.
+func HistogramWithBuckets(name string, buckets []float64, labels ...string) *HistogramMetric {
+	labelMap, valid := declareCustomMetric(name, registry.Histogram, labels)
+	if _, ok := labelMap["le"]; ok {
+		logger.Log.Warn("custom metric ignored: histograms cannot use the reserved label \"le\"", "metric", name)
+		valid = false
+	}
+	if len(buckets) == 0 {
+		buckets = registry.DefaultBuckets
+	}
+	return &HistogramMetric{name: name, labels: labelMap, buckets: buckets, valid: valid}
+}
+
+// Observe records a single observation.
+func (h *HistogramMetric) Observe(value float64) {
+	if h.valid {
+		registry.Default().RecordHistogramWithBuckets(h.name, value, h.buckets, h.labels)
+	}
+}
+
+// declareCustomMetric validates a custom metric declaration and returns its labels as a map.
+// A metric keeps the type and label names it was first declared with.
+func declareCustomMetric(name string, metricType registry.MetricType, labels []string) (map[string]string, bool) {
+	labelMap, err := parseCustomMetricLabels(labels)
+	if err == nil && !metricNameRegex.MatchString(name) {
+		err = fmt.Errorf("invalid metric name")
+	}
+	if err != nil {
+		logger.Log.Warn("custom metric ignored", "metric", name, "error", err)
+		return labelMap, false
+	}
+
+	labelNames := make([]string, 0, len(labelMap))
+	for k := range labelMap {
+		labelNames = append(labelNames, k)
+	}
+	sort.Strings(labelNames)
+
+	customMetricsMu.Lock()
+	defer customMetricsMu.Unlock()
+
+	schema, exists := customMetrics[name]
+	if !exists {
+		customMetrics[name] = customMetricSchema{metricType: metricType, labelNames: labelNames}
+		return labelMap, true
+	}
+	if schema.metricType != metricType || !slices.Equal(schema.labelNames, labelNames) {
+		logger.Log.Warn("custom metric ignored: already declared with a different type or label names",
+			"metric", name, "type", schema.metricType.String(), "labels", schema.labelNames)
+		return labelMap, false
+	}
+	return labelMap, true
+}
+
+// parseCustomMetricLabels converts key/value pairs into a label map.
+func parseCustomMetricLabels(labels []string) (map[string]string, error) {
+	if len(labels)%2 != 0 {
+		return nil, fmt.Errorf("labels must be key/value pairs, got %d values", len(labels))
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	labelMap := make(map[string]string, len(labels)/2)
+	for i := 0; i < len(labels); i += 2 {
+		key := labels[i]
+		if !labelNameRegex.MatchString(key) || strings.HasPrefix(key, "__") {
+			return nil, fmt.Errorf("invalid label name %q", key)
+		}
+		if key == "host" || key == "collector" {
+			return nil, fmt.Errorf("label name %q is reserved for stored series", key)
+		}
+		if _, dup := labelMap[key]; dup {
+			return nil, fmt.Errorf("duplicate label name %q", key)
+		}
+		labelMap[key] = labels[i+1]
+	}
+	return labelMap, nil
+}
@@ -0,0 +1,94 @@
+package monigo
+
+import (
+	"testing"
+
+	"github.com/iyashjayesh/monigo/internal/registry"
+)
+
+// findCustomMetric returns the series of the named metric with the given label value, if any.
+func findCustomMetric(name, labelName, labelValue string) *registry.MetricValue {
+	for _, m := range registry.Default().GetAll() {
+		if m.Name == name && m.Labels[labelName] == labelValue {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestCounter(t *testing.T) {
+	defer registry.Default().Delete("test_orders_total")
+
+	Counter("test_orders_total", "region", "eu").Inc()
+	Counter("test_orders_total", "region", "eu").Add(2)
+	Counter("test_orders_total", "region", "eu").Add(-5) // ignored
+	Counter("test_orders_total", "region", "us").Inc()
+
+	if m := findCustomMetric("test_orders_total", "region", "eu"); m == nil || m.Value != 3 || m.Type != registry.Counter {
+		t.Errorf("unexpected eu counter: %+v", m)
+	}
+	if m := findCustomMetric("test_orders_total", "region", "us"); m == nil || m.Value != 1 {
+		t.Errorf("unexpected us counter: %+v", m)
+	}
+}
+
+func TestGauge(t *testing.T) {
+	defer registry.Default().Delete("test_queue_depth")
+
+	g := Gauge("test_queue_depth", "queue", "emails")
+	g.Set(10)
+	g.Inc()
+	g.Dec()
+	g.Dec()
+
+	if m := findCustomMetric("test_queue_depth", "queue", "emails"); m == nil || m.Value != 9 || m.Type != registry.Gauge {
+		t.Errorf("unexpected gauge: %+v", m)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	defer registry.Default().Delete("test_checkout_seconds")
+
+	h := HistogramWithBuckets("test_checkout_seconds", []float64{0.1, 1}, "method", "card")
+	h.Observe(0.05)
+	h.Observe(0.5)
+
+	m := findCustomMetric("test_checkout_seconds", "method", "card")
+	if m == nil || m.Count != 2 || m.Type != registry.Histogram {
+		t.Fatalf("unexpected histogram: %+v", m)
+	}
+	if m.BucketCounts[0] != 1 || m.BucketCounts[1] != 2 {
+		t.Errorf("unexpected bucket counts: %v", m.BucketCounts)
+	}
+}
+
+func TestInvalidCustomMetricsAreDropped(t *testing.T) {
+	defer registry.Default().Delete("test_declared_total")
+
+	cases := map[string]func(){
+		"invalid name":          func() { Counter("orders-total").Inc() },
+		"odd labels":            func() { Counter("test_odd_total", "region").Inc() },
+		"invalid label":         func() { Counter("test_bad_label_total", "1region", "eu").Inc() },
+		"reserved label":        func() { Gauge("test_reserved", "host", "a").Set(1) },
+		"reserved histogram le": func() { Histogram("test_le_seconds", "le", "1").Observe(1) },
+	}
+	for name, record := range cases {
+		t.Run(name, func(t *testing.T) {
+			before := len(registry.Default().GetAll())
+			record()
+			if after := len(registry.Default().GetAll()); after != before {
+				t.Errorf("expected invalid metric to be dropped, series went from %d to %d", before, after)
+			}
+		})
+	}
+
+	Counter("test_declared_total", "region", "eu").Inc()
+	Gauge("test_declared_total", "region", "eu").Set(5)     // different type
+	Counter("test_declared_total", "queue", "emails").Inc() // different label names
+	if m := findCustomMetric("test_declared_total", "region", "eu"); m == nil || m.Value != 1 || m.Type != registry.Counter {
+		t.Errorf("expected redeclarations to be ignored, got %+v", m)
+	}
+	if m := findCustomMetric("test_declared_total", "queue", "emails"); m != nil {
+		t.Errorf("expected series with different label names to be dropped, got %+v", m)
+	}
+}
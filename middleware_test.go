@@ -5,6 +5,9 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/iyashjayesh/monigo/api"
+	"github.com/iyashjayesh/monigo/core"
 )
 
 func TestBasicAuthMiddleware(t *testing.T) {
@@ -250,3 +253,115 @@ func TestCustomAuthFunction(t *testing.T) {
 		t.Errorf("Expected status 401, got %d", w.Code)
 	}
 }
+
+func TestProfilingRequiresSecuredHandlers(t *testing.T) {
+	path := "/monigo/api/v1/profiles"
+
+	// Unsecured handlers refuse profiling
+	w := httptest.NewRecorder()
+	GetAPIHandlers()[path](w, httptest.NewRequest("GET", path, nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 without authentication, got %d", w.Code)
+	}
+
+	m := &Monigo{
+		ServiceName: "test-service",
+		APIMiddleware: []func(http.Handler) http.Handler{
+			APIKeyMiddleware("test-key"),
+		},
+	}
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("X-API-Key", "test-key")
+	w = httptest.NewRecorder()
+	GetSecuredAPIHandlers(m)[path](w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 behind API middleware, got %d", w.Code)
+	}
+}
+
+func TestProbesSkipAuthentication(t *testing.T) {
+	m := &Monigo{
+		ServiceName: "test-service",
+		DashboardMiddleware: []func(http.Handler) http.Handler{
+			BasicAuthMiddleware("admin", "password"),
+		},
+		AuthFunction: func(r *http.Request) bool { return false },
+	}
+	handler := GetSecuredUnifiedHandler(m)
+	// Liveness fails without the sampler
+	core.GetLatestServiceStats()
+	core.StartSampler(time.Hour)
+	defer core.StopSampler()
+
+	for _, path := range []string{"/healthz", "/readyz", "/monigo/api/v1/healthz", "/monigo/api/v1/readyz"} {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for %s without credentials, got %d", path, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/monigo/api/v1/metrics", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for other endpoints, got %d", w.Code)
+	}
+}
+
+func TestPassThroughMiddlewareDoesNotAuthenticate(t *testing.T) {
+	passThrough := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-ID", "test")
+			next.ServeHTTP(w, r)
+		})
+	}
+	m := &Monigo{
+		ServiceName:         "test-service",
+		APIMiddleware:       []func(http.Handler) http.Handler{passThrough, LoggingMiddleware()},
+		DashboardMiddleware: []func(http.Handler) http.Handler{passThrough},
+	}
+
+	requests := map[string]func() *http.Request{
+		"/monigo/api/v1/profiles": func() *http.Request { return httptest.NewRequest("GET", "/monigo/api/v1/profiles", nil) },
+		"/monigo/api/v1/import":   func() *http.Request { return httptest.NewRequest("POST", "/monigo/api/v1/import", nil) },
+	}
+	for path, req := range requests {
+		w := httptest.NewRecorder()
+		GetSecuredAPIHandlers(m)[path](w, req())
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected status 403 behind pass-through API middleware, got %d", path, w.Code)
+		}
+		w = httptest.NewRecorder()
+		GetSecuredUnifiedHandler(m)(w, req())
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected status 403 behind pass-through dashboard middleware, got %d", path, w.Code)
+		}
+	}
+
+	// Custom authentication middleware opts in by marking the request
+	m.APIMiddleware = append(m.APIMiddleware, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, api.WithAuthenticated(r))
+		})
+	})
+	w := httptest.NewRecorder()
+	GetSecuredAPIHandlers(m)["/monigo/api/v1/profiles"](w, requests["/monigo/api/v1/profiles"]())
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 behind marking middleware, got %d", w.Code)
+	}
+}
+
+func TestAuthFunctionAuthenticates(t *testing.T) {
+	m := &Monigo{
+		ServiceName:  "test-service",
+		AuthFunction: func(r *http.Request) bool { return r.Header.Get("X-Custom-Auth") == "valid" },
+	}
+	req := httptest.NewRequest("POST", "/monigo/api/v1/import", nil)
+	req.Header.Set("X-Custom-Auth", "valid")
+	w := httptest.NewRecorder()
+	GetSecuredUnifiedHandler(m)(w, req)
+	// The format is missing, so the request gets past the authentication check
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 after the auth function, got %d: %s", w.Code, w.Body.String())
+	}
+}
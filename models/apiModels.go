@@ -31,9 +31,25 @@ type ServiceStats struct {
 		BytesSent     float64 `json:"bytes_sent"`
 		BytesReceived float64 `json:"bytes_received"`
 	} `json:"network_io"`
+	NetworkStatistics NetworkStatistics `json:"network_statistics"` // Per-interface counters and rates
+	ProcessStatistics ProcessStatistics `json:"process_statistics"` // File descriptors, threads, context switches, page faults
+	GCStatistics      GCStatistics      `json:"gc_statistics"`      // GC settings, heap goal and GC activity
+
+	// Metrics reported by custom collectors, keyed by collector name
+	CollectorMetrics map[string][]CollectorMetric `json:"collector_metrics,omitempty"`
+
+	// Application metrics recorded through monigo.Counter, monigo.Gauge and monigo.Histogram
+	CustomMetrics []CustomMetric `json:"custom_metrics,omitempty"`
+
+	// Goroutine counts of the largest stack signatures, sampled by the goroutines collector
+	GoroutineSignatures []GoroutineSignatureCount `json:"goroutine_signatures,omitempty"`
 
 	// Health
 	Health ServiceHealth `json:"health"`
+
+	// Snapshot metadata, set when served from the background sampler
+	CollectedAt        time.Time `json:"collected_at"`
+	SnapshotAgeSeconds float64   `json:"snapshot_age_seconds"`
 }
 
 // CoreStatistics represents the core statistics of the service.
@@ -59,6 +75,7 @@ type LoadStatistics struct {
 	// Raw values for storage
 	ServiceCPULoadRaw       float64 `json:"-"`
 	SystemCPULoadRaw        float64 `json:"-"`
+	TotalCPULoadRaw         float64 `json:"-"`
 	ServiceMemLoadRaw       float64 `json:"-"`
 	SystemMemLoadRaw        float64 `json:"-"`
 	OverallLoadOfServiceRaw float64 `json:"-"`
@@ -97,6 +114,84 @@ type MemoryStatistics struct {
 	StackMemoryUsageRaw    float64 `json:"-"`
 }
 
+// NetworkStatistics represents the per-interface network statistics of the host.
+type NetworkStatistics struct {
+	Interfaces          []NetworkInterfaceStats `json:"interfaces"`
+	BytesSentPerSec     float64                 `json:"bytes_sent_per_sec"`
+	BytesReceivedPerSec float64                 `json:"bytes_received_per_sec"`
+}
+
+// NetworkInterfaceStats represents the cumulative counters and per-second rates of a single network interface.
+type NetworkInterfaceStats struct {
+	Name            string `json:"name"`
+	BytesSent       uint64 `json:"bytes_sent"`
+	BytesReceived   uint64 `json:"bytes_received"`
+	PacketsSent     uint64 `json:"packets_sent"`
+	PacketsReceived uint64 `json:"packets_received"`
+	ErrorsIn        uint64 `json:"errors_in"`
+	ErrorsOut       uint64 `json:"errors_out"`
+	DropsIn         uint64 `json:"drops_in"`
+	DropsOut        uint64 `json:"drops_out"`
+	// Rates computed from the previous sample; zero on the first sample
+	BytesSentPerSec       float64 `json:"bytes_sent_per_sec"`
+	BytesReceivedPerSec   float64 `json:"bytes_received_per_sec"`
+	PacketsSentPerSec     float64 `json:"packets_sent_per_sec"`
+	PacketsReceivedPerSec float64 `json:"packets_received_per_sec"`
+	ErrorsInPerSec        float64 `json:"errors_in_per_sec"`
+	ErrorsOutPerSec       float64 `json:"errors_out_per_sec"`
+	DropsInPerSec         float64 `json:"drops_in_per_sec"`
+	DropsOutPerSec        float64 `json:"drops_out_per_sec"`
+}
+
+// CollectorMetric is a single value reported by a custom collector.
+type CollectorMetric struct {
+	Name   string            `json:"name"`
+	Value  float64           `json:"value"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// CustomMetric represents the current value of a custom application metric series.
+type CustomMetric struct {
+	Name      string             `json:"name"`
+	Type      string             `json:"type"` // "counter", "gauge" or "histogram"
+	Labels    map[string]string  `json:"labels,omitempty"`
+	Value     float64            `json:"value"` // Latest observation for histograms
+	Count     uint64             `json:"count,omitempty"`
+	Sum       float64            `json:"sum,omitempty"`
+	Quantiles map[string]float64 `json:"quantiles,omitempty"` // Estimated from the histogram buckets, keyed by quantile
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// CollectorStatus represents the configuration and last run of a collector.
+type CollectorStatus struct {
+	Name           string            `json:"name"`
+	Builtin        bool              `json:"builtin"`
+	Enabled        bool              `json:"enabled"`
+	Interval       string            `json:"interval"`
+	Timeout        string            `json:"timeout"`
+	LastRun        time.Time         `json:"last_run"`
+	LastDurationMs float64           `json:"last_duration_ms"`
+	LastError      string            `json:"last_error,omitempty"`
+	Metrics        []CollectorMetric `json:"metrics,omitempty"`
+}
+
+// ProcessStatistics represents the OS-level resource usage of the service process.
+type ProcessStatistics struct {
+	OpenFDs                int32   `json:"open_fds"`
+	MaxFDs                 int64   `json:"max_fds"` // Soft RLIMIT_NOFILE, 0 when unknown or unlimited
+	FDUsagePercent         float64 `json:"fd_usage_percent"`
+	Threads                int32   `json:"threads"`
+	VoluntaryCtxSwitches   int64   `json:"voluntary_ctx_switches"`
+	InvoluntaryCtxSwitches int64   `json:"involuntary_ctx_switches"`
+	MinorPageFaults        uint64  `json:"minor_page_faults"`
+	MajorPageFaults        uint64  `json:"major_page_faults"`
+	ResidentMemory         string  `json:"resident_memory"`
+	VirtualMemory          string  `json:"virtual_memory"`
+	// Raw values for storage
+	ResidentMemoryRaw uint64 `json:"-"`
+	VirtualMemoryRaw  uint64 `json:"-"`
+}
+
 // ServiceHealth represents the health of the service.
 type ServiceHealth struct {
 	SystemHealth  Health `json:"system_health"`
@@ -105,10 +200,122 @@ type ServiceHealth struct {
 
 // Health represents the health of the service.
 type Health struct {
-	Percent float64 `json:"percent"`
-	Healthy bool    `json:"healthy"`
-	Message string  `json:"message"`
-	IconMsg string  `json:"icon_msg"`
+	Percent float64             `json:"percent"`
+	Healthy bool                `json:"healthy"`
+	Status  string              `json:"status"` // ok, warning or critical
+	Message string              `json:"message"`
+	IconMsg string              `json:"icon_msg"`
+	Checks  []HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult represents the outcome of a single health check.
+type HealthCheckResult struct {
+	Name     string  `json:"name"`
+	Score    float64 `json:"score"`  // 0 (unhealthy) to 100 (healthy)
+	Status   string  `json:"status"` // ok, warning, critical or skipped
+	Message  string  `json:"message"`
+	Weight   float64 `json:"weight"`
+	Critical bool    `json:"critical"` // Whether a critical status forces the overall health to critical
+}
+
+// Alert represents the state of an alert rule.
+type Alert struct {
+	Rule            string            `json:"rule"`
+	State           string            `json:"state"` // inactive, pending, firing or resolved
+	Metric          string            `json:"metric"`
+	Op              string            `json:"op"`
+	Threshold       float64           `json:"threshold"`
+	Value           *float64          `json:"value,omitempty"` // Latest evaluated value, nil without data
+	Labels          map[string]string `json:"labels,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	ActiveAt        *time.Time        `json:"active_at,omitempty"`   // When the condition started to hold
+	FiredAt         *time.Time        `json:"fired_at,omitempty"`    // When the alert last started firing
+	ResolvedAt      *time.Time        `json:"resolved_at,omitempty"` // When the alert last resolved
+	LastEvaluatedAt time.Time         `json:"last_evaluated_at"`
+	Error           string            `json:"error,omitempty"` // Why the last evaluation failed, e.g. no data
+}
+
+// AlertNotification represents a group of alerts delivered to the notifiers, either firing or resolved.
+type AlertNotification struct {
+	Service     string            `json:"service"`
+	Status      string            `json:"status"`       // firing or resolved
+	GroupLabels map[string]string `json:"group_labels"` // Labels shared by the grouped alerts
+	Alerts      []Alert           `json:"alerts"`
+	Title       string            `json:"title"`   // Rendered title template
+	Message     string            `json:"message"` // Rendered message template
+	Time        time.Time         `json:"time"`
+}
+
+// AnomalyEvent represents a value of a metric far from its learned baseline.
+type AnomalyEvent struct {
+	Time     time.Time `json:"time"` // Time of the anomalous data point
+	Metric   string    `json:"metric"`
+	Value    float64   `json:"value"`
+	Expected float64   `json:"expected"` // Baseline mean
+	StdDev   float64   `json:"std_dev"`  // Baseline standard deviation
+	Score    float64   `json:"score"`    // z-score, negative below the baseline
+	Seasonal bool      `json:"seasonal"` // Whether the baseline is the hour of day's rather than the overall one
+}
+
+// AnomalyStatus represents the latest scored value of a metric watched by the anomaly detector.
+type AnomalyStatus struct {
+	Metric    string    `json:"metric"`
+	Value     float64   `json:"value"`
+	Expected  float64   `json:"expected"`
+	StdDev    float64   `json:"std_dev"`
+	Score     float64   `json:"score"`
+	Samples   int       `json:"samples"`  // Samples the baseline was learned from
+	Seasonal  bool      `json:"seasonal"` // Whether the baseline is the hour of day's rather than the overall one
+	Learning  bool      `json:"learning"` // Too few samples to score the value yet
+	Anomalous bool      `json:"anomalous"`
+	Time      time.Time `json:"time"` // Time of the scored data point
+	Error     string    `json:"error,omitempty"`
+}
+
+// SLOStatus represents the attainment, error budget and burn rates of an SLO over its window.
+type SLOStatus struct {
+	Name                 string             `json:"name"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type"`
+	Objective            float64            `json:"objective"`
+	Window               string             `json:"window"`
+	GoodEvents           float64            `json:"good_events"`
+	TotalEvents          float64            `json:"total_events"`
+	Attainment           float64            `json:"attainment"`             // Percentage of good events, 100 without events
+	ErrorBudgetRemaining float64            `json:"error_budget_remaining"` // Percentage of the error budget left, negative once exhausted
+	BurnRates            map[string]float64 `json:"burn_rates"`             // Error rate relative to the budgeted one by window, e.g. "1h"
+	FastBurn             bool               `json:"fast_burn"`              // Burning over 14.4 times the budgeted rate over 1h and 5m
+	SlowBurn             bool               `json:"slow_burn"`              // Burning over 6 times the budgeted rate over 6h and 30m
+	Since                time.Time          `json:"since"`                  // Start of the window, or of the oldest recorded event within it
+	UpdatedAt            time.Time          `json:"updated_at"`
+}
+
+// HealthEvent represents a transition of the service's or system's health level.
+type HealthEvent struct {
+	Time    time.Time           `json:"time"`
+	Scope   string              `json:"scope"` // service or system
+	From    string              `json:"from"`  // Previous level, e.g. Good
+	To      string              `json:"to"`    // New level, e.g. Poor
+	Percent float64             `json:"percent"`
+	Status  string              `json:"status"` // ok, warning or critical
+	Message string              `json:"message"`
+	Checks  []HealthCheckResult `json:"checks"` // Per-check breakdown at the transition
+}
+
+// ProbeResult represents the response of a liveness or readiness probe.
+type ProbeResult struct {
+	Status string       `json:"status"` // ok or fail
+	Checks []ProbeCheck `json:"checks"`
+}
+
+// ProbeCheck represents the outcome of a single probe check.
+type ProbeCheck struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status"` // ok or fail
+	Message    string    `json:"message,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+	Cached     bool      `json:"cached"` // Whether the result was reused from an earlier check
 }
 
 // RawMemStatsRecords holds a list of raw memory statistic records.
@@ -127,8 +334,92 @@ type Record struct {
 
 // GoRoutinesStatistic represents the Go routines statistics.
 type GoRoutinesStatistic struct {
-	NumberOfGoroutines int      `json:"number_of_goroutines"`
-	StackView          []string `json:"stack_view"`
+	NumberOfGoroutines int              `json:"number_of_goroutines"`
+	States             map[string]int   `json:"states"`     // Number of goroutines per state, before filtering
+	View               string           `json:"view"`       // "goroutines" or "groups"
+	Total              int              `json:"total"`      // Number of goroutines or groups matching the filter
+	Page               int              `json:"page"`       // 1-based page number
+	PageSize           int              `json:"page_size"`  // 0 when all entries are returned
+	Goroutines         []GoroutineInfo  `json:"goroutines"` // Page of goroutines when View is "goroutines"
+	Groups             []GoroutineGroup `json:"groups"`     // Page of groups when View is "groups"
+	Leaks              []GoroutineLeak  `json:"leaks"`      // Signatures suspected of leaking, largest growth first
+	StackView          []string         `json:"stack_view"` // Raw stacks of the goroutines on the page
+}
+
+// GoroutineFrame represents a single call in a goroutine stack.
+type GoroutineFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// GoroutineInfo represents a single goroutine parsed from a stack dump.
+type GoroutineInfo struct {
+	ID             int64            `json:"id"`
+	State          string           `json:"state"`
+	WaitMinutes    int              `json:"wait_minutes"` // Time blocked, reported by the runtime in whole minutes
+	LockedToThread bool             `json:"locked_to_thread"`
+	CreatedBy      *GoroutineFrame  `json:"created_by,omitempty"`
+	CreatorID      int64            `json:"creator_id,omitempty"`
+	Frames         []GoroutineFrame `json:"frames"`
+	Signature      string           `json:"signature"`
+	Stack          string           `json:"-"`
+}
+
+// GoroutineGroup represents goroutines sharing the same stack signature.
+type GoroutineGroup struct {
+	Signature      string           `json:"signature"`
+	Count          int              `json:"count"`
+	States         map[string]int   `json:"states"`
+	MinWaitMinutes int              `json:"min_wait_minutes"`
+	MaxWaitMinutes int              `json:"max_wait_minutes"`
+	CreatedBy      *GoroutineFrame  `json:"created_by,omitempty"`
+	Frames         []GoroutineFrame `json:"frames"`
+	IDs            []int64          `json:"ids"` // Lowest goroutine IDs of the group, at most 100
+}
+
+// GoroutineSignatureCount represents the number of goroutines with a stack signature at a sample.
+type GoroutineSignatureCount struct {
+	Signature string `json:"signature"`
+	Function  string `json:"function"` // Top frame of the stack
+	Count     int    `json:"count"`
+}
+
+// GoroutineLeak represents a stack signature whose goroutine count grew monotonically over the leak window.
+type GoroutineLeak struct {
+	Signature     string           `json:"signature"`
+	Count         int              `json:"count"`
+	Growth        int              `json:"growth"` // Increase since Since
+	GrowthPerHour float64          `json:"growth_per_hour"`
+	Since         time.Time        `json:"since"`       // Time of the oldest sample in the window
+	DetectedAt    time.Time        `json:"detected_at"` // When the signature was first flagged
+	CreatedBy     *GoroutineFrame  `json:"created_by,omitempty"`
+	Frames        []GoroutineFrame `json:"frames"`
+}
+
+// ProfileRecord represents a stored profile.
+type ProfileRecord struct {
+	ID              string    `json:"id"`
+	Type            string    `json:"type"`   // cpu, trace, heap, allocs, goroutine, mutex, block or threadcreate
+	Source          string    `json:"source"` // What captured the profile, e.g. "on-demand"
+	CreatedAt       time.Time `json:"created_at"`
+	DurationSeconds float64   `json:"duration_seconds"` // Capture duration, 0 for snapshots
+	SizeBytes       int64     `json:"size_bytes"`       // Compressed size on disk
+}
+
+// ProfileTimeline represents the stored profiles captured in a time range, oldest first.
+type ProfileTimeline struct {
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Interval float64         `json:"interval_seconds"` // Continuous profiling interval, 0 when disabled
+	Profiles []ProfileRecord `json:"profiles"`
+}
+
+// ProfileReport represents a stored profile rendered by pprof.
+type ProfileReport struct {
+	Profile    ProfileRecord `json:"profile"`
+	ReportType string        `json:"report_type"`
+	Report     string        `json:"report"`
 }
 
 // FunctionTraceDetails represents the function trace details.
@@ -153,3 +444,125 @@ type FunctionMetrics struct {
 	GoroutineCount     int           `json:"goroutine_count"`
 	ExecutionTime      time.Duration `json:"execution_time"`
 }
+
+// Incident represents the profiles captured automatically when a health threshold was crossed.
+type Incident struct {
+	ID        string           `json:"id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Metrics   []IncidentMetric `json:"metrics"`          // Health metrics when the threshold was crossed
+	Profiles  []ProfileRecord  `json:"profiles"`         // CPU, heap and goroutine profiles
+	Errors    []string         `json:"errors,omitempty"` // Profiles that could not be captured
+}
+
+// IncidentMetric represents a health metric compared with its threshold.
+type IncidentMetric struct {
+	Name      string  `json:"name"` // cpu_usage, memory_usage, goroutines, fd_usage or threads
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Breached  bool    `json:"breached"`
+}
+
+// GCStatistics represents the garbage collector settings and activity of the service.
+type GCStatistics struct {
+	GCPercent       int       `json:"gc_percent"`        // GOGC, -1 when the GC is off
+	MemoryLimit     int64     `json:"memory_limit"`      // GOMEMLIMIT in bytes, 0 when unlimited
+	HeapGoal        uint64    `json:"heap_goal"`         // Heap size at which the next GC cycle starts
+	HeapLive        uint64    `json:"heap_live"`         // Heap marked live by the last GC cycle
+	Cycles          uint64    `json:"cycles"`            // Completed GC cycles
+	ForcedCycles    uint64    `json:"forced_cycles"`     // GC cycles forced by the application
+	CyclesPerMinute float64   `json:"cycles_per_minute"` // Since the previous sample
+	CPUPercent      float64   `json:"cpu_percent"`       // Share of the CPU time used by the GC since the previous sample
+	LastGC          time.Time `json:"last_gc"`
+}
+
+// GCSuggestion represents a GC tuning hint derived from the GC statistics.
+type GCSuggestion struct {
+	Severity       string `json:"severity"` // info, warning or critical
+	Setting        string `json:"setting"`  // GOGC or GOMEMLIMIT
+	Message        string `json:"message"`
+	SuggestedValue string `json:"suggested_value,omitempty"`
+}
+
+// GCAuditEntry represents a change made to the garbage collector at runtime.
+type GCAuditEntry struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"` // set_gc_percent, set_memory_limit, run_gc or free_os_memory
+	Actor    string    `json:"actor"`  // Who made the change, e.g. the user and remote address
+	OldValue string    `json:"old_value"`
+	NewValue string    `json:"new_value"`
+}
+
+// GCInsights represents the GC page: current statistics, tuning hints and recent changes.
+type GCInsights struct {
+	Statistics           GCStatistics   `json:"statistics"`
+	ContainerMemoryLimit int64          `json:"container_memory_limit"` // cgroup memory limit in bytes, 0 when unknown
+	Suggestions          []GCSuggestion `json:"suggestions"`
+	Audit                []GCAuditEntry `json:"audit"`            // Newest first
+	ControlsEnabled      bool           `json:"controls_enabled"` // Whether the request may change the GC, false unless authenticated
+}
+
+// BuildInfo represents the build of the running binary and the runtime it runs on.
+type BuildInfo struct {
+	Available    bool            `json:"available"`    // False when the binary was built without module support
+	GoVersion    string          `json:"go_version"`   // Go version the binary was built with
+	Path         string          `json:"path"`         // Main package path
+	Main         ModuleVersion   `json:"main"`         // Main module
+	VCS          VCSInfo         `json:"vcs"`          // Empty when built outside a repository or with -buildvcs=false
+	Settings     []BuildSetting  `json:"settings"`     // Build flags, GOOS, GOARCH, CGO_ENABLED etc.
+	Dependencies []ModuleVersion `json:"dependencies"` // Sorted by path
+	Runtime      RuntimeInfo     `json:"runtime"`
+}
+
+// ModuleVersion represents a module linked into the binary.
+type ModuleVersion struct {
+	Path    string         `json:"path"`
+	Version string         `json:"version"`
+	Sum     string         `json:"sum,omitempty"`
+	Replace *ModuleVersion `json:"replace,omitempty"`
+}
+
+// VCSInfo represents the version control state the binary was built from.
+type VCSInfo struct {
+	System   string `json:"system,omitempty"` // e.g. git
+	Revision string `json:"revision,omitempty"`
+	Time     string `json:"time,omitempty"` // Commit time, RFC 3339
+	Modified bool   `json:"modified"`       // True when built with uncommitted changes
+}
+
+// BuildSetting represents a key/value setting recorded at build time.
+type BuildSetting struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// RuntimeInfo represents the runtime environment of the process.
+type RuntimeInfo struct {
+	GOOS        string            `json:"goos"`
+	GOARCH      string            `json:"goarch"`
+	Compiler    string            `json:"compiler"`
+	GOMAXPROCS  int               `json:"gomaxprocs"`
+	NumCPU      int               `json:"num_cpu"`
+	GODEBUG     string            `json:"godebug"`
+	Environment map[string]string `json:"environment"` // Go runtime environment variables that are set, e.g. GOGC
+}
+
+// QueryResult is the struct to store the result of a query, the values of every series at
+// the aligned timestamps
+type QueryResult struct {
+	Query      string        `json:"query"`
+	Step       int64         `json:"step_seconds"`
+	Timestamps []time.Time   `json:"timestamps"`
+	Series     []QuerySeries `json:"series"`
+}
+
+// QuerySeries is the struct to store the values of a series at the timestamps of a query
+type QuerySeries struct {
+	Labels map[string]string `json:"labels"`
+	Values []*float64        `json:"values"` // Null where the series has no value
+}
+
+// ImportResult is the struct to store the outcome of an import
+type ImportResult struct {
+	Imported int    `json:"imported"`
+	Error    string `json:"error,omitempty"` // Why the import stopped, the points before it being imported
+}
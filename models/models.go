@@ -17,13 +17,34 @@ type ServiceHealthThresholds struct {
 	MaxCPUUsage    float64 `json:"max_cpu_usage"`    // Default is 80%
 	MaxMemoryUsage float64 `json:"max_memory_usage"` // Default is 80%
 	MaxGoRoutines  int     `json:"max_go_routines"`  // Default is 1000
+	MaxFDUsage     float64 `json:"max_fd_usage"`     // Percent of RLIMIT_NOFILE, unchecked when zero
+	MaxThreads     int     `json:"max_threads"`      // Unchecked when zero
 }
 
 // FetchDataPoints is the struct to fetch the data points from the storage
 type FetchDataPoints struct {
-	FieldName []string `json:"field_name"`
-	StartTime string   `json:"start_time"` // "2006-01-02T15:04:05Z07:00"
-	EndTime   string   `json:"end_time"`   // "2006-01-02T15:04:05Z07:00"
+	FieldName   []string          `json:"field_name"`
+	StartTime   string            `json:"start_time"`            // "2006-01-02T15:04:05Z07:00"
+	EndTime     string            `json:"end_time"`              // "2006-01-02T15:04:05Z07:00"
+	Labels      map[string]string `json:"labels,omitempty"`      // Additional series labels, e.g. {"interface": "eth0"}
+	Aggregation string            `json:"aggregation,omitempty"` // Aggregation of rolled up points over long ranges: min, max, avg (default), last or count
+}
+
+// RollupTier is the struct to store a resolution the metrics are rolled up to and how long it is kept
+type RollupTier struct {
+	Resolution string `json:"resolution"` // Width of the buckets, e.g. "10m"
+	Retention  string `json:"retention"`  // Age after which buckets are removed, e.g. "30d"
+}
+
+// RemoteWriteConfig is the struct to store where and how the stored metrics are sent with the Prometheus remote write protocol
+type RemoteWriteConfig struct {
+	URL           string            `json:"url"`                      // Receiver endpoint, e.g. "http://prometheus:9090/api/v1/write"
+	Headers       map[string]string `json:"-"`                        // Added to every request, e.g. an Authorization header
+	Labels        map[string]string `json:"labels,omitempty"`         // Added to every series without them, e.g. {"job": "orders"}
+	BatchSize     int               `json:"batch_size,omitempty"`     // Rows per request, default is 2000
+	FlushInterval string            `json:"flush_interval,omitempty"` // Longest wait for a full batch, default is "30s"
+	Timeout       string            `json:"timeout,omitempty"`        // Timeout of a request, default is "10s"
+	MaxQueueSize  int64             `json:"max_queue_size,omitempty"` // Bytes of unsent batches kept on disk, the oldest dropped beyond, default is 64 MiB
 }
 
 // DataPointsInfo is the struct to store the data points information
@@ -40,6 +61,33 @@ type ReportsRequest struct {
 	TimeFrame string `json:"time_frame"`
 }
 
+// ProfileRequest is the struct to describe a profile to capture
+type ProfileRequest struct {
+	Type     string        // cpu, trace, heap, allocs, goroutine, mutex, block or threadcreate
+	Duration time.Duration // Capture time for cpu and trace; for mutex and block, how long profiling is temporarily enabled
+	Rate     int           // Mutex profile fraction or block profile rate used while temporarily enabled
+	GC       bool          // Run a garbage collection before a heap snapshot
+}
+
+// ProfileQuery is the struct to filter stored profiles
+type ProfileQuery struct {
+	Type   string
+	Source string
+	From   time.Time // Zero for no lower bound
+	To     time.Time // Zero for no upper bound
+}
+
+// GoroutineQuery filters and paginates the goroutine analysis.
+type GoroutineQuery struct {
+	View      string        // "goroutines" (default) or "groups"
+	State     string        // Goroutine state, e.g. "chan receive", matched case-insensitively
+	Search    string        // Substring of a function, file or created-by function
+	Signature string        // Only goroutines of the group with this stack signature
+	MinWait   time.Duration // Minimum time blocked; the runtime reports it in whole minutes
+	Page      int           // 1-based page number
+	PageSize  int           // Entries per page, 0 returns all
+}
+
 // SystemHealthInPercent is the struct to store the system health in percentage
 type SystemHealthInPercent struct {
 	SystemHealth  HealthFields `json:"system_health_percentage"`
@@ -51,3 +99,131 @@ type HealthFields struct {
 	AllowedByUser float64 `json:"allowed_by_user"`
 	Message       string  `json:"message"`
 }
+
+// NetworkInterfaceFilter is the struct to store which network interfaces are excluded from collection
+type NetworkInterfaceFilter struct {
+	ExcludeLoopback   bool     `json:"exclude_loopback"`
+	ExcludeVirtual    bool     `json:"exclude_virtual"`
+	ExcludeInterfaces []string `json:"exclude_interfaces"` // Interface names or glob patterns, e.g. "docker*"
+}
+
+// CollectorConfig is the struct to store how often and for how long a collector runs
+type CollectorConfig struct {
+	Disabled bool          `json:"disabled"`
+	Interval time.Duration `json:"interval"` // Zero uses the collector default, the collection interval for most collectors
+	Timeout  time.Duration `json:"timeout"`  // Zero uses the default collector timeout
+}
+
+// HealthCheckConfig is the struct to store how a health check counts towards the overall health
+type HealthCheckConfig struct {
+	Weight   float64 `json:"weight"`   // Share of the weighted average, zero uses the default of 1
+	Critical bool    `json:"critical"` // A critical result forces the overall status to critical
+}
+
+// DependencyCheckConfig is the struct to store how long a dependency check may take and how long its result is reused
+type DependencyCheckConfig struct {
+	Timeout  time.Duration `json:"timeout"`   // Zero uses the default dependency check timeout
+	CacheTTL time.Duration `json:"cache_ttl"` // Zero uses the default cache TTL
+}
+
+// GoroutineLeakConfig is the struct to store when a goroutine stack signature is reported as a suspected leak
+type GoroutineLeakConfig struct {
+	Window    time.Duration `json:"window"`     // Period over which the count must grow monotonically
+	MinGrowth int           `json:"min_growth"` // Minimum increase of the count over the window
+}
+
+// ContinuousProfilingConfig is the struct to store how often profiles are captured in the background
+type ContinuousProfilingConfig struct {
+	Interval    time.Duration `json:"interval"`     // Time between captures
+	CPUDuration time.Duration `json:"cpu_duration"` // Length of each CPU profile
+	Retention   time.Duration `json:"retention"`    // Age after which stored profiles are removed
+}
+
+// AlertRule is the struct to store a threshold rule evaluated against the stored metrics
+type AlertRule struct {
+	Name        string            `json:"name" yaml:"name"`
+	Metric      string            `json:"metric" yaml:"metric"`                               // Stored metric, e.g. "goroutines"
+	Match       map[string]string `json:"match,omitempty" yaml:"match,omitempty"`             // Labels of the series besides host, e.g. {"collector": "custom"}
+	Op          string            `json:"op" yaml:"op"`                                       // >, >=, <, <=, == or !=
+	Threshold   float64           `json:"threshold" yaml:"threshold"`                         // Value the metric is compared with
+	For         string            `json:"for,omitempty" yaml:"for,omitempty"`                 // How long the condition must hold before firing, e.g. "5m"
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`           // Attached to the alert, e.g. {"severity": "critical"}
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"` // Shown with the alert
+}
+
+// AlertingConfig is the struct to store how often the alert rules are evaluated
+type AlertingConfig struct {
+	Interval time.Duration `json:"interval"` // Time between two evaluations
+	Lookback time.Duration `json:"lookback"` // Age of the newest data point a rule still evaluates
+
+	GroupBy        []string      `json:"group_by,omitempty"` // Alert labels grouping alerts into one notification, by rule when empty
+	RepeatInterval time.Duration `json:"repeat_interval"`    // Time before a still firing group is notified again
+	MaxRetries     int           `json:"max_retries"`        // Retries of a failed notification
+	RetryBackoff   time.Duration `json:"retry_backoff"`      // Wait before the first retry, doubled for each further one
+	NotifyTimeout  time.Duration `json:"notify_timeout"`     // Timeout of a single delivery attempt
+}
+
+// AnomalyMetric is a stored series the anomaly detector learns a baseline for
+type AnomalyMetric struct {
+	Name   string            `json:"name"`            // Identifies the series in events, default is Metric
+	Metric string            `json:"metric"`          // Stored metric, e.g. "goroutines"
+	Match  map[string]string `json:"match,omitempty"` // Labels of the series besides host, e.g. {"collector": "custom", "quantile": "0.99"}
+}
+
+// AnomalyConfig is the struct to store how the anomaly detector learns baselines and scores values
+type AnomalyConfig struct {
+	Interval   time.Duration `json:"interval"`    // Time between two detections
+	Window     time.Duration `json:"window"`      // History the baselines are learned from
+	Alpha      float64       `json:"alpha"`       // EWMA smoothing factor, higher adapts faster
+	Threshold  float64       `json:"threshold"`   // Absolute z-score from which a value is anomalous
+	MinSamples int           `json:"min_samples"` // Samples a baseline needs before values are scored
+	Retention  time.Duration `json:"retention"`   // How long events are kept, 0 keeps them forever
+}
+
+// SLO is the struct to store a service level objective over a rolling window, counting good and
+// total events from custom metrics or traced function calls
+type SLO struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Type        string            `json:"type"`                   // "availability" or "latency"
+	Objective   float64           `json:"objective"`              // Target percentage of good events, e.g. 99.9
+	Window      string            `json:"window,omitempty"`       // Rolling window, default is "30d"
+	Metric      string            `json:"metric,omitempty"`       // Custom counter of requests, or histogram of durations for latency
+	Match       map[string]string `json:"match,omitempty"`        // Labels of the counted series, values are regular expressions
+	ErrorMetric string            `json:"error_metric,omitempty"` // Custom counter of failed requests, default is Metric
+	ErrorMatch  map[string]string `json:"error_match,omitempty"`  // Labels of the failed series, e.g. {"code": "5.."}
+	Function    string            `json:"function,omitempty"`     // Traced function counted instead of a metric, e.g. "main.checkout"
+	Threshold   float64           `json:"threshold,omitempty"`    // Seconds a good event takes at most, for latency
+}
+
+// SLOConfig is the struct to store how often the SLOs are evaluated
+type SLOConfig struct {
+	Interval time.Duration `json:"interval"` // Time between two evaluations, also the resolution of the burn rates
+}
+
+// HealthHistoryConfig is the struct to store how health level transitions are recorded
+type HealthHistoryConfig struct {
+	Hysteresis float64       `json:"hysteresis"` // Percentage points the health must move past a level boundary to change level
+	Retention  time.Duration `json:"retention"`  // Age after which events are removed
+}
+
+// HealthEventQuery filters the recorded health level transitions.
+type HealthEventQuery struct {
+	Scope string    // "service" or "system", empty for both
+	From  time.Time // Zero for no lower bound
+	To    time.Time // Zero for no upper bound
+}
+
+// AnomalyEventQuery filters the recorded anomaly events.
+type AnomalyEventQuery struct {
+	Metric string    // Name of the metric, empty for all
+	From   time.Time // Zero for no lower bound
+	To     time.Time // Zero for no upper bound
+}
+
+// IncidentConfig is the struct to store how incidents are captured when a health threshold is crossed
+type IncidentConfig struct {
+	Cooldown    time.Duration `json:"cooldown"`     // Minimum time between two incidents
+	CPUDuration time.Duration `json:"cpu_duration"` // Length of the CPU profile captured for an incident
+	Retention   time.Duration `json:"retention"`    // Age after which incidents are removed
+}
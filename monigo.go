@@ -11,18 +11,24 @@ import (
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/iyashjayesh/monigo/alerting"
+	"github.com/iyashjayesh/monigo/anomaly"
 	"github.com/iyashjayesh/monigo/api"
 	"github.com/iyashjayesh/monigo/common"
 	"github.com/iyashjayesh/monigo/core"
 	"github.com/iyashjayesh/monigo/exporters"
 	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/internal/pipeline"
+	"github.com/iyashjayesh/monigo/internal/registry"
 	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/slo"
 	"github.com/iyashjayesh/monigo/timeseries"
 )
 
@@ -64,10 +70,73 @@ type Monigo struct {
 	MaxCPUUsage             float64   `json:"max_cpu_usage"`
 	MaxMemoryUsage          float64   `json:"max_memory_usage"`
 	MaxGoRoutines           int       `json:"max_go_routines"`
+	MaxFDUsage              float64   `json:"max_fd_usage"`
+	MaxThreads              int       `json:"max_threads"`
+	CollectionInterval      string    `json:"collection_interval"`
 	CustomBaseAPIPath       string    `json:"custom_base_api_path"`
 	Headless                bool      `json:"headless"`
 	SamplingRate            int       `json:"sampling_rate"`
-	StorageType             string    `json:"storage_type"`
+	StorageType             string    `json:"storage_type"`      // "disk", "memory" or "bolt", default is "disk"
+	MaxMemoryPoints         int       `json:"max_memory_points"` // Points the "memory" storage keeps at most, default is 1000000
+
+	// Rollups
+	RollupTiers    []RollupTier `json:"rollup_tiers,omitempty"` // Resolutions the metrics are rolled up to, default is 1m, 10m and 1h
+	DisableRollups bool         `json:"disable_rollups"`
+
+	// Remote write
+	RemoteWrite *RemoteWriteConfig `json:"remote_write,omitempty"` // Receiver the stored metrics are also sent to
+
+	// Network Interface Filtering
+	ExcludeLoopbackInterfaces bool     `json:"exclude_loopback_interfaces"`
+	ExcludeVirtualInterfaces  bool     `json:"exclude_virtual_interfaces"`
+	ExcludedInterfaces        []string `json:"excluded_interfaces,omitempty"`
+
+	// Collector Configuration
+	DisabledCollectors []string          `json:"disabled_collectors,omitempty"`
+	CollectorIntervals map[string]string `json:"collector_intervals,omitempty"` // Collector name to interval, e.g. "disk": "1m"
+	CollectorTimeouts  map[string]string `json:"collector_timeouts,omitempty"`  // Collector name to timeout, e.g. "network": "2s"
+	Collectors         []Collector       `json:"-"`
+
+	// Health Checks
+	HealthChecks         []HealthCheck      `json:"-"`
+	HealthCheckWeights   map[string]float64 `json:"health_check_weights,omitempty"`   // Check name to weight, default is 1
+	CriticalHealthChecks []string           `json:"critical_health_checks,omitempty"` // Checks forcing the status to critical when they fail
+
+	// Readiness Probe
+	DependencyChecks []DependencyCheck `json:"-"`
+
+	// Goroutine Leak Detection
+	GoroutineLeakWindow    string `json:"goroutine_leak_window"`     // Default is "30m"
+	GoroutineLeakMinGrowth int    `json:"goroutine_leak_min_growth"` // Default is 10
+
+	// Continuous Profiling
+	ContinuousProfilingInterval    string `json:"continuous_profiling_interval,omitempty"`     // Empty disables it, e.g. "10m"
+	ContinuousProfilingCPUDuration string `json:"continuous_profiling_cpu_duration,omitempty"` // Default is "10s"
+
+	// Health History
+	HealthHysteresis float64 `json:"health_hysteresis,omitempty"` // Percentage points past a level boundary before the level changes, default is 5
+
+	// Alerting
+	AlertRules              []AlertRule `json:"alert_rules,omitempty"`
+	AlertRulesFile          string      `json:"alert_rules_file,omitempty"`          // JSON or YAML file with more rules
+	AlertEvaluationInterval string      `json:"alert_evaluation_interval,omitempty"` // Default is "30s"
+	Notifiers               []Notifier  `json:"-"`
+	AlertGroupBy            []string    `json:"alert_group_by,omitempty"`        // Alert labels grouping notifications, by rule when empty
+	AlertRepeatInterval     string      `json:"alert_repeat_interval,omitempty"` // Default is "4h"
+
+	// Anomaly Detection
+	AnomalyDetection bool            `json:"anomaly_detection"`
+	AnomalyMetrics   []AnomalyMetric `json:"anomaly_metrics,omitempty"`   // Series watched besides the core metrics, e.g. request latency
+	AnomalyThreshold float64         `json:"anomaly_threshold,omitempty"` // Absolute z-score from which a value is anomalous, default is 3
+	AnomalyWindow    string          `json:"anomaly_window,omitempty"`    // History the baselines are learned from, default is "168h"
+
+	// Service Level Objectives
+	SLOs []SLO `json:"slos,omitempty"`
+
+	// Incident Capture
+	IncidentCapture            bool   `json:"incident_capture"`
+	IncidentCooldown           string `json:"incident_cooldown,omitempty"`             // Default is "15m"
+	IncidentCPUProfileDuration string `json:"incident_cpu_profile_duration,omitempty"` // Default is "10s"
 
 	// OpenTelemetry Configuration
 	OTelEndpoint string            `json:"otel_endpoint,omitempty"`
@@ -80,8 +149,73 @@ type Monigo struct {
 
 	// Holds a reference so we can shut down cleanly.
 	otelExporter *exporters.OTelExporter
+	otelPipeline *pipeline.Pipeline
 }
 
+// Collector is a custom source of metrics sampled alongside the built-in statistics
+type Collector = core.Collector
+
+// HealthCheck is a custom check scoring part of the service's health
+type HealthCheck = core.HealthCheck
+
+// DependencyCheckFunc reports whether a dependency of the service is reachable, e.g. a database's PingContext
+type DependencyCheckFunc = core.DependencyCheckFunc
+
+// DependencyCheck is a dependency which must be reachable for the service to be ready
+type DependencyCheck struct {
+	Name     string
+	Check    DependencyCheckFunc
+	Timeout  string // Default is "2s"
+	CacheTTL string // How long the result is reused, default is "5s"
+}
+
+// HTTPDependencyCheck returns a dependency check requesting url, failing on errors and 4xx or 5xx responses
+func HTTPDependencyCheck(url string) DependencyCheckFunc {
+	return core.HTTPDependencyCheck(url)
+}
+
+// AlertRule fires an alert when a stored metric crosses a threshold for a duration
+type AlertRule = models.AlertRule
+
+// Notifier delivers alert notifications, see NewWebhookNotifier, NewSlackNotifier and NewSMTPNotifier
+type Notifier = alerting.Notifier
+
+// WebhookConfig configures a notifier posting alert notifications as JSON
+type WebhookConfig = alerting.WebhookConfig
+
+// SlackConfig configures a notifier posting alert notifications to a Slack or Mattermost incoming webhook
+type SlackConfig = alerting.SlackConfig
+
+// SMTPConfig configures a notifier sending alert notifications by email
+type SMTPConfig = alerting.SMTPConfig
+
+// NewWebhookNotifier returns a notifier posting alert notifications as JSON to a webhook
+func NewWebhookNotifier(config WebhookConfig) Notifier {
+	return alerting.NewWebhookNotifier(config)
+}
+
+// NewSlackNotifier returns a notifier posting alert notifications to a Slack or Mattermost incoming webhook
+func NewSlackNotifier(config SlackConfig) Notifier {
+	return alerting.NewSlackNotifier(config)
+}
+
+// NewSMTPNotifier returns a notifier sending alert notifications by email
+func NewSMTPNotifier(config SMTPConfig) Notifier {
+	return alerting.NewSMTPNotifier(config)
+}
+
+// AnomalyMetric is a stored series the anomaly detector learns a baseline for, e.g. the p99 of a custom latency histogram
+type AnomalyMetric = models.AnomalyMetric
+
+// RollupTier is a resolution the metrics are rolled up to, kept for its own retention period
+type RollupTier = models.RollupTier
+
+// RemoteWriteConfig configures a Prometheus remote write receiver the stored metrics are also sent to
+type RemoteWriteConfig = models.RemoteWriteConfig
+
+// SLO is a service level objective over a rolling window, counting good and total events from custom metrics or traced function calls
+type SLO = models.SLO
+
 // MonigoInt is the interface to start the monigo service
 type MonigoInt interface {
 	Start() error
@@ -148,19 +282,251 @@ func (m *Monigo) initCommon() {
 
 	m.DataPointsSyncFrequency = common.DefaultIfEmpty(m.DataPointsSyncFrequency, "5m")
 	m.DataRetentionPeriod = common.DefaultIfEmpty(m.DataRetentionPeriod, "7d")
+	m.CollectionInterval = common.DefaultIfEmpty(m.CollectionInterval, "15s")
 	m.MaxCPUUsage = common.DefaultFloatIfZero(m.MaxCPUUsage, 95)
 	m.MaxMemoryUsage = common.DefaultFloatIfZero(m.MaxMemoryUsage, 95)
 	m.MaxGoRoutines = common.DefaultIntIfZero(m.MaxGoRoutines, 100)
+	m.GoroutineLeakWindow = common.DefaultIfEmpty(m.GoroutineLeakWindow, "30m")
+	m.GoroutineLeakMinGrowth = common.DefaultIntIfZero(m.GoroutineLeakMinGrowth, 10)
 
 	core.ConfigureServiceThresholds(&models.ServiceHealthThresholds{
 		MaxCPUUsage:    m.MaxCPUUsage,
 		MaxMemoryUsage: m.MaxMemoryUsage,
 		MaxGoRoutines:  m.MaxGoRoutines,
+		MaxFDUsage:     m.MaxFDUsage,
+		MaxThreads:     m.MaxThreads,
+	})
+	core.ConfigureNetworkInterfaces(&models.NetworkInterfaceFilter{
+		ExcludeLoopback:   m.ExcludeLoopbackInterfaces,
+		ExcludeVirtual:    m.ExcludeVirtualInterfaces,
+		ExcludeInterfaces: m.ExcludedInterfaces,
+	})
+
+	leakWindow, err := time.ParseDuration(m.GoroutineLeakWindow)
+	if err != nil || leakWindow <= 0 {
+		logger.Log.Warn("invalid goroutine leak window, using default", "window", m.GoroutineLeakWindow, "default", "30m")
+		leakWindow = 30 * time.Minute
+	}
+	core.ConfigureGoroutineLeakDetection(&models.GoroutineLeakConfig{
+		Window:    leakWindow,
+		MinGrowth: m.GoroutineLeakMinGrowth,
 	})
 
 	m.ServiceStartTime = time.Now().In(location)
 }
 
+// configureCollectors registers the custom collectors and applies the per-collector configuration.
+func (m *Monigo) configureCollectors() {
+	for _, c := range m.Collectors {
+		if err := core.RegisterCollector(c, models.CollectorConfig{}); err != nil {
+			logger.Log.Warn("failed to register collector", "error", err)
+		}
+	}
+
+	parseDuration := func(name, kind string, values map[string]string) time.Duration {
+		value, ok := values[name]
+		if !ok {
+			return 0
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			logger.Log.Warn("invalid collector "+kind+", using default", "collector", name, kind, value)
+			return 0
+		}
+		return d
+	}
+
+	for _, status := range core.GetCollectorStatuses() {
+		name := status.Name
+		config := models.CollectorConfig{
+			Disabled: slices.Contains(m.DisabledCollectors, name),
+			Interval: parseDuration(name, "interval", m.CollectorIntervals),
+			Timeout:  parseDuration(name, "timeout", m.CollectorTimeouts),
+		}
+		if err := core.ConfigureCollector(name, config); err != nil {
+			logger.Log.Warn("failed to configure collector", "collector", name, "error", err)
+		}
+	}
+}
+
+// configureHealthChecks registers the custom health checks and applies the weights and critical checks.
+func (m *Monigo) configureHealthChecks() {
+	for _, c := range m.HealthChecks {
+		if err := core.RegisterHealthCheck(c, models.HealthCheckConfig{}); err != nil {
+			logger.Log.Warn("failed to register health check", "error", err)
+		}
+	}
+
+	names := make(map[string]bool)
+	for name := range m.HealthCheckWeights {
+		names[name] = true
+	}
+	for _, name := range m.CriticalHealthChecks {
+		names[name] = true
+	}
+	for name := range names {
+		config := models.HealthCheckConfig{
+			Weight:   m.HealthCheckWeights[name],
+			Critical: slices.Contains(m.CriticalHealthChecks, name),
+		}
+		if err := core.ConfigureHealthCheck(name, config); err != nil {
+			logger.Log.Warn("failed to configure health check", "check", name, "error", err)
+		}
+	}
+}
+
+// configureDependencyChecks registers the dependency checks of the readiness probe.
+func (m *Monigo) configureDependencyChecks() {
+	for _, d := range m.DependencyChecks {
+		config := models.DependencyCheckConfig{}
+		if d.Timeout != "" {
+			config.Timeout, _ = time.ParseDuration(d.Timeout)
+		}
+		if d.CacheTTL != "" {
+			config.CacheTTL, _ = time.ParseDuration(d.CacheTTL)
+		}
+		if err := core.RegisterDependencyCheck(d.Name, d.Check, config); err != nil {
+			logger.Log.Warn("failed to register dependency check", "check", d.Name, "error", err)
+		}
+	}
+}
+
+// startContinuousProfiler starts the background profiler when an interval is configured,
+// keeping its profiles as long as the metrics.
+func (m *Monigo) startContinuousProfiler() {
+	if m.ContinuousProfilingInterval == "" {
+		return
+	}
+	interval, err := time.ParseDuration(m.ContinuousProfilingInterval)
+	if err != nil || interval <= 0 {
+		logger.Log.Warn("invalid continuous profiling interval, profiler disabled", "interval", m.ContinuousProfilingInterval)
+		return
+	}
+	cpuDuration, err := time.ParseDuration(common.DefaultIfEmpty(m.ContinuousProfilingCPUDuration, "10s"))
+	if err != nil || cpuDuration <= 0 {
+		logger.Log.Warn("invalid continuous CPU profile duration, using default", "duration", m.ContinuousProfilingCPUDuration, "default", core.DefaultContinuousCPUDuration)
+		cpuDuration = core.DefaultContinuousCPUDuration
+	}
+
+	core.StartContinuousProfiler(&models.ContinuousProfilingConfig{
+		Interval:    interval,
+		CPUDuration: cpuDuration,
+		Retention:   common.GetDataRetentionPeriod(),
+	})
+}
+
+// configureHealthHistory records the health level transitions, keeping them as long as the metrics.
+func (m *Monigo) configureHealthHistory() {
+	core.ConfigureHealthHistory(&models.HealthHistoryConfig{
+		Hysteresis: m.HealthHysteresis,
+		Retention:  common.GetDataRetentionPeriod(),
+	})
+}
+
+// configureIncidentCapture enables capturing profiles when a health threshold is crossed,
+// keeping the incidents as long as the metrics.
+func (m *Monigo) configureIncidentCapture() {
+	if !m.IncidentCapture {
+		return
+	}
+	cooldown, err := time.ParseDuration(common.DefaultIfEmpty(m.IncidentCooldown, "15m"))
+	if err != nil || cooldown <= 0 {
+		logger.Log.Warn("invalid incident cooldown, using default", "cooldown", m.IncidentCooldown, "default", core.DefaultIncidentCooldown)
+		cooldown = core.DefaultIncidentCooldown
+	}
+	cpuDuration, err := time.ParseDuration(common.DefaultIfEmpty(m.IncidentCPUProfileDuration, "10s"))
+	if err != nil || cpuDuration <= 0 {
+		logger.Log.Warn("invalid incident CPU profile duration, using default", "duration", m.IncidentCPUProfileDuration, "default", core.DefaultIncidentCPUDuration)
+		cpuDuration = core.DefaultIncidentCPUDuration
+	}
+
+	core.ConfigureIncidentCapture(&models.IncidentConfig{
+		Cooldown:    cooldown,
+		CPUDuration: cpuDuration,
+		Retention:   common.GetDataRetentionPeriod(),
+	})
+}
+
+// startAlerting evaluates the alert rules configured in code and in the rules file, notifying
+// the notifiers. Stored metrics are written every sync, so the newest point of a series is up
+// to a sync old.
+func (m *Monigo) startAlerting() {
+	rules := append([]AlertRule{}, m.AlertRules...)
+	if m.AlertRulesFile != "" {
+		fileRules, err := alerting.LoadRulesFile(m.AlertRulesFile)
+		if err != nil {
+			logger.Log.Error("failed to load alert rules, alerting disabled", "error", err)
+			return
+		}
+		rules = append(rules, fileRules...)
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	interval, err := time.ParseDuration(common.DefaultIfEmpty(m.AlertEvaluationInterval, "30s"))
+	if err != nil || interval <= 0 {
+		logger.Log.Warn("invalid alert evaluation interval, using default", "interval", m.AlertEvaluationInterval, "default", alerting.DefaultEvaluationInterval)
+		interval = alerting.DefaultEvaluationInterval
+	}
+	repeatInterval, err := time.ParseDuration(common.DefaultIfEmpty(m.AlertRepeatInterval, "4h"))
+	if err != nil || repeatInterval <= 0 {
+		logger.Log.Warn("invalid alert repeat interval, using default", "interval", m.AlertRepeatInterval, "default", alerting.DefaultRepeatInterval)
+		repeatInterval = alerting.DefaultRepeatInterval
+	}
+	syncFrequency, err := time.ParseDuration(common.DefaultIfEmpty(m.DataPointsSyncFrequency, "5m"))
+	if err != nil || syncFrequency <= 0 {
+		syncFrequency = 5 * time.Minute
+	}
+
+	if err := alerting.Start(rules, m.Notifiers, models.AlertingConfig{
+		Interval:       interval,
+		Lookback:       max(2*syncFrequency, interval),
+		GroupBy:        m.AlertGroupBy,
+		RepeatInterval: repeatInterval,
+	}); err != nil {
+		logger.Log.Error("failed to start alerting", "error", err)
+	}
+}
+
+// startAnomalyDetection scores the newest stored value of the core metrics and the configured
+// series against their baselines after every sync, keeping the events as long as the metrics.
+func (m *Monigo) startAnomalyDetection() {
+	if !m.AnomalyDetection {
+		return
+	}
+
+	window, err := time.ParseDuration(common.DefaultIfEmpty(m.AnomalyWindow, "168h"))
+	if err != nil || window <= 0 {
+		logger.Log.Warn("invalid anomaly window, using default", "window", m.AnomalyWindow, "default", anomaly.DefaultWindow)
+		window = anomaly.DefaultWindow
+	}
+	syncFrequency, err := time.ParseDuration(common.DefaultIfEmpty(m.DataPointsSyncFrequency, "5m"))
+	if err != nil || syncFrequency <= 0 {
+		syncFrequency = 5 * time.Minute
+	}
+
+	metrics := append(append([]AnomalyMetric{}, anomaly.DefaultMetrics...), m.AnomalyMetrics...)
+	if err := anomaly.Start(metrics, models.AnomalyConfig{
+		Interval:  syncFrequency,
+		Window:    window,
+		Threshold: m.AnomalyThreshold,
+		Retention: common.GetDataRetentionPeriod(),
+	}); err != nil {
+		logger.Log.Error("failed to start anomaly detection", "error", err)
+	}
+}
+
+// startSLOs evaluates the SLOs every minute, storing their attainment, error budget and burn rates.
+func (m *Monigo) startSLOs() {
+	if len(m.SLOs) == 0 {
+		return
+	}
+	if err := slo.Start(m.SLOs, models.SLOConfig{Interval: slo.DefaultInterval}); err != nil {
+		logger.Log.Error("failed to start SLO tracking", "error", err)
+	}
+}
+
 // MonigoInstanceConstructor validates the port then initialises common fields.
 func (m *Monigo) MonigoInstanceConstructor() error {
 	if err := setDashboardPort(m); err != nil {
@@ -181,9 +547,15 @@ func (m *Monigo) setup() error {
 		return fmt.Errorf("[MoniGo] service_name is required, please provide the service name")
 	}
 
-	if err := timeseries.SetDataPointsSyncFrequency(m.DataPointsSyncFrequency); err != nil {
-		return fmt.Errorf("[MoniGo] failed to set data points sync frequency: %v", err)
+	collectionInterval, err := time.ParseDuration(m.CollectionInterval)
+	if err != nil {
+		logger.Log.Warn("invalid collection interval, using default", "interval", m.CollectionInterval, "default", core.DefaultCollectionInterval, "error", err)
+		collectionInterval = core.DefaultCollectionInterval
 	}
+	m.configureCollectors()
+	m.configureHealthChecks()
+	m.configureDependencyChecks()
+	core.StartSampler(collectionInterval)
 
 	m.ProcessId = common.GetProcessId()
 	m.GoVersion = runtime.Version()
@@ -216,15 +588,39 @@ func (m *Monigo) setup() error {
 	if m.StorageType != "" {
 		timeseries.SetStorageType(m.StorageType)
 	}
+	if m.MaxMemoryPoints > 0 {
+		timeseries.SetMaxMemoryPoints(m.MaxMemoryPoints)
+	}
+	if m.DisableRollups {
+		timeseries.SetRollupTiers(nil)
+	} else if len(m.RollupTiers) > 0 {
+		timeseries.SetRollupTiers(m.RollupTiers)
+	}
+	if m.RemoteWrite != nil {
+		timeseries.SetRemoteWrite(m.RemoteWrite)
+	}
+
+	// Opens the storage, so the retention and the storage settings above must be set first
+	if err := timeseries.SetDataPointsSyncFrequency(m.DataPointsSyncFrequency); err != nil {
+		return fmt.Errorf("[MoniGo] failed to set data points sync frequency: %v", err)
+	}
+
+	m.startContinuousProfiler()
+	m.configureIncidentCapture()
+	m.configureHealthHistory()
+
 	if m.SamplingRate > 0 {
 		core.SetSamplingRate(m.SamplingRate)
 	}
 
-	_, err := timeseries.GetStorageInstance()
+	_, err = timeseries.GetStorageInstance()
 	if err != nil {
 		logger.Log.Error("failed to initialize storage", "error", err)
 		return fmt.Errorf("failed to initialize storage: %w", err)
 	}
+	m.startAlerting()
+	m.startAnomalyDetection()
+	m.startSLOs()
 
 	if m.OTelEndpoint != "" {
 		otelExp, otelErr := exporters.NewOTelExporter(context.Background(), exporters.OTelConfig{
@@ -236,6 +632,9 @@ func (m *Monigo) setup() error {
 			logger.Log.Error("failed to initialize OTel exporter", "error", otelErr)
 		} else {
 			m.otelExporter = otelExp
+			// Pushing custom application metrics to the collector
+			m.otelPipeline = pipeline.NewPipeline(registry.Default(), otelExp, collectionInterval)
+			m.otelPipeline.Start(context.Background())
 			logger.Log.Info("OTel exporter initialized", "endpoint", m.OTelEndpoint)
 		}
 	}
@@ -246,6 +645,16 @@ func (m *Monigo) setup() error {
 // Shutdown performs a graceful cleanup of resources (OTel provider, storage, etc.).
 func (m *Monigo) Shutdown(ctx context.Context) error {
 	var errs []error
+	core.StopSampler()
+	core.StopContinuousProfiler()
+	core.StopIncidentCapture()
+	core.StopHealthHistory()
+	alerting.Stop()
+	anomaly.Stop()
+	slo.Stop()
+	if m.otelPipeline != nil {
+		m.otelPipeline.Stop()
+	}
 	if m.otelExporter != nil {
 		if err := m.otelExporter.Shutdown(ctx); err != nil {
 			errs = append(errs, fmt.Errorf("otel shutdown: %w", err))
@@ -408,12 +817,41 @@ func (m *Monigo) registerShutdownHandler(srv *http.Server) {
 func registerAPIEndpoints(mux *http.ServeMux, apiPath string) {
 	mux.HandleFunc(fmt.Sprintf("%s/metrics", apiPath), api.GetServiceStatistics)
 	mux.HandleFunc(fmt.Sprintf("%s/service-info", apiPath), api.GetServiceInfoAPI)
+	mux.HandleFunc(fmt.Sprintf("%s/build-info", apiPath), api.GetBuildInfo)
 	mux.HandleFunc(fmt.Sprintf("%s/service-metrics", apiPath), api.GetServiceMetricsFromStorage)
 	mux.HandleFunc(fmt.Sprintf("%s/go-routines-stats", apiPath), api.GetGoRoutinesStats)
 	mux.HandleFunc(fmt.Sprintf("%s/function", apiPath), api.GetFunctionTraceDetails)
 	mux.HandleFunc(fmt.Sprintf("%s/function-details", apiPath), api.ViewFunctionMetrics)
 	mux.HandleFunc("/metrics", api.PrometheusMetricsHandler)
 	mux.HandleFunc(fmt.Sprintf("%s/reports", apiPath), api.GetReportData)
+	mux.HandleFunc(fmt.Sprintf("%s/collectors", apiPath), api.GetCollectors)
+	mux.HandleFunc(fmt.Sprintf("%s/custom-metrics", apiPath), api.GetCustomMetrics)
+	mux.HandleFunc(fmt.Sprintf("%s/profiles", apiPath), api.GetProfiles)
+	mux.HandleFunc(fmt.Sprintf("%s/profiles/capture", apiPath), api.CaptureProfile)
+	mux.HandleFunc(fmt.Sprintf("%s/profiles/download", apiPath), api.DownloadProfile)
+	mux.HandleFunc(fmt.Sprintf("%s/profiles/view", apiPath), api.ViewProfile)
+	mux.HandleFunc(fmt.Sprintf("%s/profiles/delete", apiPath), api.DeleteProfile)
+	mux.HandleFunc(fmt.Sprintf("%s/profiles/timeline", apiPath), api.GetProfileTimeline)
+	mux.HandleFunc(fmt.Sprintf("%s/incidents", apiPath), api.GetIncidents)
+	mux.HandleFunc(fmt.Sprintf("%s/incidents/delete", apiPath), api.DeleteIncident)
+	mux.HandleFunc(fmt.Sprintf("%s/health/events", apiPath), api.GetHealthEvents)
+	mux.HandleFunc(fmt.Sprintf("%s/alerts", apiPath), api.GetAlerts)
+	mux.HandleFunc(fmt.Sprintf("%s/alerts/rules", apiPath), api.GetAlertRules)
+	mux.HandleFunc(fmt.Sprintf("%s/anomalies", apiPath), api.GetAnomalies)
+	mux.HandleFunc(fmt.Sprintf("%s/anomalies/status", apiPath), api.GetAnomalyStatus)
+	mux.HandleFunc(fmt.Sprintf("%s/slos", apiPath), api.GetSLOs)
+	mux.HandleFunc(fmt.Sprintf("%s/query", apiPath), api.Query)
+	mux.HandleFunc(fmt.Sprintf("%s/export", apiPath), api.Export)
+	mux.HandleFunc(fmt.Sprintf("%s/import", apiPath), api.Import)
+	mux.HandleFunc(fmt.Sprintf("%s/gc", apiPath), api.GetGCInsights)
+	mux.HandleFunc(fmt.Sprintf("%s/gc/percent", apiPath), api.SetGCPercent)
+	mux.HandleFunc(fmt.Sprintf("%s/gc/memory-limit", apiPath), api.SetMemoryLimit)
+	mux.HandleFunc(fmt.Sprintf("%s/gc/run", apiPath), api.RunGC)
+	mux.HandleFunc(fmt.Sprintf("%s/gc/free-os-memory", apiPath), api.FreeOSMemory)
+	mux.HandleFunc(fmt.Sprintf("%s/healthz", apiPath), api.Liveness)
+	mux.HandleFunc(fmt.Sprintf("%s/readyz", apiPath), api.Readiness)
+	mux.HandleFunc("/healthz", api.Liveness)
+	mux.HandleFunc("/readyz", api.Readiness)
 }
 
 // RegisterDashboardHandlers registers all dashboard handlers to the provided HTTP mux
@@ -466,12 +904,41 @@ func GetAPIHandlers(customBaseAPIPath ...string) map[string]http.HandlerFunc {
 	return map[string]http.HandlerFunc{
 		fmt.Sprintf("%s/metrics", apiPath):           api.GetServiceStatistics,
 		fmt.Sprintf("%s/service-info", apiPath):      api.GetServiceInfoAPI,
+		fmt.Sprintf("%s/build-info", apiPath):        api.GetBuildInfo,
 		fmt.Sprintf("%s/service-metrics", apiPath):   api.GetServiceMetricsFromStorage,
 		fmt.Sprintf("%s/go-routines-stats", apiPath): api.GetGoRoutinesStats,
 		fmt.Sprintf("%s/function", apiPath):          api.GetFunctionTraceDetails,
 		fmt.Sprintf("%s/function-details", apiPath):  api.ViewFunctionMetrics,
 		"/metrics":                                   api.PrometheusMetricsHandler,
 		fmt.Sprintf("%s/reports", apiPath):           api.GetReportData,
+		fmt.Sprintf("%s/collectors", apiPath):        api.GetCollectors,
+		fmt.Sprintf("%s/custom-metrics", apiPath):    api.GetCustomMetrics,
+		fmt.Sprintf("%s/profiles", apiPath):          api.GetProfiles,
+		fmt.Sprintf("%s/profiles/capture", apiPath):  api.CaptureProfile,
+		fmt.Sprintf("%s/profiles/download", apiPath): api.DownloadProfile,
+		fmt.Sprintf("%s/profiles/view", apiPath):     api.ViewProfile,
+		fmt.Sprintf("%s/profiles/delete", apiPath):   api.DeleteProfile,
+		fmt.Sprintf("%s/profiles/timeline", apiPath): api.GetProfileTimeline,
+		fmt.Sprintf("%s/incidents", apiPath):         api.GetIncidents,
+		fmt.Sprintf("%s/incidents/delete", apiPath):  api.DeleteIncident,
+		fmt.Sprintf("%s/health/events", apiPath):     api.GetHealthEvents,
+		fmt.Sprintf("%s/alerts", apiPath):            api.GetAlerts,
+		fmt.Sprintf("%s/alerts/rules", apiPath):      api.GetAlertRules,
+		fmt.Sprintf("%s/anomalies", apiPath):         api.GetAnomalies,
+		fmt.Sprintf("%s/anomalies/status", apiPath):  api.GetAnomalyStatus,
+		fmt.Sprintf("%s/slos", apiPath):              api.GetSLOs,
+		fmt.Sprintf("%s/query", apiPath):             api.Query,
+		fmt.Sprintf("%s/export", apiPath):            api.Export,
+		fmt.Sprintf("%s/import", apiPath):            api.Import,
+		fmt.Sprintf("%s/gc", apiPath):                api.GetGCInsights,
+		fmt.Sprintf("%s/gc/percent", apiPath):        api.SetGCPercent,
+		fmt.Sprintf("%s/gc/memory-limit", apiPath):   api.SetMemoryLimit,
+		fmt.Sprintf("%s/gc/run", apiPath):            api.RunGC,
+		fmt.Sprintf("%s/gc/free-os-memory", apiPath): api.FreeOSMemory,
+		fmt.Sprintf("%s/healthz", apiPath):           api.Liveness,
+		fmt.Sprintf("%s/readyz", apiPath):            api.Readiness,
+		"/healthz":                                   api.Liveness,
+		"/readyz":                                    api.Readiness,
 	}
 }
 
@@ -492,6 +959,9 @@ func GetUnifiedHandler(customBaseAPIPath ...string) http.HandlerFunc {
 			routeToAPIHandler(w, r, apiPath)
 			return
 		}
+		if routeToProbeHandler(w, r) {
+			return
+		}
 		serveHtmlSite(w, r)
 	}
 }
@@ -508,6 +978,12 @@ func GetFiberHandler(customBaseAPIPath ...string) func(*fiber.Ctx) error {
 		if strings.HasPrefix(path, apiPath) {
 			return routeToFiberAPIHandler(c, path, apiPath)
 		}
+		switch path {
+		case "/healthz":
+			return handleFiberAPI(c, api.Liveness)
+		case "/readyz":
+			return handleFiberAPI(c, api.Readiness)
+		}
 		return serveFiberStaticFiles(c, path)
 	}
 }
@@ -524,6 +1000,9 @@ func GetSecuredUnifiedHandler(m *Monigo, customBaseAPIPath ...string) http.Handl
 			routeToAPIHandler(w, r, apiPath)
 			return
 		}
+		if routeToProbeHandler(w, r) {
+			return
+		}
 		serveHtmlSite(w, r)
 	}
 
@@ -540,12 +1019,41 @@ func GetSecuredAPIHandlers(m *Monigo, customBaseAPIPath ...string) map[string]ht
 	baseHandlers := map[string]http.HandlerFunc{
 		fmt.Sprintf("%s/metrics", apiPath):           api.GetServiceStatistics,
 		fmt.Sprintf("%s/service-info", apiPath):      api.GetServiceInfoAPI,
+		fmt.Sprintf("%s/build-info", apiPath):        api.GetBuildInfo,
 		fmt.Sprintf("%s/service-metrics", apiPath):   api.GetServiceMetricsFromStorage,
 		fmt.Sprintf("%s/go-routines-stats", apiPath): api.GetGoRoutinesStats,
 		fmt.Sprintf("%s/function", apiPath):          api.GetFunctionTraceDetails,
 		fmt.Sprintf("%s/function-details", apiPath):  api.ViewFunctionMetrics,
 		"/metrics":                                   api.PrometheusMetricsHandler,
 		fmt.Sprintf("%s/reports", apiPath):           api.GetReportData,
+		fmt.Sprintf("%s/collectors", apiPath):        api.GetCollectors,
+		fmt.Sprintf("%s/custom-metrics", apiPath):    api.GetCustomMetrics,
+		fmt.Sprintf("%s/profiles", apiPath):          api.GetProfiles,
+		fmt.Sprintf("%s/profiles/capture", apiPath):  api.CaptureProfile,
+		fmt.Sprintf("%s/profiles/download", apiPath): api.DownloadProfile,
+		fmt.Sprintf("%s/profiles/view", apiPath):     api.ViewProfile,
+		fmt.Sprintf("%s/profiles/delete", apiPath):   api.DeleteProfile,
+		fmt.Sprintf("%s/profiles/timeline", apiPath): api.GetProfileTimeline,
+		fmt.Sprintf("%s/incidents", apiPath):         api.GetIncidents,
+		fmt.Sprintf("%s/incidents/delete", apiPath):  api.DeleteIncident,
+		fmt.Sprintf("%s/health/events", apiPath):     api.GetHealthEvents,
+		fmt.Sprintf("%s/alerts", apiPath):            api.GetAlerts,
+		fmt.Sprintf("%s/alerts/rules", apiPath):      api.GetAlertRules,
+		fmt.Sprintf("%s/anomalies", apiPath):         api.GetAnomalies,
+		fmt.Sprintf("%s/anomalies/status", apiPath):  api.GetAnomalyStatus,
+		fmt.Sprintf("%s/slos", apiPath):              api.GetSLOs,
+		fmt.Sprintf("%s/query", apiPath):             api.Query,
+		fmt.Sprintf("%s/export", apiPath):            api.Export,
+		fmt.Sprintf("%s/import", apiPath):            api.Import,
+		fmt.Sprintf("%s/gc", apiPath):                api.GetGCInsights,
+		fmt.Sprintf("%s/gc/percent", apiPath):        api.SetGCPercent,
+		fmt.Sprintf("%s/gc/memory-limit", apiPath):   api.SetMemoryLimit,
+		fmt.Sprintf("%s/gc/run", apiPath):            api.RunGC,
+		fmt.Sprintf("%s/gc/free-os-memory", apiPath): api.FreeOSMemory,
+		fmt.Sprintf("%s/healthz", apiPath):           api.Liveness,
+		fmt.Sprintf("%s/readyz", apiPath):            api.Readiness,
+		"/healthz":                                   api.Liveness,
+		"/readyz":                                    api.Readiness,
 	}
 
 	securedHandlers := make(map[string]http.HandlerFunc)
@@ -566,7 +1074,7 @@ func applyMiddlewareChain(handler http.HandlerFunc, middleware []func(http.Handl
 
 	if authFunc != nil {
 		finalHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if isStaticFile(r.URL.Path) {
+			if isStaticFile(r.URL.Path) || isProbePath(r.URL.Path) {
 				handler(w, r)
 				return
 			}
@@ -574,17 +1082,16 @@ func applyMiddlewareChain(handler http.HandlerFunc, middleware []func(http.Handl
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
-			handler(w, r)
+			// Passing the auth function unlocks the endpoints refused to anonymous requests,
+			// such as profiling
+			handler(w, api.WithAuthenticated(r))
 		})
 	}
 
 	for i := len(middleware) - 1; i >= 0; i-- {
 		finalHandler = middleware[i](finalHandler)
 	}
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		finalHandler.ServeHTTP(w, r)
-	})
+	return finalHandler.ServeHTTP
 }
 
 func routeToAPIHandler(w http.ResponseWriter, r *http.Request, apiPath string) {
@@ -595,6 +1102,8 @@ func routeToAPIHandler(w http.ResponseWriter, r *http.Request, apiPath string) {
 		api.GetServiceStatistics(w, r)
 	case path == fmt.Sprintf("%s/service-info", apiPath):
 		api.GetServiceInfoAPI(w, r)
+	case path == fmt.Sprintf("%s/build-info", apiPath):
+		api.GetBuildInfo(w, r)
 	case path == fmt.Sprintf("%s/service-metrics", apiPath):
 		api.GetServiceMetricsFromStorage(w, r)
 	case path == fmt.Sprintf("%s/go-routines-stats", apiPath):
@@ -605,17 +1114,85 @@ func routeToAPIHandler(w http.ResponseWriter, r *http.Request, apiPath string) {
 		api.ViewFunctionMetrics(w, r)
 	case path == fmt.Sprintf("%s/reports", apiPath):
 		api.GetReportData(w, r)
+	case path == fmt.Sprintf("%s/collectors", apiPath):
+		api.GetCollectors(w, r)
+	case path == fmt.Sprintf("%s/custom-metrics", apiPath):
+		api.GetCustomMetrics(w, r)
+	case path == fmt.Sprintf("%s/profiles", apiPath):
+		api.GetProfiles(w, r)
+	case path == fmt.Sprintf("%s/profiles/capture", apiPath):
+		api.CaptureProfile(w, r)
+	case path == fmt.Sprintf("%s/profiles/download", apiPath):
+		api.DownloadProfile(w, r)
+	case path == fmt.Sprintf("%s/profiles/view", apiPath):
+		api.ViewProfile(w, r)
+	case path == fmt.Sprintf("%s/profiles/delete", apiPath):
+		api.DeleteProfile(w, r)
+	case path == fmt.Sprintf("%s/profiles/timeline", apiPath):
+		api.GetProfileTimeline(w, r)
+	case path == fmt.Sprintf("%s/incidents", apiPath):
+		api.GetIncidents(w, r)
+	case path == fmt.Sprintf("%s/incidents/delete", apiPath):
+		api.DeleteIncident(w, r)
+	case path == fmt.Sprintf("%s/health/events", apiPath):
+		api.GetHealthEvents(w, r)
+	case path == fmt.Sprintf("%s/alerts", apiPath):
+		api.GetAlerts(w, r)
+	case path == fmt.Sprintf("%s/alerts/rules", apiPath):
+		api.GetAlertRules(w, r)
+	case path == fmt.Sprintf("%s/anomalies", apiPath):
+		api.GetAnomalies(w, r)
+	case path == fmt.Sprintf("%s/anomalies/status", apiPath):
+		api.GetAnomalyStatus(w, r)
+	case path == fmt.Sprintf("%s/slos", apiPath):
+		api.GetSLOs(w, r)
+	case path == fmt.Sprintf("%s/query", apiPath):
+		api.Query(w, r)
+	case path == fmt.Sprintf("%s/export", apiPath):
+		api.Export(w, r)
+	case path == fmt.Sprintf("%s/import", apiPath):
+		api.Import(w, r)
+	case path == fmt.Sprintf("%s/gc", apiPath):
+		api.GetGCInsights(w, r)
+	case path == fmt.Sprintf("%s/gc/percent", apiPath):
+		api.SetGCPercent(w, r)
+	case path == fmt.Sprintf("%s/gc/memory-limit", apiPath):
+		api.SetMemoryLimit(w, r)
+	case path == fmt.Sprintf("%s/gc/run", apiPath):
+		api.RunGC(w, r)
+	case path == fmt.Sprintf("%s/gc/free-os-memory", apiPath):
+		api.FreeOSMemory(w, r)
+	case path == fmt.Sprintf("%s/healthz", apiPath):
+		api.Liveness(w, r)
+	case path == fmt.Sprintf("%s/readyz", apiPath):
+		api.Readiness(w, r)
 	default:
 		http.NotFound(w, r)
 	}
 }
 
+// routeToProbeHandler serves the liveness and readiness probes mounted at the root,
+// reporting whether the request was a probe.
+func routeToProbeHandler(w http.ResponseWriter, r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz":
+		api.Liveness(w, r)
+	case "/readyz":
+		api.Readiness(w, r)
+	default:
+		return false
+	}
+	return true
+}
+
 func routeToFiberAPIHandler(c *fiber.Ctx, path, apiPath string) error {
 	switch {
 	case path == fmt.Sprintf("%s/metrics", apiPath):
 		return handleFiberAPI(c, api.GetServiceStatistics)
 	case path == fmt.Sprintf("%s/service-info", apiPath):
 		return handleFiberAPI(c, api.GetServiceInfoAPI)
+	case path == fmt.Sprintf("%s/build-info", apiPath):
+		return handleFiberAPI(c, api.GetBuildInfo)
 	case path == fmt.Sprintf("%s/service-metrics", apiPath):
 		return handleFiberAPI(c, api.GetServiceMetricsFromStorage)
 	case path == fmt.Sprintf("%s/go-routines-stats", apiPath):
@@ -626,6 +1203,58 @@ func routeToFiberAPIHandler(c *fiber.Ctx, path, apiPath string) error {
 		return handleFiberAPI(c, api.ViewFunctionMetrics)
 	case path == fmt.Sprintf("%s/reports", apiPath):
 		return handleFiberAPI(c, api.GetReportData)
+	case path == fmt.Sprintf("%s/collectors", apiPath):
+		return handleFiberAPI(c, api.GetCollectors)
+	case path == fmt.Sprintf("%s/custom-metrics", apiPath):
+		return handleFiberAPI(c, api.GetCustomMetrics)
+	case path == fmt.Sprintf("%s/profiles", apiPath):
+		return handleFiberAPI(c, api.GetProfiles)
+	case path == fmt.Sprintf("%s/profiles/capture", apiPath):
+		return handleFiberAPI(c, api.CaptureProfile)
+	case path == fmt.Sprintf("%s/profiles/download", apiPath):
+		return handleFiberAPI(c, api.DownloadProfile)
+	case path == fmt.Sprintf("%s/profiles/view", apiPath):
+		return handleFiberAPI(c, api.ViewProfile)
+	case path == fmt.Sprintf("%s/profiles/delete", apiPath):
+		return handleFiberAPI(c, api.DeleteProfile)
+	case path == fmt.Sprintf("%s/profiles/timeline", apiPath):
+		return handleFiberAPI(c, api.GetProfileTimeline)
+	case path == fmt.Sprintf("%s/incidents", apiPath):
+		return handleFiberAPI(c, api.GetIncidents)
+	case path == fmt.Sprintf("%s/incidents/delete", apiPath):
+		return handleFiberAPI(c, api.DeleteIncident)
+	case path == fmt.Sprintf("%s/health/events", apiPath):
+		return handleFiberAPI(c, api.GetHealthEvents)
+	case path == fmt.Sprintf("%s/alerts", apiPath):
+		return handleFiberAPI(c, api.GetAlerts)
+	case path == fmt.Sprintf("%s/alerts/rules", apiPath):
+		return handleFiberAPI(c, api.GetAlertRules)
+	case path == fmt.Sprintf("%s/anomalies", apiPath):
+		return handleFiberAPI(c, api.GetAnomalies)
+	case path == fmt.Sprintf("%s/anomalies/status", apiPath):
+		return handleFiberAPI(c, api.GetAnomalyStatus)
+	case path == fmt.Sprintf("%s/slos", apiPath):
+		return handleFiberAPI(c, api.GetSLOs)
+	case path == fmt.Sprintf("%s/query", apiPath):
+		return handleFiberAPI(c, api.Query)
+	case path == fmt.Sprintf("%s/export", apiPath):
+		return handleFiberAPI(c, api.Export)
+	case path == fmt.Sprintf("%s/import", apiPath):
+		return handleFiberAPI(c, api.Import)
+	case path == fmt.Sprintf("%s/gc", apiPath):
+		return handleFiberAPI(c, api.GetGCInsights)
+	case path == fmt.Sprintf("%s/gc/percent", apiPath):
+		return handleFiberAPI(c, api.SetGCPercent)
+	case path == fmt.Sprintf("%s/gc/memory-limit", apiPath):
+		return handleFiberAPI(c, api.SetMemoryLimit)
+	case path == fmt.Sprintf("%s/gc/run", apiPath):
+		return handleFiberAPI(c, api.RunGC)
+	case path == fmt.Sprintf("%s/gc/free-os-memory", apiPath):
+		return handleFiberAPI(c, api.FreeOSMemory)
+	case path == fmt.Sprintf("%s/healthz", apiPath):
+		return handleFiberAPI(c, api.Liveness)
+	case path == fmt.Sprintf("%s/readyz", apiPath):
+		return handleFiberAPI(c, api.Readiness)
 	default:
 		c.Status(404).SendString("Not Found")
 		return nil
@@ -636,9 +1265,14 @@ func handleFiberAPI(c *fiber.Ctx, handler func(http.ResponseWriter, *http.Reques
 	respWriter := &fiberResponseWriter{c: c}
 	body := c.Request().Body()
 
+	url := "http://localhost" + string(c.Request().URI().Path())
+	if query := c.Request().URI().QueryString(); len(query) > 0 {
+		url += "?" + string(query)
+	}
+
 	req, err := http.NewRequest(
 		string(c.Request().Header.Method()),
-		"http://localhost"+string(c.Request().URI().Path()),
+		url,
 		strings.NewReader(string(body)),
 	)
 	if err != nil {
@@ -731,11 +1365,12 @@ func (w *fiberResponseWriter) WriteHeader(statusCode int) {
 
 // ---- Built-in Security Middleware ----
 
-// BasicAuthMiddleware creates a basic authentication middleware
+// BasicAuthMiddleware creates a basic authentication middleware. Authenticated requests are marked
+// with api.WithAuthenticated, unlocking profiling, GC controls, incident deletion and imports.
 func BasicAuthMiddleware(username, password string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if isStaticFile(r.URL.Path) {
+			if isStaticFile(r.URL.Path) || isProbePath(r.URL.Path) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -745,16 +1380,17 @@ func BasicAuthMiddleware(username, password string) func(http.Handler) http.Hand
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, api.WithAuthenticated(r))
 		})
 	}
 }
 
-// APIKeyMiddleware creates an API key authentication middleware
+// APIKeyMiddleware creates an API key authentication middleware. Authenticated requests are marked
+// with api.WithAuthenticated like with BasicAuthMiddleware.
 func APIKeyMiddleware(apiKey string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if isStaticFile(r.URL.Path) {
+			if isStaticFile(r.URL.Path) || isProbePath(r.URL.Path) {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -766,7 +1402,7 @@ func APIKeyMiddleware(apiKey string) func(http.Handler) http.Handler {
 				http.Error(w, "Unauthorized", http.StatusUnauthorized)
 				return
 			}
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, api.WithAuthenticated(r))
 		})
 	}
 }
@@ -939,3 +1575,9 @@ func isStaticFile(path string) bool {
 
 	return false
 }
+
+// isProbePath reports whether path is a liveness or readiness probe. Probes are served without
+// authentication since orchestrators such as the kubelet cannot authenticate.
+func isProbePath(path string) bool {
+	return strings.HasSuffix(path, "/healthz") || strings.HasSuffix(path, "/readyz")
+}
@@ -0,0 +1,437 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/iyashjayesh/monigo/common"
+)
+
+// Aggregations across series, grouped by the labels of the by clause.
+var aggregations = []string{"sum", "avg", "min", "max", "count"}
+
+// rangeFunctions are the functions over the points of a range selector, e.g. rate(m[5m]).
+var rangeFunctions = []string{
+	"rate", "increase", "avg_over_time", "min_over_time", "max_over_time", "sum_over_time",
+	"count_over_time", "last_over_time", "quantile_over_time",
+}
+
+// Expr is a parsed query.
+type Expr interface {
+	String() string
+}
+
+type numberExpr struct {
+	value float64
+}
+
+// matcher selects the series whose label matches a value or a regular expression.
+type matcher struct {
+	name  string
+	op    string // =, !=, =~ or !~
+	value string
+	re    *regexp.Regexp
+}
+
+type selectorExpr struct {
+	metric   string
+	matchers []matcher
+	window   time.Duration // Range of a range selector, zero for an instant selector
+}
+
+type callExpr struct {
+	fn       string
+	quantile float64 // Argument of quantile_over_time
+	arg      *selectorExpr
+}
+
+type aggregateExpr struct {
+	op  string
+	by  []string
+	arg Expr
+}
+
+type binaryExpr struct {
+	op       byte // +, -, * or /
+	lhs, rhs Expr
+}
+
+func (e *numberExpr) String() string { return strconv.FormatFloat(e.value, 'g', -1, 64) }
+
+func (m matcher) String() string { return m.name + m.op + strconv.Quote(m.value) }
+
+func (e *selectorExpr) String() string {
+	var b strings.Builder
+	b.WriteString(e.metric)
+	if len(e.matchers) > 0 {
+		parts := make([]string, len(e.matchers))
+		for i, m := range e.matchers {
+			parts[i] = m.String()
+		}
+		b.WriteString("{" + strings.Join(parts, ",") + "}")
+	}
+	if e.window > 0 {
+		b.WriteString("[" + e.window.String() + "]")
+	}
+	return b.String()
+}
+
+func (e *callExpr) String() string {
+	if e.fn == "quantile_over_time" {
+		return fmt.Sprintf("%s(%g, %s)", e.fn, e.quantile, e.arg)
+	}
+	return fmt.Sprintf("%s(%s)", e.fn, e.arg)
+}
+
+func (e *aggregateExpr) String() string {
+	if len(e.by) == 0 {
+		return fmt.Sprintf("%s(%s)", e.op, e.arg)
+	}
+	return fmt.Sprintf("%s by (%s) (%s)", e.op, strings.Join(e.by, ", "), e.arg)
+}
+
+func (e *binaryExpr) String() string {
+	return fmt.Sprintf("(%s %c %s)", e.lhs, e.op, e.rhs)
+}
+
+// parser is a recursive descent parser over the query text.
+type parser struct {
+	input string
+	pos   int
+}
+
+// Parse parses a query: a number, a selector such as http_requests_total{code=~"5.."}, a
+// range function such as rate(http_requests_total[5m]), an aggregation such as
+// sum by (route) (...), or arithmetic between them with +, -, * and /.
+func Parse(query string) (Expr, error) {
+	p := &parser{input: query}
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return expr, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("parse error at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// peek returns the next character after spaces, zero at the end.
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// parseExpr parses terms separated by + and -.
+func (p *parser) parseExpr() (Expr, error) {
+	lhs, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for c := p.peek(); c == '+' || c == '-'; c = p.peek() {
+		p.pos++
+		rhs, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryExpr{op: c, lhs: lhs, rhs: rhs}
+	}
+	return lhs, nil
+}
+
+// parseTerm parses factors separated by * and /.
+func (p *parser) parseTerm() (Expr, error) {
+	lhs, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for c := p.peek(); c == '*' || c == '/'; c = p.peek() {
+		p.pos++
+		rhs, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryExpr{op: c, lhs: lhs, rhs: rhs}
+	}
+	return lhs, nil
+}
+
+// parseFactor parses a number, a parenthesized expression, a negation, a function call, an
+// aggregation or a selector.
+func (p *parser) parseFactor() (Expr, error) {
+	c := p.peek()
+	switch {
+	case c == 0:
+		return nil, p.errorf("unexpected end of query")
+	case c == '(':
+		p.pos++
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(')')
+	case c == '-':
+		p.pos++
+		expr, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryExpr{op: '-', lhs: &numberExpr{}, rhs: expr}, nil
+	case c == '.' || c >= '0' && c <= '9':
+		return p.parseNumber()
+	}
+
+	name := p.parseIdentifier()
+	if name == "" {
+		return nil, p.errorf("unexpected %q", c)
+	}
+	switch {
+	case slices.Contains(aggregations, name):
+		return p.parseAggregation(name)
+	case slices.Contains(rangeFunctions, name):
+		return p.parseCall(name)
+	}
+	return p.parseSelector(name)
+}
+
+func (p *parser) parseNumber() (Expr, error) {
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("0123456789.eE", p.input[p.pos]) >= 0 {
+		// An exponent may be signed
+		if c := p.input[p.pos]; (c == 'e' || c == 'E') && p.pos+1 < len(p.input) && strings.IndexByte("+-", p.input[p.pos+1]) >= 0 {
+			p.pos++
+		}
+		p.pos++
+	}
+	value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number %q", p.input[start:p.pos])
+	}
+	return &numberExpr{value: value}, nil
+}
+
+// parseIdentifier returns the metric, label or function name at the position, empty if none.
+func (p *parser) parseIdentifier() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '_' || c == ':' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || p.pos > start && c >= '0' && c <= '9' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
+
+// parseAggregation parses sum(...), sum(...) by (labels) or sum by (labels) (...).
+func (p *parser) parseAggregation(op string) (Expr, error) {
+	expr := &aggregateExpr{op: op}
+	by, err := p.parseBy()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	if expr.arg, err = p.parseExpr(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	if by == nil {
+		if by, err = p.parseBy(); err != nil {
+			return nil, err
+		}
+	}
+	expr.by = by
+	return expr, nil
+}
+
+// parseBy parses a by clause if there is one.
+func (p *parser) parseBy() ([]string, error) {
+	p.skipSpace()
+	if !strings.HasPrefix(p.input[p.pos:], "by") {
+		return nil, nil
+	}
+	start := p.pos
+	p.pos += len("by")
+	if p.peek() != '(' {
+		p.pos = start
+		return nil, nil
+	}
+	p.pos++
+
+	by := []string{}
+	for p.peek() != ')' {
+		name := p.parseIdentifier()
+		if name == "" {
+			return nil, p.errorf("expected a label name")
+		}
+		by = append(by, name)
+		if p.peek() == ',' {
+			p.pos++
+		}
+	}
+	p.pos++
+	return by, nil
+}
+
+// parseCall parses a range function, rate(m[5m]) or quantile_over_time(0.99, m[5m]).
+func (p *parser) parseCall(fn string) (Expr, error) {
+	call := &callExpr{fn: fn}
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	if fn == "quantile_over_time" {
+		q, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		call.quantile = q.(*numberExpr).value
+		if call.quantile < 0 || call.quantile > 1 {
+			return nil, p.errorf("quantile must be between 0 and 1")
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+
+	name := p.parseIdentifier()
+	if name == "" {
+		return nil, p.errorf("%s expects a range selector such as metric[5m]", fn)
+	}
+	arg, err := p.parseSelector(name)
+	if err != nil {
+		return nil, err
+	}
+	call.arg = arg.(*selectorExpr)
+	if call.arg.window == 0 {
+		return nil, p.errorf("%s expects a range selector such as %s[5m]", fn, name)
+	}
+	return call, p.expect(')')
+}
+
+// parseSelector parses the label matchers and the range of a selector of a metric.
+func (p *parser) parseSelector(metric string) (Expr, error) {
+	sel := &selectorExpr{metric: metric}
+	if p.peek() == '{' {
+		p.pos++
+		for p.peek() != '}' {
+			m, err := p.parseMatcher()
+			if err != nil {
+				return nil, err
+			}
+			sel.matchers = append(sel.matchers, m)
+			if p.peek() == ',' {
+				p.pos++
+			} else if p.peek() != '}' {
+				return nil, p.errorf("expected ',' or '}'")
+			}
+		}
+		p.pos++
+	}
+
+	if p.peek() == '[' {
+		p.pos++
+		end := strings.IndexByte(p.input[p.pos:], ']')
+		if end < 0 {
+			return nil, p.errorf("expected ']'")
+		}
+		window, err := common.ParseDuration(strings.TrimSpace(p.input[p.pos : p.pos+end]))
+		if err != nil || window <= 0 {
+			return nil, p.errorf("invalid range %q, e.g. '5m'", p.input[p.pos:p.pos+end])
+		}
+		sel.window = window
+		p.pos += end + 1
+	}
+	return sel, nil
+}
+
+func (p *parser) parseMatcher() (matcher, error) {
+	m := matcher{name: p.parseIdentifier()}
+	if m.name == "" {
+		return m, p.errorf("expected a label name")
+	}
+
+	p.skipSpace()
+	for _, op := range []string{"=~", "!~", "!=", "="} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			m.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if m.op == "" {
+		return m, p.errorf("expected =, !=, =~ or !~ after %s", m.name)
+	}
+
+	value, err := p.parseString()
+	if err != nil {
+		return m, err
+	}
+	m.value = value
+	if m.op == "=~" || m.op == "!~" {
+		if m.re, err = regexp.Compile("^(?:" + value + ")$"); err != nil {
+			return m, p.errorf("invalid regular expression %q: %v", value, err)
+		}
+	}
+	return m, nil
+}
+
+// parseString parses a double or single quoted string.
+func (p *parser) parseString() (string, error) {
+	quote := p.peek()
+	if quote != '"' && quote != '\'' {
+		return "", p.errorf("expected a quoted label value")
+	}
+	start := p.pos
+	for p.pos++; p.pos < len(p.input) && p.input[p.pos] != quote; p.pos++ {
+		if p.input[p.pos] == '\\' {
+			p.pos++
+		}
+	}
+	if p.pos >= len(p.input) {
+		return "", p.errorf("unterminated string")
+	}
+	p.pos++
+
+	literal := p.input[start:p.pos]
+	if quote == '\'' {
+		literal = `"` + strings.ReplaceAll(strings.ReplaceAll(literal[1:len(literal)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	value, err := strconv.Unquote(literal)
+	if err != nil {
+		return "", p.errorf("invalid string %s", p.input[start:p.pos])
+	}
+	return value, nil
+}
@@ -0,0 +1,414 @@
+// Package query evaluates a small PromQL-like query language over the stored metrics at
+// aligned timestamps.
+package query
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+const (
+	// MaxPoints is the number of timestamps a query evaluates at most.
+	MaxPoints = 11000
+	// Lookback is how old the latest point an instant selector returns may be.
+	Lookback = 10 * time.Minute
+)
+
+// series is the values of a series at every timestamp, NaN where it has none.
+type series struct {
+	labels map[string]string
+	values []float64
+}
+
+// value is the result of an expression, a scalar or a vector of series.
+type value struct {
+	scalar bool
+	series []series
+}
+
+// evaluator evaluates an expression at the timestamps.
+type evaluator struct {
+	timestamps []int64
+}
+
+// Range evaluates a query at every step from start to end, both aligned down to the step.
+func Range(expr Expr, start, end time.Time, step time.Duration) (*models.QueryResult, error) {
+	if step < time.Second {
+		return nil, errors.New("step must be at least a second")
+	}
+	stepSeconds := int64(step / time.Second)
+	from := start.Unix() - start.Unix()%stepSeconds
+	to := end.Unix() - end.Unix()%stepSeconds
+	if to < from {
+		return nil, errors.New("end must not be before start")
+	}
+	if (to-from)/stepSeconds+1 > MaxPoints {
+		return nil, fmt.Errorf("the range holds more than %d steps, increase the step", MaxPoints)
+	}
+
+	e := &evaluator{}
+	for ts := from; ts <= to; ts += stepSeconds {
+		e.timestamps = append(e.timestamps, ts)
+	}
+	v, err := e.eval(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.QueryResult{
+		Query:      expr.String(),
+		Step:       int64(step / time.Second),
+		Timestamps: make([]time.Time, len(e.timestamps)),
+		Series:     []models.QuerySeries{},
+	}
+	for i, ts := range e.timestamps {
+		result.Timestamps[i] = time.Unix(ts, 0).UTC()
+	}
+	for _, s := range v.series {
+		out := models.QuerySeries{Labels: s.labels, Values: make([]*float64, len(s.values))}
+		if out.Labels == nil {
+			out.Labels = map[string]string{}
+		}
+		present := false
+		for i, x := range s.values {
+			if !math.IsNaN(x) && !math.IsInf(x, 0) {
+				out.Values[i] = &x
+				present = true
+			}
+		}
+		if present {
+			result.Series = append(result.Series, out)
+		}
+	}
+	return result, nil
+}
+
+func (e *evaluator) eval(expr Expr) (value, error) {
+	switch expr := expr.(type) {
+	case *numberExpr:
+		return value{scalar: true, series: []series{e.constant(expr.value)}}, nil
+	case *selectorExpr:
+		return e.evalSelector(expr, func(points []timeseries.DataPoint) float64 {
+			return points[len(points)-1].Value
+		})
+	case *callExpr:
+		return e.evalSelector(expr.arg, func(points []timeseries.DataPoint) float64 {
+			return overTime(expr, points)
+		})
+	case *aggregateExpr:
+		return e.evalAggregate(expr)
+	case *binaryExpr:
+		return e.evalBinary(expr)
+	}
+	return value{}, fmt.Errorf("unsupported expression %s", expr)
+}
+
+func (e *evaluator) constant(x float64) series {
+	values := make([]float64, len(e.timestamps))
+	for i := range values {
+		values[i] = x
+	}
+	return series{values: values}
+}
+
+// evalSelector applies fn to the points of every series matching the selector within its
+// range, or the lookback for an instant selector, before every timestamp.
+func (e *evaluator) evalSelector(sel *selectorExpr, fn func(points []timeseries.DataPoint) float64) (value, error) {
+	window := sel.window
+	if window == 0 {
+		window = Lookback
+	}
+	windowSeconds := int64(window / time.Second)
+
+	all, err := timeseries.GetSeries(sel.metric)
+	if err != nil {
+		return value{}, err
+	}
+	sto, err := timeseries.GetStorageInstance()
+	if err != nil {
+		return value{}, err
+	}
+
+	result := value{series: []series{}}
+	first, last := e.timestamps[0], e.timestamps[len(e.timestamps)-1]
+	for _, labels := range all {
+		if !matchesAll(labels, sel.matchers) {
+			continue
+		}
+		points, err := sto.Select(sel.metric, labels, first-windowSeconds, last+1)
+		if err != nil && !errors.Is(err, timeseries.ErrNoDataPoints) {
+			return value{}, fmt.Errorf("reading %s: %w", sel.metric, err)
+		}
+		if len(points) == 0 {
+			continue
+		}
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+
+		s := series{labels: make(map[string]string, len(labels)), values: make([]float64, len(e.timestamps))}
+		for _, l := range labels {
+			s.labels[l.Name] = l.Value
+		}
+		for i, ts := range e.timestamps {
+			// The points in (ts - window, ts], inclusive of the start for instant selectors
+			lo := sort.Search(len(points), func(j int) bool { return points[j].Timestamp > ts-windowSeconds })
+			if sel.window == 0 {
+				lo = sort.Search(len(points), func(j int) bool { return points[j].Timestamp >= ts-windowSeconds })
+			}
+			hi := sort.Search(len(points), func(j int) bool { return points[j].Timestamp > ts })
+			s.values[i] = math.NaN()
+			if lo < hi {
+				s.values[i] = fn(points[lo:hi])
+			}
+		}
+		result.series = append(result.series, s)
+	}
+	return result, nil
+}
+
+// matchesAll reports whether the labels satisfy every matcher, a missing label being empty.
+func matchesAll(labels []timeseries.Label, matchers []matcher) bool {
+	for _, m := range matchers {
+		var v string
+		for _, l := range labels {
+			if l.Name == m.name {
+				v = l.Value
+			}
+		}
+		var ok bool
+		switch m.op {
+		case "=":
+			ok = v == m.value
+		case "!=":
+			ok = v != m.value
+		case "=~":
+			ok = m.re.MatchString(v)
+		case "!~":
+			ok = !m.re.MatchString(v)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// overTime applies a range function to the points of a window, oldest first.
+func overTime(call *callExpr, points []timeseries.DataPoint) float64 {
+	switch call.fn {
+	case "rate", "increase":
+		if len(points) < 2 {
+			return math.NaN()
+		}
+		var increase float64
+		for i := 1; i < len(points); i++ {
+			if points[i].Value < points[i-1].Value {
+				increase += points[i].Value // A reset restarts the counter from zero
+			} else {
+				increase += points[i].Value - points[i-1].Value
+			}
+		}
+		if call.fn == "rate" {
+			return increase / call.arg.window.Seconds()
+		}
+		return increase
+	case "avg_over_time", "sum_over_time":
+		var sum float64
+		for _, p := range points {
+			sum += p.Value
+		}
+		if call.fn == "avg_over_time" {
+			return sum / float64(len(points))
+		}
+		return sum
+	case "min_over_time":
+		return slices.MinFunc(points, func(a, b timeseries.DataPoint) int { return cmp.Compare(a.Value, b.Value) }).Value
+	case "max_over_time":
+		return slices.MaxFunc(points, func(a, b timeseries.DataPoint) int { return cmp.Compare(a.Value, b.Value) }).Value
+	case "count_over_time":
+		return float64(len(points))
+	case "last_over_time":
+		return points[len(points)-1].Value
+	case "quantile_over_time":
+		values := make([]float64, len(points))
+		for i, p := range points {
+			values[i] = p.Value
+		}
+		return quantile(call.quantile, values)
+	}
+	return math.NaN()
+}
+
+// quantile returns the q-quantile of the values, interpolating linearly between the closest ranks.
+func quantile(q float64, values []float64) float64 {
+	sort.Float64s(values)
+	rank := q * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return values[lower]*(1-weight) + values[upper]*weight
+}
+
+// evalAggregate aggregates the series with the same values of the by labels at every timestamp.
+func (e *evaluator) evalAggregate(agg *aggregateExpr) (value, error) {
+	v, err := e.eval(agg.arg)
+	if err != nil {
+		return value{}, err
+	}
+	if v.scalar {
+		return value{}, fmt.Errorf("%s expects series, not a number", agg.op)
+	}
+
+	groups := make(map[string]*series)
+	counts := make(map[string][]int)
+	var keys []string
+	for _, s := range v.series {
+		labels := make(map[string]string)
+		for _, name := range agg.by {
+			if v, ok := s.labels[name]; ok {
+				labels[name] = v
+			}
+		}
+		key := labelsKey(labels)
+		group, ok := groups[key]
+		if !ok {
+			group = &series{labels: labels, values: make([]float64, len(e.timestamps))}
+			for i := range group.values {
+				group.values[i] = math.NaN()
+			}
+			groups[key] = group
+			counts[key] = make([]int, len(e.timestamps))
+			keys = append(keys, key)
+		}
+
+		for i, x := range s.values {
+			if math.IsNaN(x) {
+				continue
+			}
+			n := counts[key][i]
+			counts[key][i]++
+			if n == 0 {
+				group.values[i] = x
+				if agg.op == "count" {
+					group.values[i] = 1
+				}
+				continue
+			}
+			switch agg.op {
+			case "sum", "avg":
+				group.values[i] += x
+			case "min":
+				group.values[i] = min(group.values[i], x)
+			case "max":
+				group.values[i] = max(group.values[i], x)
+			case "count":
+				group.values[i]++
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	result := value{series: make([]series, 0, len(keys))}
+	for _, key := range keys {
+		group := groups[key]
+		if agg.op == "avg" {
+			for i, n := range counts[key] {
+				if n > 0 {
+					group.values[i] /= float64(n)
+				}
+			}
+		}
+		result.series = append(result.series, *group)
+	}
+	return result, nil
+}
+
+// labelsKey returns a key identifying a set of labels.
+func labelsKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte(0)
+		b.WriteString(labels[name])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// evalBinary applies an arithmetic operator between numbers, a number and every series, or
+// the series with the same labels on both sides.
+func (e *evaluator) evalBinary(bin *binaryExpr) (value, error) {
+	lhs, err := e.eval(bin.lhs)
+	if err != nil {
+		return value{}, err
+	}
+	rhs, err := e.eval(bin.rhs)
+	if err != nil {
+		return value{}, err
+	}
+
+	apply := func(l, r series, labels map[string]string) series {
+		out := series{labels: labels, values: make([]float64, len(e.timestamps))}
+		for i := range out.values {
+			out.values[i] = arithmetic(bin.op, l.values[i], r.values[i])
+		}
+		return out
+	}
+
+	switch {
+	case lhs.scalar && rhs.scalar:
+		return value{scalar: true, series: []series{apply(lhs.series[0], rhs.series[0], nil)}}, nil
+	case lhs.scalar:
+		result := value{series: make([]series, len(rhs.series))}
+		for i, s := range rhs.series {
+			result.series[i] = apply(lhs.series[0], s, s.labels)
+		}
+		return result, nil
+	case rhs.scalar:
+		result := value{series: make([]series, len(lhs.series))}
+		for i, s := range lhs.series {
+			result.series[i] = apply(s, rhs.series[0], s.labels)
+		}
+		return result, nil
+	}
+
+	byLabels := make(map[string]series, len(rhs.series))
+	for _, s := range rhs.series {
+		byLabels[labelsKey(s.labels)] = s
+	}
+	result := value{series: []series{}}
+	for _, s := range lhs.series {
+		if other, ok := byLabels[labelsKey(s.labels)]; ok {
+			result.series = append(result.series, apply(s, other, s.labels))
+		}
+	}
+	return result, nil
+}
+
+func arithmetic(op byte, l, r float64) float64 {
+	switch op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	case '/':
+		return l / r
+	}
+	return math.NaN()
+}
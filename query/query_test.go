@@ -0,0 +1,173 @@
+package query
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+// base is the timestamp the test series start at, a multiple of every step used.
+var base = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var seriesOnce sync.Once
+
+// withSeries stores the test series in the in-memory storage once: a requests counter per
+// route and code growing by 60, 30 and 6 per minute, and a latency gauge.
+func withSeries(t *testing.T) {
+	t.Helper()
+	timeseries.SetStorageType("memory")
+	sto, err := timeseries.GetStorageInstance()
+	if err != nil {
+		t.Fatalf("GetStorageInstance error: %v", err)
+	}
+	seriesOnce.Do(func() { insertSeries(t, sto) })
+}
+
+func insertSeries(t *testing.T, sto timeseries.Storage) {
+
+	var rows []timeseries.Row
+	for i := range 11 {
+		ts := base.Add(time.Duration(i) * time.Minute).Unix()
+		for _, s := range []struct {
+			route, code string
+			perMinute   float64
+		}{{"/checkout", "200", 60}, {"/checkout", "500", 6}, {"/cart", "200", 30}} {
+			rows = append(rows, timeseries.Row{
+				Metric:    "query_test_requests_total",
+				Labels:    []timeseries.Label{{Name: "route", Value: s.route}, {Name: "code", Value: s.code}},
+				DataPoint: timeseries.DataPoint{Timestamp: ts, Value: s.perMinute * float64(i)},
+			})
+		}
+		rows = append(rows, timeseries.Row{
+			Metric:    "query_test_latency",
+			Labels:    []timeseries.Label{{Name: "route", Value: "/checkout"}},
+			DataPoint: timeseries.DataPoint{Timestamp: ts, Value: float64(i)},
+		})
+	}
+	if err := sto.InsertRows(rows); err != nil {
+		t.Fatalf("InsertRows error: %v", err)
+	}
+}
+
+func run(t *testing.T, q string, step time.Duration) *models.QueryResult {
+	t.Helper()
+	expr, err := Parse(q)
+	if err != nil {
+		t.Fatalf("Parse(%q) error: %v", q, err)
+	}
+	result, err := Range(expr, base.Add(5*time.Minute), base.Add(10*time.Minute), step)
+	if err != nil {
+		t.Fatalf("Range(%q) error: %v", q, err)
+	}
+	return result
+}
+
+// last returns the value of the series with the label at the last timestamp.
+func last(result *models.QueryResult, name, value string) float64 {
+	for _, s := range result.Series {
+		if s.Labels[name] == value || name == "" {
+			if v := s.Values[len(s.Values)-1]; v != nil {
+				return *v
+			}
+		}
+	}
+	return math.NaN()
+}
+
+func TestParse(t *testing.T) {
+	valid := map[string]string{
+		`up`:                                     `up`,
+		`m{a="x", b!='y', c=~"5..",d!~"a|b"}`:    `m{a="x",b!="y",c=~"5..",d!~"a|b"}`,
+		`rate(m[5m])`:                            `rate(m[5m0s])`,
+		`quantile_over_time(0.99, m{a="x"}[1h])`: `quantile_over_time(0.99, m{a="x"}[1h0m0s])`,
+		`sum by (route) (rate(m[5m]))`:           `sum by (route) (rate(m[5m0s]))`,
+		`sum(rate(m[5m])) by (route, code)`:      `sum by (route, code) (rate(m[5m0s]))`,
+		`1 + 2 * 3 / -a`:                         `(1 + ((2 * 3) / (0 - a)))`,
+		`(a - b) * 1e2`:                          `((a - b) * 100)`,
+	}
+	for q, want := range valid {
+		expr, err := Parse(q)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", q, err)
+			continue
+		}
+		if expr.String() != want {
+			t.Errorf("Parse(%q) = %s, expected %s", q, expr, want)
+		}
+	}
+
+	for _, q := range []string{``, `rate(m)`, `m{a="x"`, `m{a~"x"}`, `m{a=~"("}`, `m[forever]`, `quantile_over_time(2, m[5m])`, `sum(m) +`, `m )`} {
+		if _, err := Parse(q); err == nil {
+			t.Errorf("Parse(%q): expected an error", q)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	withSeries(t)
+
+	result := run(t, `query_test_requests_total{route="/checkout"}`, time.Minute)
+	if len(result.Timestamps) != 6 || !result.Timestamps[0].Equal(base.Add(5*time.Minute)) {
+		t.Fatalf("expected 6 aligned timestamps, got %v", result.Timestamps)
+	}
+	if len(result.Series) != 2 || last(result, "code", "200") != 600 || last(result, "code", "500") != 60 {
+		t.Errorf("expected the /checkout series, got %+v", result.Series)
+	}
+
+	tests := []struct {
+		query string
+		label string
+		value string
+		want  float64
+	}{
+		{`rate(query_test_requests_total{code="200",route="/cart"}[5m])`, "", "", 0.4},
+		{`increase(query_test_requests_total{code="500"}[5m])`, "", "", 24},
+		{`sum(query_test_requests_total)`, "", "", 960},
+		{`sum by (route) (rate(query_test_requests_total[5m]))`, "route", "/checkout", 0.88},
+		{`count(query_test_requests_total{code=~"2.."})`, "", "", 2},
+		{`max(query_test_requests_total) - min(query_test_requests_total)`, "", "", 540},
+		{`avg_over_time(query_test_latency[5m])`, "", "", 8},
+		{`quantile_over_time(0.5, query_test_latency[10m])`, "", "", 5.5},
+		{`last_over_time(query_test_latency[5m]) * 2 + 1`, "", "", 21},
+		{`sum(rate(query_test_requests_total{code="500"}[5m])) / sum(rate(query_test_requests_total[5m]))`, "", "", 0.0625},
+	}
+	for _, tt := range tests {
+		result := run(t, tt.query, time.Minute)
+		if got := last(result, tt.label, tt.value); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.want, got)
+		}
+	}
+}
+
+func TestRangeMissingValues(t *testing.T) {
+	withSeries(t)
+
+	// rate needs two points in the window, so the first timestamp has none
+	expr, _ := Parse(`rate(query_test_latency[2m])`)
+	result, err := Range(expr, base, base.Add(2*time.Minute), time.Minute)
+	if err != nil {
+		t.Fatalf("Range error: %v", err)
+	}
+	if len(result.Series) != 1 || result.Series[0].Values[0] != nil || *result.Series[0].Values[1] != 1.0/120 {
+		t.Errorf("expected no value before the second point, got %+v", result.Series)
+	}
+
+	if result := run(t, `query_test_missing`, time.Minute); len(result.Series) != 0 {
+		t.Errorf("expected no series for an unknown metric, got %+v", result.Series)
+	}
+	if _, err := Range(expr, base, base.Add(time.Hour), time.Millisecond); err == nil {
+		t.Error("expected an error for a step under a second")
+	}
+}
+
+func TestCounterReset(t *testing.T) {
+	points := []timeseries.DataPoint{{Timestamp: 0, Value: 10}, {Timestamp: 60, Value: 20}, {Timestamp: 120, Value: 5}, {Timestamp: 180, Value: 15}}
+	call := &callExpr{fn: "increase", arg: &selectorExpr{window: 3 * time.Minute}}
+	if got := overTime(call, points); got != 25 {
+		t.Errorf("expected an increase of 25 across the reset, got %v", got)
+	}
+}
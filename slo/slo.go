@@ -0,0 +1,481 @@
+// Package slo tracks service level objectives: the share of good events over a rolling window,
+// the error budget left and how fast it burns.
+package slo
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/internal/registry"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+// SLO types.
+const (
+	TypeAvailability = "availability" // Good events are the requests or calls which didn't fail
+	TypeLatency      = "latency"      // Good events are the requests or calls no slower than the threshold
+)
+
+const (
+	// DefaultWindow is the rolling window of an SLO unless configured otherwise.
+	DefaultWindow = "30d"
+	// DefaultInterval is the time between two evaluations unless configured otherwise.
+	DefaultInterval = time.Minute
+)
+
+// Burn rates from which the error budget burns too fast, as multiples of the rate which
+// exhausts it exactly at the end of the window. Each needs both a long and a short window to
+// burn that fast, so an alert fires quickly and resets quickly once the burn stops.
+const (
+	FastBurnRate = 14.4 // 2% of a 30 day budget in an hour
+	SlowBurnRate = 6.0  // 5% of a 30 day budget in 6 hours
+)
+
+// burnWindows are the windows the burn rates are computed over.
+var burnWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"30m", 30 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+	{"3d", 72 * time.Hour},
+}
+
+// bucket holds the events counted during an evaluation interval ending at its time.
+type bucket struct {
+	at    int64
+	good  float64
+	total float64
+}
+
+// tracker counts the events of an SLO.
+type tracker struct {
+	slo        models.SLO
+	window     time.Duration
+	match      map[string]*regexp.Regexp
+	errorMatch map[string]*regexp.Regexp
+	buckets    []bucket // Oldest first, within the window
+	status     models.SLOStatus
+
+	// Cumulative counts of the metric at the previous evaluation
+	lastGood, lastTotal float64
+
+	// Traced calls since the previous evaluation, guarded by callsMu
+	calls bucket
+}
+
+var (
+	mu       sync.Mutex
+	config   models.SLOConfig
+	trackers []*tracker
+	stopEval context.CancelFunc
+	evalDone chan struct{}
+
+	callsMu      sync.Mutex
+	callTrackers []*tracker // Trackers counting traced function calls
+)
+
+// ValidateSLOs checks that every SLO has a unique name, a known type, an objective between 0
+// and 100, a valid window and a single source.
+func ValidateSLOs(slos []models.SLO) error {
+	names := make(map[string]bool)
+	for _, s := range slos {
+		if _, err := newTracker(s); err != nil {
+			return err
+		}
+		if names[s.Name] {
+			return fmt.Errorf("SLO %q is defined more than once", s.Name)
+		}
+		names[s.Name] = true
+	}
+	return nil
+}
+
+// newTracker returns the tracker of a valid SLO.
+func newTracker(s models.SLO) (*tracker, error) {
+	if s.Name == "" {
+		return nil, errors.New("SLOs must have a name")
+	}
+	if s.Type != TypeAvailability && s.Type != TypeLatency {
+		return nil, fmt.Errorf("SLO %q has unknown type %q, expected %s or %s", s.Name, s.Type, TypeAvailability, TypeLatency)
+	}
+	if s.Objective <= 0 || s.Objective >= 100 {
+		return nil, fmt.Errorf("SLO %q must have an objective between 0 and 100, e.g. 99.9", s.Name)
+	}
+	window, err := common.ParseDuration(common.DefaultIfEmpty(s.Window, DefaultWindow))
+	if err != nil || window <= 0 {
+		return nil, fmt.Errorf("SLO %q has an invalid window %q, e.g. '30d' or '24h'", s.Name, s.Window)
+	}
+	if (s.Metric == "") == (s.Function == "") {
+		return nil, fmt.Errorf("SLO %q must count either a metric or a function", s.Name)
+	}
+	if s.Type == TypeLatency && s.Threshold <= 0 {
+		return nil, fmt.Errorf("latency SLO %q must have a positive threshold in seconds", s.Name)
+	}
+	if s.Type == TypeAvailability && s.Metric != "" && s.ErrorMetric == "" && len(s.ErrorMatch) == 0 {
+		return nil, fmt.Errorf("availability SLO %q must have an error metric or error labels", s.Name)
+	}
+
+	t := &tracker{slo: s, window: window}
+	if t.match, err = compileMatch(s.Match); err != nil {
+		return nil, fmt.Errorf("SLO %q: %w", s.Name, err)
+	}
+	if t.errorMatch, err = compileMatch(s.ErrorMatch); err != nil {
+		return nil, fmt.Errorf("SLO %q: %w", s.Name, err)
+	}
+	t.slo.Window = common.DefaultIfEmpty(s.Window, DefaultWindow)
+	return t, nil
+}
+
+// compileMatch compiles label matchers, anchored so they match whole values.
+func compileMatch(match map[string]string) (map[string]*regexp.Regexp, error) {
+	compiled := make(map[string]*regexp.Regexp, len(match))
+	for name, pattern := range match {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q for label %q", pattern, name)
+		}
+		compiled[name] = re
+	}
+	return compiled, nil
+}
+
+// Start evaluates the SLOs every interval until Stop is called, restoring the events recorded
+// within their windows. Calling it again restarts the evaluation with the new SLOs.
+func Start(slos []models.SLO, cfg models.SLOConfig) error {
+	if err := ValidateSLOs(slos); err != nil {
+		return err
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+
+	Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	now := time.Now()
+	config = cfg
+	trackers = make([]*tracker, len(slos))
+	var counting []*tracker
+	for i, s := range slos {
+		t, _ := newTracker(s)
+		t.restore(now)
+		if t.slo.Function != "" {
+			counting = append(counting, t)
+		} else {
+			t.lastGood, t.lastTotal = t.countMetric(registry.Default().GetAll())
+		}
+		t.summarize(now)
+		trackers[i] = t
+	}
+
+	callsMu.Lock()
+	callTrackers = counting
+	callsMu.Unlock()
+	if len(counting) > 0 {
+		core.SetFunctionCallObserver(observeCall)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	stopEval, evalDone = cancel, done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				evaluate(now)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops evaluating the SLOs and counting traced calls. Safe to call multiple times.
+func Stop() {
+	mu.Lock()
+	cancel, done := stopEval, evalDone
+	stopEval, evalDone = nil, nil
+	mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	core.SetFunctionCallObserver(nil)
+	callsMu.Lock()
+	callTrackers = nil
+	callsMu.Unlock()
+}
+
+// GetStatuses returns the status of every SLO, in the order they were defined.
+func GetStatuses() []models.SLOStatus {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := make([]models.SLOStatus, len(trackers))
+	for i, t := range trackers {
+		result[i] = t.status
+	}
+	return result
+}
+
+// functionBaseName returns a traced function's name without the parameter and result types
+// added when it is traced with arguments.
+func functionBaseName(name string) string {
+	base, _, _ := strings.Cut(name, "(")
+	return strings.TrimSuffix(base, "->")
+}
+
+// observeCall counts a traced call in the SLOs of its function.
+func observeCall(name string, elapsed time.Duration, failed bool) {
+	base := functionBaseName(name)
+
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	for _, t := range callTrackers {
+		if t.slo.Function != name && t.slo.Function != base {
+			continue
+		}
+		t.calls.total++
+		if t.slo.Type == TypeAvailability && !failed || t.slo.Type == TypeLatency && elapsed.Seconds() <= t.slo.Threshold {
+			t.calls.good++
+		}
+	}
+}
+
+// matches reports whether every matcher matches the value of its label.
+func matches(labels map[string]string, matchers map[string]*regexp.Regexp) bool {
+	for name, re := range matchers {
+		if !re.MatchString(labels[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// countMetric returns the cumulative good and total events of the SLO's metric series.
+func (t *tracker) countMetric(values []*registry.MetricValue) (good, total float64) {
+	var bad float64
+	errorMetric := common.DefaultIfEmpty(t.slo.ErrorMetric, t.slo.Metric)
+	for _, v := range values {
+		switch {
+		case t.slo.Type == TypeLatency:
+			if v.Name != t.slo.Metric || v.Type != registry.Histogram || !matches(v.Labels, t.match) {
+				continue
+			}
+			// Requests are good up to the highest bucket bound within the threshold
+			var within uint64
+			for i, bound := range v.Buckets {
+				if bound <= t.slo.Threshold {
+					within = v.BucketCounts[i]
+				}
+			}
+			good += float64(within)
+			total += float64(v.Count)
+		case v.Type != registry.Counter:
+			continue
+		default:
+			if v.Name == t.slo.Metric && matches(v.Labels, t.match) {
+				total += v.Value
+			}
+			// A separate error counter has labels of its own, so only the error labels select it
+			if v.Name == errorMetric && matches(v.Labels, t.errorMatch) && (errorMetric != t.slo.Metric || matches(v.Labels, t.match)) {
+				bad += v.Value
+			}
+		}
+	}
+	if t.slo.Type == TypeAvailability {
+		good = max(total-bad, 0)
+	}
+	return good, total
+}
+
+// increase returns how much a cumulative count increased, treating a decrease as a reset.
+func increase(current, last float64) float64 {
+	if current < last {
+		return current
+	}
+	return current - last
+}
+
+// evaluate counts the events since the previous evaluation of every SLO, updates their
+// statuses and stores them as series.
+func evaluate(now time.Time) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	values := registry.Default().GetAll()
+	callsMu.Lock()
+	counted := make([]bucket, len(trackers))
+	for i, t := range trackers {
+		if t.slo.Function != "" {
+			counted[i], t.calls = t.calls, bucket{}
+		}
+	}
+	callsMu.Unlock()
+
+	var rows []timeseries.Row
+	for i, t := range trackers {
+		b := counted[i]
+		if t.slo.Function == "" {
+			good, total := t.countMetric(values)
+			b = bucket{good: increase(good, t.lastGood), total: increase(total, t.lastTotal)}
+			b.good = min(b.good, b.total)
+			t.lastGood, t.lastTotal = good, total
+		}
+		b.at = now.Unix()
+		t.buckets = append(t.buckets, b)
+
+		t.summarize(now)
+		rows = append(rows, t.rows(b)...)
+	}
+
+	if len(rows) == 0 {
+		return
+	}
+	sto, err := timeseries.GetStorageInstance()
+	if err == nil {
+		err = sto.InsertRows(rows)
+	}
+	if err != nil {
+		logger.Log.Error("failed to store SLO series", "error", err)
+	}
+}
+
+// sum returns the events counted after from.
+func (t *tracker) sum(from int64) (good, total float64) {
+	for i := len(t.buckets) - 1; i >= 0 && t.buckets[i].at > from; i-- {
+		good += t.buckets[i].good
+		total += t.buckets[i].total
+	}
+	return good, total
+}
+
+// burnRate returns the error rate of the events relative to the rate the objective allows.
+func (t *tracker) burnRate(good, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (total - good) / total / (1 - t.slo.Objective/100)
+}
+
+// summarize drops the buckets older than the window and computes the status from the others.
+func (t *tracker) summarize(now time.Time) {
+	windowStart := now.Add(-t.window).Unix()
+	drop := 0
+	for drop < len(t.buckets) && t.buckets[drop].at <= windowStart {
+		drop++
+	}
+	t.buckets = t.buckets[drop:]
+
+	good, total := t.sum(windowStart)
+	status := models.SLOStatus{
+		Name:                 t.slo.Name,
+		Description:          t.slo.Description,
+		Type:                 t.slo.Type,
+		Objective:            t.slo.Objective,
+		Window:               t.slo.Window,
+		GoodEvents:           good,
+		TotalEvents:          total,
+		Attainment:           100,
+		ErrorBudgetRemaining: 100,
+		BurnRates:            make(map[string]float64),
+		Since:                time.Unix(windowStart, 0),
+		UpdatedAt:            now,
+	}
+	if len(t.buckets) > 0 && t.buckets[0].at > windowStart {
+		status.Since = time.Unix(t.buckets[0].at, 0)
+	}
+	if total > 0 {
+		status.Attainment = good / total * 100
+		status.ErrorBudgetRemaining = (1 - t.burnRate(good, total)) * 100
+	}
+
+	for _, w := range burnWindows {
+		if w.duration > t.window {
+			continue
+		}
+		status.BurnRates[w.name] = t.burnRate(t.sum(now.Add(-w.duration).Unix()))
+	}
+	status.FastBurn = status.BurnRates["1h"] >= FastBurnRate && status.BurnRates["5m"] >= FastBurnRate
+	status.SlowBurn = status.BurnRates["6h"] >= SlowBurnRate && status.BurnRates["30m"] >= SlowBurnRate
+	t.status = status
+}
+
+// labels returns the labels of the SLO's series.
+func (t *tracker) labels(extra ...timeseries.Label) []timeseries.Label {
+	return append([]timeseries.Label{timeseries.GetHostLabel(), {Name: "slo", Value: t.slo.Name}}, extra...)
+}
+
+// rows returns the series of an evaluation: the events counted, so the window can be restored
+// after a restart, the attainment, the error budget left and the burn rates.
+func (t *tracker) rows(b bucket) []timeseries.Row {
+	row := func(metric string, value float64, extra ...timeseries.Label) timeseries.Row {
+		return timeseries.Row{Metric: metric, Labels: t.labels(extra...), DataPoint: timeseries.DataPoint{Timestamp: b.at, Value: value}}
+	}
+	rows := []timeseries.Row{
+		row("slo_good_events", b.good),
+		row("slo_total_events", b.total),
+		row("slo_attainment", t.status.Attainment),
+		row("slo_error_budget_remaining", t.status.ErrorBudgetRemaining),
+	}
+	for _, w := range burnWindows {
+		if rate, ok := t.status.BurnRates[w.name]; ok {
+			rows = append(rows, row("slo_burn_rate", rate, timeseries.Label{Name: "window", Value: w.name}))
+		}
+	}
+	return rows
+}
+
+// restore loads the events stored within the window by a previous run.
+func (t *tracker) restore(now time.Time) {
+	start, end := now.Add(-t.window).Unix()+1, now.Unix()
+	good, err := timeseries.GetDataPoints("slo_good_events", t.labels(), start, end)
+	if err != nil && !errors.Is(err, timeseries.ErrNoDataPoints) {
+		logger.Log.Warn("failed to restore SLO events", "slo", t.slo.Name, "error", err)
+		return
+	}
+	total, err := timeseries.GetDataPoints("slo_total_events", t.labels(), start, end)
+	if err != nil && !errors.Is(err, timeseries.ErrNoDataPoints) {
+		logger.Log.Warn("failed to restore SLO events", "slo", t.slo.Name, "error", err)
+		return
+	}
+
+	byTime := make(map[int64]*bucket, len(total))
+	for _, p := range total {
+		byTime[p.Timestamp] = &bucket{at: p.Timestamp, total: p.Value}
+	}
+	for _, p := range good {
+		if b, ok := byTime[p.Timestamp]; ok {
+			b.good = min(p.Value, b.total)
+		}
+	}
+
+	t.buckets = t.buckets[:0]
+	for _, b := range byTime {
+		t.buckets = append(t.buckets, *b)
+	}
+	slices.SortFunc(t.buckets, func(a, b bucket) int { return cmp.Compare(a.at, b.at) })
+}
@@ -0,0 +1,251 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/core"
+	"github.com/iyashjayesh/monigo/internal/registry"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/iyashjayesh/monigo/timeseries"
+)
+
+// withTrackers evaluates the SLOs against the in-memory storage for the duration of the test,
+// without starting the evaluation loop.
+func withTrackers(t *testing.T, slos ...models.SLO) {
+	t.Helper()
+	timeseries.SetStorageType("memory")
+
+	mu.Lock()
+	defer mu.Unlock()
+	trackers = nil
+	for _, s := range slos {
+		tr, err := newTracker(s)
+		if err != nil {
+			t.Fatalf("newTracker error: %v", err)
+		}
+		trackers = append(trackers, tr)
+	}
+	t.Cleanup(func() {
+		Stop()
+		mu.Lock()
+		trackers = nil
+		mu.Unlock()
+	})
+}
+
+func TestValidateSLOs(t *testing.T) {
+	valid := models.SLO{Name: "checkout", Type: TypeAvailability, Objective: 99.9, Metric: "requests_total", ErrorMatch: map[string]string{"code": "5.."}}
+	if err := ValidateSLOs([]models.SLO{valid, {Name: "fast", Type: TypeLatency, Objective: 99, Window: "7d", Function: "main.checkout", Threshold: 0.3}}); err != nil {
+		t.Fatalf("expected valid SLOs, got %v", err)
+	}
+
+	tests := map[string]models.SLO{
+		"missing name":      {Type: TypeAvailability, Objective: 99, Metric: "a", ErrorMetric: "b"},
+		"unknown type":      {Name: "a", Type: "errors", Objective: 99, Metric: "a", ErrorMetric: "b"},
+		"objective of 100":  {Name: "a", Type: TypeAvailability, Objective: 100, Metric: "a", ErrorMetric: "b"},
+		"invalid window":    {Name: "a", Type: TypeAvailability, Objective: 99, Window: "a month", Metric: "a", ErrorMetric: "b"},
+		"no source":         {Name: "a", Type: TypeAvailability, Objective: 99},
+		"two sources":       {Name: "a", Type: TypeAvailability, Objective: 99, Metric: "a", ErrorMetric: "b", Function: "main.f"},
+		"no threshold":      {Name: "a", Type: TypeLatency, Objective: 99, Metric: "a"},
+		"no errors":         {Name: "a", Type: TypeAvailability, Objective: 99, Metric: "a"},
+		"invalid error tag": {Name: "a", Type: TypeAvailability, Objective: 99, Metric: "a", ErrorMatch: map[string]string{"code": "5(("}},
+	}
+	for name, s := range tests {
+		if err := ValidateSLOs([]models.SLO{s}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if err := ValidateSLOs([]models.SLO{valid, valid}); err == nil {
+		t.Error("expected an error for a duplicate name")
+	}
+}
+
+func TestCountMetric(t *testing.T) {
+	r := registry.NewRegistry()
+	r.IncrementCounter("http_requests_total", 90, map[string]string{"route": "/checkout", "code": "200"})
+	r.IncrementCounter("http_requests_total", 6, map[string]string{"route": "/checkout", "code": "503"})
+	r.IncrementCounter("http_requests_total", 4, map[string]string{"route": "/checkout", "code": "500"})
+	r.IncrementCounter("http_requests_total", 50, map[string]string{"route": "/health", "code": "500"})
+	r.IncrementCounter("http_errors_total", 3, map[string]string{"route": "/checkout"})
+	for _, d := range []float64{0.05, 0.2, 0.2, 0.9} {
+		r.RecordHistogram("http_request_duration_seconds", d, map[string]string{"route": "/checkout"})
+	}
+	r.RecordHistogram("http_request_duration_seconds", 0.01, map[string]string{"route": "/health"})
+
+	tests := []struct {
+		name        string
+		slo         models.SLO
+		good, total float64
+	}{
+		{"error labels", models.SLO{Type: TypeAvailability, Metric: "http_requests_total", Match: map[string]string{"route": "/checkout"}, ErrorMatch: map[string]string{"code": "5.."}}, 90, 100},
+		{"error counter", models.SLO{Type: TypeAvailability, Metric: "http_requests_total", Match: map[string]string{"route": "/checkout"}, ErrorMetric: "http_errors_total"}, 97, 100},
+		{"latency", models.SLO{Type: TypeLatency, Metric: "http_request_duration_seconds", Match: map[string]string{"route": "/checkout"}, Threshold: 0.25}, 3, 4},
+		{"all routes", models.SLO{Type: TypeLatency, Metric: "http_request_duration_seconds", Threshold: 0.3}, 4, 5},
+	}
+	for _, tt := range tests {
+		tt.slo.Name, tt.slo.Objective = tt.name, 99
+		tr, err := newTracker(tt.slo)
+		if err != nil {
+			t.Fatalf("%s: newTracker error: %v", tt.name, err)
+		}
+		if good, total := tr.countMetric(r.GetAll()); good != tt.good || total != tt.total {
+			t.Errorf("%s: expected %v good of %v, got %v of %v", tt.name, tt.good, tt.total, good, total)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tr, _ := newTracker(models.SLO{Name: "checkout", Type: TypeAvailability, Objective: 99, Window: "1d", Function: "main.checkout"})
+	now := time.Now()
+	at := func(ago time.Duration) int64 { return now.Add(-ago).Unix() }
+	tr.buckets = []bucket{
+		{at: at(48 * time.Hour), good: 0, total: 1000}, // Outside the window
+		{at: at(12 * time.Hour), good: 990, total: 1000},
+		{at: at(2 * time.Minute), good: 80, total: 100},
+	}
+	tr.summarize(now)
+
+	s := tr.status
+	if len(tr.buckets) != 2 || s.TotalEvents != 1100 || s.GoodEvents != 1070 {
+		t.Fatalf("expected the buckets outside the window to be dropped, got %+v", s)
+	}
+	if math.Abs(s.Attainment-1070.0/1100*100) > 1e-9 {
+		t.Errorf("unexpected attainment %v", s.Attainment)
+	}
+	// 30 failures of the 11 allowed
+	if math.Abs(s.ErrorBudgetRemaining-(1-30.0/11)*100) > 1e-9 {
+		t.Errorf("unexpected error budget remaining %v", s.ErrorBudgetRemaining)
+	}
+	if math.Abs(s.BurnRates["5m"]-20) > 1e-9 || math.Abs(s.BurnRates["1h"]-20) > 1e-9 || !s.FastBurn || !s.SlowBurn {
+		t.Errorf("expected a fast burn over 5m and 1h, got %+v", s)
+	}
+	if _, ok := s.BurnRates["3d"]; ok {
+		t.Errorf("expected no burn rate over a window longer than the SLO's, got %v", s.BurnRates)
+	}
+	if !s.Since.Equal(time.Unix(at(12*time.Hour), 0)) {
+		t.Errorf("expected the oldest event within the window as start, got %v", s.Since)
+	}
+
+	tr.buckets = nil
+	tr.summarize(now)
+	if tr.status.Attainment != 100 || tr.status.ErrorBudgetRemaining != 100 || tr.status.FastBurn {
+		t.Errorf("expected a full budget without events, got %+v", tr.status)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	withTrackers(t, models.SLO{
+		Name: "slo_test_checkout", Type: TypeAvailability, Objective: 99.9,
+		Metric: "slo_test_requests_total", ErrorMatch: map[string]string{"code": "5.."},
+	})
+	requests := func(code string, n float64) {
+		registry.Default().IncrementCounter("slo_test_requests_total", n, map[string]string{"code": code})
+	}
+	requests("200", 500) // Before the SLO started, not counted
+
+	mu.Lock()
+	trackers[0].lastGood, trackers[0].lastTotal = trackers[0].countMetric(registry.Default().GetAll())
+	mu.Unlock()
+
+	now := time.Now()
+	requests("200", 998)
+	requests("502", 2)
+	evaluate(now)
+
+	statuses := GetStatuses()
+	if len(statuses) != 1 || statuses[0].TotalEvents != 1000 || statuses[0].GoodEvents != 998 {
+		t.Fatalf("expected the requests since the start to be counted, got %+v", statuses)
+	}
+	if math.Abs(statuses[0].BurnRates["1h"]-2) > 1e-9 || math.Abs(statuses[0].ErrorBudgetRemaining+100) > 1e-6 {
+		t.Errorf("expected a burn rate of 2 and an exhausted budget, got %+v", statuses[0])
+	}
+
+	points, err := timeseries.GetDataPoints("slo_attainment", []timeseries.Label{timeseries.GetHostLabel(), {Name: "slo", Value: "slo_test_checkout"}}, now.Unix(), now.Unix())
+	if err != nil || !slices.Contains(points, timeseries.DataPoint{Timestamp: now.Unix(), Value: 99.8}) {
+		t.Errorf("expected the attainment to be stored, got %v, %v", points, err)
+	}
+}
+
+func checkout(fail bool) error {
+	if fail {
+		return errors.New("payment declined")
+	}
+	return nil
+}
+
+func TestFunctionSLO(t *testing.T) {
+	timeseries.SetStorageType("memory")
+	core.SetSamplingRate(math.MaxInt32) // No profiles of the traced calls
+	defer core.SetSamplingRate(100)
+
+	err := Start([]models.SLO{
+		{Name: "checkout", Type: TypeAvailability, Objective: 99, Function: "github.com-iyashjayesh-monigo-slo.checkout"},
+		{Name: "checkout-latency", Type: TypeLatency, Objective: 99, Function: "github.com-iyashjayesh-monigo-slo.checkout", Threshold: 60},
+	}, models.SLOConfig{Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("Start error: %v", err)
+	}
+	defer Stop()
+	// The in-memory storage ignores labels, so the events stored by other tests are restored
+	before := GetStatuses()
+
+	for _, fail := range []bool{false, false, true} {
+		core.TraceFunctionWithReturns(context.Background(), checkout, fail)
+	}
+	evaluate(time.Now())
+
+	statuses := GetStatuses()
+	if statuses[0].TotalEvents-before[0].TotalEvents != 3 || statuses[0].GoodEvents-before[0].GoodEvents != 2 {
+		t.Errorf("expected the failed call to be bad, got %+v", statuses[0])
+	}
+	if statuses[1].TotalEvents-before[1].TotalEvents != 3 || statuses[1].GoodEvents-before[1].GoodEvents != 3 {
+		t.Errorf("expected every call to be fast enough, got %+v", statuses[1])
+	}
+
+	Stop()
+	core.TraceFunctionWithReturns(context.Background(), checkout, true)
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	if callTrackers != nil {
+		t.Error("expected Stop to stop counting calls")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	timeseries.SetStorageType("memory")
+	sto, err := timeseries.GetStorageInstance()
+	if err != nil {
+		t.Fatalf("GetStorageInstance error: %v", err)
+	}
+
+	// Far in the past so the events of the other tests are outside the window
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	tr, _ := newTracker(models.SLO{Name: "restored", Type: TypeAvailability, Objective: 99, Window: "1h", Function: "main.f"})
+	for _, b := range []bucket{{at: now.Add(-2 * time.Hour).Unix(), good: 1, total: 1}, {at: now.Add(-time.Minute).Unix(), good: 8, total: 10}, {at: now.Add(-2 * time.Minute).Unix(), good: 5, total: 5}} {
+		if err := sto.InsertRows(tr.rows(b)[:2]); err != nil {
+			t.Fatalf("InsertRows error: %v", err)
+		}
+	}
+
+	tr.restore(now)
+	if len(tr.buckets) != 2 || tr.buckets[0].total != 5 || tr.buckets[1].good != 8 {
+		t.Errorf("expected the events within the window oldest first, got %+v", tr.buckets)
+	}
+}
+
+func TestFunctionBaseName(t *testing.T) {
+	for name, want := range map[string]string{
+		"main.checkout":                      "main.checkout",
+		"main.checkout(string,int)->(error)": "main.checkout",
+		"main.checkout->(error)":             "main.checkout",
+	} {
+		if got := functionBaseName(name); got != want {
+			t.Errorf("functionBaseName(%q) = %q, expected %q", name, got, want)
+		}
+	}
+}
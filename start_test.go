@@ -0,0 +1,162 @@
+package monigo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/timeseries"
+	"github.com/klauspost/compress/s2"
+)
+
+// startTestEnv names the test a subprocess started by runStartTest runs.
+const startTestEnv = "MONIGO_START_TEST"
+
+// runStartTest runs test in a subprocess of its own, inside a temporary working directory, as
+// the storage is opened once per process with the settings of the first Start().
+func runStartTest(t *testing.T, test func(t *testing.T)) {
+	t.Helper()
+	if os.Getenv(startTestEnv) == t.Name() {
+		t.Chdir(t.TempDir())
+		test(t)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^"+t.Name()+"$", "-test.count=1", "-test.v")
+	cmd.Env = append(os.Environ(), startTestEnv+"="+t.Name())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("subprocess failed: %v\n%s", err, out)
+	}
+}
+
+// startHeadless starts the built instance without its dashboard, shutting it down at the end of the test.
+func startHeadless(t *testing.T, b *MonigoBuilder) *Monigo {
+	t.Helper()
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	m := b.WithPort(port).WithHeadless(true).Build()
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := m.Shutdown(context.Background()); err != nil {
+			t.Errorf("Shutdown() failed: %v", err)
+		}
+	})
+	return m
+}
+
+func TestStartBoltStorage(t *testing.T) {
+	runStartTest(t, func(t *testing.T) {
+		startHeadless(t, NewBuilder().WithServiceName("test").WithStorageType("bolt"))
+
+		basePath := common.GetBasePath()
+		if _, err := os.Stat(filepath.Join(basePath, timeseries.BoltFileName)); err != nil {
+			t.Errorf("expected the bolt database: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(basePath, "data")); !os.IsNotExist(err) {
+			t.Errorf("expected no disk storage directory, got %v", err)
+		}
+	})
+}
+
+func TestStartRemoteWrite(t *testing.T) {
+	runStartTest(t, func(t *testing.T) {
+		requests := make(chan []byte, 1)
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Content-Encoding") == "snappy" {
+				select {
+				case requests <- body:
+				default:
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		startHeadless(t, NewBuilder().
+			WithServiceName("test").
+			WithStorageType("memory").
+			WithRemoteWrite(RemoteWriteConfig{URL: receiver.URL, Labels: map[string]string{"job": "start-test"}, BatchSize: 1}))
+
+		select {
+		case body := <-requests:
+			data, err := s2.Decode(nil, body)
+			if err != nil {
+				t.Fatalf("decoding snappy: %v", err)
+			}
+			if !bytes.Contains(data, []byte("start-test")) {
+				t.Errorf("expected the job label in the write request, got %q", data)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("expected a remote write request")
+		}
+	})
+}
+
+func TestStartRollupTiers(t *testing.T) {
+	runStartTest(t, func(t *testing.T) {
+		startHeadless(t, NewBuilder().
+			WithServiceName("test").
+			WithRollupTiers(RollupTier{Resolution: "1m", Retention: "1d"}, RollupTier{Resolution: "1h", Retention: "7d"}))
+
+		// The 1m tier is finer than the default 5m sync frequency
+		rollups := filepath.Join(common.GetBasePath(), "rollups")
+		entries, err := os.ReadDir(rollups)
+		if err != nil || len(entries) != 1 || entries[0].Name() != "1h" {
+			t.Errorf("expected only the 1h rollup storage, got %v (%v)", entries, err)
+		}
+	})
+}
+
+func TestStartDisableRollups(t *testing.T) {
+	runStartTest(t, func(t *testing.T) {
+		startHeadless(t, NewBuilder().WithServiceName("test").WithDisableRollups(true))
+
+		if _, err := os.Stat(filepath.Join(common.GetBasePath(), "rollups")); !os.IsNotExist(err) {
+			t.Errorf("expected no rollup storage, got %v", err)
+		}
+	})
+}
+
+func TestStartMaxMemoryPoints(t *testing.T) {
+	runStartTest(t, func(t *testing.T) {
+		startHeadless(t, NewBuilder().
+			WithServiceName("test").
+			WithStorageType("memory").
+			WithMaxMemoryPoints(10).
+			WithDisableRollups(true))
+
+		sto, err := timeseries.GetStorageInstance()
+		if err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now().Unix()
+		for i := range 20 {
+			if err := sto.InsertRows([]timeseries.Row{{Metric: "capped", DataPoint: timeseries.DataPoint{Timestamp: now + 1 + int64(i), Value: float64(i)}}}); err != nil {
+				t.Fatalf("InsertRows error: %v", err)
+			}
+		}
+
+		// The oldest points are evicted beyond the cap, the service metrics stored at start first
+		points, err := sto.Select("capped", nil, 0, now+20)
+		if err != nil || len(points) != 10 || points[0].Value != 10 {
+			t.Errorf("expected the 10 newest points, got %v (%v)", points, err)
+		}
+	})
+}
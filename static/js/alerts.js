@@ -0,0 +1,140 @@
+document.addEventListener('DOMContentLoaded', () => {
+    // Function to get API key from URL parameters
+    function getApiKey() {
+        const urlParams = new URLSearchParams(window.location.search);
+        return urlParams.get('api_key');
+    }
+
+    // Function to add API key to fetch URL (only for API key auth)
+    function addApiKeyToUrl(url) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            const separator = url.includes('?') ? '&' : '?';
+            return `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        }
+        return url;
+    }
+
+    // Function to make authenticated fetch request
+    function authenticatedFetch(url, options = {}) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            // API key authentication - add to URL
+            const separator = url.includes('?') ? '&' : '?';
+            url = `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        } else {
+            // Check for custom authentication methods
+            const urlParams = new URLSearchParams(window.location.search);
+            const secret = urlParams.get('secret');
+
+            if (secret === 'monigo-admin-secret') {
+                // Custom query parameter authentication
+                const separator = url.includes('?') ? '&' : '?';
+                url = `${url}${separator}secret=${encodeURIComponent(secret)}`;
+            } else {
+                // Check for custom header authentication
+                // For custom auth, we need to add headers
+                if (!options.headers) {
+                    options.headers = {};
+                }
+
+                // Add custom header for admin access
+                options.headers['X-User-Role'] = 'admin';
+
+                // Set custom user agent for automated access
+                options.headers['User-Agent'] = 'MoniGo-Admin/1.0';
+            }
+        }
+        // For basic auth, the browser handles credentials automatically
+        return fetch(url, options);
+    }
+
+    const apiBase = '/monigo/api/v1';
+
+    // Function to escape text inserted into the page
+    function escapeHtml(value) {
+        return String(value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    // Function to raise the error text returned by the API
+    function checkResponse(response) {
+        if (!response.ok) {
+            return response.text().then(text => {
+                throw new Error(text.trim() || response.statusText);
+            });
+        }
+        return response;
+    }
+
+    const stateClass = { firing: 'text-danger', pending: 'text-warning', resolved: 'text-success' };
+
+    function formatLabels(labels) {
+        return Object.entries(labels || {})
+            .map(([name, value]) => `<code>${escapeHtml(name)}=${escapeHtml(value)}</code>`)
+            .join(' ');
+    }
+
+    function formatTime(time) {
+        return time ? new Date(time).toLocaleString() : '-';
+    }
+
+    function fetchAlerts() {
+        const state = document.getElementById('alerts-state-select').value;
+        authenticatedFetch(`${apiBase}/alerts?state=${encodeURIComponent(state)}`)
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(renderAlerts)
+            .catch((error) => console.error('Error:', error));
+    }
+
+    function renderAlerts(alerts) {
+        document.getElementById('alerts-table').innerHTML = alerts.map(a => {
+            const since = a.state === 'firing' ? a.fired_at : a.state === 'resolved' ? a.resolved_at : a.active_at;
+            const value = a.value === undefined || a.value === null ? (a.error ? escapeHtml(a.error) : '-') : escapeHtml(a.value);
+            return `<tr>
+                <td class="${stateClass[a.state] || ''}"><strong>${escapeHtml(a.state)}</strong></td>
+                <td>${escapeHtml(a.rule)}</td>
+                <td><code>${escapeHtml(a.metric)} ${escapeHtml(a.op)} ${escapeHtml(a.threshold)}</code></td>
+                <td>${value}</td>
+                <td>${escapeHtml(formatTime(since))}</td>
+                <td>${formatLabels(a.labels)}</td>
+                <td>${escapeHtml(a.description || '')}</td>
+            </tr>`;
+        }).join('');
+        document.getElementById('alerts-empty').style.display = alerts.length ? 'none' : 'block';
+    }
+
+    function fetchRules() {
+        authenticatedFetch(`${apiBase}/alerts/rules`)
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(renderRules)
+            .catch((error) => console.error('Error:', error));
+    }
+
+    function renderRules(rules) {
+        document.getElementById('alert-rules-table').innerHTML = rules.map(r => `<tr>
+                <td>${escapeHtml(r.name)}</td>
+                <td>${escapeHtml(r.metric)}</td>
+                <td>${formatLabels(r.match)}</td>
+                <td><code>${escapeHtml(r.op)} ${escapeHtml(r.threshold)}</code></td>
+                <td>${escapeHtml(r.for || '0s')}</td>
+                <td>${formatLabels(r.labels)}</td>
+            </tr>`).join('');
+        document.getElementById('alert-rules-empty').style.display = rules.length ? 'none' : 'block';
+    }
+
+    document.getElementById('alerts-state-select').addEventListener('change', fetchAlerts);
+    document.getElementById('refresh-btn').addEventListener('click', () => {
+        fetchAlerts();
+        fetchRules();
+    });
+
+    fetchAlerts();
+    fetchRules();
+    setInterval(fetchAlerts, 30000);
+});
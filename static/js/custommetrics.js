@@ -0,0 +1,225 @@
+document.addEventListener('DOMContentLoaded', () => {
+    // Function to get API key from URL parameters
+    function getApiKey() {
+        const urlParams = new URLSearchParams(window.location.search);
+        return urlParams.get('api_key');
+    }
+
+    // Function to add API key to fetch URL (only for API key auth)
+    function addApiKeyToUrl(url) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            const separator = url.includes('?') ? '&' : '?';
+            return `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        }
+        return url;
+    }
+
+    // Function to make authenticated fetch request
+    function authenticatedFetch(url, options = {}) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            // API key authentication - add to URL
+            const separator = url.includes('?') ? '&' : '?';
+            url = `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        } else {
+            // Check for custom authentication methods
+            const urlParams = new URLSearchParams(window.location.search);
+            const secret = urlParams.get('secret');
+
+            if (secret === 'monigo-admin-secret') {
+                // Custom query parameter authentication
+                const separator = url.includes('?') ? '&' : '?';
+                url = `${url}${separator}secret=${encodeURIComponent(secret)}`;
+            } else {
+                // Check for custom header authentication
+                // For custom auth, we need to add headers
+                if (!options.headers) {
+                    options.headers = {};
+                }
+
+                // Add custom header for admin access
+                options.headers['X-User-Role'] = 'admin';
+
+                // Set custom user agent for automated access
+                options.headers['User-Agent'] = 'MoniGo-Admin/1.0';
+            }
+        }
+        // For basic auth, the browser handles credentials automatically
+        return fetch(url, options);
+    }
+
+    const apiBase = '/monigo/api/v1';
+    let customMetrics = [];
+
+    // Function to escape text inserted into the page
+    function escapeHtml(value) {
+        return String(value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    function formatLabels(labels) {
+        return Object.entries(labels || {}).map(([k, v]) => `${k}="${v}"`).join(', ');
+    }
+
+    function formatNumber(value) {
+        return Number(value || 0).toLocaleString(undefined, { maximumFractionDigits: 4 });
+    }
+
+    // Function to get the local ISO string with timezone offset
+    function toLocalISOString(date) {
+        const tzOffset = -date.getTimezoneOffset();
+        const diff = tzOffset >= 0 ? '+' : '-';
+        const pad = (num) => `${Math.floor(Math.abs(num))}`.padStart(2, '0');
+
+        return date.getFullYear() +
+            '-' + pad(date.getMonth() + 1) +
+            '-' + pad(date.getDate()) +
+            'T' + pad(date.getHours()) +
+            ':' + pad(date.getMinutes()) +
+            ':' + pad(date.getSeconds()) +
+            diff + pad(tzOffset / 60) + ':' + pad(tzOffset % 60);
+    }
+
+    const timeRangeMinutes = { '5m': 5, '15m': 15, '30m': 30, '1h': 60, '6h': 360, '1d': 1440, '3d': 4320, '7d': 10080 };
+
+    function fetchCustomMetrics() {
+        authenticatedFetch(`${apiBase}/custom-metrics`)
+            .then(response => response.json())
+            .then(data => {
+                customMetrics = data || [];
+                renderTable();
+                renderMetricSelect();
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    function renderTable() {
+        const tbody = document.getElementById('custom-metrics-table');
+        document.getElementById('custom-metrics-empty').style.display = customMetrics.length ? 'none' : '';
+
+        tbody.innerHTML = customMetrics.map(m => {
+            const isHistogram = m.type === 'histogram';
+            const quantiles = isHistogram
+                ? ['0.5', '0.95', '0.99'].map(q => formatNumber((m.quantiles || {})[q])).join(' / ')
+                : '-';
+            return `<tr>
+                <td>${escapeHtml(m.name)}</td>
+                <td>${escapeHtml(m.type)}</td>
+                <td>${escapeHtml(formatLabels(m.labels))}</td>
+                <td>${formatNumber(m.value)}</td>
+                <td>${isHistogram ? formatNumber(m.count) : '-'}</td>
+                <td>${isHistogram ? formatNumber(m.sum) : '-'}</td>
+                <td>${quantiles}</td>
+                <td>${escapeHtml(new Date(m.updated_at).toLocaleString())}</td>
+            </tr>`;
+        }).join('');
+    }
+
+    function renderMetricSelect() {
+        const select = document.getElementById('custom-metric-select');
+        const selected = select.value;
+        const names = [...new Set(customMetrics.map(m => m.name))];
+
+        select.innerHTML = names.map(n => `<option value="${escapeHtml(n)}">${escapeHtml(n)}</option>`).join('');
+        if (names.includes(selected)) {
+            select.value = selected;
+        }
+        updateChart();
+    }
+
+    // Function to list the stored series of a metric: histograms are charted by their quantiles
+    function seriesOf(name) {
+        const series = [];
+        customMetrics.filter(m => m.name === name).forEach(m => {
+            const labels = Object.assign({}, m.labels, { collector: 'custom' });
+            const labelText = formatLabels(m.labels);
+            if (m.type !== 'histogram') {
+                series.push({ title: labelText ? `${name}{${labelText}}` : name, labels: labels });
+                return;
+            }
+            Object.keys(m.quantiles || {}).forEach(q => {
+                const quantileText = labelText ? `${labelText}, quantile="${q}"` : `quantile="${q}"`;
+                series.push({ title: `${name}{${quantileText}}`, labels: Object.assign({}, labels, { quantile: q }) });
+            });
+        });
+        return series;
+    }
+
+    function updateChart() {
+        const name = document.getElementById('custom-metric-select').value;
+        const timeRange = document.getElementById('custom-metric-time-select').value;
+        if (!name) {
+            return;
+        }
+        document.getElementById('custom-metric-chart-title').textContent = `History: ${name}`;
+
+        const end = new Date();
+        const start = new Date(end.getTime() - timeRangeMinutes[timeRange] * 60000);
+
+        const requests = seriesOf(name).map(s => authenticatedFetch(`${apiBase}/service-metrics`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({
+                field_name: [name],
+                timerange: timeRange,
+                start_time: toLocalISOString(start),
+                end_time: toLocalISOString(end),
+                labels: s.labels
+            }),
+        }).then(response => response.json())
+            .then(points => ({
+                name: s.title,
+                type: 'line',
+                showSymbol: false,
+                data: (points || []).map(p => [new Date(p.time), p.value[name]])
+            })));
+
+        Promise.all(requests)
+            .then(renderChart)
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    function renderChart(series) {
+        const chart = echarts.init(document.getElementById('custom-metric-chart'));
+        chart.setOption({
+            tooltip: {
+                trigger: 'axis'
+            },
+            legend: {
+                data: series.map(s => s.name),
+                top: 0,
+                type: 'scroll'
+            },
+            grid: {
+                left: '3%',
+                right: '4%',
+                bottom: '3%',
+                containLabel: true
+            },
+            xAxis: {
+                type: 'time',
+                boundaryGap: false
+            },
+            yAxis: {
+                type: 'value'
+            },
+            series: series
+        }, true);
+    }
+
+    document.getElementById('custom-metric-select').addEventListener('change', updateChart);
+    document.getElementById('custom-metric-time-select').addEventListener('change', updateChart);
+    document.getElementById('refresh-btn').addEventListener('click', fetchCustomMetrics);
+
+    fetchCustomMetrics();
+});
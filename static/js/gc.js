@@ -0,0 +1,297 @@
+document.addEventListener('DOMContentLoaded', () => {
+    // Function to get API key from URL parameters
+    function getApiKey() {
+        const urlParams = new URLSearchParams(window.location.search);
+        return urlParams.get('api_key');
+    }
+
+    // Function to add API key to fetch URL (only for API key auth)
+    function addApiKeyToUrl(url) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            const separator = url.includes('?') ? '&' : '?';
+            return `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        }
+        return url;
+    }
+
+    // Function to make authenticated fetch request
+    function authenticatedFetch(url, options = {}) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            // API key authentication - add to URL
+            const separator = url.includes('?') ? '&' : '?';
+            url = `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        } else {
+            // Check for custom authentication methods
+            const urlParams = new URLSearchParams(window.location.search);
+            const secret = urlParams.get('secret');
+
+            if (secret === 'monigo-admin-secret') {
+                // Custom query parameter authentication
+                const separator = url.includes('?') ? '&' : '?';
+                url = `${url}${separator}secret=${encodeURIComponent(secret)}`;
+            } else {
+                // Check for custom header authentication
+                // For custom auth, we need to add headers
+                if (!options.headers) {
+                    options.headers = {};
+                }
+
+                // Add custom header for admin access
+                options.headers['X-User-Role'] = 'admin';
+
+                // Set custom user agent for automated access
+                options.headers['User-Agent'] = 'MoniGo-Admin/1.0';
+            }
+        }
+        // For basic auth, the browser handles credentials automatically
+        return fetch(url, options);
+    }
+
+    const apiBase = '/monigo/api/v1';
+
+    // Function to escape text inserted into the page
+    function escapeHtml(value) {
+        return String(value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    function formatBytes(bytes) {
+        const units = ['B', 'KB', 'MB', 'GB'];
+        let value = bytes || 0;
+        let unit = 0;
+        while (value >= 1024 && unit < units.length - 1) {
+            value /= 1024;
+            unit++;
+        }
+        return `${value.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`;
+    }
+
+    // Function to raise the error text returned by the API
+    function checkResponse(response) {
+        if (!response.ok) {
+            return response.text().then(text => {
+                throw new Error(text.trim() || response.statusText);
+            });
+        }
+        return response;
+    }
+
+    // Function to get the local ISO string with timezone offset
+    function toLocalISOString(date) {
+        const tzOffset = -date.getTimezoneOffset();
+        const diff = tzOffset >= 0 ? '+' : '-';
+        const pad = (num) => `${Math.floor(Math.abs(num))}`.padStart(2, '0');
+
+        return date.getFullYear() +
+            '-' + pad(date.getMonth() + 1) +
+            '-' + pad(date.getDate()) +
+            'T' + pad(date.getHours()) +
+            ':' + pad(date.getMinutes()) +
+            ':' + pad(date.getSeconds()) +
+            diff + pad(tzOffset / 60) + ':' + pad(tzOffset % 60);
+    }
+
+    const timeRangeMinutes = { '15m': 15, '1h': 60, '6h': 360, '1d': 1440, '7d': 10080 };
+
+    function setStatus(message) {
+        document.getElementById('gc-status').textContent = message;
+    }
+
+    function formatMemoryLimit(limit) {
+        return limit ? formatBytes(limit) : 'off';
+    }
+
+    function fetchInsights() {
+        authenticatedFetch(`${apiBase}/gc`)
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(renderInsights)
+            .catch((error) => {
+                console.error('Error:', error);
+                setStatus(error.message);
+            });
+    }
+
+    function renderInsights(insights) {
+        // Changing the GC requires authentication, the controls are disabled without it
+        document.querySelectorAll('#gc-controls input, #gc-controls button')
+            .forEach(el => el.disabled = !insights.controls_enabled);
+        if (!insights.controls_enabled) {
+            setStatus('Changing the GC requires authentication, configure WithAuthFunction() or authentication middleware');
+        }
+
+        const stats = insights.statistics;
+        document.getElementById('gc-percent').textContent = stats.gc_percent < 0 ? 'off' : `${stats.gc_percent}`;
+        document.getElementById('gc-memory-limit').textContent = formatMemoryLimit(stats.memory_limit);
+        document.getElementById('gc-container-limit').textContent = insights.container_memory_limit
+            ? `Container limit: ${formatBytes(insights.container_memory_limit)}` : '';
+        document.getElementById('gc-heap').textContent = `${formatBytes(stats.heap_live)} / ${formatBytes(stats.heap_goal)}`;
+        document.getElementById('gc-cycles').textContent = `${stats.cycles} (${stats.forced_cycles} forced)`;
+        document.getElementById('gc-last').textContent = stats.cycles
+            ? `Last GC: ${new Date(stats.last_gc).toLocaleString()}` : '';
+
+        const suggestions = insights.suggestions || [];
+        const severityClass = { critical: 'text-danger', warning: 'text-warning', info: '' };
+        document.getElementById('gc-suggestions-empty').style.display = suggestions.length ? 'none' : '';
+        document.getElementById('gc-suggestions').innerHTML = suggestions.map(s => `<li class="${severityClass[s.severity] || ''}">
+                <strong>${escapeHtml(s.setting)}</strong>: ${escapeHtml(s.message)}
+                ${s.suggested_value ? `(suggested: <code>${escapeHtml(s.suggested_value)}</code>)` : ''}
+            </li>`).join('');
+
+        const audit = insights.audit || [];
+        document.getElementById('gc-audit-empty').style.display = audit.length ? 'none' : '';
+        document.getElementById('gc-audit-table').innerHTML = audit.map(a => `<tr>
+                <td>${escapeHtml(new Date(a.time).toLocaleString())}</td>
+                <td>${escapeHtml(a.action)}</td>
+                <td>${escapeHtml(a.actor)}</td>
+                <td>${escapeHtml(a.old_value)}</td>
+                <td>${escapeHtml(a.new_value)}</td>
+            </tr>`).join('');
+    }
+
+    // Function to fetch the stored GC metrics of the selected range
+    function fetchHistory() {
+        const timeRange = document.getElementById('gc-time-select').value;
+        const end = new Date();
+        const start = new Date(end.getTime() - timeRangeMinutes[timeRange] * 60000);
+        const metrics = ['gc_heap_goal', 'gc_heap_live', 'gc_cycles_per_minute', 'gc_cpu_percent'];
+        const healthEvents = fetchHealthEvents(start, end);
+
+        authenticatedFetch(`${apiBase}/service-metrics`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({
+                field_name: metrics,
+                timerange: timeRange,
+                start_time: toLocalISOString(start),
+                end_time: toLocalISOString(end)
+            }),
+        }).then(response => response.json())
+            .then(points => Promise.all([points, healthEvents]))
+            .then(([points, events]) => {
+                points = points || [];
+                const series = (name, title, yAxisIndex = 0) => ({
+                    name: title,
+                    type: 'line',
+                    showSymbol: false,
+                    yAxisIndex: yAxisIndex,
+                    data: points.filter(p => p.value[name] !== undefined).map(p => [new Date(p.time), p.value[name]])
+                });
+                const heap = [series('gc_heap_goal', 'Heap Goal'), series('gc_heap_live', 'Live Heap')];
+                const activity = [series('gc_cycles_per_minute', 'Cycles/min'), series('gc_cpu_percent', 'GC CPU %', 1)];
+                heap[0].markLine = activity[0].markLine = healthEventMarkLine(events);
+                renderChart('gc-heap-chart', heap, [{ type: 'value', axisLabel: { formatter: formatBytes } }]);
+                renderChart('gc-activity-chart', activity, [{ type: 'value', name: 'cycles/min' }, { type: 'value', name: '%' }]);
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    const healthLevelOrder = ['Critical', 'Poor', 'Fair', 'Satisfactory', 'Good', 'Excellent'];
+
+    // Function to fetch the service's health level transitions between two dates, empty on errors
+    function fetchHealthEvents(start, end) {
+        const params = new URLSearchParams({ scope: 'service', from: start.toISOString(), to: end.toISOString() });
+        return authenticatedFetch(`${apiBase}/health/events?${params}`)
+            .then(response => response.ok ? response.json() : [])
+            .catch(() => []);
+    }
+
+    // Function to mark health level transitions on a chart, red when the health degraded and green when it recovered
+    function healthEventMarkLine(events) {
+        return {
+            symbol: 'none',
+            label: { formatter: p => p.name, position: 'insideEndTop', fontSize: 10 },
+            data: events.map(e => ({
+                name: `${e.from} → ${e.to} (${e.percent}%)`,
+                xAxis: new Date(e.time),
+                lineStyle: {
+                    color: healthLevelOrder.indexOf(e.to) < healthLevelOrder.indexOf(e.from) ? '#dc3545' : '#28a745',
+                    type: 'dashed'
+                }
+            }))
+        };
+    }
+
+    function renderChart(id, series, yAxis) {
+        const chart = echarts.init(document.getElementById(id));
+        chart.setOption({
+            tooltip: {
+                trigger: 'axis'
+            },
+            legend: {
+                data: series.map(s => s.name),
+                top: 0
+            },
+            grid: {
+                left: '3%',
+                right: '4%',
+                bottom: '3%',
+                containLabel: true
+            },
+            xAxis: {
+                type: 'time'
+            },
+            yAxis: yAxis,
+            series: series
+        }, true);
+    }
+
+    // Function to apply a GC control and refresh the page with the recorded change
+    function applyControl(path, confirmation) {
+        if (confirmation && !confirm(confirmation)) {
+            return;
+        }
+        setStatus('Applying...');
+        authenticatedFetch(`${apiBase}/gc/${path}`, { method: 'POST' })
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(entry => {
+                setStatus(entry.audit_error
+                    ? `Applied, but the change could not be recorded: ${entry.audit_error}`
+                    : `${entry.action}: ${entry.old_value} -> ${entry.new_value}`);
+                fetchInsights();
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+                setStatus(error.message);
+            });
+    }
+
+    document.getElementById('gc-percent-btn').addEventListener('click', () => {
+        const value = document.getElementById('gc-percent-input').value;
+        if (value === '') {
+            setStatus('Enter a GC percent, -1 turns the GC off.');
+            return;
+        }
+        applyControl(`percent?value=${encodeURIComponent(value)}`,
+            value === '-1' ? 'Turn the garbage collector off?' : null);
+    });
+    document.getElementById('gc-memory-limit-btn').addEventListener('click', () => {
+        const value = document.getElementById('gc-memory-limit-input').value;
+        if (value === '') {
+            setStatus('Enter a memory limit in MiB.');
+            return;
+        }
+        applyControl(`memory-limit?value=${Math.round(Number(value) * 1024 * 1024)}`, null);
+    });
+    document.getElementById('gc-memory-limit-off-btn').addEventListener('click', () => applyControl('memory-limit?value=off', null));
+    document.getElementById('gc-run-btn').addEventListener('click', () => applyControl('run', null));
+    document.getElementById('gc-free-btn').addEventListener('click', () => applyControl('free-os-memory', 'Run a GC and return as much memory as possible to the OS?'));
+    document.getElementById('gc-time-select').addEventListener('change', fetchHistory);
+    document.getElementById('refresh-btn').addEventListener('click', () => {
+        fetchInsights();
+        fetchHistory();
+    });
+
+    fetchInsights();
+    fetchHistory();
+});
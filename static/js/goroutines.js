@@ -51,10 +51,6 @@ document.addEventListener('DOMContentLoaded', () => {
 
     const goRoutinesNumber = document.getElementById('goroutine-count');
 
-    if (goRoutinesNumber) {
-        fetchGoRoutines();
-    }
-
     // Function to get the local ISO string with timezone offset
     function toLocalISOString(date) {
         const tzOffset = -date.getTimezoneOffset(); // in minutes
@@ -154,62 +150,269 @@ document.addEventListener('DOMContentLoaded', () => {
             });
     }
 
-    function fetchGoRoutines() {
-        authenticatedFetch(`/monigo/api/v1/go-routines-stats`)
+    const pageSize = 50;
+    let currentPage = 1;
+    let stackView = [];
+
+    // Function to escape stack traces and function names inserted into the page
+    function escapeHtml(value) {
+        return String(value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    function formatFrames(frames, createdBy) {
+        let lines = (frames || []).map(f => `${f.function}()\n\t${f.file}:${f.line}`);
+        if (createdBy) {
+            lines.push(`created by ${createdBy.function}\n\t${createdBy.file}:${createdBy.line}`);
+        }
+        return escapeHtml(lines.join('\n'));
+    }
+
+    function formatWait(min, max) {
+        if (!max) {
+            return '';
+        }
+        return min === max ? `, ${max} min` : `, ${min}-${max} min`;
+    }
+
+    function goroutineQueryUrl() {
+        const params = new URLSearchParams({
+            view: document.getElementById('goroutine-view').value,
+            page: currentPage,
+            page_size: pageSize
+        });
+        const filters = {
+            state: document.getElementById('goroutine-state').value,
+            min_wait: document.getElementById('goroutine-min-wait').value,
+            search: document.getElementById('goroutine-search').value.trim()
+        };
+        Object.entries(filters).forEach(([key, value]) => {
+            if (value) {
+                params.set(key, value);
+            }
+        });
+        return `/monigo/api/v1/go-routines-stats?${params.toString()}`;
+    }
+
+    function renderStateOptions(states) {
+        const select = document.getElementById('goroutine-state');
+        const selected = select.value;
+        const options = Object.entries(states || {})
+            .sort((a, b) => b[1] - a[1])
+            .map(([state, count]) => `<option value="${escapeHtml(state)}">${escapeHtml(state)} (${count})</option>`);
+        select.innerHTML = '<option value="">All states</option>' + options.join('');
+        select.value = selected in (states || {}) ? selected : '';
+    }
+
+    function renderPagination(data) {
+        const pages = Math.max(1, Math.ceil(data.total / pageSize));
+        const label = data.view === 'groups' ? 'groups' : 'goroutines';
+        document.getElementById('goroutine-page-info').textContent = `Page ${data.page} of ${pages} (${data.total} ${label})`;
+        document.getElementById('goroutine-prev').disabled = data.page <= 1;
+        document.getElementById('goroutine-next').disabled = data.page >= pages;
+    }
+
+    function renderGroups(container, groups) {
+        groups.forEach(group => {
+            const states = Object.entries(group.states).map(([state, count]) => `${count} ${state}`).join(', ');
+            const div = document.createElement('div');
+            div.className = 'goroutine';
+            div.innerHTML = `
+                <div class="goroutine-header">
+                    ${group.count} goroutine${group.count === 1 ? '' : 's'}: ${escapeHtml(states)}${formatWait(group.min_wait_minutes, group.max_wait_minutes)}
+                    <a href="#" class="ml-2 goroutine-show-group" data-signature="${escapeHtml(group.signature)}">Show goroutines</a>
+                </div>
+                <pre>${formatFrames(group.frames, group.created_by)}</pre>
+            `;
+            container.appendChild(div);
+        });
+
+        container.querySelectorAll('.goroutine-show-group').forEach(link => {
+            link.addEventListener('click', (event) => {
+                event.preventDefault();
+                fetchGroupGoroutines(link.dataset.signature);
+            });
+        });
+    }
+
+    function renderGoroutines(container, goroutines) {
+        goroutines.forEach(goroutine => {
+            const div = document.createElement('div');
+            div.className = 'goroutine';
+            div.innerHTML = `
+                <div class="goroutine-header">Goroutine ${goroutine.id} [${escapeHtml(goroutine.state)}${goroutine.wait_minutes ? `, ${goroutine.wait_minutes} min` : ''}${goroutine.locked_to_thread ? ', locked to thread' : ''}]:</div>
+                <pre>${formatFrames(goroutine.frames, goroutine.created_by)}</pre>
+            `;
+            container.appendChild(div);
+        });
+    }
+
+    function renderLeaks(leaks) {
+        const card = document.getElementById('goroutine-leaks-card');
+        const container = document.getElementById('goroutine-leaks-container');
+        card.style.display = leaks.length > 0 ? 'block' : 'none';
+        container.innerHTML = '';
+
+        leaks.forEach(leak => {
+            const div = document.createElement('div');
+            div.className = 'goroutine';
+            div.innerHTML = `
+                <div class="goroutine-header">
+                    ${leak.count} goroutines, +${leak.growth} since ${new Date(leak.since).toLocaleString()} (${leak.growth_per_hour}/h)
+                    <a href="#" class="ml-2 goroutine-show-group" data-signature="${escapeHtml(leak.signature)}">Show goroutines</a>
+                    <a href="#" class="ml-2 goroutine-show-trend" data-signature="${escapeHtml(leak.signature)}" data-since="${escapeHtml(leak.since)}">Show trend</a>
+                </div>
+                <pre>${formatFrames(leak.frames, leak.created_by)}</pre>
+            `;
+            container.appendChild(div);
+        });
+
+        container.querySelectorAll('.goroutine-show-group').forEach(link => {
+            link.addEventListener('click', (event) => {
+                event.preventDefault();
+                fetchGroupGoroutines(link.dataset.signature);
+            });
+        });
+        container.querySelectorAll('.goroutine-show-trend').forEach(link => {
+            link.addEventListener('click', (event) => {
+                event.preventDefault();
+                fetchLeakTrend(link.dataset.signature, new Date(link.dataset.since));
+            });
+        });
+    }
+
+    // Function to chart the stored goroutine count of a signature since the start of its leak window
+    function fetchLeakTrend(signature, since) {
+        const data = {
+            field_name: ['goroutines_by_signature'],
+            start_time: toLocalISOString(since),
+            end_time: toLocalISOString(new Date()),
+            labels: { signature: signature }
+        };
+
+        authenticatedFetch(`/monigo/api/v1/service-metrics`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify(data),
+        }).then(response => response.json())
+            .then(points => {
+                const chartElement = document.getElementById('goroutine-leak-chart');
+                chartElement.style.display = 'block';
+                const chart = echarts.init(chartElement);
+                chart.setOption({
+                    title: {
+                        text: `Goroutines with signature ${signature}`,
+                        left: 'center'
+                    },
+                    tooltip: {
+                        trigger: 'axis'
+                    },
+                    grid: {
+                        left: '3%',
+                        right: '4%',
+                        bottom: '3%',
+                        containLabel: true
+                    },
+                    xAxis: {
+                        type: 'time',
+                        boundaryGap: false
+                    },
+                    yAxis: {
+                        type: 'value'
+                    },
+                    series: [{
+                        name: 'Goroutines',
+                        type: 'line',
+                        data: (points || []).map(p => [new Date(p.time), p.value.goroutines_by_signature])
+                    }]
+                }, true);
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    function fetchGroupGoroutines(signature) {
+        authenticatedFetch(`/monigo/api/v1/go-routines-stats?view=goroutines&page_size=${pageSize}&signature=${encodeURIComponent(signature)}`)
             .then(response => response.json())
             .then(data => {
-                goRoutinesNumber.innerHTML = data.number_of_goroutines;
                 const container = document.getElementById('goroutines-container');
-                const countElement = document.getElementById('goroutine-count');
-
-                let goroutines = [];
-                data.stack_view.forEach((item, index) => {
-                    const goroutine = {
-                        id: index + 1,
-                        stackTrace: item
-                    };
-                    goroutines.push(goroutine);
-                });
-
-                fetchDataPointsFromServer();
-
-
-                if (goroutines.length > 0) {
-                    const downloadBtn = document.getElementById('download-stack-view');
-                    if (downloadBtn) {
-                        downloadBtn.style.display = 'block';
-                        downloadBtn.addEventListener('click', () => {
-                            const blob = new Blob([goroutines.map(g => g.stackTrace).join('\n')], {
-                                type: 'text/plain'
-                            });
-                            const url = URL.createObjectURL(blob);
-                            const a = document.createElement('a');
-                            a.href = url;
-                            a.download = 'go-routines-stack-view.txt';
-                            a.click();
-                            URL.revokeObjectURL(url);
-                        });
-                    } else {
-                        downloadBtn.style.display = 'none';
-                    }
-                }
-
-                countElement.textContent = goroutines.length;
                 container.innerHTML = '';
+                renderGoroutines(container, data.goroutines || []);
+                stackView = data.stack_view || [];
+            }).catch(error => {
+                console.error(error);
+            });
+    }
 
-                // Iterate over each goroutine and create HTML content
-                goroutines.forEach(goroutine => {
-                    const div = document.createElement('div');
-                    div.className = 'goroutine';
-                    div.innerHTML = `
-                        <div class="goroutine-header">Goroutine ${goroutine.id}:</div>
-                        <pre>${goroutine.stackTrace}</pre>
-                    `;
-                    container.appendChild(div);
-                });
+    function fetchGoRoutines() {
+        authenticatedFetch(goroutineQueryUrl())
+            .then(response => response.json())
+            .then(data => {
+                goRoutinesNumber.textContent = data.number_of_goroutines;
+                const container = document.getElementById('goroutines-container');
+
+                renderStateOptions(data.states);
+                renderLeaks(data.leaks || []);
+                renderPagination(data);
 
+                container.innerHTML = '';
+                if (data.view === 'groups') {
+                    renderGroups(container, data.groups || []);
+                } else {
+                    renderGoroutines(container, data.goroutines || []);
+                }
+                stackView = data.stack_view || [];
+                document.getElementById('download-stack-view').style.display = stackView.length > 0 ? 'block' : 'none';
             }).catch(error => {
                 console.error(error);
             });
     }
-});
\ No newline at end of file
+
+    if (goRoutinesNumber) {
+        fetchGoRoutines();
+        fetchDataPointsFromServer();
+
+        document.getElementById('download-stack-view').addEventListener('click', () => {
+            const blob = new Blob([stackView.join('\n')], {
+                type: 'text/plain'
+            });
+            const url = URL.createObjectURL(blob);
+            const a = document.createElement('a');
+            a.href = url;
+            a.download = 'go-routines-stack-view.txt';
+            a.click();
+            URL.revokeObjectURL(url);
+        });
+
+        ['goroutine-view', 'goroutine-state', 'goroutine-min-wait'].forEach(id => {
+            document.getElementById(id).addEventListener('change', () => {
+                currentPage = 1;
+                fetchGoRoutines();
+            });
+        });
+
+        let searchTimer;
+        document.getElementById('goroutine-search').addEventListener('input', () => {
+            clearTimeout(searchTimer);
+            searchTimer = setTimeout(() => {
+                currentPage = 1;
+                fetchGoRoutines();
+            }, 300);
+        });
+
+        document.getElementById('goroutine-prev').addEventListener('click', () => {
+            currentPage = Math.max(1, currentPage - 1);
+            fetchGoRoutines();
+        });
+        document.getElementById('goroutine-next').addEventListener('click', () => {
+            currentPage++;
+            fetchGoRoutines();
+        });
+    }
+});
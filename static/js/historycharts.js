@@ -62,7 +62,8 @@ document.addEventListener('DOMContentLoaded', () => {
         cpuUsageChart: document.getElementById('cpu-usage-chart'),
         goroutinesChart: document.getElementById('goroutines-chart'),
         loadMemoryChart: document.getElementById('load-memory-chart'),
-        healthChart: document.getElementById('health-chart')
+        healthChart: document.getElementById('health-chart'),
+        collectorMetricsChart: document.getElementById('collector-metrics-chart')
     };
 
     Object.values(elements).forEach(el => el && (el.innerHTML = refreshHtml));
@@ -86,9 +87,9 @@ document.addEventListener('DOMContentLoaded', () => {
             diff + offsetHours + ':' + offsetMinutes;
     }
 
-    function fetchDataPointsFromServer(metricName, timeRange) {
+    // Function to get the start of the selected time range
+    function getStartTime(timeRange) {
         let StartTime = new Date();
-        let EndTime = new Date();
 
         if (timeRange == "5m") {
             StartTime = new Date(new Date().getTime() - 5 * 60000); // Subtract 5 minutes
@@ -108,6 +109,42 @@ document.addEventListener('DOMContentLoaded', () => {
             StartTime = new Date(new Date().getTime() - 10080 * 60000); // Subtract 7 days
         }
 
+        return StartTime;
+    }
+
+    // Function to fetch the anomaly events in a time range, none when the request fails
+    function fetchAnomalies(start, end) {
+        const params = new URLSearchParams({ from: start.toISOString(), to: end.toISOString() });
+        return authenticatedFetch(`/monigo/api/v1/anomalies?${params}`)
+            .then(response => (response.ok ? response.json() : []))
+            .catch(() => []);
+    }
+
+    // Function to mark the anomalies of a metric on its series, placing each on the first sample taken after it
+    function anomalyMarkPoint(events, metric, data, values) {
+        return {
+            symbol: 'pin',
+            symbolSize: 36,
+            itemStyle: { color: '#dc3545' },
+            label: { formatter: p => p.value, fontSize: 9 },
+            data: events
+                .filter(e => e.metric === metric)
+                .map(e => {
+                    const index = data.findIndex(d => d.time >= new Date(e.time));
+                    return index < 0 ? undefined : {
+                        name: `z-score ${e.score.toFixed(1)}, expected ${e.expected.toFixed(2)}`,
+                        coord: [index, values[index]],
+                        value: e.score.toFixed(1)
+                    };
+                })
+                .filter(d => d !== undefined)
+        };
+    }
+
+    function fetchDataPointsFromServer(metricName, timeRange) {
+        let StartTime = getStartTime(timeRange);
+        let EndTime = new Date();
+
         // else if (timeRange == "7d") {
         //     StartTime = new Date(new Date().getTime() - 10080 * 60000); // Subtract 7 days
         // } else if (timeRange == "1month") {
@@ -133,6 +170,8 @@ document.addEventListener('DOMContentLoaded', () => {
         };
 
 
+        const anomalies = fetchAnomalies(StartTime, EndTime);
+
         authenticatedFetch(`/monigo/api/v1/service-metrics`, {
             method: 'POST',
             headers: {
@@ -140,7 +179,8 @@ document.addEventListener('DOMContentLoaded', () => {
             },
             body: JSON.stringify(data),
         }).then(response => response.json())
-            .then(data => {
+            .then(data => Promise.all([data, anomalies]))
+            .then(([data, events]) => {
                 let rawData = [];
                 for (let i = 0; i < data.length; i++) {
                     const timestamp = new Date(data[i].time);
@@ -159,11 +199,11 @@ document.addEventListener('DOMContentLoaded', () => {
                 }
 
                 if (metricName == "goroutines") {
-                    renderGoroutinesChart(rawData);
+                    renderGoroutinesChart(rawData, events);
                 }
 
                 if (metricName == "load-memory") {
-                    renderLoadMemoryChart(rawData);
+                    renderLoadMemoryChart(rawData, events);
                 }
             })
             .catch((error) => {
@@ -275,7 +315,7 @@ document.addEventListener('DOMContentLoaded', () => {
         cpuUsageChart.setOption(option);
     }
 
-    function renderGoroutinesChart(data) {
+    function renderGoroutinesChart(data, events) {
         const goroutinesChart = echarts.init(elements.goroutinesChart);
         const time = data.map(entry => entry.time);
         const goroutines = data.map(entry => entry.value.goroutines);
@@ -310,7 +350,8 @@ document.addEventListener('DOMContentLoaded', () => {
                 {
                     name: 'Goroutines',
                     type: 'line',
-                    data: goroutines
+                    data: goroutines,
+                    markPoint: anomalyMarkPoint(events, 'goroutines', data, goroutines)
                 }
             ]
         };
@@ -318,7 +359,7 @@ document.addEventListener('DOMContentLoaded', () => {
         goroutinesChart.setOption(option);
     }
 
-    function renderLoadMemoryChart(data) {
+    function renderLoadMemoryChart(data, events) {
         const loadMemoryChart = echarts.init(elements.loadMemoryChart);
         const time = data.map(entry => entry.time);
         const overallLoadOfService = data.map(entry => entry.value.overall_load_of_service);
@@ -363,12 +404,14 @@ document.addEventListener('DOMContentLoaded', () => {
                 {
                     name: 'Service CPU Load',
                     type: 'line',
-                    data: serviceCpuLoad
+                    data: serviceCpuLoad,
+                    markPoint: anomalyMarkPoint(events, 'service_cpu_load', data, serviceCpuLoad)
                 },
                 {
                     name: 'Service Memory Load',
                     type: 'line',
-                    data: serviceMemoryLoad
+                    data: serviceMemoryLoad,
+                    markPoint: anomalyMarkPoint(events, 'service_memory_load', data, serviceMemoryLoad)
                 },
                 {
                     name: 'System CPU Load',
@@ -386,6 +429,106 @@ document.addEventListener('DOMContentLoaded', () => {
         loadMemoryChart.setOption(option);
     }
 
+    // Function to build the series name of a collector metric from its labels
+    function collectorSeriesName(metric) {
+        const labels = Object.entries(metric.labels || {}).map(([k, v]) => `${k}="${v}"`);
+        return labels.length ? `${metric.name}{${labels.join(', ')}}` : metric.name;
+    }
+
+    let collectorStatuses = [];
+
+    function fetchCollectors() {
+        authenticatedFetch(`/monigo/api/v1/collectors`)
+            .then(response => response.json())
+            .then(data => {
+                collectorStatuses = (data || []).filter(c => !c.builtin && c.enabled);
+                const card = document.getElementById('collector-metrics-card');
+                if (!card || collectorStatuses.length === 0) {
+                    return;
+                }
+                card.style.display = '';
+
+                const select = document.getElementById('collector-select');
+                select.innerHTML = collectorStatuses
+                    .map(c => `<option value="${c.name}">${c.name}</option>`)
+                    .join('');
+                updateCollectorChart();
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    function updateCollectorChart() {
+        const name = document.getElementById('collector-select').value;
+        const timeRange = document.getElementById('collector-metrics-time-select').value;
+        const collector = collectorStatuses.find(c => c.name === name);
+        if (!collector) {
+            return;
+        }
+
+        const requests = (collector.metrics || []).map(metric => {
+            const data = {
+                field_name: [metric.name],
+                timerange: timeRange,
+                start_time: toLocalISOString(getStartTime(timeRange)),
+                end_time: toLocalISOString(new Date()),
+                labels: Object.assign({}, metric.labels, { collector: collector.name })
+            };
+
+            return authenticatedFetch(`/monigo/api/v1/service-metrics`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                },
+                body: JSON.stringify(data),
+            }).then(response => response.json())
+                .then(points => ({
+                    name: collectorSeriesName(metric),
+                    type: 'line',
+                    showSymbol: false,
+                    data: (points || []).map(p => [new Date(p.time), p.value[metric.name]])
+                }));
+        });
+
+        Promise.all(requests)
+            .then(renderCollectorMetricsChart)
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    function renderCollectorMetricsChart(series) {
+        const collectorMetricsChart = echarts.init(elements.collectorMetricsChart);
+
+        const option = {
+            tooltip: {
+                trigger: 'axis'
+            },
+            legend: {
+                data: series.map(s => s.name),
+                top: 0,
+                type: 'scroll'
+            },
+            grid: {
+                left: '3%',
+                right: '4%',
+                bottom: '3%',
+                containLabel: true
+            },
+            xAxis: {
+                type: 'time',
+                boundaryGap: false
+            },
+            yAxis: {
+                type: 'value'
+            },
+            series: series
+        };
+
+        collectorMetricsChart.setOption(option, true);
+    }
+
     function updateHistoryChart(metricName) {
         const metricSelect = metricName;
         const timeSelect = document.getElementById(`${metricName}-time-select`).value;
@@ -396,9 +539,12 @@ document.addEventListener('DOMContentLoaded', () => {
     document.getElementById('goroutines-time-select').addEventListener('change', () => updateHistoryChart("goroutines"));
     document.getElementById('load-memory-time-select').addEventListener('change', () => updateHistoryChart("load-memory"));
     document.getElementById('health-time-select').addEventListener('change', () => updateHistoryChart("health"));
+    document.getElementById('collector-select').addEventListener('change', updateCollectorChart);
+    document.getElementById('collector-metrics-time-select').addEventListener('change', updateCollectorChart);
 
     updateHistoryChart("cpu-usage");
     updateHistoryChart("goroutines");
     updateHistoryChart("load-memory");
     updateHistoryChart("health");
+    fetchCollectors();
 });
\ No newline at end of file
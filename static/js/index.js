@@ -113,6 +113,7 @@ document.addEventListener('DOMContentLoaded', () => {
     } else if (DASHBOARD) {
         fetchMetrics();
         fetchServiceInfo();
+        fetchBuildInfo();
     } else {
         console.warn('No valid page found');
     }
@@ -161,6 +162,60 @@ document.addEventListener('DOMContentLoaded', () => {
             });
     }
 
+    // Function to escape text inserted into the page
+    function escapeHtml(value) {
+        return String(value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    function fetchBuildInfo() {
+        authenticatedFetch(`/monigo/api/v1/build-info`)
+            .then((response) => response.json())
+            .then(renderBuildInfo)
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    function renderBuildInfo(data) {
+        const vcs = data.vcs || {};
+        const rt = data.runtime || {};
+        const env = Object.entries(rt.environment || {}).map(([k, v]) => `${k}=${v}`).join(' ');
+        const revision = vcs.revision
+            ? `${vcs.revision.substring(0, 12)}${vcs.modified ? ' (modified)' : ''}`
+            : 'unknown';
+        const summary = [
+            ['Module', data.main && data.main.path ? `${data.main.path} ${data.main.version}` : data.path || 'unknown'],
+            ['Revision', revision],
+            ['Commit Time', vcs.time ? new Date(vcs.time).toLocaleString() : '-'],
+            ['Go', data.go_version],
+            ['Platform', `${rt.goos}/${rt.goarch}`],
+            ['GOMAXPROCS', `${rt.gomaxprocs} of ${rt.num_cpu} CPUs`],
+            ['GODEBUG', rt.godebug || '-'],
+            ['Environment', env || '-']
+        ];
+        document.getElementById('build-info-summary').innerHTML = summary.map(([label, value]) => `
+            <div class="col-lg-3 col-md-6 mb-3">
+                <p class="mb-1">${label}</p>
+                <h6 class="text-break">${escapeHtml(value)}</h6>
+            </div>`).join('');
+
+        document.getElementById('build-settings-table').innerHTML = (data.settings || []).map(s => `<tr>
+                <td>${escapeHtml(s.key)}</td>
+                <td class="text-break">${escapeHtml(s.value)}</td>
+            </tr>`).join('');
+
+        const deps = data.dependencies || [];
+        document.getElementById('build-dependencies-count').textContent = `(${deps.length})`;
+        document.getElementById('build-dependencies-table').innerHTML = deps.map(d => `<tr>
+                <td>${escapeHtml(d.path)}</td>
+                <td>${escapeHtml(d.version)}${d.replace ? ` => ${escapeHtml(d.replace.path)} ${escapeHtml(d.replace.version)}` : ''}</td>
+            </tr>`).join('');
+    }
+
     function updateElement(element, label, value, info = '', obj) {
         if (element) {
             element.innerHTML = `
@@ -713,6 +768,34 @@ document.addEventListener('DOMContentLoaded', () => {
         );
     }
 
+    const healthLevelOrder = ['Critical', 'Poor', 'Fair', 'Satisfactory', 'Good', 'Excellent'];
+
+    // Function to fetch the service's health level transitions between two dates, empty on errors
+    function fetchHealthEvents(start, end) {
+        const params = new URLSearchParams({ scope: 'service', from: start.toISOString(), to: end.toISOString() });
+        return authenticatedFetch(`/monigo/api/v1/health/events?${params}`)
+            .then((response) => (response.ok ? response.json() : []))
+            .catch(() => []);
+    }
+
+    // Function to mark health level transitions on a chart, red when the health degraded and green when it recovered
+    function healthEventMarkLine(events, toAxisValue) {
+        return {
+            symbol: 'none',
+            label: { formatter: (p) => p.name, position: 'insideEndTop', fontSize: 10 },
+            data: events
+                .map((e) => {
+                    const degraded = healthLevelOrder.indexOf(e.to) < healthLevelOrder.indexOf(e.from);
+                    return {
+                        name: `${e.from} → ${e.to} (${e.percent}%)`,
+                        xAxis: toAxisValue(new Date(e.time)),
+                        lineStyle: { color: degraded ? '#dc3545' : '#28a745', type: 'dashed' }
+                    };
+                })
+                .filter((d) => d.xAxis !== undefined)
+        };
+    }
+
     function fetchDataPointsFromServer(metricName, timeRange) {
         let StartTime = new Date();
         let EndTime = new Date();
@@ -773,6 +856,8 @@ document.addEventListener('DOMContentLoaded', () => {
             end_time: toLocalISOString(EndTime)
         };
 
+        const healthEvents = fetchHealthEvents(StartTime, EndTime);
+
         authenticatedFetch(`/monigo/api/v1/service-metrics`, {
             method: 'POST',
             headers: {
@@ -781,7 +866,8 @@ document.addEventListener('DOMContentLoaded', () => {
             body: JSON.stringify(data)
         })
             .then((response) => response.json())
-            .then((data) => {
+            .then((data) => Promise.all([data, healthEvents]))
+            .then(([data, events]) => {
                 let rawData = [];
                 for (let i = 0; i < data.length; i++) {
                     const timestamp = new Date(data[i].time);
@@ -812,6 +898,14 @@ document.addEventListener('DOMContentLoaded', () => {
                     });
                 }
 
+                // Health transitions are placed on the first sample taken after them
+                if (series.length > 0) {
+                    series[0].markLine = healthEventMarkLine(events, (time) => {
+                        const point = rawData.find((d) => d.time >= time);
+                        return point ? point.time.toLocaleString() : undefined;
+                    });
+                }
+
                 chart.setOption({
                     title: {
                         text: getMetricTitle(metricName),
@@ -922,6 +1016,24 @@ document.addEventListener('DOMContentLoaded', () => {
         return [fillColor, tag]; // Return an array
     }
 
+    // Function to list the service's health checks, failing ones first
+    function renderHealthChecks(checks) {
+        const container = document.getElementById('health-checks');
+        if (!container) {
+            return;
+        }
+        const order = { critical: 0, warning: 1, ok: 2, skipped: 3 };
+        const statusClass = { critical: 'text-danger', warning: 'text-warning', ok: 'text-success', skipped: 'text-muted' };
+        container.innerHTML = checks
+            .filter(c => c.status !== 'skipped')
+            .sort((a, b) => order[a.status] - order[b.status])
+            .map(c => `<div class="small">
+                <span class="${statusClass[c.status] || ''}">&#9679;</span>
+                ${escapeHtml(c.name)}${c.critical ? ' (critical)' : ''}: ${c.score}%
+                <span class="text-muted">${escapeHtml(c.message)}</span>
+            </div>`).join('');
+    }
+
     function updateGauge(gaugeId, health) {
         const srevPercentage = health.service_health.percent;
         const sysPercentage = health.system_health.percent;
@@ -961,6 +1073,8 @@ document.addEventListener('DOMContentLoaded', () => {
         `;
         }
 
+        renderHealthChecks(health.service_health.checks || []);
+
         // Reset the --o property to 0 to restart the animation
         gauge.style.setProperty('--o', 0);
 
@@ -0,0 +1,406 @@
+document.addEventListener('DOMContentLoaded', () => {
+    // Function to get API key from URL parameters
+    function getApiKey() {
+        const urlParams = new URLSearchParams(window.location.search);
+        return urlParams.get('api_key');
+    }
+
+    // Function to add API key to fetch URL (only for API key auth)
+    function addApiKeyToUrl(url) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            const separator = url.includes('?') ? '&' : '?';
+            return `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        }
+        return url;
+    }
+
+    // Function to make authenticated fetch request
+    function authenticatedFetch(url, options = {}) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            // API key authentication - add to URL
+            const separator = url.includes('?') ? '&' : '?';
+            url = `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        } else {
+            // Check for custom authentication methods
+            const urlParams = new URLSearchParams(window.location.search);
+            const secret = urlParams.get('secret');
+
+            if (secret === 'monigo-admin-secret') {
+                // Custom query parameter authentication
+                const separator = url.includes('?') ? '&' : '?';
+                url = `${url}${separator}secret=${encodeURIComponent(secret)}`;
+            } else {
+                // Check for custom header authentication
+                // For custom auth, we need to add headers
+                if (!options.headers) {
+                    options.headers = {};
+                }
+
+                // Add custom header for admin access
+                options.headers['X-User-Role'] = 'admin';
+
+                // Set custom user agent for automated access
+                options.headers['User-Agent'] = 'MoniGo-Admin/1.0';
+            }
+        }
+        // For basic auth, the browser handles credentials automatically
+        return fetch(url, options);
+    }
+
+    const apiBase = '/monigo/api/v1';
+    let selectedProfile = null;
+
+    // Function to escape text inserted into the page
+    function escapeHtml(value) {
+        return String(value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    function formatBytes(bytes) {
+        const units = ['B', 'KB', 'MB', 'GB'];
+        let value = bytes || 0;
+        let unit = 0;
+        while (value >= 1024 && unit < units.length - 1) {
+            value /= 1024;
+            unit++;
+        }
+        return `${value.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`;
+    }
+
+    function setStatus(message) {
+        document.getElementById('profile-status').textContent = message;
+    }
+
+    // Function to raise the error text returned by the API
+    function checkResponse(response) {
+        if (!response.ok) {
+            return response.text().then(text => {
+                throw new Error(text.trim() || response.statusText);
+            });
+        }
+        return response;
+    }
+
+    // Function to download a response body as a file named by the server
+    function saveResponse(response) {
+        const disposition = response.headers.get('Content-Disposition') || '';
+        const match = disposition.match(/filename="([^"]+)"/);
+        return response.blob().then(blob => {
+            const link = document.createElement('a');
+            link.href = URL.createObjectURL(blob);
+            link.download = match ? match[1] : 'profile';
+            document.body.appendChild(link);
+            link.click();
+            link.remove();
+            URL.revokeObjectURL(link.href);
+        });
+    }
+
+    function captureUrl(store) {
+        const params = new URLSearchParams({ type: document.getElementById('profile-type').value });
+        const seconds = document.getElementById('profile-seconds').value;
+        const rate = document.getElementById('profile-rate').value;
+        if (seconds !== '') {
+            params.set('seconds', seconds);
+        }
+        if (rate !== '') {
+            params.set('rate', rate);
+        }
+        if (store) {
+            params.set('store', '1');
+        }
+        return `${apiBase}/profiles/capture?${params.toString()}`;
+    }
+
+    // Profiling requires authentication, without it the controls are disabled once the API refuses the list
+    let profilingAllowed = true;
+    let incidents = [];
+
+    function disableProfiling(message) {
+        profilingAllowed = false;
+        document.querySelectorAll('#profile-controls input, #profile-controls select, #profile-controls button')
+            .forEach(el => el.disabled = true);
+        setStatus(message);
+        renderIncidents(incidents);
+    }
+
+    function captureProfile(store) {
+        const buttons = [document.getElementById('profile-store-btn'), document.getElementById('profile-download-btn')];
+        buttons.forEach(b => b.disabled = true);
+        setStatus('Capturing profile...');
+
+        authenticatedFetch(captureUrl(store), { method: 'POST' })
+            .then(checkResponse)
+            .then(response => store ? response.json().then(() => {
+                fetchProfiles();
+                fetchTimeline();
+            }) : saveResponse(response))
+            .then(() => setStatus('Profile captured.'))
+            .catch((error) => {
+                console.error('Error:', error);
+                setStatus(`Failed to capture profile: ${error.message}`);
+            })
+            .finally(() => buttons.forEach(b => b.disabled = !profilingAllowed));
+    }
+
+    function fetchProfiles() {
+        const source = document.getElementById('profiles-source').value;
+        authenticatedFetch(`${apiBase}/profiles${source ? `?source=${encodeURIComponent(source)}` : ''}`)
+            .then(response => {
+                if (response.status === 403) {
+                    return response.text().then(text => disableProfiling(text.trim()));
+                }
+                return Promise.resolve(checkResponse(response))
+                    .then(response => response.json())
+                    .then(renderProfiles);
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+                setStatus(error.message);
+            });
+    }
+
+    function renderProfiles(profiles) {
+        profiles = profiles || [];
+        document.getElementById('profiles-empty').style.display = profiles.length ? 'none' : '';
+        document.getElementById('profiles-table').innerHTML = profiles.map(p => `<tr>
+                <td>${escapeHtml(new Date(p.created_at).toLocaleString())}</td>
+                <td>${escapeHtml(p.type)}</td>
+                <td>${escapeHtml(p.source)}</td>
+                <td>${p.duration_seconds ? `${p.duration_seconds}s` : '-'}</td>
+                <td>${formatBytes(p.size_bytes)}</td>
+                <td class="text-right">
+                    ${p.type === 'trace' ? '' : `<button class="btn btn-sm border" data-action="view" data-id="${escapeHtml(p.id)}">View</button>`}
+                    <button class="btn btn-sm border" data-action="download" data-id="${escapeHtml(p.id)}">Download</button>
+                    <button class="btn btn-sm border" data-action="delete" data-id="${escapeHtml(p.id)}">Delete</button>
+                </td>
+            </tr>`).join('');
+    }
+
+    function fetchIncidents() {
+        authenticatedFetch(`${apiBase}/incidents`)
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(renderIncidents)
+            .catch((error) => {
+                console.error('Error:', error);
+            });
+    }
+
+    function formatIncidentMetric(m) {
+        const unit = m.name.endsWith('_usage') ? '%' : '';
+        return `${escapeHtml(m.name)} ${m.value}${unit} / ${m.threshold}${unit}`;
+    }
+
+    function renderIncidents(list) {
+        incidents = list || [];
+        document.getElementById('incidents-empty').style.display = incidents.length ? 'none' : '';
+        document.getElementById('incidents-table').innerHTML = incidents.map(incident => {
+            const crossed = (incident.metrics || []).filter(m => m.breached).map(formatIncidentMetric).join('<br>');
+            const profiles = (incident.profiles || []).map(p => `<div class="mb-1">
+                    ${escapeHtml(p.type)}
+                    ${profilingAllowed ? `<button class="btn btn-sm border ml-1" data-action="view" data-id="${escapeHtml(p.id)}">View</button>
+                    <button class="btn btn-sm border" data-action="download" data-id="${escapeHtml(p.id)}">Download</button>` : ''}
+                </div>`).join('');
+            const errors = (incident.errors || []).map(e => `<div class="text-danger">${escapeHtml(e)}</div>`).join('');
+            return `<tr>
+                <td>${escapeHtml(new Date(incident.created_at).toLocaleString())}</td>
+                <td>${crossed}</td>
+                <td>${profiles}${errors}</td>
+                <td class="text-right">
+                    ${profilingAllowed ? `<button class="btn btn-sm border" data-action="delete-incident" data-id="${escapeHtml(incident.id)}">Delete</button>` : ''}
+                </td>
+            </tr>`;
+        }).join('');
+    }
+
+    function deleteIncident(id) {
+        if (!confirm('Delete this incident and its profiles?')) {
+            return;
+        }
+        authenticatedFetch(`${apiBase}/incidents/delete?id=${encodeURIComponent(id)}`, { method: 'DELETE' })
+            .then(checkResponse)
+            .then(() => {
+                fetchIncidents();
+                fetchTimeline();
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+                setStatus(error.message);
+            });
+    }
+
+    const timelineRangeMinutes = { '1h': 60, '6h': 360, '1d': 1440, '3d': 4320, '7d': 10080 };
+
+    // Function to fetch the profiles of the selected range, centred on the "Around" time when set
+    function fetchTimeline() {
+        const rangeMinutes = timelineRangeMinutes[document.getElementById('profile-timeline-range').value];
+        const at = document.getElementById('profile-timeline-at').value;
+        let end = new Date();
+        if (at) {
+            end = new Date(new Date(at).getTime() + rangeMinutes * 30000);
+        }
+        const start = new Date(end.getTime() - rangeMinutes * 60000);
+
+        const params = new URLSearchParams({ from: start.toISOString(), to: end.toISOString() });
+        authenticatedFetch(`${apiBase}/profiles/timeline?${params.toString()}`)
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(renderTimeline)
+            .catch((error) => {
+                console.error('Error:', error);
+                document.getElementById('profile-timeline-info').textContent = error.message;
+            });
+    }
+
+    function renderTimeline(timeline) {
+        const profiles = timeline.profiles || [];
+        const types = [...new Set(profiles.map(p => p.type))].sort();
+        document.getElementById('profile-timeline-info').textContent = timeline.interval_seconds
+            ? `${profiles.length} profiles, captured every ${timeline.interval_seconds / 60} minutes. Click a profile to view it.`
+            : `${profiles.length} profiles. Continuous profiling is disabled, enable it with WithContinuousProfiling().`;
+
+        const chart = echarts.init(document.getElementById('profile-timeline-chart'));
+        chart.setOption({
+            tooltip: {
+                trigger: 'item',
+                formatter: (params) => {
+                    const p = params.data.profile;
+                    return `${escapeHtml(p.type)} (${escapeHtml(p.source)})<br>${new Date(p.created_at).toLocaleString()}<br>${formatBytes(p.size_bytes)}`;
+                }
+            },
+            grid: {
+                left: '3%',
+                right: '4%',
+                bottom: '3%',
+                containLabel: true
+            },
+            xAxis: {
+                type: 'time',
+                min: new Date(timeline.from),
+                max: new Date(timeline.to)
+            },
+            yAxis: {
+                type: 'category',
+                data: types
+            },
+            series: [{
+                type: 'scatter',
+                symbolSize: 12,
+                data: profiles.map(p => ({ value: [new Date(p.created_at), p.type], profile: p }))
+            }]
+        }, true);
+
+        chart.off('click');
+        chart.on('click', (params) => {
+            if (params.data.profile.type !== 'trace') {
+                viewProfile(params.data.profile.id);
+            }
+        });
+    }
+
+    function viewProfile(id) {
+        selectedProfile = id;
+        const reportType = document.getElementById('profile-report-type').value;
+        const report = document.getElementById('profile-report');
+        document.getElementById('profile-report-card').style.display = '';
+        report.textContent = 'Loading report...';
+
+        authenticatedFetch(`${apiBase}/profiles/view?id=${encodeURIComponent(id)}&reportType=${encodeURIComponent(reportType)}`)
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(data => {
+                document.getElementById('profile-report-title').textContent =
+                    `Report: ${data.profile.type} profile of ${new Date(data.profile.created_at).toLocaleString()}`;
+                report.textContent = data.report;
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+                report.textContent = error.message;
+            });
+    }
+
+    function downloadProfile(id) {
+        authenticatedFetch(`${apiBase}/profiles/download?id=${encodeURIComponent(id)}`)
+            .then(checkResponse)
+            .then(saveResponse)
+            .catch((error) => {
+                console.error('Error:', error);
+                setStatus(error.message);
+            });
+    }
+
+    function deleteProfile(id) {
+        if (!confirm('Delete this profile?')) {
+            return;
+        }
+        authenticatedFetch(`${apiBase}/profiles/delete?id=${encodeURIComponent(id)}`, { method: 'DELETE' })
+            .then(checkResponse)
+            .then(() => {
+                if (selectedProfile === id) {
+                    selectedProfile = null;
+                    document.getElementById('profile-report-card').style.display = 'none';
+                }
+                fetchProfiles();
+            })
+            .catch((error) => {
+                console.error('Error:', error);
+                setStatus(error.message);
+            });
+    }
+
+    // Function to handle the View, Download and Delete buttons of the profile and incident tables
+    function handleTableClick(event) {
+        const button = event.target.closest('button[data-action]');
+        if (!button) {
+            return;
+        }
+        const id = button.dataset.id;
+        switch (button.dataset.action) {
+            case 'view':
+                viewProfile(id);
+                break;
+            case 'download':
+                downloadProfile(id);
+                break;
+            case 'delete':
+                deleteProfile(id);
+                break;
+            case 'delete-incident':
+                deleteIncident(id);
+                break;
+        }
+    }
+
+    document.getElementById('profiles-table').addEventListener('click', handleTableClick);
+    document.getElementById('incidents-table').addEventListener('click', handleTableClick);
+    document.getElementById('profile-report-type').addEventListener('change', () => {
+        if (selectedProfile) {
+            viewProfile(selectedProfile);
+        }
+    });
+    document.getElementById('profile-type').addEventListener('change', (event) => {
+        // CPU profiles and traces always run for a while, snapshots only when sampling contention
+        const seconds = { cpu: '30', trace: '5', mutex: '10', block: '10' };
+        document.getElementById('profile-seconds').value = seconds[event.target.value] || '0';
+    });
+    document.getElementById('profile-store-btn').addEventListener('click', () => captureProfile(true));
+    document.getElementById('profile-download-btn').addEventListener('click', () => captureProfile(false));
+    document.getElementById('profiles-source').addEventListener('change', fetchProfiles);
+    document.getElementById('profile-timeline-at').addEventListener('change', fetchTimeline);
+    document.getElementById('profile-timeline-range').addEventListener('change', fetchTimeline);
+    document.getElementById('refresh-btn').addEventListener('click', () => {
+        fetchIncidents();
+        fetchProfiles();
+        fetchTimeline();
+    });
+
+    fetchIncidents();
+    fetchProfiles();
+    fetchTimeline();
+});
@@ -0,0 +1,237 @@
+document.addEventListener('DOMContentLoaded', () => {
+    // Function to get API key from URL parameters
+    function getApiKey() {
+        const urlParams = new URLSearchParams(window.location.search);
+        return urlParams.get('api_key');
+    }
+
+    // Function to add API key to fetch URL (only for API key auth)
+    function addApiKeyToUrl(url) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            const separator = url.includes('?') ? '&' : '?';
+            return `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        }
+        return url;
+    }
+
+    // Function to make authenticated fetch request
+    function authenticatedFetch(url, options = {}) {
+        const apiKey = getApiKey();
+        if (apiKey) {
+            // API key authentication - add to URL
+            const separator = url.includes('?') ? '&' : '?';
+            url = `${url}${separator}api_key=${encodeURIComponent(apiKey)}`;
+        } else {
+            // Check for custom authentication methods
+            const urlParams = new URLSearchParams(window.location.search);
+            const secret = urlParams.get('secret');
+
+            if (secret === 'monigo-admin-secret') {
+                // Custom query parameter authentication
+                const separator = url.includes('?') ? '&' : '?';
+                url = `${url}${separator}secret=${encodeURIComponent(secret)}`;
+            } else {
+                // Check for custom header authentication
+                // For custom auth, we need to add headers
+                if (!options.headers) {
+                    options.headers = {};
+                }
+
+                // Add custom header for admin access
+                options.headers['X-User-Role'] = 'admin';
+
+                // Set custom user agent for automated access
+                options.headers['User-Agent'] = 'MoniGo-Admin/1.0';
+            }
+        }
+        // For basic auth, the browser handles credentials automatically
+        return fetch(url, options);
+    }
+
+    const apiBase = '/monigo/api/v1';
+
+    // Function to escape text inserted into the page
+    function escapeHtml(value) {
+        return String(value)
+            .replace(/&/g, '&amp;')
+            .replace(/</g, '&lt;')
+            .replace(/>/g, '&gt;')
+            .replace(/"/g, '&quot;');
+    }
+
+    // Function to raise the error text returned by the API
+    function checkResponse(response) {
+        if (!response.ok) {
+            return response.text().then(text => {
+                throw new Error(text.trim() || response.statusText);
+            });
+        }
+        return response;
+    }
+
+    const timeRanges = { '1h': 3600e3, '6h': 6 * 3600e3, '1d': 86400e3, '7d': 7 * 86400e3, '30d': 30 * 86400e3 };
+
+    let slos = [];
+
+    function formatPercent(value, digits = 3) {
+        return `${Number(value).toFixed(digits)}%`;
+    }
+
+    function budgetClass(remaining) {
+        if (remaining <= 0) {
+            return 'text-danger';
+        }
+        return remaining < 25 ? 'text-warning' : 'text-success';
+    }
+
+    function formatBurnRate(slo, window) {
+        const rate = (slo.burn_rates || {})[window];
+        return rate === undefined ? '-' : Number(rate).toFixed(2);
+    }
+
+    function fetchSLOs() {
+        authenticatedFetch(`${apiBase}/slos`)
+            .then(checkResponse)
+            .then(response => response.json())
+            .then(renderSLOs)
+            .catch((error) => console.error('Error:', error));
+    }
+
+    function renderSLOs(statuses) {
+        slos = statuses;
+        document.getElementById('slos-table').innerHTML = statuses.map(s => {
+            const burning = s.fast_burn ? ' <span class="badge badge-danger">fast burn</span>'
+                : s.slow_burn ? ' <span class="badge badge-warning">slow burn</span>' : '';
+            return `<tr>
+                <td><strong>${escapeHtml(s.name)}</strong><br><small>${escapeHtml(s.description || '')}</small></td>
+                <td>${escapeHtml(s.type)}</td>
+                <td>${escapeHtml(s.objective)}% over ${escapeHtml(s.window)}</td>
+                <td class="${s.attainment < s.objective ? 'text-danger' : ''}">${formatPercent(s.attainment)}</td>
+                <td class="${budgetClass(s.error_budget_remaining)}">${formatPercent(s.error_budget_remaining, 1)}</td>
+                <td>${formatBurnRate(s, '1h')} / ${formatBurnRate(s, '6h')}${burning}</td>
+                <td>${escapeHtml(s.good_events)} good of ${escapeHtml(s.total_events)}</td>
+                <td>${escapeHtml(new Date(s.since).toLocaleString())}</td>
+            </tr>`;
+        }).join('');
+        document.getElementById('slos-empty').style.display = statuses.length ? 'none' : 'block';
+
+        const card = document.getElementById('slo-history-card');
+        const select = document.getElementById('slo-select');
+        card.style.display = statuses.length ? '' : 'none';
+        const selected = select.value;
+        select.innerHTML = statuses
+            .map(s => `<option value="${escapeHtml(s.name)}">${escapeHtml(s.name)}</option>`)
+            .join('');
+        if (statuses.some(s => s.name === selected)) {
+            select.value = selected;
+        }
+        if (statuses.length) {
+            fetchHistory();
+        }
+    }
+
+    // Function to fetch stored SLO series, keyed by time
+    function fetchSeries(fields, labels) {
+        const range = document.getElementById('slo-time-select').value;
+        const end = new Date();
+        return authenticatedFetch(`${apiBase}/service-metrics`, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({
+                field_name: fields,
+                timerange: range,
+                start_time: new Date(end.getTime() - timeRanges[range]).toISOString(),
+                end_time: end.toISOString(),
+                labels: labels
+            }),
+        }).then(checkResponse)
+            .then(response => response.json())
+            .then(points => (points || []).map(p => ({ time: new Date(p.time), value: p.value })));
+    }
+
+    function fetchHistory() {
+        const name = document.getElementById('slo-select').value;
+        const slo = slos.find(s => s.name === name);
+        if (!slo) {
+            return;
+        }
+
+        Promise.all([
+            fetchSeries(['slo_attainment', 'slo_error_budget_remaining'], { slo: name }),
+            fetchSeries(['slo_burn_rate'], { slo: name, window: '1h' }),
+            fetchSeries(['slo_burn_rate'], { slo: name, window: '6h' })
+        ]).then(([budget, burn1h, burn6h]) => renderHistory(slo, budget, burn1h, burn6h))
+            .catch((error) => console.error('Error:', error));
+    }
+
+    function renderHistory(slo, budget, burn1h, burn6h) {
+        const budgetChart = echarts.init(document.getElementById('slo-budget-chart'));
+        budgetChart.setOption({
+            title: { text: 'Attainment and Error Budget', left: 'center' },
+            tooltip: { trigger: 'axis' },
+            legend: { data: ['Attainment', 'Error Budget Left'], top: 30 },
+            grid: { left: '3%', right: '4%', bottom: '3%', top: 70, containLabel: true },
+            xAxis: { type: 'time', boundaryGap: false },
+            yAxis: [
+                { type: 'value', name: 'Attainment %', scale: true },
+                { type: 'value', name: 'Budget %', max: 100 }
+            ],
+            series: [
+                {
+                    name: 'Attainment',
+                    type: 'line',
+                    showSymbol: false,
+                    data: budget.map(p => [p.time, p.value.slo_attainment]),
+                    markLine: { symbol: 'none', data: [{ yAxis: slo.objective, name: 'Objective' }], lineStyle: { type: 'dashed' } }
+                },
+                {
+                    name: 'Error Budget Left',
+                    type: 'line',
+                    yAxisIndex: 1,
+                    showSymbol: false,
+                    areaStyle: { opacity: 0.1 },
+                    data: budget.map(p => [p.time, p.value.slo_error_budget_remaining])
+                }
+            ]
+        }, true);
+
+        const burnChart = echarts.init(document.getElementById('slo-burn-chart'));
+        burnChart.setOption({
+            title: { text: 'Burn Rate', left: 'center' },
+            tooltip: { trigger: 'axis' },
+            legend: { data: ['1h', '6h'], top: 30 },
+            grid: { left: '3%', right: '4%', bottom: '3%', top: 70, containLabel: true },
+            xAxis: { type: 'time', boundaryGap: false },
+            yAxis: { type: 'value' },
+            series: [
+                {
+                    name: '1h',
+                    type: 'line',
+                    showSymbol: false,
+                    data: burn1h.map(p => [p.time, p.value.slo_burn_rate]),
+                    markLine: {
+                        symbol: 'none',
+                        lineStyle: { type: 'dashed', color: '#dc3545' },
+                        data: [{ yAxis: 14.4, name: 'Fast burn' }, { yAxis: 6, name: 'Slow burn' }]
+                    }
+                },
+                {
+                    name: '6h',
+                    type: 'line',
+                    showSymbol: false,
+                    data: burn6h.map(p => [p.time, p.value.slo_burn_rate])
+                }
+            ]
+        }, true);
+    }
+
+    document.getElementById('slo-select').addEventListener('change', fetchHistory);
+    document.getElementById('slo-time-select').addEventListener('change', fetchHistory);
+    document.getElementById('refresh-btn').addEventListener('click', fetchSLOs);
+
+    fetchSLOs();
+    setInterval(fetchSLOs, 60000);
+});
@@ -9,6 +9,7 @@ import (
 
 func BenchmarkInMemoryStorageInsert(b *testing.B) {
 	s := NewInMemoryStorage()
+	defer s.Close()
 	now := time.Now().Unix()
 	rows := []Row{
 		{Metric: "bench_metric", DataPoint: DataPoint{Timestamp: now, Value: 42.0}, Labels: []Label{{Name: "host", Value: "bench"}}},
@@ -22,6 +23,7 @@ func BenchmarkInMemoryStorageInsert(b *testing.B) {
 
 func BenchmarkInMemoryStorageSelect(b *testing.B) {
 	s := NewInMemoryStorage()
+	defer s.Close()
 	now := time.Now().Unix()
 	for i := 0; i < 1000; i++ {
 		s.InsertRows([]Row{
@@ -30,7 +32,7 @@ func BenchmarkInMemoryStorageSelect(b *testing.B) {
 	}
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		s.Select("bench_metric", nil, now, now+1000)
+		s.Select("bench_metric", []Label{{Name: "host", Value: "bench"}}, now, now+1000)
 	}
 }
 
@@ -0,0 +1,238 @@
+package timeseries
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltFileName is the name of the database file of the "bolt" storage in the data directory.
+const BoltFileName = "monigo.db"
+
+var (
+	// boltRawBucket is the bucket of the raw points.
+	boltRawBucket = "data"
+	// boltSeriesKey is the key of the metric and labels in the bucket of a series.
+	boltSeriesKey = []byte("series")
+	// boltPointsBucket is the bucket of the points in the bucket of a series.
+	boltPointsBucket = []byte("points")
+)
+
+// boltStorage stores points in a bucket of a single-file bbolt database. Every series has a
+// bucket keyed by metric and labels holding its labels and its points keyed by timestamp, so
+// the points of a series are sorted and a point replaces one at the same timestamp. Points
+// older than the retention are evicted periodically.
+type boltStorage struct {
+	db        *bolt.DB
+	bucket    []byte
+	retention time.Duration
+	owner     bool // Closes the database, false for the storages sharing it
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// openBoltStorage opens the database at path, created if missing, and returns the storage of
+// the raw points with the given retention, zero for none.
+func openBoltStorage(path string, retention time.Duration) (*boltStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	// Another process holding the file fails the open instead of blocking it
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	s, err := newBoltStorage(db, boltRawBucket, retention)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s.owner = true
+	return s, nil
+}
+
+// newBoltStorage returns a storage in the named bucket of the database, evicting expired points
+// until it is closed.
+func newBoltStorage(db *bolt.DB, bucket string, retention time.Duration) (*boltStorage, error) {
+	s := &boltStorage{db: db, bucket: []byte(bucket), retention: retention, stop: make(chan struct{})}
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(s.bucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating the %s bucket: %w", bucket, err)
+	}
+	if retention > 0 {
+		go s.evictLoop()
+	}
+	return s, nil
+}
+
+// share returns a storage in another bucket of the same database, closed without closing it.
+func (s *boltStorage) share(bucket string, retention time.Duration) (*boltStorage, error) {
+	return newBoltStorage(s.db, bucket, retention)
+}
+
+// timestampKey encodes a timestamp so the keys sort like the timestamps, negative ones included.
+func timestampKey(ts int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(ts)^(1<<63))
+	return key
+}
+
+func keyTimestamp(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key) ^ (1 << 63))
+}
+
+// InsertRows stores the rows in a single transaction.
+func (s *boltStorage) InsertRows(rows []Row) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(s.bucket)
+		for _, row := range rows {
+			key := []byte(seriesKey(row.Metric, row.Labels))
+			series := root.Bucket(key)
+			if series == nil {
+				var err error
+				if series, err = createBoltSeries(root, key, row); err != nil {
+					return err
+				}
+			}
+			value := make([]byte, 8) // Put keeps the slice until the transaction ends
+			binary.BigEndian.PutUint64(value, math.Float64bits(row.DataPoint.Value))
+			if err := series.Bucket(boltPointsBucket).Put(timestampKey(row.DataPoint.Timestamp), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createBoltSeries creates the bucket of the series of a row, recording its metric and labels.
+func createBoltSeries(root *bolt.Bucket, key []byte, row Row) (*bolt.Bucket, error) {
+	series, err := root.CreateBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := series.CreateBucket(boltPointsBucket); err != nil {
+		return nil, err
+	}
+	record := seriesRecord{Metric: row.Metric, Labels: make(map[string]string, len(row.Labels))}
+	for _, l := range row.Labels {
+		if l.Name != "" && l.Value != "" {
+			record.Labels[l.Name] = l.Value
+		}
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return series, series.Put(boltSeriesKey, data)
+}
+
+// Select returns the points of the series with exactly the given labels between start and
+// end inclusive, oldest first.
+func (s *boltStorage) Select(metric string, labels []Label, start, end int64) ([]DataPoint, error) {
+	var result []DataPoint
+	err := s.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(s.bucket).Bucket([]byte(seriesKey(metric, labels)))
+		if series == nil {
+			return nil
+		}
+		c := series.Bucket(boltPointsBucket).Cursor()
+		for k, v := c.Seek(timestampKey(start)); k != nil; k, v = c.Next() {
+			ts := keyTimestamp(k)
+			if ts > end {
+				break
+			}
+			result = append(result, DataPoint{Timestamp: ts, Value: math.Float64frombits(binary.BigEndian.Uint64(v))})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// series returns the metric and labels of every stored series.
+func (s *boltStorage) series() ([]seriesRecord, error) {
+	var records []seriesRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).ForEachBucket(func(key []byte) error {
+			var record seriesRecord
+			if err := json.Unmarshal(tx.Bucket(s.bucket).Bucket(key).Get(boltSeriesKey), &record); err != nil {
+				return fmt.Errorf("reading series %q: %w", key, err)
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	return records, err
+}
+
+// Close stops the eviction and closes the database unless it is shared. Safe to call multiple
+// times.
+func (s *boltStorage) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		if s.owner {
+			err = s.db.Close()
+		}
+	})
+	return err
+}
+
+// evictLoop evicts the points older than the retention every evictInterval until closed.
+func (s *boltStorage) evictLoop() {
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			if err := s.evictExpired(now); err != nil && !errors.Is(err, bolt.ErrDatabaseNotOpen) {
+				logger.Log.Error("evicting expired points", "bucket", string(s.bucket), "error", err)
+			}
+		}
+	}
+}
+
+// evictExpired removes the points older than the retention and the series left without any.
+func (s *boltStorage) evictExpired(now time.Time) error {
+	cutoff := timestampKey(now.Add(-s.retention).Unix())
+	return s.db.Update(func(tx *bolt.Tx) error {
+		root := tx.Bucket(s.bucket)
+		var empty [][]byte
+		err := root.ForEachBucket(func(key []byte) error {
+			points := root.Bucket(key).Bucket(boltPointsBucket)
+			c := points.Cursor()
+			for k, _ := c.First(); k != nil && bytes.Compare(k, cutoff) < 0; k, _ = c.First() {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+			if k, _ := c.First(); k == nil {
+				empty = append(empty, bytes.Clone(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range empty {
+			if err := root.DeleteBucket(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,457 @@
+package timeseries
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of exported and imported points.
+const (
+	FormatCSV         = "csv"
+	FormatNDJSON      = "ndjson"
+	FormatOpenMetrics = "openmetrics"
+)
+
+// importBatchSize is the number of imported rows inserted at once.
+const importBatchSize = 5000
+
+var (
+	// csvHeader is the first record of a CSV export.
+	csvHeader = []string{"metric", "labels", "timestamp", "value"}
+	// labelValueEscaper escapes label values as in OpenMetrics.
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// IsExportFormat reports whether points can be exported and imported in the format.
+func IsExportFormat(format string) bool {
+	return format == FormatCSV || format == FormatNDJSON || format == FormatOpenMetrics
+}
+
+// ExportOptions selects the exported points.
+type ExportOptions struct {
+	Metrics []string          // Exported metrics, every stored metric when empty
+	Match   map[string]string // Labels the exported series have, e.g. {"route": "/checkout"}
+	Start   int64             // Unix seconds of the first exported point, inclusive
+	End     int64             // Unix seconds of the last exported point, inclusive
+}
+
+// ImportOptions remaps the labels of the imported points.
+type ImportOptions struct {
+	RenameLabels map[string]string // Label names replaced, e.g. {"host": "source_host"}, an empty name drops the label
+	Labels       map[string]string // Set on every point, replacing a label of the same name, e.g. {"source": "prod"}
+}
+
+// exportedPoint is a point of an NDJSON export.
+type exportedPoint struct {
+	Metric    string            `json:"metric"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	Value     exportedValue     `json:"value"`
+}
+
+// exportedValue is a value encoded as a JSON number, or a string for NaN and infinities.
+type exportedValue float64
+
+func (v exportedValue) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+		return json.Marshal(formatValue(float64(v)))
+	}
+	return json.Marshal(float64(v))
+}
+
+func (v *exportedValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		f, err := strconv.ParseFloat(s, 64)
+		*v = exportedValue(f)
+		return err
+	}
+	return json.Unmarshal(data, (*float64)(v))
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Export writes the points of the stored series selected by the options in the format, series by
+// series with their points oldest first, and returns the number of points written.
+func Export(w io.Writer, format string, opts ExportOptions) (int, error) {
+	if !IsExportFormat(format) {
+		return 0, fmt.Errorf("unknown format %q, expected csv, ndjson or openmetrics", format)
+	}
+	sto, err := GetStorageInstance()
+	if err != nil {
+		return 0, fmt.Errorf("error getting storage instance: %w", err)
+	}
+	metrics := opts.Metrics
+	if len(metrics) == 0 {
+		metrics = manager.index.metrics()
+	}
+
+	buf := bufio.NewWriter(w)
+	var csvWriter *csv.Writer
+	if format == FormatCSV {
+		csvWriter = csv.NewWriter(buf)
+		csvWriter.Write(csvHeader)
+	}
+	encoder := json.NewEncoder(buf)
+
+	count := 0
+	for _, metric := range metrics {
+		typed := false
+		for _, labels := range manager.index.labels(metric) {
+			if !hasLabels(labels, opts.Match) {
+				continue
+			}
+			points, err := sto.Select(metric, labels, opts.Start, opts.End)
+			if err != nil && !errors.Is(err, ErrNoDataPoints) {
+				return count, fmt.Errorf("reading %s: %w", metric, err)
+			}
+			sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+
+			for _, p := range points {
+				switch format {
+				case FormatCSV:
+					err = csvWriter.Write([]string{metric, formatLabelSet(labels, false), time.Unix(p.Timestamp, 0).UTC().Format(time.RFC3339), formatValue(p.Value)})
+				case FormatNDJSON:
+					point := exportedPoint{Metric: metric, Labels: make(map[string]string, len(labels)), Timestamp: time.Unix(p.Timestamp, 0).UTC(), Value: exportedValue(p.Value)}
+					for _, l := range labels {
+						point.Labels[l.Name] = l.Value
+					}
+					err = encoder.Encode(point)
+				case FormatOpenMetrics:
+					name := promName(metric, true)
+					if !typed {
+						// The points of a metric are written together, as its family
+						fmt.Fprintf(buf, "# TYPE %s unknown\n", name)
+						typed = true
+					}
+					if len(labels) > 0 {
+						name += "{" + formatLabelSet(labels, true) + "}"
+					}
+					_, err = fmt.Fprintf(buf, "%s %s %d\n", name, formatValue(p.Value), p.Timestamp)
+				}
+				if err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return count, err
+		}
+	}
+	if format == FormatOpenMetrics {
+		buf.WriteString("# EOF\n")
+	}
+	return count, buf.Flush()
+}
+
+// hasLabels reports whether the labels include every label of match.
+func hasLabels(labels []Label, match map[string]string) bool {
+	for name, value := range match {
+		found := false
+		for _, l := range labels {
+			if l.Name == name && l.Value == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLabelSet formats labels as name="value" pairs separated by commas, the values escaped
+// as in OpenMetrics and the names made valid Prometheus names if prom is set.
+func formatLabelSet(labels []Label, prom bool) string {
+	var b strings.Builder
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name := l.Name
+		if prom {
+			name = promName(name, false)
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(l.Value))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+// parseLabelSet parses labels formatted by formatLabelSet up to the end of s or a closing brace,
+// returning the rest of s after it.
+func parseLabelSet(s string) ([]Label, string, error) {
+	var labels []Label
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" || s[0] == '}' {
+			return labels, s, nil
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return nil, "", fmt.Errorf("invalid label near %q", s)
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+
+		var value strings.Builder
+		closed := false
+		for i := 0; i < len(s); i++ {
+			switch c := s[i]; {
+			case c == '\\' && i+1 < len(s):
+				i++
+				if s[i] == 'n' {
+					value.WriteByte('\n')
+				} else {
+					value.WriteByte(s[i])
+				}
+			case c == '"':
+				s, closed = s[i+1:], true
+			default:
+				value.WriteByte(c)
+			}
+			if closed {
+				break
+			}
+		}
+		if !closed {
+			return nil, "", fmt.Errorf("unterminated value of label %q", name)
+		}
+		labels = append(labels, Label{Name: name, Value: value.String()})
+		s = strings.TrimLeft(s, " ")
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+		}
+	}
+}
+
+// parseTimestamp parses RFC 3339 times and Unix seconds, fractions of a second dropped.
+func parseTimestamp(s string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Unix(), nil
+	}
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q, expected RFC 3339 or Unix seconds", s)
+	}
+	return int64(seconds), nil
+}
+
+// Import reads points in the format and inserts them with their labels remapped, in batches,
+// returning the number of points inserted. Reading stops at the first invalid line, the points
+// before it being inserted. Imported points are indexed and rolled up but not sent to the
+// remote write receiver.
+func Import(r io.Reader, format string, opts ImportOptions) (int, error) {
+	if !IsExportFormat(format) {
+		return 0, fmt.Errorf("unknown format %q, expected csv, ndjson or openmetrics", format)
+	}
+	sto, err := GetStorageInstance()
+	if err != nil {
+		return 0, fmt.Errorf("error getting storage instance: %w", err)
+	}
+
+	count := 0
+	batch := make([]Row, 0, importBatchSize)
+	insert := func(row Row) error {
+		row.Labels = remapLabels(row.Labels, opts)
+		batch = append(batch, row)
+		if len(batch) < importBatchSize {
+			return nil
+		}
+		if err := importRows(sto, batch); err != nil {
+			return err
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	switch format {
+	case FormatCSV:
+		err = importCSV(r, insert)
+	case FormatNDJSON:
+		err = importNDJSON(r, insert)
+	case FormatOpenMetrics:
+		err = importOpenMetrics(r, insert)
+	}
+	if len(batch) > 0 {
+		if insertErr := importRows(sto, batch); insertErr != nil {
+			return count, insertErr
+		}
+		count += len(batch)
+	}
+	return count, err
+}
+
+// rowImporter is implemented by the storage decorators to insert imported rows, which are
+// stored, indexed and rolled up like others but not sent to the remote write receiver.
+type rowImporter interface {
+	importRows(rows []Row) error
+}
+
+// importRows inserts imported rows into the storage, through importRows where implemented.
+func importRows(sto Storage, rows []Row) error {
+	if importer, ok := sto.(rowImporter); ok {
+		return importer.importRows(rows)
+	}
+	return sto.InsertRows(rows)
+}
+
+// remapLabels renames and sets labels as set by the options.
+func remapLabels(labels []Label, opts ImportOptions) []Label {
+	result := make([]Label, 0, len(labels)+len(opts.Labels))
+	for _, l := range labels {
+		if name, ok := opts.RenameLabels[l.Name]; ok {
+			l.Name = name
+		}
+		if _, ok := opts.Labels[l.Name]; ok || l.Name == "" {
+			continue
+		}
+		result = append(result, l)
+	}
+	for name, value := range opts.Labels {
+		result = append(result, Label{Name: name, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func importCSV(r io.Reader, insert func(Row) error) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n == 1 && record[0] == csvHeader[0] {
+			continue
+		}
+
+		labels, rest, err := parseLabelSet(record[1])
+		if err == nil && rest != "" {
+			err = fmt.Errorf("invalid labels %q", record[1])
+		}
+		var row Row
+		if err == nil {
+			row, err = newImportedRow(record[0], labels, record[2], record[3])
+		}
+		if err == nil {
+			err = insert(row)
+		}
+		if err != nil {
+			return fmt.Errorf("record %d: %w", n, err)
+		}
+	}
+}
+
+func importNDJSON(r io.Reader, insert func(Row) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var point exportedPoint
+		if err := json.Unmarshal(scanner.Bytes(), &point); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if point.Metric == "" || point.Timestamp.IsZero() {
+			return fmt.Errorf("line %d: metric and timestamp are required", line)
+		}
+		row := Row{Metric: point.Metric, DataPoint: DataPoint{Timestamp: point.Timestamp.Unix(), Value: float64(point.Value)}}
+		for name, value := range point.Labels {
+			row.Labels = append(row.Labels, Label{Name: name, Value: value})
+		}
+		if err := insert(row); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// importOpenMetrics reads the samples of an OpenMetrics or Prometheus text exposition, those
+// without a timestamp taken at the current time. The timestamps are seconds, as in OpenMetrics.
+func importOpenMetrics(r io.Reader, insert func(Row) error) error {
+	now := time.Now().Unix()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "# EOF" {
+			return nil
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		end := strings.IndexAny(text, "{ ")
+		if end <= 0 {
+			return fmt.Errorf("line %d: expected a metric name and a value", line)
+		}
+		metric, rest := text[:end], text[end:]
+		var labels []Label
+		if strings.HasPrefix(rest, "{") {
+			var err error
+			if labels, rest, err = parseLabelSet(rest[1:]); err != nil || !strings.HasPrefix(rest, "}") {
+				return fmt.Errorf("line %d: invalid labels: %v", line, err)
+			}
+			rest = rest[1:]
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 || len(fields) > 2 {
+			return fmt.Errorf("line %d: expected a value and an optional timestamp", line)
+		}
+		timestamp := strconv.FormatInt(now, 10)
+		if len(fields) == 2 {
+			timestamp = fields[1]
+		}
+		row, err := newImportedRow(metric, labels, timestamp, fields[0])
+		if err == nil {
+			err = insert(row)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// newImportedRow returns the row of a point read as text.
+func newImportedRow(metric string, labels []Label, timestamp, value string) (Row, error) {
+	if metric == "" {
+		return Row{}, errors.New("metric is required")
+	}
+	ts, err := parseTimestamp(timestamp)
+	if err != nil {
+		return Row{}, err
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return Row{}, fmt.Errorf("invalid value %q", value)
+	}
+	return Row{Metric: metric, Labels: labels, DataPoint: DataPoint{Timestamp: ts, Value: v}}, nil
+}
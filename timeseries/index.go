@@ -0,0 +1,201 @@
+package timeseries
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/iyashjayesh/monigo/internal/logger"
+)
+
+// seriesRecord is a series recorded in the series index file.
+type seriesRecord struct {
+	Metric string            `json:"metric"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// indexedStorage decorates a storage, recording the labels of every inserted series by metric
+// so queries can find the series matching label matchers. The disk storage's series are also
+// appended to a file, so the series written before a restart are found too.
+type indexedStorage struct {
+	Storage
+	mu     sync.RWMutex
+	series map[string]map[string][]Label // Labels of every series by metric and series key
+	path   string                        // Series index file, empty for none
+}
+
+// newIndexedStorage returns the storage decorated with an index, loaded from the file at path
+// unless it is empty.
+func newIndexedStorage(inner Storage, path string) *indexedStorage {
+	s := &indexedStorage{Storage: inner, series: make(map[string]map[string][]Label), path: path}
+	if path == "" {
+		return s
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Log.Warn("failed to read the series index", "error", err)
+		}
+		return s
+	}
+	defer file.Close()
+
+	var records []seriesRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record seriesRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue // A line cut short by a crash
+		}
+		records = append(records, record)
+	}
+	s.load(records)
+	return s
+}
+
+// load records the series stored before the index was created.
+func (s *indexedStorage) load(records []seriesRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range records {
+		labels := make([]Label, 0, len(record.Labels))
+		for name, value := range record.Labels {
+			labels = append(labels, Label{Name: name, Value: value})
+		}
+		s.add(record.Metric, labels)
+	}
+}
+
+// add records a series unless known and reports whether it was new. The caller holds s.mu.
+func (s *indexedStorage) add(metric string, labels []Label) bool {
+	key := seriesKey(metric, labels)
+	series, ok := s.series[metric]
+	if !ok {
+		series = make(map[string][]Label)
+		s.series[metric] = series
+	}
+	if _, ok := series[key]; ok {
+		return false
+	}
+
+	sorted := make([]Label, 0, len(labels))
+	for _, l := range labels {
+		if l.Name != "" && l.Value != "" {
+			sorted = append(sorted, l)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	series[key] = sorted
+	return true
+}
+
+// InsertRows inserts the rows and records their series.
+func (s *indexedStorage) InsertRows(rows []Row) error {
+	return s.insertRows(rows, s.Storage.InsertRows)
+}
+
+// importRows inserts imported rows, not sent to the remote write receiver, and records their series.
+func (s *indexedStorage) importRows(rows []Row) error {
+	return s.insertRows(rows, func(rows []Row) error { return importRows(s.Storage, rows) })
+}
+
+// insertRows inserts the rows with insert and records their series.
+func (s *indexedStorage) insertRows(rows []Row, insert func([]Row) error) error {
+	if err := insert(rows); err != nil {
+		return err
+	}
+
+	var added []seriesRecord
+	s.mu.Lock()
+	for _, row := range rows {
+		if s.add(row.Metric, row.Labels) && s.path != "" {
+			record := seriesRecord{Metric: row.Metric, Labels: make(map[string]string, len(row.Labels))}
+			for _, l := range row.Labels {
+				record.Labels[l.Name] = l.Value
+			}
+			added = append(added, record)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(added) > 0 {
+		if err := s.appendRecords(added); err != nil {
+			logger.Log.Warn("failed to record new series", "error", err)
+		}
+	}
+	return nil
+}
+
+// appendRecords appends series to the index file.
+func (s *indexedStorage) appendRecords(records []seriesRecord) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// metrics returns the names of the indexed metrics, sorted.
+func (s *indexedStorage) metrics() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.series))
+	for name := range s.series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// labels returns the labels of every indexed series of a metric, sorted by series key.
+func (s *indexedStorage) labels(metric string) [][]Label {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.series[metric]))
+	for key := range s.series[metric] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([][]Label, len(keys))
+	for i, key := range keys {
+		result[i] = append([]Label(nil), s.series[metric][key]...)
+	}
+	return result
+}
+
+// GetMetricNames returns the names of the metrics stored since the storage was created, or
+// ever for the disk storage, sorted.
+func GetMetricNames() ([]string, error) {
+	if _, err := GetStorageInstance(); err != nil {
+		return nil, err
+	}
+	return manager.index.metrics(), nil
+}
+
+// GetSeries returns the labels of every series of a metric stored since the storage was
+// created, or ever for the disk storage, with the labels of each series sorted by name.
+func GetSeries(metric string) ([][]Label, error) {
+	if _, err := GetStorageInstance(); err != nil {
+		return nil, err
+	}
+	return manager.index.labels(metric), nil
+}
@@ -0,0 +1,254 @@
+package timeseries
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+)
+
+// DefaultMaxMemoryPoints is the number of points the in-memory storage keeps at most unless
+// configured otherwise.
+const DefaultMaxMemoryPoints = 1_000_000
+
+const (
+	// chunkSize is the number of points a chunk of a series holds before a new one is started.
+	chunkSize = 128
+	// evictInterval is how often the points older than the retention are evicted.
+	evictInterval = time.Minute
+)
+
+// InMemoryStorage provides an in-memory implementation of the Storage interface. Series are
+// keyed by metric and labels and kept in chunks sorted by timestamp. Points older than the
+// retention are evicted periodically and the oldest points once the total exceeds the cap.
+type InMemoryStorage struct {
+	mu        sync.RWMutex
+	series    map[string]*memorySeries
+	points    int // Total number of points of every series
+	retention time.Duration
+	maxPoints int
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// memorySeries is the points of a series in chunks of up to chunkSize points, every chunk
+// and the chunks themselves sorted by timestamp.
+type memorySeries struct {
+	chunks [][]DataPoint
+}
+
+// NewInMemoryStorage returns an in-memory storage keeping the points for the data retention
+// period, at most the number set with SetMaxMemoryPoints.
+func NewInMemoryStorage() *InMemoryStorage {
+	return newInMemoryStorage(common.GetDataRetentionPeriod(), maxMemoryPoints)
+}
+
+// newInMemoryStorage returns an in-memory storage with the given retention and cap, zero
+// for none, evicting expired points until it is closed.
+func newInMemoryStorage(retention time.Duration, maxPoints int) *InMemoryStorage {
+	s := &InMemoryStorage{
+		series:    make(map[string]*memorySeries),
+		retention: retention,
+		maxPoints: maxPoints,
+		stop:      make(chan struct{}),
+	}
+	if retention > 0 {
+		go s.evictLoop()
+	}
+	return s
+}
+
+// seriesKey returns the key of the series of a metric and labels, independent of the order of
+// the labels. Labels with an empty name or value are ignored, as by the disk storage.
+func seriesKey(metric string, labels []Label) string {
+	sorted := make([]Label, 0, len(labels))
+	for _, l := range labels {
+		if l.Name != "" && l.Value != "" {
+			sorted = append(sorted, l)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var b strings.Builder
+	b.WriteString(metric)
+	for _, l := range sorted {
+		b.WriteByte(0)
+		b.WriteString(l.Name)
+		b.WriteByte(0)
+		b.WriteString(l.Value)
+	}
+	return b.String()
+}
+
+// InsertRows appends the rows to their series, evicting the oldest points beyond the cap.
+func (s *InMemoryStorage) InsertRows(rows []Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range rows {
+		key := seriesKey(row.Metric, row.Labels)
+		series, ok := s.series[key]
+		if !ok {
+			series = &memorySeries{}
+			s.series[key] = series
+		}
+		series.insert(row.DataPoint)
+		s.points++
+	}
+	if s.maxPoints > 0 && s.points > s.maxPoints {
+		s.evictOldest(s.points - s.maxPoints)
+	}
+	return nil
+}
+
+// Select returns the points of the series with exactly the given labels between start and
+// end inclusive, oldest first.
+func (s *InMemoryStorage) Select(metric string, labels []Label, start, end int64) ([]DataPoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	series, ok := s.series[seriesKey(metric, labels)]
+	if !ok {
+		return nil, nil
+	}
+	return series.selectRange(start, end), nil
+}
+
+// Close stops the eviction. Safe to call multiple times.
+func (s *InMemoryStorage) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	return nil
+}
+
+// evictLoop evicts the points older than the retention every evictInterval until closed.
+func (s *InMemoryStorage) evictLoop() {
+	ticker := time.NewTicker(evictInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.evictExpired(now)
+		}
+	}
+}
+
+// evictExpired removes the points older than the retention.
+func (s *InMemoryStorage) evictExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := now.Add(-s.retention).Unix()
+	for key, series := range s.series {
+		s.points -= series.evictBefore(cutoff)
+		if len(series.chunks) == 0 {
+			delete(s.series, key)
+		}
+	}
+}
+
+// evictOldest removes the n oldest points across every series. The caller holds s.mu.
+func (s *InMemoryStorage) evictOldest(n int) {
+	for n > 0 && len(s.series) > 0 {
+		// Drop the points of the series starting first up to the start of the next one
+		var oldestKey string
+		var oldest *memorySeries
+		next := int64(math.MaxInt64)
+		for key, series := range s.series {
+			first := series.chunks[0][0].Timestamp
+			if oldest == nil || first < oldest.chunks[0][0].Timestamp {
+				if oldest != nil {
+					next = min(next, oldest.chunks[0][0].Timestamp)
+				}
+				oldestKey, oldest = key, series
+			} else {
+				next = min(next, first)
+			}
+		}
+
+		chunk := oldest.chunks[0]
+		count := sort.Search(len(chunk), func(i int) bool { return chunk[i].Timestamp >= next })
+		count = min(max(count, 1), n)
+		oldest.dropFirst(count)
+		s.points -= count
+		n -= count
+		if len(oldest.chunks) == 0 {
+			delete(s.series, oldestKey)
+		}
+	}
+}
+
+// insert adds a point, appending to the last chunk unless it is older than the newest point.
+func (ms *memorySeries) insert(p DataPoint) {
+	n := len(ms.chunks)
+	if n == 0 || p.Timestamp >= ms.last(n-1).Timestamp {
+		if n == 0 || len(ms.chunks[n-1]) >= chunkSize {
+			ms.chunks = append(ms.chunks, make([]DataPoint, 0, chunkSize))
+			n++
+		}
+		ms.chunks[n-1] = append(ms.chunks[n-1], p)
+		return
+	}
+
+	// An out of order point goes to the first chunk ending after it, split once it is full
+	i := sort.Search(n, func(i int) bool { return ms.last(i).Timestamp > p.Timestamp })
+	chunk := ms.chunks[i]
+	j := sort.Search(len(chunk), func(j int) bool { return chunk[j].Timestamp > p.Timestamp })
+	chunk = slices.Insert(chunk, j, p)
+	if len(chunk) <= chunkSize {
+		ms.chunks[i] = chunk
+		return
+	}
+	half := len(chunk) / 2
+	ms.chunks[i] = chunk[:half:half]
+	ms.chunks = slices.Insert(ms.chunks, i+1, slices.Clone(chunk[half:]))
+}
+
+// last returns the newest point of the i-th chunk.
+func (ms *memorySeries) last(i int) DataPoint {
+	chunk := ms.chunks[i]
+	return chunk[len(chunk)-1]
+}
+
+// selectRange returns the points between start and end inclusive, finding the chunks and the
+// points within them by binary search.
+func (ms *memorySeries) selectRange(start, end int64) []DataPoint {
+	var result []DataPoint
+	i := sort.Search(len(ms.chunks), func(i int) bool { return ms.last(i).Timestamp >= start })
+	for ; i < len(ms.chunks) && ms.chunks[i][0].Timestamp <= end; i++ {
+		chunk := ms.chunks[i]
+		lo := sort.Search(len(chunk), func(j int) bool { return chunk[j].Timestamp >= start })
+		hi := sort.Search(len(chunk), func(j int) bool { return chunk[j].Timestamp > end })
+		result = append(result, chunk[lo:hi]...)
+	}
+	return result
+}
+
+// evictBefore removes the points older than cutoff and returns how many were removed.
+func (ms *memorySeries) evictBefore(cutoff int64) int {
+	i := sort.Search(len(ms.chunks), func(i int) bool { return ms.last(i).Timestamp >= cutoff })
+	removed := 0
+	for _, chunk := range ms.chunks[:i] {
+		removed += len(chunk)
+	}
+	ms.chunks = slices.Delete(ms.chunks, 0, i)
+	if len(ms.chunks) > 0 {
+		chunk := ms.chunks[0]
+		j := sort.Search(len(chunk), func(j int) bool { return chunk[j].Timestamp >= cutoff })
+		ms.chunks[0] = chunk[j:]
+		removed += j
+	}
+	return removed
+}
+
+// dropFirst removes the n oldest points of the first chunk.
+func (ms *memorySeries) dropFirst(n int) {
+	if n >= len(ms.chunks[0]) {
+		ms.chunks = slices.Delete(ms.chunks, 0, 1)
+		return
+	}
+	ms.chunks[0] = ms.chunks[0][n:]
+}
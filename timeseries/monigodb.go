@@ -23,48 +23,6 @@ type Storage interface {
 	Close() error
 }
 
-// InMemoryStorage provides an in-memory implementation of the Storage interface.
-type InMemoryStorage struct {
-	mu   sync.RWMutex
-	data map[string][]DataPoint
-}
-
-func NewInMemoryStorage() *InMemoryStorage {
-	return &InMemoryStorage{
-		data: make(map[string][]DataPoint),
-	}
-}
-
-func (s *InMemoryStorage) InsertRows(rows []Row) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	for _, row := range rows {
-		s.data[row.Metric] = append(s.data[row.Metric], row.DataPoint)
-	}
-	return nil
-}
-
-func (s *InMemoryStorage) Select(metric string, labels []Label, start, end int64) ([]DataPoint, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	points, ok := s.data[metric]
-	if !ok {
-		return nil, nil
-	}
-
-	var result []DataPoint
-	for _, p := range points {
-		if p.Timestamp >= start && p.Timestamp <= end {
-			result = append(result, p)
-		}
-	}
-	return result, nil
-}
-
-func (s *InMemoryStorage) Close() error {
-	return nil
-}
-
 // StorageWrapper wraps the tstorage.Storage to implement the Storage interface.
 type StorageWrapper struct {
 	storage tstorage.Storage
@@ -101,6 +59,8 @@ func (s *StorageWrapper) Close() error {
 
 type storageManager struct {
 	storage   Storage
+	rollups   *rollupStorage  // Decorates the raw storage, sending remote writes if set, unless rollups are disabled
+	index     *indexedStorage // Decorates the rollups or the raw storage
 	ctx       context.Context
 	cancel    context.CancelFunc
 	once      sync.Once
@@ -109,8 +69,9 @@ type storageManager struct {
 }
 
 var (
-	manager     = &storageManager{}
-	storageType = "disk" // "disk" or "memory"
+	manager         = &storageManager{}
+	storageType     = "disk" // "disk", "memory" or "bolt"
+	maxMemoryPoints = DefaultMaxMemoryPoints
 )
 
 // SetStorageType sets the storage type
@@ -118,27 +79,82 @@ func SetStorageType(t string) {
 	storageType = t
 }
 
+// SetMaxMemoryPoints sets the number of points the in-memory storage keeps at most
+func SetMaxMemoryPoints(n int) {
+	maxMemoryPoints = n
+}
+
 // GetStorageInstance initializes and returns a Storage instance.
 func GetStorageInstance() (Storage, error) {
 	var err error
 	manager.once.Do(func() {
-		if storageType == "memory" {
-			manager.storage = NewInMemoryStorage()
-			manager.ctx, manager.cancel = context.WithCancel(context.Background())
-			return
+		var raw Storage
+		switch storageType {
+		case "memory":
+			raw = NewInMemoryStorage()
+		case "bolt":
+			bolt, initErr := openBoltStorage(filepath.Join(common.GetBasePath(), BoltFileName), common.GetDataRetentionPeriod())
+			if initErr != nil {
+				err = initErr
+				logger.Log.Error("initializing storage", "error", err)
+				return
+			}
+			raw = bolt
+		default:
+			basePath := common.GetBasePath()
+			storageInstance, initErr := tstorage.NewStorage(
+				tstorage.WithDataPath(filepath.Join(basePath, "data")),
+				tstorage.WithRetention(common.GetDataRetentionPeriod()),
+			)
+			if initErr != nil {
+				err = initErr
+				logger.Log.Error("initializing storage", "error", err)
+				return
+			}
+			raw = &StorageWrapper{storage: storageInstance}
+		}
+
+		manager.storage = raw
+		if remoteWrite != nil {
+			writer, initErr := newRemoteWriteStorage(raw, *remoteWrite, filepath.Join(common.GetBasePath(), "remote_write"))
+			if initErr != nil {
+				raw.Close()
+				manager.storage = nil
+				err = initErr
+				logger.Log.Error("initializing remote write", "error", err)
+				return
+			}
+			manager.storage = writer
+		}
+		if len(rollupTiers) > 0 {
+			tiers, initErr := openRollupTiers(raw)
+			if initErr != nil {
+				manager.storage.Close()
+				manager.storage = nil
+				err = initErr
+				logger.Log.Error("initializing rollup storage", "error", err)
+				return
+			}
+			if len(tiers) > 0 {
+				manager.rollups = newRollupStorage(manager.storage, tiers)
+				manager.storage = manager.rollups
+			}
 		}
 
-		basePath := common.GetBasePath()
-		storageInstance, initErr := tstorage.NewStorage(
-			tstorage.WithDataPath(filepath.Join(basePath, "data")),
-			tstorage.WithRetention(common.GetDataRetentionPeriod()),
-		)
-		if initErr != nil {
-			err = initErr
-			logger.Log.Error("initializing storage", "error", err)
-			return
+		indexPath := ""
+		if storageType == "disk" {
+			indexPath = filepath.Join(common.GetBasePath(), "series.log")
+		}
+		manager.index = newIndexedStorage(manager.storage, indexPath)
+		if bolt, ok := raw.(*boltStorage); ok {
+			// The database records its series itself
+			records, seriesErr := bolt.series()
+			if seriesErr != nil {
+				logger.Log.Warn("failed to read the stored series", "error", seriesErr)
+			}
+			manager.index.load(records)
 		}
-		manager.storage = &StorageWrapper{storage: storageInstance}
+		manager.storage = manager.index
 		// Initialize context and cancel function for goroutines
 		manager.ctx, manager.cancel = context.WithCancel(context.Background())
 	})
@@ -197,6 +213,7 @@ func SetDataPointsSyncFrequency(frequency ...string) error {
 		logger.Log.Warn("invalid frequency format, using default 5m", "error", err)
 		freqTime = 5 * time.Minute
 	}
+	rawInterval.Store(int64(freqTime))
 
 	// Ensure storage is initialized before starting the sync loop
 	if _, err := GetStorageInstance(); err != nil {
@@ -204,7 +221,7 @@ func SetDataPointsSyncFrequency(frequency ...string) error {
 	}
 
 	// Initializing service metrics once
-	serviceMetrics := core.GetServiceStats(context.Background())
+	serviceMetrics := core.GetLatestServiceStats()
 	if err := StoreServiceMetrics(&serviceMetrics); err != nil {
 		return errors.New("[MoniGo] error storing service metrics, err: " + err.Error())
 	}
@@ -217,7 +234,7 @@ func SetDataPointsSyncFrequency(frequency ...string) error {
 			case <-manager.ctx.Done():
 				return
 			case <-ticker.C:
-				serviceMetrics := core.GetServiceStats(manager.ctx)
+				serviceMetrics := core.GetLatestServiceStats()
 				if err := StoreServiceMetrics(&serviceMetrics); err != nil {
 					logger.Log.Error("storing service metrics", "error", err)
 				}
@@ -0,0 +1,505 @@
+package timeseries
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// DefaultRemoteWriteBatchSize is the number of rows a remote write request holds at most
+	// unless configured otherwise.
+	DefaultRemoteWriteBatchSize = 2000
+	// DefaultRemoteWriteFlushInterval is how long rows wait for a full batch unless configured otherwise.
+	DefaultRemoteWriteFlushInterval = 30 * time.Second
+	// DefaultRemoteWriteTimeout is the timeout of a remote write request unless configured otherwise.
+	DefaultRemoteWriteTimeout = 10 * time.Second
+	// DefaultRemoteWriteMaxQueueSize is the size in bytes of the unsent batches kept on disk unless
+	// configured otherwise.
+	DefaultRemoteWriteMaxQueueSize = 64 << 20
+
+	// maxRemoteWriteBackoff is the longest wait between two attempts to send a batch.
+	maxRemoteWriteBackoff = time.Minute
+)
+
+var (
+	remoteWrite *models.RemoteWriteConfig
+	// remoteWriteBackoff is the wait before the first retry of a batch, doubled for each further one.
+	remoteWriteBackoff = time.Second
+)
+
+// SetRemoteWrite sets where the stored rows are also sent with the Prometheus remote write
+// protocol, nil for nowhere
+func SetRemoteWrite(cfg *models.RemoteWriteConfig) {
+	remoteWrite = cfg
+}
+
+// remoteWriteConfig is a remote write configuration with its durations parsed and defaults applied.
+type remoteWriteConfig struct {
+	url           string
+	headers       map[string]string
+	labels        []Label
+	batchSize     int
+	flushInterval time.Duration
+	timeout       time.Duration
+	maxQueueSize  int64
+}
+
+// ValidateRemoteWrite checks that the URL is an http or https URL and the sizes and durations
+// are valid.
+func ValidateRemoteWrite(cfg models.RemoteWriteConfig) error {
+	_, err := parseRemoteWrite(cfg)
+	return err
+}
+
+func parseRemoteWrite(cfg models.RemoteWriteConfig) (remoteWriteConfig, error) {
+	parsed := remoteWriteConfig{
+		url:           cfg.URL,
+		headers:       cfg.Headers,
+		batchSize:     cfg.BatchSize,
+		flushInterval: DefaultRemoteWriteFlushInterval,
+		timeout:       DefaultRemoteWriteTimeout,
+		maxQueueSize:  cfg.MaxQueueSize,
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return parsed, fmt.Errorf("remote write URL %q must be an http or https URL", cfg.URL)
+	}
+	if cfg.BatchSize < 0 || cfg.MaxQueueSize < 0 {
+		return parsed, errors.New("remote write batch size and max queue size must be >= 0")
+	}
+	for _, d := range []struct {
+		value  string
+		parsed *time.Duration
+	}{{cfg.FlushInterval, &parsed.flushInterval}, {cfg.Timeout, &parsed.timeout}} {
+		if d.value == "" {
+			continue
+		}
+		if *d.parsed, err = common.ParseDuration(d.value); err != nil || *d.parsed <= 0 {
+			return parsed, fmt.Errorf("remote write duration %q is invalid, e.g. '30s'", d.value)
+		}
+	}
+	if parsed.batchSize == 0 {
+		parsed.batchSize = DefaultRemoteWriteBatchSize
+	}
+	if parsed.maxQueueSize == 0 {
+		parsed.maxQueueSize = DefaultRemoteWriteMaxQueueSize
+	}
+	for name, value := range cfg.Labels {
+		parsed.labels = append(parsed.labels, Label{Name: promName(name, false), Value: value})
+	}
+	return parsed, nil
+}
+
+// remoteWriteStorage decorates a storage, sending the inserted rows in batches to a Prometheus
+// remote write receiver. Batches are queued on disk until sent, retried with an exponential
+// backoff while the receiver fails or is unreachable, so they survive restarts and outages
+// up to the size of the queue.
+type remoteWriteStorage struct {
+	Storage
+	config    remoteWriteConfig
+	client    *http.Client
+	queue     *batchQueue
+	mu        sync.Mutex
+	pending   []Row // Rows of the batch being filled
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newRemoteWriteStorage returns the storage decorated with remote writes, the batches queued
+// in dir.
+func newRemoteWriteStorage(inner Storage, cfg models.RemoteWriteConfig, dir string) (*remoteWriteStorage, error) {
+	config, err := parseRemoteWrite(cfg)
+	if err != nil {
+		return nil, err
+	}
+	queue, err := openBatchQueue(dir, config.maxQueueSize)
+	if err != nil {
+		return nil, fmt.Errorf("opening the remote write queue: %w", err)
+	}
+	s := &remoteWriteStorage{
+		Storage: inner,
+		config:  config,
+		client:  &http.Client{Timeout: config.timeout},
+		queue:   queue,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// InsertRows inserts the rows and adds them to the batch, queued once full.
+func (s *remoteWriteStorage) InsertRows(rows []Row) error {
+	if err := s.Storage.InsertRows(rows); err != nil {
+		return err
+	}
+
+	var full [][]Row
+	s.mu.Lock()
+	s.pending = append(s.pending, rows...)
+	for len(s.pending) >= s.config.batchSize {
+		full = append(full, s.pending[:s.config.batchSize:s.config.batchSize])
+		s.pending = s.pending[s.config.batchSize:]
+	}
+	s.mu.Unlock()
+
+	for _, batch := range full {
+		s.enqueue(batch)
+	}
+	return nil
+}
+
+// importRows inserts imported rows without sending them, since receivers reject samples as old
+// as most imports and the retries would fill the queue.
+func (s *remoteWriteStorage) importRows(rows []Row) error {
+	return s.Storage.InsertRows(rows)
+}
+
+// flush queues the rows of the batch being filled.
+func (s *remoteWriteStorage) flush() {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	if len(batch) > 0 {
+		s.enqueue(batch)
+	}
+}
+
+// enqueue encodes a batch into a request body, queues it and wakes the sender.
+func (s *remoteWriteStorage) enqueue(rows []Row) {
+	// The protocol expects the block format of Snappy
+	if err := s.queue.push(s2.EncodeSnappy(nil, encodeWriteRequest(rows, s.config.labels))); err != nil {
+		logger.Log.Error("failed to queue remote write batch", "rows", len(rows), "error", err)
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run sends the queued batches whenever one is queued, and queues the batch being filled every
+// flush interval, until closed.
+func (s *remoteWriteStorage) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.flushInterval)
+	defer ticker.Stop()
+	for {
+		s.drain()
+		select {
+		case <-s.stop:
+			return
+		case <-s.wake:
+		case <-ticker.C:
+			s.flush()
+		}
+	}
+}
+
+// drain sends the queued batches oldest first, retrying a failing batch until it is sent, is
+// rejected or the storage is closed.
+func (s *remoteWriteStorage) drain() {
+	backoff := remoteWriteBackoff
+	for {
+		name, body, err := s.queue.oldest()
+		if err != nil {
+			logger.Log.Error("failed to read remote write batch", "batch", name, "error", err)
+			s.queue.remove(name)
+			continue
+		}
+		if name == "" {
+			return
+		}
+
+		err = s.send(body)
+		var rejected *rejectedError
+		switch {
+		case err == nil:
+			s.queue.remove(name)
+			backoff = remoteWriteBackoff
+			continue
+		case errors.As(err, &rejected):
+			logger.Log.Error("remote write batch rejected, dropping it", "batch", name, "error", err)
+			s.queue.remove(name)
+			continue
+		}
+
+		logger.Log.Warn("remote write failed, retrying", "batch", name, "backoff", backoff, "error", err)
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRemoteWriteBackoff)
+	}
+}
+
+// rejectedError is a response retrying cannot change, a client error other than 429.
+type rejectedError struct {
+	status int
+	body   string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("receiver responded %d: %s", e.status, e.body)
+}
+
+// send posts a request body to the receiver.
+func (s *remoteWriteStorage) send(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "monigo")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for name, value := range s.config.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+		return &rejectedError{status: resp.StatusCode, body: strings.TrimSpace(string(message))}
+	}
+	return fmt.Errorf("receiver responded %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// Close stops sending, queues the batch being filled to be sent after a restart and closes
+// the storage.
+func (s *remoteWriteStorage) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		s.flush()
+		err = s.Storage.Close()
+	})
+	return err
+}
+
+// encodeWriteRequest encodes the rows into a remote write WriteRequest, a TimeSeries for every
+// series with its labels sorted by name and its samples by time.
+func encodeWriteRequest(rows []Row, extra []Label) []byte {
+	type timeSeries struct {
+		labels  []Label
+		samples []DataPoint
+	}
+	var keys []string
+	series := make(map[string]*timeSeries)
+	for _, row := range rows {
+		key := seriesKey(row.Metric, row.Labels)
+		ts, ok := series[key]
+		if !ok {
+			ts = &timeSeries{labels: promLabels(row.Metric, row.Labels, extra)}
+			series[key] = ts
+			keys = append(keys, key)
+		}
+		ts.samples = append(ts.samples, row.DataPoint)
+	}
+
+	var request []byte
+	for _, key := range keys {
+		ts := series[key]
+		sort.SliceStable(ts.samples, func(i, j int) bool { return ts.samples[i].Timestamp < ts.samples[j].Timestamp })
+
+		var message []byte
+		for _, l := range ts.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l.Name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l.Value)
+			message = protowire.AppendTag(message, 1, protowire.BytesType)
+			message = protowire.AppendBytes(message, label)
+		}
+		for _, p := range ts.samples {
+			var sample []byte
+			sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+			sample = protowire.AppendFixed64(sample, math.Float64bits(p.Value))
+			sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+			sample = protowire.AppendVarint(sample, uint64(p.Timestamp*1000)) // Milliseconds
+			message = protowire.AppendTag(message, 2, protowire.BytesType)
+			message = protowire.AppendBytes(message, sample)
+		}
+		request = protowire.AppendTag(request, 1, protowire.BytesType)
+		request = protowire.AppendBytes(request, message)
+	}
+	return request
+}
+
+// promLabels returns the Prometheus labels of a series: its metric name as __name__, its
+// labels and the extra labels it doesn't have, with valid names and sorted by name.
+func promLabels(metric string, labels, extra []Label) []Label {
+	result := []Label{{Name: "__name__", Value: promName(metric, true)}}
+	seen := map[string]bool{"__name__": true}
+	for _, group := range [][]Label{labels, extra} {
+		for _, l := range group {
+			name := promName(l.Name, false)
+			if l.Name == "" || l.Value == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			result = append(result, Label{Name: name, Value: l.Value})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// promName replaces the characters not allowed in a Prometheus metric or label name with
+// underscores, colons being allowed in metric names only.
+func promName(name string, metric bool) string {
+	b := []byte(name)
+	for i, c := range b {
+		valid := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9' && i > 0) || (c == ':' && metric)
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// batchQueue is a directory of request bodies waiting to be sent, one file each named after
+// its sequence number, dropping the oldest once their total size exceeds the maximum.
+type batchQueue struct {
+	dir     string
+	maxSize int64
+	mu      sync.Mutex
+	names   []string // Files oldest first
+	sizes   map[string]int64
+	size    int64
+	next    uint64 // Sequence number of the next file
+}
+
+// openBatchQueue opens the queue in dir, created if missing, with the batches left unsent.
+func openBatchQueue(dir string, maxSize int64) (*batchQueue, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &batchQueue{dir: dir, maxSize: maxSize, sizes: make(map[string]int64)}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			os.Remove(filepath.Join(dir, entry.Name())) // Cut short before its rename
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(entry.Name(), ".batch"), 10, 64)
+		if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), ".batch") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		q.names = append(q.names, entry.Name())
+		q.sizes[entry.Name()] = info.Size()
+		q.size += info.Size()
+		q.next = max(q.next, seq+1)
+	}
+	sort.Strings(q.names) // Zero-padded, so sorted by sequence number
+	return q, nil
+}
+
+// push adds a batch, written to a temporary file first so a crash never leaves half a batch.
+func (q *batchQueue) push(body []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	name := fmt.Sprintf("%020d.batch", q.next)
+	tmp := filepath.Join(q.dir, name+".tmp")
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(q.dir, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	q.next++
+	q.names = append(q.names, name)
+	q.sizes[name] = int64(len(body))
+	q.size += int64(len(body))
+
+	for q.size > q.maxSize && len(q.names) > 1 {
+		logger.Log.Warn("remote write queue is full, dropping the oldest batch", "batch", q.names[0], "max_size", q.maxSize)
+		q.removeLocked(q.names[0])
+	}
+	return nil
+}
+
+// oldest returns the name and body of the oldest batch, an empty name when there is none.
+func (q *batchQueue) oldest() (string, []byte, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.names) == 0 {
+		return "", nil, nil
+	}
+	name := q.names[0]
+	body, err := os.ReadFile(filepath.Join(q.dir, name))
+	return name, body, err
+}
+
+// remove deletes a batch.
+func (q *batchQueue) remove(name string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.removeLocked(name)
+}
+
+// removeLocked deletes a batch. The caller holds q.mu.
+func (q *batchQueue) removeLocked(name string) {
+	i := sort.SearchStrings(q.names, name)
+	if i == len(q.names) || q.names[i] != name {
+		return
+	}
+	q.names = append(q.names[:i], q.names[i+1:]...)
+	q.size -= q.sizes[name]
+	delete(q.sizes, name)
+	if err := os.Remove(filepath.Join(q.dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Log.Warn("failed to remove remote write batch", "batch", name, "error", err)
+	}
+}
+
+// count returns the number of queued batches.
+func (q *batchQueue) count() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.names)
+}
@@ -0,0 +1,389 @@
+package timeseries
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/iyashjayesh/monigo/common"
+	"github.com/iyashjayesh/monigo/internal/logger"
+	"github.com/iyashjayesh/monigo/models"
+	"github.com/nakabonne/tstorage"
+)
+
+// Aggregations stored for every rollup bucket.
+const (
+	AggregationMin   = "min"
+	AggregationMax   = "max"
+	AggregationAvg   = "avg"
+	AggregationLast  = "last"
+	AggregationCount = "count"
+)
+
+// RollupLabel is the label holding the aggregation of a rollup series.
+const RollupLabel = "rollup"
+
+// DefaultMaxQueryPoints is the number of points per series a downsampled query returns at most
+// when a resolution can keep it under.
+const DefaultMaxQueryPoints = 1000
+
+// DefaultRollupTiers are the resolutions the metrics are rolled up to unless configured otherwise.
+var DefaultRollupTiers = []models.RollupTier{
+	{Resolution: "1m", Retention: "3d"},
+	{Resolution: "10m", Retention: "30d"},
+	{Resolution: "1h", Retention: "365d"},
+}
+
+var (
+	rollupTiers = DefaultRollupTiers
+	rawInterval atomic.Int64 // Time between two raw points, set with the sync frequency
+)
+
+func init() {
+	rawInterval.Store(int64(5 * time.Minute))
+}
+
+// SetRollupTiers sets the resolutions the metrics are rolled up to, none disables the rollups
+func SetRollupTiers(tiers []models.RollupTier) {
+	rollupTiers = tiers
+}
+
+// ValidateRollupTiers checks that every tier has a distinct resolution of at least a second and
+// a positive retention.
+func ValidateRollupTiers(tiers []models.RollupTier) error {
+	_, err := parseRollupTiers(tiers)
+	return err
+}
+
+// rollupTier is a resolution the metrics are rolled up to, stored with its own retention.
+type rollupTier struct {
+	name       string
+	resolution time.Duration
+	retention  time.Duration
+	storage    Storage
+}
+
+// parseRollupTiers returns the tiers without storage, finest first.
+func parseRollupTiers(tiers []models.RollupTier) ([]*rollupTier, error) {
+	parsed := make([]*rollupTier, 0, len(tiers))
+	for _, t := range tiers {
+		resolution, err := common.ParseDuration(t.Resolution)
+		if err != nil || resolution < time.Second {
+			return nil, fmt.Errorf("rollup resolution %q must be a duration of at least a second, e.g. '10m'", t.Resolution)
+		}
+		retention, err := common.ParseDuration(t.Retention)
+		if err != nil || retention <= 0 {
+			return nil, fmt.Errorf("rollup tier %q has an invalid retention %q, e.g. '30d'", t.Resolution, t.Retention)
+		}
+		if slices.ContainsFunc(parsed, func(p *rollupTier) bool { return p.resolution == resolution }) {
+			return nil, fmt.Errorf("rollup resolution %q is configured more than once", t.Resolution)
+		}
+		parsed = append(parsed, &rollupTier{name: t.Resolution, resolution: resolution, retention: retention})
+	}
+	sort.Slice(parsed, func(i, j int) bool { return parsed[i].resolution < parsed[j].resolution })
+	return parsed, nil
+}
+
+// openRollupTiers returns the configured tiers coarser than the sync frequency with their
+// storage, in memory, in a bucket of the database of the raw points or on disk under the data
+// directory like the raw points.
+func openRollupTiers(raw Storage) ([]*rollupTier, error) {
+	tiers, err := parseRollupTiers(rollupTiers)
+	if err != nil {
+		return nil, err
+	}
+	// The buckets of the other tiers would hold a single point
+	tiers = slices.DeleteFunc(tiers, func(tier *rollupTier) bool { return tier.resolution <= time.Duration(rawInterval.Load()) })
+	for i, tier := range tiers {
+		if storageType == "memory" {
+			tier.storage = newInMemoryStorage(tier.retention, maxMemoryPoints)
+			continue
+		}
+		if bolt, ok := raw.(*boltStorage); ok {
+			if tier.storage, err = bolt.share("rollups/"+tier.name, tier.retention); err != nil {
+				for _, opened := range tiers[:i] {
+					opened.storage.Close()
+				}
+				return nil, err
+			}
+			continue
+		}
+		storageInstance, err := tstorage.NewStorage(
+			tstorage.WithDataPath(filepath.Join(common.GetBasePath(), "rollups", tier.name)),
+			tstorage.WithRetention(tier.retention),
+		)
+		if err != nil {
+			for _, opened := range tiers[:i] {
+				opened.storage.Close()
+			}
+			return nil, fmt.Errorf("opening the %s rollup storage: %w", tier.name, err)
+		}
+		tier.storage = &StorageWrapper{storage: storageInstance}
+	}
+	return tiers, nil
+}
+
+// bucket accumulates the points of a series within a rollup bucket.
+type bucket struct {
+	metric string
+	labels []Label
+	start  int64
+	min    float64
+	max    float64
+	sum    float64
+	last   float64
+	count  int
+}
+
+func newBucket(row Row, start int64) *bucket {
+	v := row.DataPoint.Value
+	return &bucket{metric: row.Metric, labels: slices.Clone(row.Labels), start: start, min: v, max: v, sum: v, last: v, count: 1}
+}
+
+func (b *bucket) add(v float64) {
+	b.min = min(b.min, v)
+	b.max = max(b.max, v)
+	b.sum += v
+	b.last = v
+	b.count++
+}
+
+// value returns the aggregation of the bucket's points.
+func (b *bucket) value(aggregation string) float64 {
+	switch aggregation {
+	case AggregationMin:
+		return b.min
+	case AggregationMax:
+		return b.max
+	case AggregationLast:
+		return b.last
+	case AggregationCount:
+		return float64(b.count)
+	default:
+		return b.sum / float64(b.count)
+	}
+}
+
+// rows returns a row per aggregation, timestamped with the start of the bucket.
+func (b *bucket) rows() []Row {
+	aggregations := []string{AggregationMin, AggregationMax, AggregationAvg, AggregationLast, AggregationCount}
+	rows := make([]Row, len(aggregations))
+	for i, aggregation := range aggregations {
+		rows[i] = Row{
+			Metric:    b.metric,
+			Labels:    rollupLabels(b.labels, aggregation),
+			DataPoint: DataPoint{Timestamp: b.start, Value: b.value(aggregation)},
+		}
+	}
+	return rows
+}
+
+// rollupLabels returns the labels of the rollup series of an aggregation.
+func rollupLabels(labels []Label, aggregation string) []Label {
+	return append(labels[:len(labels):len(labels)], Label{Name: RollupLabel, Value: aggregation})
+}
+
+// rollupStorage decorates the storage of the raw points, rolling the inserted points up into
+// buckets of every tier coarser than the sync frequency, written to the tier's storage once
+// complete.
+type rollupStorage struct {
+	Storage
+	tiers     []*rollupTier
+	mu        sync.Mutex
+	buckets   []map[string]*bucket // Open bucket of every series by tier
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newRollupStorage returns the raw storage decorated with the tiers, flushing the buckets
+// completed without newer points every finest resolution until closed.
+func newRollupStorage(raw Storage, tiers []*rollupTier) *rollupStorage {
+	s := &rollupStorage{
+		Storage: raw,
+		tiers:   tiers,
+		buckets: make([]map[string]*bucket, len(tiers)),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for i := range tiers {
+		s.buckets[i] = make(map[string]*bucket)
+	}
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(tiers[0].resolution)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case now := <-ticker.C:
+				if err := s.flush(now.Unix()); err != nil {
+					logger.Log.Error("writing rollups", "error", err)
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// InsertRows inserts the raw rows and adds them to the open buckets, writing the buckets a
+// row is past. Rows older than the open bucket of their series are only kept raw.
+func (s *rollupStorage) InsertRows(rows []Row) error {
+	return s.insertRows(rows, s.Storage.InsertRows)
+}
+
+// importRows inserts imported raw rows, not sent to the remote write receiver, and rolls them up.
+func (s *rollupStorage) importRows(rows []Row) error {
+	return s.insertRows(rows, func(rows []Row) error { return importRows(s.Storage, rows) })
+}
+
+// insertRows inserts the raw rows with insert and adds them to the open buckets.
+func (s *rollupStorage) insertRows(rows []Row, insert func([]Row) error) error {
+	if err := insert(rows); err != nil {
+		return err
+	}
+
+	raw := time.Duration(rawInterval.Load())
+	complete := make([][]Row, len(s.tiers))
+	s.mu.Lock()
+	for _, row := range rows {
+		key := seriesKey(row.Metric, row.Labels)
+		for i, tier := range s.tiers {
+			if tier.resolution <= raw {
+				continue // Buckets would hold a single point
+			}
+			ts := row.DataPoint.Timestamp
+			start := ts - ts%int64(tier.resolution/time.Second)
+			b := s.buckets[i][key]
+			switch {
+			case b == nil || start > b.start:
+				if b != nil {
+					complete[i] = append(complete[i], b.rows()...)
+				}
+				s.buckets[i][key] = newBucket(row, start)
+			case start == b.start:
+				b.add(row.DataPoint.Value)
+			}
+		}
+	}
+	s.mu.Unlock()
+	return s.write(complete)
+}
+
+// flush writes the buckets ending at or before now, or every open bucket for a zero now.
+func (s *rollupStorage) flush(now int64) error {
+	complete := make([][]Row, len(s.tiers))
+	s.mu.Lock()
+	for i, tier := range s.tiers {
+		for key, b := range s.buckets[i] {
+			if now == 0 || b.start+int64(tier.resolution/time.Second) <= now {
+				complete[i] = append(complete[i], b.rows()...)
+				delete(s.buckets[i], key)
+			}
+		}
+	}
+	s.mu.Unlock()
+	return s.write(complete)
+}
+
+// write inserts the rows of every tier into its storage.
+func (s *rollupStorage) write(rows [][]Row) error {
+	var errs []error
+	for i, tier := range s.tiers {
+		if len(rows[i]) == 0 {
+			continue
+		}
+		if err := tier.storage.InsertRows(rows[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%s rollups: %w", tier.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// selectRollup returns the points of a tier's series with the aggregation between start and
+// end, the open bucket included.
+func (s *rollupStorage) selectRollup(i int, metric string, labels []Label, aggregation string, start, end int64) ([]DataPoint, error) {
+	points, err := s.tiers[i].storage.Select(metric, rollupLabels(labels, aggregation), start, end)
+	if err != nil && !errors.Is(err, ErrNoDataPoints) {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.buckets[i][seriesKey(metric, labels)]; ok && b.start >= start && b.start <= end {
+		points = append(points, DataPoint{Timestamp: b.start, Value: b.value(aggregation)})
+	}
+	return points, nil
+}
+
+// pickTier returns the finest tier, -1 for the raw points, returning at most maxPoints points
+// between start and end and keeping points as old as start, or the one keeping points longest
+// when none does both.
+func (s *rollupStorage) pickTier(start, end, now int64, maxPoints int) int {
+	resolutions := []time.Duration{time.Duration(rawInterval.Load())}
+	retentions := []time.Duration{common.GetDataRetentionPeriod()}
+	for _, tier := range s.tiers {
+		resolutions = append(resolutions, tier.resolution)
+		retentions = append(retentions, tier.retention)
+	}
+
+	longest := 0
+	for i := range resolutions {
+		if i > 0 && resolutions[i] <= resolutions[0] {
+			continue // Not rolled up
+		}
+		covers := start >= now-int64(retentions[i]/time.Second)
+		if covers && (end-start)/int64(resolutions[i]/time.Second) <= int64(maxPoints) {
+			return i - 1
+		}
+		if retentions[i] > retentions[longest] {
+			longest = i
+		}
+	}
+	return longest - 1
+}
+
+// Close writes the open buckets and closes the storage of every tier and of the raw points.
+func (s *rollupStorage) Close() error {
+	var errs []error
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		errs = append(errs, s.flush(0))
+		for _, tier := range s.tiers {
+			errs = append(errs, tier.storage.Close())
+		}
+		errs = append(errs, s.Storage.Close())
+	})
+	return errors.Join(errs...)
+}
+
+// GetDownsampledDataPoints returns the points of a metric between start and end at the finest
+// resolution returning at most maxPoints points, the raw points or the given aggregation of a
+// rollup tier, and the resolution of the points, zero for raw points.
+func GetDownsampledDataPoints(metric string, labels []Label, aggregation string, start, end int64, maxPoints int) ([]DataPoint, time.Duration, error) {
+	sto, err := GetStorageInstance()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error getting storage instance: %w", err)
+	}
+
+	rollups := manager.rollups
+	if rollups == nil {
+		points, err := sto.Select(metric, labels, start, end)
+		return points, 0, err
+	}
+	i := rollups.pickTier(start, end, time.Now().Unix(), maxPoints)
+	if i < 0 {
+		points, err := sto.Select(metric, labels, start, end)
+		return points, 0, err
+	}
+	points, err := rollups.selectRollup(i, metric, labels, aggregation, start, end)
+	return points, rollups.tiers[i].resolution, err
+}
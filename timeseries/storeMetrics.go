@@ -3,6 +3,7 @@ package timeseries
 import (
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/iyashjayesh/monigo/models"
@@ -47,7 +48,12 @@ func StoreServiceMetrics(serviceMetrics *models.ServiceStats) error {
 	rows = append(rows, generateCPUStatsRows(serviceMetrics, label, timestamp)...)
 	rows = append(rows, generateMemoryStatsRows(serviceMetrics, label, timestamp)...)
 	rows = append(rows, generateNetworkIORows(serviceMetrics, label, timestamp)...)
+	rows = append(rows, generateProcessStatsRows(serviceMetrics, label, timestamp)...)
+	rows = append(rows, generateGCStatsRows(serviceMetrics, label, timestamp)...)
 	rows = append(rows, generateHealthStatsRows(serviceMetrics, label, timestamp)...)
+	rows = append(rows, generateCollectorRows(serviceMetrics, label, timestamp)...)
+	rows = append(rows, generateCustomMetricRows(serviceMetrics, label, timestamp)...)
+	rows = append(rows, generateGoroutineSignatureRows(serviceMetrics, label, timestamp)...)
 
 	if err := sto.InsertRows(rows); err != nil {
 		return fmt.Errorf("error storing service metrics: %w", err)
@@ -200,7 +206,7 @@ func generateMemoryStatsRows(serviceMetrics *models.ServiceStats, label Label, t
 
 // generateNetworkIORows generates rows for network IO statistics.
 func generateNetworkIORows(serviceMetrics *models.ServiceStats, label Label, timestamp int64) []Row {
-	return []Row{
+	rows := []Row{
 		{
 			Metric:    "bytes_sent",
 			DataPoint: DataPoint{Timestamp: timestamp, Value: serviceMetrics.NetworkIO.BytesSent},
@@ -211,7 +217,108 @@ func generateNetworkIORows(serviceMetrics *models.ServiceStats, label Label, tim
 			DataPoint: DataPoint{Timestamp: timestamp, Value: serviceMetrics.NetworkIO.BytesReceived},
 			Labels:    []Label{label},
 		},
+		{
+			Metric:    "bytes_sent_per_sec",
+			DataPoint: DataPoint{Timestamp: timestamp, Value: serviceMetrics.NetworkStatistics.BytesSentPerSec},
+			Labels:    []Label{label},
+		},
+		{
+			Metric:    "bytes_received_per_sec",
+			DataPoint: DataPoint{Timestamp: timestamp, Value: serviceMetrics.NetworkStatistics.BytesReceivedPerSec},
+			Labels:    []Label{label},
+		},
+	}
+
+	// Adding per-interface series, labelled with the interface name
+	for _, iface := range serviceMetrics.NetworkStatistics.Interfaces {
+		labels := []Label{label, {Name: "interface", Value: iface.Name}}
+		values := []struct {
+			metric string
+			value  float64
+		}{
+			{"net_bytes_sent", float64(iface.BytesSent)},
+			{"net_bytes_received", float64(iface.BytesReceived)},
+			{"net_packets_sent", float64(iface.PacketsSent)},
+			{"net_packets_received", float64(iface.PacketsReceived)},
+			{"net_errors_in", float64(iface.ErrorsIn)},
+			{"net_errors_out", float64(iface.ErrorsOut)},
+			{"net_drops_in", float64(iface.DropsIn)},
+			{"net_drops_out", float64(iface.DropsOut)},
+			{"net_bytes_sent_per_sec", iface.BytesSentPerSec},
+			{"net_bytes_received_per_sec", iface.BytesReceivedPerSec},
+			{"net_packets_sent_per_sec", iface.PacketsSentPerSec},
+			{"net_packets_received_per_sec", iface.PacketsReceivedPerSec},
+			{"net_errors_in_per_sec", iface.ErrorsInPerSec},
+			{"net_errors_out_per_sec", iface.ErrorsOutPerSec},
+			{"net_drops_in_per_sec", iface.DropsInPerSec},
+			{"net_drops_out_per_sec", iface.DropsOutPerSec},
+		}
+		for _, v := range values {
+			rows = append(rows, Row{
+				Metric:    v.metric,
+				DataPoint: DataPoint{Timestamp: timestamp, Value: v.value},
+				Labels:    labels,
+			})
+		}
+	}
+
+	return rows
+}
+
+// generateProcessStatsRows generates rows for process resource statistics.
+func generateProcessStatsRows(serviceMetrics *models.ServiceStats, label Label, timestamp int64) []Row {
+	ps := serviceMetrics.ProcessStatistics
+	values := []struct {
+		metric string
+		value  float64
+	}{
+		{"process_open_fds", float64(ps.OpenFDs)},
+		{"process_max_fds", float64(ps.MaxFDs)},
+		{"process_fd_usage_percent", ps.FDUsagePercent},
+		{"process_threads", float64(ps.Threads)},
+		{"process_voluntary_ctx_switches", float64(ps.VoluntaryCtxSwitches)},
+		{"process_involuntary_ctx_switches", float64(ps.InvoluntaryCtxSwitches)},
+		{"process_minor_page_faults", float64(ps.MinorPageFaults)},
+		{"process_major_page_faults", float64(ps.MajorPageFaults)},
+		{"process_resident_memory", float64(ps.ResidentMemoryRaw)},
+		{"process_virtual_memory", float64(ps.VirtualMemoryRaw)},
+	}
+
+	rows := make([]Row, 0, len(values))
+	for _, v := range values {
+		rows = append(rows, Row{
+			Metric:    v.metric,
+			DataPoint: DataPoint{Timestamp: timestamp, Value: v.value},
+			Labels:    []Label{label},
+		})
 	}
+	return rows
+}
+
+// generateGCStatsRows generates rows for GC statistics.
+func generateGCStatsRows(serviceMetrics *models.ServiceStats, label Label, timestamp int64) []Row {
+	gc := serviceMetrics.GCStatistics
+	values := []struct {
+		metric string
+		value  float64
+	}{
+		{"gc_percent", float64(gc.GCPercent)},
+		{"gc_memory_limit", float64(gc.MemoryLimit)},
+		{"gc_heap_goal", float64(gc.HeapGoal)},
+		{"gc_heap_live", float64(gc.HeapLive)},
+		{"gc_cycles_per_minute", gc.CyclesPerMinute},
+		{"gc_cpu_percent", gc.CPUPercent},
+	}
+
+	rows := make([]Row, 0, len(values))
+	for _, v := range values {
+		rows = append(rows, Row{
+			Metric:    v.metric,
+			DataPoint: DataPoint{Timestamp: timestamp, Value: v.value},
+			Labels:    []Label{label},
+		})
+	}
+	return rows
 }
 
 // generateHealthStatsRows generates rows for service and system health statistics.
@@ -229,3 +336,89 @@ func generateHealthStatsRows(serviceMetrics *models.ServiceStats, label Label, t
 		},
 	}
 }
+
+// generateCollectorRows generates rows for metrics reported by custom collectors, labelled
+// with the collector name and the labels of each metric.
+func generateCollectorRows(serviceMetrics *models.ServiceStats, label Label, timestamp int64) []Row {
+	var rows []Row
+	for collector, metrics := range serviceMetrics.CollectorMetrics {
+		for _, metric := range metrics {
+			rows = append(rows, Row{
+				Metric:    metric.Name,
+				DataPoint: DataPoint{Timestamp: timestamp, Value: metric.Value},
+				Labels:    collectorLabels(label, collector, metric.Labels),
+			})
+		}
+	}
+	return rows
+}
+
+// generateCustomMetricRows generates rows for custom application metrics, labelled with
+// collector "custom" and the labels of each metric. Histograms are stored as
+// <name>_count, <name>_sum and <name> series labelled with each estimated quantile.
+func generateCustomMetricRows(serviceMetrics *models.ServiceStats, label Label, timestamp int64) []Row {
+	var rows []Row
+	for _, metric := range serviceMetrics.CustomMetrics {
+		labels := collectorLabels(label, "custom", metric.Labels)
+		if metric.Type != "histogram" {
+			rows = append(rows, Row{
+				Metric:    metric.Name,
+				DataPoint: DataPoint{Timestamp: timestamp, Value: metric.Value},
+				Labels:    labels,
+			})
+			continue
+		}
+
+		rows = append(rows,
+			Row{
+				Metric:    metric.Name + "_count",
+				DataPoint: DataPoint{Timestamp: timestamp, Value: float64(metric.Count)},
+				Labels:    labels,
+			},
+			Row{
+				Metric:    metric.Name + "_sum",
+				DataPoint: DataPoint{Timestamp: timestamp, Value: metric.Sum},
+				Labels:    labels,
+			},
+		)
+		for quantile, value := range metric.Quantiles {
+			rows = append(rows, Row{
+				Metric:    metric.Name,
+				DataPoint: DataPoint{Timestamp: timestamp, Value: value},
+				Labels:    append(labels[:len(labels):len(labels)], Label{Name: "quantile", Value: quantile}),
+			})
+		}
+	}
+	return rows
+}
+
+// generateGoroutineSignatureRows generates goroutines_by_signature rows for the largest
+// stack signatures, labelled with the signature.
+func generateGoroutineSignatureRows(serviceMetrics *models.ServiceStats, label Label, timestamp int64) []Row {
+	rows := make([]Row, 0, len(serviceMetrics.GoroutineSignatures))
+	for _, sig := range serviceMetrics.GoroutineSignatures {
+		rows = append(rows, Row{
+			Metric:    "goroutines_by_signature",
+			DataPoint: DataPoint{Timestamp: timestamp, Value: float64(sig.Count)},
+			Labels:    []Label{label, {Name: "signature", Value: sig.Signature}},
+		})
+	}
+	return rows
+}
+
+// collectorLabels returns the host and collector labels followed by the metric labels sorted by name.
+func collectorLabels(label Label, collector string, metricLabels map[string]string) []Label {
+	labels := []Label{label, {Name: "collector", Value: collector}}
+	names := make([]string, 0, len(metricLabels))
+	for name := range metricLabels {
+		// "host" and "collector" are reserved for the labels set above
+		if name != label.Name && name != "collector" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		labels = append(labels, Label{Name: name, Value: metricLabels[name]})
+	}
+	return labels
+}
@@ -1,12 +1,23 @@
 package timeseries
 
 import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/iyashjayesh/monigo/common"
 	"github.com/iyashjayesh/monigo/models"
+	"github.com/klauspost/compress/s2"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 func init() {
@@ -15,8 +26,10 @@ func init() {
 
 func TestInMemoryStorage_InsertAndSelect(t *testing.T) {
 	s := NewInMemoryStorage()
+	defer s.Close()
 
 	now := time.Now().Unix()
+	host := []Label{{Name: "host", Value: "test"}}
 	rows := []Row{
 		{Metric: "cpu_load", DataPoint: DataPoint{Timestamp: now, Value: 45.5}, Labels: []Label{{Name: "host", Value: "test"}}},
 		{Metric: "cpu_load", DataPoint: DataPoint{Timestamp: now + 10, Value: 55.0}, Labels: []Label{{Name: "host", Value: "test"}}},
@@ -28,7 +41,7 @@ func TestInMemoryStorage_InsertAndSelect(t *testing.T) {
 	}
 
 	// Select cpu_load
-	points, err := s.Select("cpu_load", nil, now-1, now+20)
+	points, err := s.Select("cpu_load", host, now-1, now+20)
 	if err != nil {
 		t.Fatalf("Select error: %v", err)
 	}
@@ -37,7 +50,7 @@ func TestInMemoryStorage_InsertAndSelect(t *testing.T) {
 	}
 
 	// Select with time range filter
-	points, err = s.Select("cpu_load", nil, now+5, now+20)
+	points, err = s.Select("cpu_load", host, now+5, now+20)
 	if err != nil {
 		t.Fatalf("Select error: %v", err)
 	}
@@ -46,7 +59,7 @@ func TestInMemoryStorage_InsertAndSelect(t *testing.T) {
 	}
 
 	// Select non-existent metric
-	points, err = s.Select("nonexistent", nil, now-1, now+20)
+	points, err = s.Select("nonexistent", host, now-1, now+20)
 	if err != nil {
 		t.Fatalf("Select error: %v", err)
 	}
@@ -62,6 +75,618 @@ func TestInMemoryStorage_Close(t *testing.T) {
 	}
 }
 
+func TestInMemoryStorage_Labels(t *testing.T) {
+	s := newInMemoryStorage(0, 0)
+
+	eth0 := []Label{{Name: "host", Value: "test"}, {Name: "interface", Value: "eth0"}}
+	eth1 := []Label{{Name: "host", Value: "test"}, {Name: "interface", Value: "eth1"}}
+	s.InsertRows([]Row{
+		{Metric: "net_bytes_sent", DataPoint: DataPoint{Timestamp: 1, Value: 100}, Labels: eth0},
+		{Metric: "net_bytes_sent", DataPoint: DataPoint{Timestamp: 1, Value: 50}, Labels: eth1},
+	})
+
+	// Labels select a series whatever their order
+	points, _ := s.Select("net_bytes_sent", []Label{eth1[1], eth1[0]}, 0, 10)
+	if len(points) != 1 || points[0].Value != 50 {
+		t.Errorf("expected the eth1 point, got %v", points)
+	}
+	if points, _ := s.Select("net_bytes_sent", eth0[:1], 0, 10); points != nil {
+		t.Errorf("expected no series with only the host label, got %v", points)
+	}
+}
+
+func TestInMemoryStorage_Chunks(t *testing.T) {
+	s := newInMemoryStorage(0, 0)
+	labels := []Label{{Name: "host", Value: "test"}}
+
+	// Odd timestamps in order, then even ones out of order, across several chunks
+	for ts := int64(1); ts < 4*chunkSize; ts += 2 {
+		s.InsertRows([]Row{{Metric: "m", DataPoint: DataPoint{Timestamp: ts, Value: float64(ts)}, Labels: labels}})
+	}
+	for ts := int64(4*chunkSize - 2); ts >= 0; ts -= 2 {
+		s.InsertRows([]Row{{Metric: "m", DataPoint: DataPoint{Timestamp: ts, Value: float64(ts)}, Labels: labels}})
+	}
+
+	points, _ := s.Select("m", labels, 0, 4*chunkSize)
+	if len(points) != 4*chunkSize {
+		t.Fatalf("expected %d points, got %d", 4*chunkSize, len(points))
+	}
+	for i, p := range points {
+		if p.Timestamp != int64(i) {
+			t.Fatalf("expected the points sorted, got %d at %d", p.Timestamp, i)
+		}
+	}
+	for _, chunk := range s.series[seriesKey("m", labels)].chunks {
+		if len(chunk) > chunkSize {
+			t.Errorf("expected chunks of at most %d points, got %d", chunkSize, len(chunk))
+		}
+	}
+
+	points, _ = s.Select("m", labels, 100, 300)
+	if len(points) != 201 || points[0].Timestamp != 100 || points[200].Timestamp != 300 {
+		t.Errorf("expected the points from 100 to 300, got %d points", len(points))
+	}
+}
+
+func TestInMemoryStorage_Retention(t *testing.T) {
+	s := newInMemoryStorage(time.Hour, 0)
+	defer s.Close()
+
+	now := time.Now()
+	labels := []Label{{Name: "host", Value: "test"}}
+	s.InsertRows([]Row{
+		{Metric: "old", DataPoint: DataPoint{Timestamp: now.Add(-2 * time.Hour).Unix()}, Labels: labels},
+		{Metric: "mixed", DataPoint: DataPoint{Timestamp: now.Add(-90 * time.Minute).Unix()}, Labels: labels},
+		{Metric: "mixed", DataPoint: DataPoint{Timestamp: now.Unix(), Value: 1}, Labels: labels},
+	})
+
+	s.evictExpired(now)
+	if _, ok := s.series[seriesKey("old", labels)]; ok {
+		t.Error("expected the expired series to be removed")
+	}
+	points, _ := s.Select("mixed", labels, 0, now.Unix())
+	if len(points) != 1 || points[0].Value != 1 || s.points != 1 {
+		t.Errorf("expected only the recent point to be kept, got %v (%d in total)", points, s.points)
+	}
+}
+
+func TestInMemoryStorage_MaxPoints(t *testing.T) {
+	s := newInMemoryStorage(0, 10)
+
+	a := []Label{{Name: "series", Value: "a"}}
+	b := []Label{{Name: "series", Value: "b"}}
+	for ts := int64(0); ts < 8; ts++ {
+		s.InsertRows([]Row{
+			{Metric: "m", DataPoint: DataPoint{Timestamp: ts}, Labels: a},
+			{Metric: "m", DataPoint: DataPoint{Timestamp: ts + 4}, Labels: b},
+		})
+	}
+
+	// The oldest points are evicted first, whichever series they belong to
+	if s.points != 10 {
+		t.Fatalf("expected the cap of 10 points, got %d", s.points)
+	}
+	pointsA, _ := s.Select("m", a, 0, 20)
+	pointsB, _ := s.Select("m", b, 0, 20)
+	if len(pointsA) != 3 || pointsA[0].Timestamp != 5 || len(pointsB) != 7 || pointsB[0].Timestamp != 5 {
+		t.Errorf("expected the points from 5 on, got %v and %v", pointsA, pointsB)
+	}
+}
+
+func TestRollupStorage(t *testing.T) {
+	tiers, err := parseRollupTiers([]models.RollupTier{{Resolution: "1h", Retention: "30d"}, {Resolution: "1m", Retention: "1d"}})
+	if err != nil {
+		t.Fatalf("parseRollupTiers error: %v", err)
+	}
+	for _, tier := range tiers {
+		tier.storage = newInMemoryStorage(0, 0)
+	}
+	raw := newInMemoryStorage(0, 0)
+	s := newRollupStorage(raw, tiers)
+	defer rawInterval.Store(rawInterval.Load())
+	rawInterval.Store(int64(15 * time.Second))
+
+	labels := []Label{{Name: "host", Value: "test"}}
+	for _, p := range []DataPoint{{Timestamp: 3600, Value: 4}, {Timestamp: 3630, Value: 2}, {Timestamp: 3660, Value: 9}, {Timestamp: 7200, Value: 1}} {
+		if err := s.InsertRows([]Row{{Metric: "m", Labels: labels, DataPoint: p}}); err != nil {
+			t.Fatalf("InsertRows error: %v", err)
+		}
+	}
+
+	if points, _ := s.Select("m", labels, 0, 10000); len(points) != 4 {
+		t.Errorf("expected the raw points to be kept, got %v", points)
+	}
+
+	// The buckets a newer point is past are written, the open ones are read from memory
+	want := map[string]float64{AggregationMin: 2, AggregationMax: 4, AggregationAvg: 3, AggregationLast: 2, AggregationCount: 2}
+	for aggregation, value := range want {
+		points, _ := tiers[0].storage.Select("m", rollupLabels(labels, aggregation), 0, 3659)
+		if len(points) != 1 || points[0] != (DataPoint{Timestamp: 3600, Value: value}) {
+			t.Errorf("expected the 1m %s of the first minute, got %v", aggregation, points)
+		}
+	}
+	points, _ := s.selectRollup(1, "m", labels, AggregationMax, 0, 10000)
+	if len(points) != 2 || points[0] != (DataPoint{Timestamp: 3600, Value: 9}) || points[1] != (DataPoint{Timestamp: 7200, Value: 1}) {
+		t.Errorf("expected the written and the open 1h buckets, got %v", points)
+	}
+
+	// Closing writes the open buckets
+	s.Close()
+	if points, _ := tiers[1].storage.Select("m", rollupLabels(labels, AggregationAvg), 0, 10000); len(points) != 2 || points[0].Value != 5 {
+		t.Errorf("expected both 1h buckets to be written, got %v", points)
+	}
+}
+
+func TestOpenRollupTiersSkipsRawResolution(t *testing.T) {
+	t.Chdir(t.TempDir())
+	defer rawInterval.Store(rawInterval.Load())
+	rawInterval.Store(int64(5 * time.Minute))
+
+	tiers, err := openRollupTiers(nil)
+	if err != nil {
+		t.Fatalf("openRollupTiers error: %v", err)
+	}
+	defer func() {
+		for _, tier := range tiers {
+			tier.storage.Close()
+		}
+	}()
+	if len(tiers) != 2 || tiers[0].name != "10m" || tiers[1].name != "1h" {
+		t.Errorf("expected the 10m and 1h tiers, got %v", tiers)
+	}
+	if _, err := os.Stat(filepath.Join(common.GetBasePath(), "rollups", "1m")); !os.IsNotExist(err) {
+		t.Errorf("expected no 1m rollup storage, got %v", err)
+	}
+}
+
+func TestPickTier(t *testing.T) {
+	tiers, _ := parseRollupTiers(DefaultRollupTiers)
+	s := &rollupStorage{tiers: tiers}
+	now := time.Now().Unix()
+	day := int64(24 * 3600)
+	defer rawInterval.Store(rawInterval.Load())
+
+	tests := []struct {
+		name       string
+		sync       time.Duration
+		start, end int64
+		want       int
+	}{
+		{"raw points for two hours", 15 * time.Second, now - 7200, now, -1},
+		{"1m for half a day", 15 * time.Second, now - day/2, now, 0},
+		{"10m for six days", 15 * time.Second, now - 6*day, now, 1},
+		{"1h for a month", 15 * time.Second, now - 29*day, now, 2},
+		{"1h beyond every retention", 15 * time.Second, now - 400*day, now, 2},
+		{"no 1m rollups of a 5m sync", 5 * time.Minute, now - 4*day, now, 1},
+	}
+	for _, tt := range tests {
+		rawInterval.Store(int64(tt.sync))
+		if got := s.pickTier(tt.start, tt.end, now, DefaultMaxQueryPoints); got != tt.want {
+			t.Errorf("%s: expected tier %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestValidateRollupTiers(t *testing.T) {
+	if err := ValidateRollupTiers(DefaultRollupTiers); err != nil {
+		t.Fatalf("expected the default tiers to be valid, got %v", err)
+	}
+	tests := map[string][]models.RollupTier{
+		"sub-second resolution": {{Resolution: "100ms", Retention: "1d"}},
+		"invalid retention":     {{Resolution: "1m", Retention: "forever"}},
+		"duplicate resolution":  {{Resolution: "1h", Retention: "1d"}, {Resolution: "60m", Retention: "7d"}},
+	}
+	for name, tiers := range tests {
+		if err := ValidateRollupTiers(tiers); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestIndexedStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "series.log")
+	s := newIndexedStorage(newInMemoryStorage(0, 0), path)
+	defer s.Close()
+
+	get := []Label{{Name: "route", Value: "/a"}, {Name: "method", Value: "GET"}}
+	post := []Label{{Name: "method", Value: "POST"}, {Name: "route", Value: "/a"}}
+	for i := range 3 {
+		s.InsertRows([]Row{
+			{Metric: "http_requests_total", Labels: get, DataPoint: DataPoint{Timestamp: int64(i), Value: 1}},
+			{Metric: "http_requests_total", Labels: post, DataPoint: DataPoint{Timestamp: int64(i), Value: 1}},
+			{Metric: "goroutines", DataPoint: DataPoint{Timestamp: int64(i), Value: 1}},
+		})
+	}
+
+	// The series written before a restart are loaded from the file, once each
+	for _, index := range []*indexedStorage{s, newIndexedStorage(newInMemoryStorage(0, 0), path)} {
+		if names := index.metrics(); len(names) != 2 || names[0] != "goroutines" || names[1] != "http_requests_total" {
+			t.Errorf("expected both metrics, got %v", names)
+		}
+		series := index.labels("http_requests_total")
+		if len(series) != 2 || series[0][0] != (Label{Name: "method", Value: "GET"}) || series[1][0] != (Label{Name: "method", Value: "POST"}) {
+			t.Errorf("expected both series with sorted labels, got %v", series)
+		}
+		if series := index.labels("goroutines"); len(series) != 1 || len(series[0]) != 0 {
+			t.Errorf("expected a series without labels, got %v", series)
+		}
+	}
+}
+
+func TestBoltStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), BoltFileName)
+	s, err := openBoltStorage(path, 0)
+	if err != nil {
+		t.Fatalf("openBoltStorage error: %v", err)
+	}
+
+	eth0 := []Label{{Name: "host", Value: "test"}, {Name: "interface", Value: "eth0"}}
+	eth1 := []Label{{Name: "host", Value: "test"}, {Name: "interface", Value: "eth1"}}
+	s.InsertRows([]Row{
+		{Metric: "net_bytes_sent", DataPoint: DataPoint{Timestamp: 20, Value: 2}, Labels: eth0},
+		{Metric: "net_bytes_sent", DataPoint: DataPoint{Timestamp: 10, Value: 1}, Labels: eth0},
+		{Metric: "net_bytes_sent", DataPoint: DataPoint{Timestamp: -5, Value: 0}, Labels: eth0},
+		{Metric: "net_bytes_sent", DataPoint: DataPoint{Timestamp: 10, Value: 50}, Labels: eth1},
+	})
+
+	// Points come back sorted between start and end inclusive, labels in any order
+	points, _ := s.Select("net_bytes_sent", []Label{eth0[1], eth0[0]}, -10, 20)
+	if len(points) != 3 || points[0].Timestamp != -5 || points[1].Value != 1 || points[2].Value != 2 {
+		t.Errorf("expected the three eth0 points in order, got %v", points)
+	}
+	if points, _ := s.Select("net_bytes_sent", eth0[:1], 0, 100); points != nil {
+		t.Errorf("expected no series with only the host label, got %v", points)
+	}
+
+	// Rollup tiers share the file in their own bucket
+	tier, err := s.share("rollups/1m", 0)
+	if err != nil {
+		t.Fatalf("share error: %v", err)
+	}
+	tier.InsertRows([]Row{{Metric: "net_bytes_sent", DataPoint: DataPoint{Timestamp: 10, Value: 9}, Labels: eth1}})
+	tier.Close()
+	s.Close()
+
+	s, err = openBoltStorage(path, time.Minute)
+	if err != nil {
+		t.Fatalf("reopening error: %v", err)
+	}
+	defer s.Close()
+	if points, _ := s.Select("net_bytes_sent", eth1, 0, 100); len(points) != 1 || points[0].Value != 50 {
+		t.Errorf("expected the eth1 point to survive a restart, got %v", points)
+	}
+	if records, err := s.series(); err != nil || len(records) != 2 || records[0].Labels["interface"] != "eth0" {
+		t.Errorf("expected both series, got %v (%v)", records, err)
+	}
+
+	// Expired points are evicted along with the series left empty
+	if err := s.evictExpired(time.Unix(75, 0)); err != nil {
+		t.Fatalf("evictExpired error: %v", err)
+	}
+	if points, _ := s.Select("net_bytes_sent", eth0, -10, 100); len(points) != 1 || points[0].Timestamp != 20 {
+		t.Errorf("expected only the point at 20, got %v", points)
+	}
+	if records, _ := s.series(); len(records) != 1 {
+		t.Errorf("expected the eth1 series to be dropped, got %v", records)
+	}
+}
+
+// decodeWriteRequest decodes the series of a remote write request body into their labels joined
+// by commas and their samples.
+func decodeWriteRequest(t *testing.T, body []byte) map[string][]DataPoint {
+	t.Helper()
+	data, err := s2.Decode(nil, body)
+	if err != nil {
+		t.Fatalf("decoding snappy: %v", err)
+	}
+	fields := func(b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) int) {
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			b = b[n:]
+			n = fn(num, typ, b)
+			if n < 0 {
+				t.Fatalf("invalid protobuf: %v", protowire.ParseError(n))
+			}
+			b = b[n:]
+		}
+	}
+
+	result := make(map[string][]DataPoint)
+	fields(data, func(_ protowire.Number, _ protowire.Type, b []byte) int {
+		series, n := protowire.ConsumeBytes(b)
+		var labels []string
+		var samples []DataPoint
+		fields(series, func(num protowire.Number, _ protowire.Type, b []byte) int {
+			message, n := protowire.ConsumeBytes(b)
+			if num == 1 {
+				var pair []string
+				fields(message, func(_ protowire.Number, _ protowire.Type, b []byte) int {
+					v, n := protowire.ConsumeString(b)
+					pair = append(pair, v)
+					return n
+				})
+				labels = append(labels, strings.Join(pair, "="))
+			} else {
+				var p DataPoint
+				fields(message, func(num protowire.Number, typ protowire.Type, b []byte) int {
+					if num == 1 {
+						v, n := protowire.ConsumeFixed64(b)
+						p.Value = math.Float64frombits(v)
+						return n
+					}
+					v, n := protowire.ConsumeVarint(b)
+					p.Timestamp = int64(v)
+					return n
+				})
+				samples = append(samples, p)
+			}
+			return n
+		})
+		result[strings.Join(labels, ",")] = samples
+		return n
+	})
+	return result
+}
+
+func TestRemoteWriteStorage(t *testing.T) {
+	defer func(backoff time.Duration) { remoteWriteBackoff = backoff }(remoteWriteBackoff)
+	remoteWriteBackoff = 10 * time.Millisecond
+
+	var mu sync.Mutex
+	var received []map[string][]DataPoint
+	failures := 1
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		if failures > 0 {
+			failures--
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, decodeWriteRequest(t, body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	dir := t.TempDir()
+	cfg := models.RemoteWriteConfig{
+		URL:       receiver.URL,
+		Headers:   map[string]string{"Authorization": "Bearer token"},
+		Labels:    map[string]string{"job": "orders", "host": "ignored"},
+		BatchSize: 3,
+	}
+	s, err := newRemoteWriteStorage(newInMemoryStorage(0, 0), cfg, dir)
+	if err != nil {
+		t.Fatalf("newRemoteWriteStorage error: %v", err)
+	}
+	host := []Label{{Name: "host", Value: "test"}}
+	s.InsertRows([]Row{
+		{Metric: "http.requests", Labels: append(host, Label{Name: "route-name", Value: "/a"}), DataPoint: DataPoint{Timestamp: 20, Value: 2}},
+		{Metric: "http.requests", Labels: append(host, Label{Name: "route-name", Value: "/a"}), DataPoint: DataPoint{Timestamp: 10, Value: 1}},
+		{Metric: "goroutines", Labels: host, DataPoint: DataPoint{Timestamp: 10, Value: 7}},
+		{Metric: "goroutines", Labels: host, DataPoint: DataPoint{Timestamp: 20, Value: 8}},
+	})
+
+	// The full batch is sent once the receiver recovers, the rest is queued on close
+	deadline := time.Now().Add(5 * time.Second)
+	for s.queue.count() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if points, _ := s.Select("goroutines", host, 0, 100); len(points) != 2 {
+		t.Errorf("expected the rows to be stored too, got %v", points)
+	}
+	s.Close()
+
+	mu.Lock()
+	if len(received) != 1 || len(received[0]) != 2 {
+		t.Fatalf("expected a request with two series, got %v", received)
+	}
+	requests := received[0]
+	if points := requests["__name__=http_requests,host=test,job=orders,route_name=/a"]; len(points) != 2 || points[0] != (DataPoint{Timestamp: 10000, Value: 1}) {
+		t.Errorf("expected the sorted samples with sanitized names, got %v", requests)
+	}
+	if points := requests["__name__=goroutines,host=test,job=orders"]; len(points) != 1 || points[0].Value != 7 {
+		t.Errorf("expected the first goroutines sample, got %v", requests)
+	}
+	mu.Unlock()
+
+	// The queued batch is sent after a restart
+	s, err = newRemoteWriteStorage(newInMemoryStorage(0, 0), cfg, dir)
+	if err != nil {
+		t.Fatalf("reopening error: %v", err)
+	}
+	defer s.Close()
+	for s.queue.count() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || len(received[1]["__name__=goroutines,host=test,job=orders"]) != 1 {
+		t.Errorf("expected the queued row to be sent, got %v", received)
+	}
+}
+
+func TestRemoteWriteStorage_Rejected(t *testing.T) {
+	var requests atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+
+	s, err := newRemoteWriteStorage(newInMemoryStorage(0, 0), models.RemoteWriteConfig{URL: receiver.URL, BatchSize: 1}, t.TempDir())
+	if err != nil {
+		t.Fatalf("newRemoteWriteStorage error: %v", err)
+	}
+	defer s.Close()
+	s.InsertRows([]Row{{Metric: "goroutines", DataPoint: DataPoint{Timestamp: 10, Value: 7}}})
+
+	// A client error drops the batch instead of retrying it
+	deadline := time.Now().Add(5 * time.Second)
+	for s.queue.count() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if s.queue.count() != 0 || requests.Load() != 1 {
+		t.Errorf("expected a single attempt, got %d with %d batches queued", requests.Load(), s.queue.count())
+	}
+}
+
+func TestImportSkipsRemoteWrite(t *testing.T) {
+	var requests atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	tiers, err := parseRollupTiers([]models.RollupTier{{Resolution: "1m", Retention: "1d"}})
+	if err != nil {
+		t.Fatalf("parseRollupTiers error: %v", err)
+	}
+	tiers[0].storage = newInMemoryStorage(0, 0)
+	defer rawInterval.Store(rawInterval.Load())
+	rawInterval.Store(int64(15 * time.Second))
+
+	raw := newInMemoryStorage(0, 0)
+	writer, err := newRemoteWriteStorage(raw, models.RemoteWriteConfig{URL: receiver.URL, BatchSize: 1}, t.TempDir())
+	if err != nil {
+		t.Fatalf("newRemoteWriteStorage error: %v", err)
+	}
+	s := newIndexedStorage(newRollupStorage(writer, tiers), "")
+	defer s.Close()
+
+	// Historical points are stored, indexed and rolled up without being sent
+	var rows []Row
+	for _, ts := range []int64{60, 90, 120} {
+		rows = append(rows, Row{Metric: "imported", DataPoint: DataPoint{Timestamp: ts, Value: float64(ts)}})
+	}
+	if err := importRows(s, rows); err != nil {
+		t.Fatalf("importRows error: %v", err)
+	}
+	if points, _ := raw.Select("imported", nil, 0, 1000); len(points) != 3 {
+		t.Errorf("expected the imported points to be stored, got %v", points)
+	}
+	if metrics := s.metrics(); len(metrics) != 1 || metrics[0] != "imported" {
+		t.Errorf("expected the imported series to be indexed, got %v", metrics)
+	}
+	if points, _ := tiers[0].storage.Select("imported", rollupLabels(nil, AggregationAvg), 0, 1000); len(points) != 1 || points[0].Value != 75 {
+		t.Errorf("expected the imported points to be rolled up, got %v", points)
+	}
+	writer.mu.Lock()
+	pending := len(writer.pending)
+	writer.mu.Unlock()
+	if pending != 0 || writer.queue.count() != 0 || requests.Load() != 0 {
+		t.Errorf("expected nothing sent or queued, got %d pending, %d queued and %d requests", pending, writer.queue.count(), requests.Load())
+	}
+
+	// Inserted points are still sent
+	s.InsertRows([]Row{{Metric: "live", DataPoint: DataPoint{Timestamp: 180, Value: 1}}})
+	deadline := time.Now().Add(5 * time.Second)
+	for requests.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("expected the inserted point to be sent, got %d requests", requests.Load())
+	}
+}
+
+func TestBatchQueue(t *testing.T) {
+	dir := t.TempDir()
+	q, err := openBatchQueue(dir, 10)
+	if err != nil {
+		t.Fatalf("openBatchQueue error: %v", err)
+	}
+	for _, body := range []string{"first", "second", "third"} {
+		if err := q.push([]byte(body)); err != nil {
+			t.Fatalf("push error: %v", err)
+		}
+	}
+
+	// Beyond the maximum size the oldest batches are dropped, the rest survive a restart
+	os.WriteFile(filepath.Join(dir, "00000000000000000009.batch.tmp"), []byte("partial"), 0o644)
+	q, _ = openBatchQueue(dir, 10)
+	if name, body, _ := q.oldest(); q.count() != 1 || string(body) != "third" {
+		t.Fatalf("expected only the third batch, got %d batches, oldest %s %q", q.count(), name, body)
+	}
+	q.push([]byte("fourth"))
+	if name, _, _ := q.oldest(); name != "00000000000000000003.batch" {
+		t.Errorf("expected sequence numbers to continue after a restart, got %s", name)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "00000000000000000009.batch.tmp")); !os.IsNotExist(err) {
+		t.Errorf("expected the partial batch to be removed, got %v", err)
+	}
+}
+
+func TestValidateRemoteWrite(t *testing.T) {
+	valid := models.RemoteWriteConfig{URL: "https://prometheus:9090/api/v1/write", FlushInterval: "10s", Timeout: "10s"}
+	if err := ValidateRemoteWrite(valid); err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+	for _, cfg := range []models.RemoteWriteConfig{
+		{URL: "prometheus:9090"},
+		{URL: "ftp://prometheus/write"},
+		{URL: "http://prometheus/write", BatchSize: -1},
+		{URL: "http://prometheus/write", FlushInterval: "soon"},
+		{URL: "http://prometheus/write", Timeout: "-1s"},
+	} {
+		if err := ValidateRemoteWrite(cfg); err == nil {
+			t.Errorf("expected an error for %+v", cfg)
+		}
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	SetStorageType("memory")
+	manager = &storageManager{} // Reset singleton
+	sto, err := GetStorageInstance()
+	if err != nil {
+		t.Fatalf("GetStorageInstance error: %v", err)
+	}
+	defer func() { manager = &storageManager{} }()
+
+	api := []Label{{Name: "host", Value: "a"}, {Name: "route", Value: `/q?x="1"`}}
+	other := []Label{{Name: "host", Value: "b"}, {Name: "route", Value: "/other"}}
+	sto.InsertRows([]Row{
+		{Metric: "export_requests_total", Labels: api, DataPoint: DataPoint{Timestamp: 100, Value: 1.5}},
+		{Metric: "export_requests_total", Labels: api, DataPoint: DataPoint{Timestamp: 160, Value: math.Inf(1)}},
+		{Metric: "export_requests_total", Labels: other, DataPoint: DataPoint{Timestamp: 100, Value: 7}},
+		{Metric: "export_requests_total", Labels: api, DataPoint: DataPoint{Timestamp: 500, Value: 9}},
+	})
+
+	opts := ExportOptions{Metrics: []string{"export_requests_total"}, Match: map[string]string{"host": "a"}, Start: 0, End: 200}
+	remap := ImportOptions{RenameLabels: map[string]string{"host": "source_host"}, Labels: map[string]string{"source": "backup"}}
+	for i, format := range []string{FormatCSV, FormatNDJSON, FormatOpenMetrics} {
+		var buf strings.Builder
+		if n, err := Export(&buf, format, opts); err != nil || n != 2 {
+			t.Fatalf("%s: Export = %d, %v, expected the 2 points in range of the matched series", format, n, err)
+		}
+		remap.Labels["source"] = format
+		if n, err := Import(strings.NewReader(buf.String()), format, remap); err != nil || n != 2 {
+			t.Fatalf("%s: Import = %d, %v, expected 2 points:\n%s", format, n, err, buf.String())
+		}
+
+		labels := []Label{{Name: "route", Value: api[1].Value}, {Name: "source", Value: format}, {Name: "source_host", Value: "a"}}
+		points, err := sto.Select("export_requests_total", labels, 0, 1000)
+		if err != nil || len(points) != 2 || points[0] != (DataPoint{Timestamp: 100, Value: 1.5}) || !math.IsInf(points[1].Value, 1) {
+			t.Errorf("%s: expected the imported points under the remapped labels, got %v, %v", format, points, err)
+		}
+		if got := len(manager.index.labels("export_requests_total")); got != 3+i {
+			t.Errorf("%s: expected %d series, got %d", format, 3+i, got)
+		}
+	}
+
+	if _, err := Import(strings.NewReader("m{a=\"1\"} 1 100\nm{a=1} 2 100\n"), FormatOpenMetrics, ImportOptions{}); err == nil {
+		t.Error("expected an error for an unquoted label value")
+	}
+	if _, err := Export(io.Discard, "xml", ExportOptions{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
 func TestGetHostLabel(t *testing.T) {
 	label := GetHostLabel()
 	if label.Name != "host" {
@@ -149,3 +774,131 @@ func TestStoreAndRetrieveMetrics(t *testing.T) {
 	// Cleanup
 	CloseStorage()
 }
+
+func TestGenerateNetworkIORows_PerInterface(t *testing.T) {
+	stats := &models.ServiceStats{
+		NetworkStatistics: models.NetworkStatistics{
+			BytesSentPerSec: 150,
+			Interfaces: []models.NetworkInterfaceStats{
+				{Name: "eth0", BytesSent: 1000, BytesSentPerSec: 100},
+				{Name: "eth1", BytesSent: 500, BytesSentPerSec: 50},
+			},
+		},
+	}
+	host := Label{Name: "host", Value: "test"}
+	rows := generateNetworkIORows(stats, host, 1)
+
+	found := map[string]float64{}
+	for _, r := range rows {
+		if r.Metric == "bytes_sent_per_sec" && r.DataPoint.Value != 150 {
+			t.Errorf("expected aggregate bytes_sent_per_sec 150, got %v", r.DataPoint.Value)
+		}
+		if r.Metric != "net_bytes_sent_per_sec" {
+			continue
+		}
+		if len(r.Labels) != 2 || r.Labels[1].Name != "interface" {
+			t.Fatalf("expected host and interface labels, got %v", r.Labels)
+		}
+		found[r.Labels[1].Value] = r.DataPoint.Value
+	}
+	if found["eth0"] != 100 || found["eth1"] != 50 {
+		t.Errorf("unexpected per-interface rates: %v", found)
+	}
+}
+
+func TestGenerateCollectorRows(t *testing.T) {
+	stats := &models.ServiceStats{
+		CollectorMetrics: map[string][]models.CollectorMetric{
+			"queue": {
+				{Name: "queue_depth", Value: 7, Labels: map[string]string{"queue": "emails", "host": "ignored"}},
+			},
+		},
+	}
+	host := Label{Name: "host", Value: "test"}
+	rows := generateCollectorRows(stats, host, 1)
+
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	r := rows[0]
+	if r.Metric != "queue_depth" || r.DataPoint.Value != 7 {
+		t.Errorf("unexpected row: %+v", r)
+	}
+	want := []Label{host, {Name: "collector", Value: "queue"}, {Name: "queue", Value: "emails"}}
+	if len(r.Labels) != len(want) {
+		t.Fatalf("expected labels %v, got %v", want, r.Labels)
+	}
+	for i := range want {
+		if r.Labels[i] != want[i] {
+			t.Errorf("label %d: expected %v, got %v", i, want[i], r.Labels[i])
+		}
+	}
+}
+
+func TestGenerateGoroutineSignatureRows(t *testing.T) {
+	stats := &models.ServiceStats{
+		GoroutineSignatures: []models.GoroutineSignatureCount{
+			{Signature: "a1b2", Function: "main.worker", Count: 12},
+		},
+	}
+	host := Label{Name: "host", Value: "test"}
+	rows := generateGoroutineSignatureRows(stats, host, 1)
+
+	if len(rows) != 1 || rows[0].Metric != "goroutines_by_signature" || rows[0].DataPoint.Value != 12 {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if len(rows[0].Labels) != 2 || rows[0].Labels[1] != (Label{Name: "signature", Value: "a1b2"}) {
+		t.Errorf("unexpected labels: %v", rows[0].Labels)
+	}
+}
+
+func TestGenerateGCStatsRows(t *testing.T) {
+	stats := &models.ServiceStats{
+		GCStatistics: models.GCStatistics{GCPercent: -1, MemoryLimit: 1 << 30, HeapGoal: 2048, HeapLive: 1024, CyclesPerMinute: 4, CPUPercent: 1.5},
+	}
+	rows := generateGCStatsRows(stats, Label{Name: "host", Value: "test"}, 1)
+
+	values := map[string]float64{}
+	for _, r := range rows {
+		values[r.Metric] = r.DataPoint.Value
+	}
+	if values["gc_percent"] != -1 || values["gc_memory_limit"] != 1<<30 || values["gc_heap_goal"] != 2048 ||
+		values["gc_heap_live"] != 1024 || values["gc_cycles_per_minute"] != 4 || values["gc_cpu_percent"] != 1.5 {
+		t.Errorf("unexpected GC rows: %v", values)
+	}
+}
+
+func TestGenerateCustomMetricRows(t *testing.T) {
+	stats := &models.ServiceStats{
+		CustomMetrics: []models.CustomMetric{
+			{Name: "orders_total", Type: "counter", Value: 3, Labels: map[string]string{"region": "eu"}},
+			{Name: "checkout_seconds", Type: "histogram", Count: 2, Sum: 1.5, Quantiles: map[string]float64{"0.5": 0.7}},
+		},
+	}
+	host := Label{Name: "host", Value: "test"}
+	rows := generateCustomMetricRows(stats, host, 1)
+
+	values := map[string]float64{}
+	for _, r := range rows {
+		if r.Labels[1] != (Label{Name: "collector", Value: "custom"}) {
+			t.Errorf("expected collector=custom label on %s, got %v", r.Metric, r.Labels)
+		}
+		key := r.Metric
+		for _, l := range r.Labels[2:] {
+			key += "," + l.Name + "=" + l.Value
+		}
+		values[key] = r.DataPoint.Value
+	}
+
+	want := map[string]float64{
+		"orders_total,region=eu":        3,
+		"checkout_seconds_count":        2,
+		"checkout_seconds_sum":          1.5,
+		"checkout_seconds,quantile=0.5": 0.7,
+	}
+	for key, v := range want {
+		if got, ok := values[key]; !ok || got != v {
+			t.Errorf("expected %s = %v, got %v (present: %v)", key, v, got, ok)
+		}
+	}
+}
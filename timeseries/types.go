@@ -2,6 +2,9 @@ package timeseries
 
 import "github.com/nakabonne/tstorage"
 
+// ErrNoDataPoints is returned by the disk storage when no data point matches a query.
+var ErrNoDataPoints = tstorage.ErrNoDataPoints
+
 // Label represents a metric label (key-value pair).
 type Label struct {
 	Name  string